@@ -15,9 +15,12 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -28,10 +31,13 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/webhook"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcli"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/protocol/third"
 	"github.com/openimsdk/tools/a2r"
 	"github.com/openimsdk/tools/apiresp"
 	"github.com/openimsdk/tools/errs"
@@ -78,13 +84,15 @@ func getMsgDataDescriptor() []protoreflect.FieldDescriptor {
 
 type MessageApi struct {
 	Client        msg.MsgClient
+	ExtClient     msgext.MsgExtClient
 	userClient    *rpcli.UserClient
+	thirdClient   third.ThirdClient
 	imAdminUserID []string
 	validate      *validator.Validate
 }
 
-func NewMessageApi(client msg.MsgClient, userClient *rpcli.UserClient, imAdminUserID []string) MessageApi {
-	return MessageApi{Client: client, userClient: userClient, imAdminUserID: imAdminUserID, validate: validator.New()}
+func NewMessageApi(client msg.MsgClient, extClient msgext.MsgExtClient, userClient *rpcli.UserClient, thirdClient third.ThirdClient, imAdminUserID []string) MessageApi {
+	return MessageApi{Client: client, ExtClient: extClient, userClient: userClient, thirdClient: thirdClient, imAdminUserID: imAdminUserID, validate: validator.New()}
 }
 
 func (*MessageApi) SetOptions(options map[string]bool, value bool) {
@@ -163,6 +171,158 @@ func (m *MessageApi) RevokeMsg(c *gin.Context) {
 	a2r.Call(c, msg.MsgClient.RevokeMsg, m.Client)
 }
 
+func (m *MessageApi) ModifyMsg(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.ModifyMsg, m.ExtClient)
+}
+
+func (m *MessageApi) GetMsgModifyHistory(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetMsgModifyHistory, m.ExtClient)
+}
+
+func (m *MessageApi) AddMsgReaction(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.AddMsgReaction, m.ExtClient)
+}
+
+func (m *MessageApi) DeleteMsgReaction(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.DeleteMsgReaction, m.ExtClient)
+}
+
+func (m *MessageApi) GetThreadReplies(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetThreadReplies, m.ExtClient)
+}
+
+func (m *MessageApi) SetThreadHasReadSeq(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.SetThreadHasReadSeq, m.ExtClient)
+}
+
+func (m *MessageApi) GetThreadsHasReadAndMaxSeq(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetThreadsHasReadAndMaxSeq, m.ExtClient)
+}
+
+func (m *MessageApi) ScheduleMsg(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.ScheduleMsg, m.ExtClient)
+}
+
+func (m *MessageApi) CancelScheduledMsg(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.CancelScheduledMsg, m.ExtClient)
+}
+
+func (m *MessageApi) ListScheduledMsgs(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.ListScheduledMsgs, m.ExtClient)
+}
+
+func (m *MessageApi) SearchMsgByKeyword(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.SearchMsg, m.ExtClient)
+}
+
+func (m *MessageApi) SetRetentionRule(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.SetRetentionRule, m.ExtClient)
+}
+
+func (m *MessageApi) DeleteRetentionRule(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.DeleteRetentionRule, m.ExtClient)
+}
+
+func (m *MessageApi) GetRetentionRules(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetRetentionRules, m.ExtClient)
+}
+
+func (m *MessageApi) GetRetentionReport(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetRetentionReport, m.ExtClient)
+}
+
+func (m *MessageApi) PlaceLegalHold(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.PlaceLegalHold, m.ExtClient)
+}
+
+func (m *MessageApi) ReleaseLegalHold(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.ReleaseLegalHold, m.ExtClient)
+}
+
+func (m *MessageApi) GetLegalHolds(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetLegalHolds, m.ExtClient)
+}
+
+func (m *MessageApi) GetLegalHoldLogs(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetLegalHoldLogs, m.ExtClient)
+}
+
+func (m *MessageApi) ImportMsgs(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.ImportMsgs, m.ExtClient)
+}
+
+func (m *MessageApi) GetImportCheckpoints(c *gin.Context) {
+	a2r.Call(c, msgext.MsgExtClient.GetImportCheckpoints, m.ExtClient)
+}
+
+// ExportConversations streams the conversations back as a zip archive in the msgexport format.
+func (m *MessageApi) ExportConversations(c *gin.Context) {
+	var req apistruct.ExportConversationsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if len(req.ConversationIDs) == 0 {
+		apiresp.GinError(c, errs.ErrArgs.WrapMsg("conversationIDs is empty"))
+		return
+	}
+	if err := authverify.CheckAdmin(c); err != nil {
+		apiresp.GinError(c, errs.ErrNoPermission.WrapMsg("only app manager can export conversations"))
+		return
+	}
+	req.ConversationIDs = datautil.Distinct(req.ConversationIDs)
+	source := &exportSource{client: m.ExtClient}
+	// fail with a json error while nothing has been written yet
+	first, err := source.GetConversation(c, req.ConversationIDs[0])
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	source.first = first
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="openim-export-%d.zip"`, time.Now().Unix()))
+	opts := msgexport.Options{
+		ConversationIDs: req.ConversationIDs,
+		Attachments:     req.WithAttachments,
+		AccessURL:       m.accessURL,
+	}
+	if _, err := msgexport.Export(c, c.Writer, source, opts); err != nil {
+		// the archive is left without its central directory, so the client sees it as corrupt
+		log.ZError(c, "export conversations failed", err, "conversationIDs", req.ConversationIDs)
+	}
+}
+
+// accessURL signs the object through the third service, so only objects of the configured storage are downloaded.
+func (m *MessageApi) accessURL(ctx context.Context, name string) (string, error) {
+	resp, err := m.thirdClient.AccessURL(ctx, &third.AccessURLReq{Name: name})
+	if err != nil {
+		return "", err
+	}
+	return resp.Url, nil
+}
+
+type exportSource struct {
+	client msgext.MsgExtClient
+	first  *msgext.GetExportConversationResp
+}
+
+func (e *exportSource) GetConversation(ctx context.Context, conversationID string) (*msgext.GetExportConversationResp, error) {
+	if e.first != nil && e.first.ConversationID == conversationID {
+		first := e.first
+		e.first = nil
+		return first, nil
+	}
+	return e.client.GetExportConversation(ctx, &msgext.GetExportConversationReq{ConversationID: conversationID})
+}
+
+func (e *exportSource) GetMsgDoc(ctx context.Context, conversationID string, index int64) ([]*msgext.ExportedMsg, error) {
+	resp, err := e.client.GetExportMsgDoc(ctx, &msgext.GetExportMsgDocReq{ConversationID: conversationID, Index: index})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msgs, nil
+}
+
 func (m *MessageApi) MarkMsgsAsRead(c *gin.Context) {
 	a2r.Call(c, msg.MsgClient.MarkMsgsAsRead, m.Client)
 }
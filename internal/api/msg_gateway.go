@@ -0,0 +1,95 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/gatewayext"
+	"github.com/openimsdk/tools/apiresp"
+	"github.com/openimsdk/tools/discovery"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+type MsgGatewayApi struct {
+	discov discovery.Conn
+	config config.RpcService
+}
+
+func NewMsgGatewayApi(discov discovery.Conn, config config.RpcService) MsgGatewayApi {
+	return MsgGatewayApi{discov: discov, config: config}
+}
+
+type GetGatewayNodesResp struct {
+	Nodes []*gatewayext.GatewayNode `json:"nodes"`
+}
+
+// GetNodes lists the msg gateways found in the discovery.
+func (m *MsgGatewayApi) GetNodes(c *gin.Context) {
+	conns, err := m.discov.GetConns(c, m.config.MessageGateway)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := GetGatewayNodesResp{Nodes: make([]*gatewayext.GatewayNode, 0, len(conns))}
+	for _, conn := range conns {
+		reply, err := gatewayext.NewMsgGatewayExtClient(conn).GetGatewayNode(c, &gatewayext.GetGatewayNodeReq{})
+		if err != nil {
+			if apiresp.ParseError(err).ErrCode == errs.NoPermissionError {
+				apiresp.GinError(c, err)
+				return
+			}
+			log.ZWarn(c, "GetGatewayNode rpc error", err)
+			continue
+		}
+		resp.Nodes = append(resp.Nodes, reply.Node)
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// DrainNode asks every msg gateway to drain, only the node with the requested id does.
+func (m *MsgGatewayApi) DrainNode(c *gin.Context) {
+	var req gatewayext.DrainGatewayReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := req.Check(); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WrapMsg(err.Error()))
+		return
+	}
+	conns, err := m.discov.GetConns(c, m.config.MessageGateway)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	for _, conn := range conns {
+		reply, err := gatewayext.NewMsgGatewayExtClient(conn).DrainGateway(c, &req)
+		if err != nil {
+			if apiresp.ParseError(err).ErrCode == errs.NoPermissionError {
+				apiresp.GinError(c, err)
+				return
+			}
+			log.ZWarn(c, "DrainGateway rpc error", err)
+			continue
+		}
+		if reply.Node != nil {
+			apiresp.GinSuccess(c, reply)
+			return
+		}
+	}
+	apiresp.GinError(c, errs.ErrRecordNotFound.WrapMsg("msg gateway node not found", "nodeID", req.NodeID))
+}
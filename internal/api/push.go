@@ -0,0 +1,41 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/pushext"
+	"github.com/openimsdk/tools/a2r"
+)
+
+type PushApi struct {
+	Client pushext.PushExtClient
+}
+
+func NewPushApi(client pushext.PushExtClient) PushApi {
+	return PushApi{client}
+}
+
+func (o *PushApi) PageOfflinePushDeadLetters(c *gin.Context) {
+	a2r.Call(c, pushext.PushExtClient.PageOfflinePushDeadLetters, o.Client)
+}
+
+func (o *PushApi) RetryOfflinePushDeadLetters(c *gin.Context) {
+	a2r.Call(c, pushext.PushExtClient.RetryOfflinePushDeadLetters, o.Client)
+}
+
+func (o *PushApi) DeleteOfflinePushDeadLetters(c *gin.Context) {
+	a2r.Call(c, pushext.PushExtClient.DeleteOfflinePushDeadLetters, o.Client)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/gin-contrib/gzip"
@@ -14,6 +15,10 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/pushext"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcli"
 	pbAuth "github.com/openimsdk/protocol/auth"
 	"github.com/openimsdk/protocol/constant"
@@ -83,6 +88,10 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 	if err != nil {
 		return nil, err
 	}
+	pushConn, err := client.GetConn(ctx, cfg.Discovery.RpcService.Push)
+	if err != nil {
+		return nil, err
+	}
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -117,7 +126,7 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 	r.Use(api.GinLogger(), prommetricsGin(), gin.RecoveryWithWriter(gin.DefaultErrorWriter, mw.GinPanicErr), mw.CorsHandler(),
 		mw.GinParseOperationID(), GinParseToken(rpcli.NewAuthClient(authConn)), setGinIsAdmin(cfg.Share.IMAdminUser.UserIDs))
 
-	u := NewUserApi(user.NewUserClient(userConn), client, cfg.Discovery.RpcService)
+	u := NewUserApi(user.NewUserClient(userConn), userext.NewUserExtClient(userConn), client, cfg.Discovery.RpcService)
 	{
 		userRouterGroup := r.Group("/user")
 		userRouterGroup.POST("/user_register", u.UserRegister)
@@ -133,6 +142,8 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 		userRouterGroup.POST("/subscribe_users_status", u.SubscriberStatus)
 		userRouterGroup.POST("/get_users_status", u.GetUserStatus)
 		userRouterGroup.POST("/get_subscribe_users_status", u.GetSubscribeUsersStatus)
+		userRouterGroup.POST("/set_user_presence", u.SetUserPresence)
+		userRouterGroup.POST("/get_users_presence", u.GetUsersPresence)
 
 		userRouterGroup.POST("/process_user_command_add", u.ProcessUserCommandAdd)
 		userRouterGroup.POST("/process_user_command_delete", u.ProcessUserCommandDelete)
@@ -225,11 +236,15 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 	}
 	// Third service
 	{
-		t := NewThirdApi(third.NewThirdClient(thirdConn), cfg.API.Prometheus.GrafanaURL)
+		t := NewThirdApi(third.NewThirdClient(thirdConn), thirdext.NewThirdExtClient(thirdConn), cfg.API.Prometheus.GrafanaURL, cfg.Push.WebPush.PublicKey)
 		thirdGroup := r.Group("/third")
 		thirdGroup.GET("/prometheus", t.GetPrometheus)
 		thirdGroup.POST("/fcm_update_token", t.FcmUpdateToken)
+		thirdGroup.POST("/update_vendor_token", t.UpdateVendorToken)
 		thirdGroup.POST("/set_app_badge", t.SetAppBadge)
+		thirdGroup.POST("/register_web_push_subscription", t.RegisterWebPushSubscription)
+		thirdGroup.POST("/unregister_web_push_subscription", t.UnregisterWebPushSubscription)
+		thirdGroup.POST("/get_web_push_public_key", t.GetWebPushPublicKey)
 
 		logs := thirdGroup.Group("/logs")
 		logs.POST("/upload", t.UploadLogs)
@@ -247,9 +262,16 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 		objectGroup.POST("/initiate_form_data", t.InitiateFormData)
 		objectGroup.POST("/complete_form_data", t.CompleteFormData)
 		objectGroup.GET("/*name", t.ObjectRedirect)
+
+		applicationGroup := r.Group("/application")
+		applicationGroup.POST("/add_version", t.AddApplicationVersion)
+		applicationGroup.POST("/update_version", t.UpdateApplicationVersion)
+		applicationGroup.POST("/delete_version", t.DeleteApplicationVersion)
+		applicationGroup.POST("/page_versions", t.PageApplicationVersion)
+		applicationGroup.POST("/latest_version", t.LatestApplicationVersion)
 	}
 	// Message
-	m := NewMessageApi(msg.NewMsgClient(msgConn), rpcli.NewUserClient(userConn), cfg.Share.IMAdminUser.UserIDs)
+	m := NewMessageApi(msg.NewMsgClient(msgConn), msgext.NewMsgExtClient(msgConn), rpcli.NewUserClient(userConn), third.NewThirdClient(thirdConn), cfg.Share.IMAdminUser.UserIDs)
 	{
 		msgGroup := r.Group("/msg")
 		msgGroup.POST("/newest_seq", m.GetSeq)
@@ -258,6 +280,28 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 		msgGroup.POST("/send_business_notification", m.SendBusinessNotification)
 		msgGroup.POST("/pull_msg_by_seq", m.PullMsgBySeqs)
 		msgGroup.POST("/revoke_msg", m.RevokeMsg)
+		msgGroup.POST("/modify_msg", m.ModifyMsg)
+		msgGroup.POST("/get_msg_modify_history", m.GetMsgModifyHistory)
+		msgGroup.POST("/add_msg_reaction", m.AddMsgReaction)
+		msgGroup.POST("/delete_msg_reaction", m.DeleteMsgReaction)
+		msgGroup.POST("/get_thread_replies", m.GetThreadReplies)
+		msgGroup.POST("/set_thread_has_read_seq", m.SetThreadHasReadSeq)
+		msgGroup.POST("/get_threads_has_read_and_max_seq", m.GetThreadsHasReadAndMaxSeq)
+		msgGroup.POST("/schedule_msg", m.ScheduleMsg)
+		msgGroup.POST("/cancel_scheduled_msg", m.CancelScheduledMsg)
+		msgGroup.POST("/list_scheduled_msgs", m.ListScheduledMsgs)
+		msgGroup.POST("/search_msg_by_keyword", m.SearchMsgByKeyword)
+		msgGroup.POST("/set_retention_rule", m.SetRetentionRule)
+		msgGroup.POST("/delete_retention_rule", m.DeleteRetentionRule)
+		msgGroup.POST("/get_retention_rules", m.GetRetentionRules)
+		msgGroup.POST("/get_retention_report", m.GetRetentionReport)
+		msgGroup.POST("/place_legal_hold", m.PlaceLegalHold)
+		msgGroup.POST("/release_legal_hold", m.ReleaseLegalHold)
+		msgGroup.POST("/get_legal_holds", m.GetLegalHolds)
+		msgGroup.POST("/get_legal_hold_logs", m.GetLegalHoldLogs)
+		msgGroup.POST("/export_conversations", m.ExportConversations)
+		msgGroup.POST("/import_msgs", m.ImportMsgs)
+		msgGroup.POST("/get_import_checkpoints", m.GetImportCheckpoints)
 		msgGroup.POST("/mark_msgs_as_read", m.MarkMsgsAsRead)
 		msgGroup.POST("/mark_conversation_as_read", m.MarkConversationAsRead)
 		msgGroup.POST("/get_conversations_has_read_and_max_seq", m.GetConversationsHasReadAndMaxSeq)
@@ -308,6 +352,19 @@ func newGinRouter(ctx context.Context, client discovery.SvcDiscoveryRegistry, cf
 		jssdk.POST("/get_conversations", j.GetConversations)
 		jssdk.POST("/get_active_conversations", j.GetActiveConversations)
 	}
+	{
+		p := NewPushApi(pushext.NewPushExtClient(pushConn))
+		pushGroup := r.Group("/push")
+		pushGroup.POST("/page_dead_letters", p.PageOfflinePushDeadLetters)
+		pushGroup.POST("/retry_dead_letters", p.RetryOfflinePushDeadLetters)
+		pushGroup.POST("/delete_dead_letters", p.DeleteOfflinePushDeadLetters)
+	}
+	{
+		mg := NewMsgGatewayApi(client, cfg.Discovery.RpcService)
+		msgGatewayGroup := r.Group("/msg_gateway")
+		msgGatewayGroup.POST("/get_nodes", mg.GetNodes)
+		msgGatewayGroup.POST("/drain_node", mg.DrainNode)
+	}
 	{
 		pd := NewPrometheusDiscoveryApi(cfg, client)
 		proDiscoveryGroup := r.Group("/prometheus_discovery")
@@ -356,6 +413,10 @@ func GinParseToken(authClient *rpcli.AuthClient) gin.HandlerFunc {
 			}
 
 			token := c.Request.Header.Get(constant.Token)
+			if token == "" && slices.Contains(OptionalTokenList, c.Request.URL.Path) {
+				c.Next()
+				return
+			}
 			if token == "" {
 				log.ZWarn(c, "header get token error", servererrs.ErrArgs.WrapMsg("header must have token"))
 				apiresp.GinError(c, servererrs.ErrArgs.WrapMsg("header must have token"))
@@ -386,3 +447,8 @@ var Whitelist = []string{
 	"/auth/get_admin_token",
 	"/auth/parse_token",
 }
+
+// OptionalTokenList api parse the token only if there is one
+var OptionalTokenList = []string{
+	"/application/latest_version",
+}
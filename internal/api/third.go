@@ -24,8 +24,10 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
 	"github.com/openimsdk/protocol/third"
 	"github.com/openimsdk/tools/a2r"
+	"github.com/openimsdk/tools/apiresp"
 	"github.com/openimsdk/tools/errs"
 	"github.com/openimsdk/tools/mcontext"
 )
@@ -33,16 +35,23 @@ import (
 type ThirdApi struct {
 	GrafanaUrl string
 	Client     third.ThirdClient
+	ExtClient  thirdext.ThirdExtClient
+	// WebPushPublicKey is the VAPID public key of the push service.
+	WebPushPublicKey string
 }
 
-func NewThirdApi(client third.ThirdClient, grafanaUrl string) ThirdApi {
-	return ThirdApi{Client: client, GrafanaUrl: grafanaUrl}
+func NewThirdApi(client third.ThirdClient, extClient thirdext.ThirdExtClient, grafanaUrl string, webPushPublicKey string) ThirdApi {
+	return ThirdApi{Client: client, ExtClient: extClient, GrafanaUrl: grafanaUrl, WebPushPublicKey: webPushPublicKey}
 }
 
 func (o *ThirdApi) FcmUpdateToken(c *gin.Context) {
 	a2r.Call(c, third.ThirdClient.FcmUpdateToken, o.Client)
 }
 
+func (o *ThirdApi) UpdateVendorToken(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.UpdateVendorToken, o.ExtClient)
+}
+
 func (o *ThirdApi) SetAppBadge(c *gin.Context) {
 	a2r.Call(c, third.ThirdClient.SetAppBadge, o.Client)
 }
@@ -172,3 +181,37 @@ func (o *ThirdApi) SearchLogs(c *gin.Context) {
 func (o *ThirdApi) GetPrometheus(c *gin.Context) {
 	c.Redirect(http.StatusFound, o.GrafanaUrl)
 }
+
+// #################### application ####################
+
+func (o *ThirdApi) AddApplicationVersion(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.AddApplicationVersion, o.ExtClient)
+}
+
+func (o *ThirdApi) UpdateApplicationVersion(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.UpdateApplicationVersion, o.ExtClient)
+}
+
+func (o *ThirdApi) DeleteApplicationVersion(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.DeleteApplicationVersion, o.ExtClient)
+}
+
+func (o *ThirdApi) PageApplicationVersion(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.PageApplicationVersion, o.ExtClient)
+}
+
+func (o *ThirdApi) LatestApplicationVersion(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.LatestApplicationVersion, o.ExtClient)
+}
+
+func (o *ThirdApi) RegisterWebPushSubscription(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.RegisterWebPushSubscription, o.ExtClient)
+}
+
+func (o *ThirdApi) UnregisterWebPushSubscription(c *gin.Context) {
+	a2r.Call(c, thirdext.ThirdExtClient.UnregisterWebPushSubscription, o.ExtClient)
+}
+
+func (o *ThirdApi) GetWebPushPublicKey(c *gin.Context) {
+	apiresp.GinSuccess(c, &thirdext.GetWebPushPublicKeyResp{PublicKey: o.WebPushPublicKey})
+}
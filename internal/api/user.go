@@ -17,6 +17,7 @@ package api
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/msggateway"
 	"github.com/openimsdk/protocol/user"
@@ -28,13 +29,14 @@ import (
 )
 
 type UserApi struct {
-	Client user.UserClient
-	discov discovery.Conn
-	config config.RpcService
+	Client    user.UserClient
+	ExtClient userext.UserExtClient
+	discov    discovery.Conn
+	config    config.RpcService
 }
 
-func NewUserApi(client user.UserClient, discov discovery.Conn, config config.RpcService) UserApi {
-	return UserApi{Client: client, discov: discov, config: config}
+func NewUserApi(client user.UserClient, extClient userext.UserExtClient, discov discovery.Conn, config config.RpcService) UserApi {
+	return UserApi{Client: client, ExtClient: extClient, discov: discov, config: config}
 }
 
 func (u *UserApi) UserRegister(c *gin.Context) {
@@ -126,6 +128,14 @@ func (u *UserApi) GetUsersOnlineStatus(c *gin.Context) {
 	apiresp.GinSuccess(c, respResult)
 }
 
+func (u *UserApi) SetUserPresence(c *gin.Context) {
+	a2r.Call(c, userext.UserExtClient.SetUserPresence, u.ExtClient)
+}
+
+func (u *UserApi) GetUsersPresence(c *gin.Context) {
+	a2r.Call(c, userext.UserExtClient.GetUsersPresence, u.ExtClient)
+}
+
 func (u *UserApi) UserRegisterCount(c *gin.Context) {
 	a2r.Call(c, user.UserClient.UserRegisterCount, u.Client)
 }
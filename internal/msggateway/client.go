@@ -70,6 +70,7 @@ type Client struct {
 	SDKType        string `json:"sdkType"`
 	SDKVersion     string `json:"sdkVersion"`
 	Encoder        Encoder
+	compressor     Compressor
 	ctx            *UserConnContext
 	longConnServer LongConnServer
 	closed         atomic.Bool
@@ -79,6 +80,8 @@ type Client struct {
 	hbCancel       context.CancelFunc
 	subLock        *sync.Mutex
 	subUserIDs     map[string]struct{} // client conn subscription list
+	session        *session
+	replaced       bool // taken over by a connection resuming its session
 }
 
 // ResetClient updates the client's state with new connection and context information.
@@ -87,6 +90,7 @@ func (c *Client) ResetClient(ctx *UserConnContext, conn ClientConn, longConnServ
 	c.conn = conn
 	c.PlatformID = ctx.GetPlatformID()
 	c.IsCompress = ctx.GetCompression()
+	c.compressor, _ = longConnServer.GetCompressor(ctx.GetCompressionProtocol())
 	c.IsBackground = ctx.GetBackground()
 	c.UserID = ctx.GetUserID()
 	c.ctx = ctx
@@ -102,12 +106,10 @@ func (c *Client) ResetClient(ctx *UserConnContext, conn ClientConn, longConnServ
 	if c.subUserIDs != nil {
 		clear(c.subUserIDs)
 	}
-	if c.SDKType == GoSDK {
-		c.Encoder = NewGobEncoder()
-	} else {
-		c.Encoder = NewJsonEncoder()
-	}
+	c.Encoder = NewEncoder(ctx.GetEncoding(), c.SDKType)
 	c.subUserIDs = make(map[string]struct{})
+	c.session = nil
+	c.replaced = false
 }
 
 // readMessage continuously reads messages from the connection.
@@ -147,7 +149,7 @@ func (c *Client) readMessage() {
 func (c *Client) handleMessage(message []byte) error {
 	if c.IsCompress {
 		var err error
-		message, err = c.longConnServer.DecompressWithPool(message)
+		message, err = c.compressor.DecompressWithPool(message)
 		if err != nil {
 			return errs.Wrap(err)
 		}
@@ -175,6 +177,11 @@ func (c *Client) handleMessage(message []byte) error {
 
 	log.ZDebug(ctx, "gateway req message", "req", binaryReq.String())
 
+	if err := c.longConnServer.LimitReq(ctx, c, binaryReq); err != nil {
+		// the client keeps the connection, it only has to slow down
+		return c.replyMessage(ctx, binaryReq, err, nil)
+	}
+
 	var (
 		resp       []byte
 		messageErr error
@@ -201,6 +208,8 @@ func (c *Client) handleMessage(message []byte) error {
 		resp, messageErr = c.setAppBackgroundStatus(ctx, binaryReq)
 	case WsSubUserOnlineStatus:
 		resp, messageErr = c.longConnServer.SubUserOnlineStatus(ctx, c, binaryReq)
+	case WsSendEphemeralEvent:
+		resp, messageErr = c.longConnServer.SendEphemeralEvent(ctx, c, binaryReq)
 	default:
 		return fmt.Errorf(
 			"ReqIdentifier failed,sendID:%s,msgIncr:%s,reqIdentifier:%d",
@@ -255,6 +264,9 @@ func (c *Client) replyMessage(ctx context.Context, binaryReq *Req, err error, re
 	log.ZDebug(ctx, "wireBinaryMsg end", "time cost", time.Since(t))
 
 	if binaryReq.ReqIdentifier == WsLogoutMsg {
+		if c.session != nil {
+			c.session.close()
+		}
 		return errs.New("user logout", "operationID", binaryReq.OperationID).Wrap()
 	}
 	return nil
@@ -279,7 +291,8 @@ func (c *Client) PushMessage(ctx context.Context, msgData *sdkws.MsgData) error
 		OperationID:   mcontext.GetOperationID(ctx),
 		Data:          data,
 	}
-	return c.writeBinaryMsg(resp)
+	// a missed typing is harmless, it must not get a slow client evicted
+	return c.pushFrame(resp, msgData.ContentType == constant.Typing)
 }
 
 func (c *Client) KickOnlineMessage() error {
@@ -297,10 +310,23 @@ func (c *Client) PushUserOnlineStatus(data []byte) error {
 		ReqIdentifier: WsSubUserOnlineStatus,
 		Data:          data,
 	}
-	return c.writeBinaryMsg(resp)
+	return c.pushFrame(resp, true)
+}
+
+// pushFrame writes a frame the client did not ask for, through the session if any so it can be replayed on resume.
+// A droppable frame is dropped rather than queued when the client is falling behind.
+func (c *Client) pushFrame(resp Resp, droppable bool) error {
+	if c.session != nil {
+		return c.session.push(resp, droppable)
+	}
+	return c.writeFrame(resp, droppable)
 }
 
 func (c *Client) writeBinaryMsg(resp Resp) error {
+	return c.writeFrame(resp, false)
+}
+
+func (c *Client) writeFrame(resp Resp, droppable bool) error {
 	if c.closed.Load() {
 		return nil
 	}
@@ -313,13 +339,18 @@ func (c *Client) writeBinaryMsg(resp Resp) error {
 	c.w.Lock()
 	defer c.w.Unlock()
 
+	write := c.conn.WriteMessage
+	if droppable {
+		write = c.conn.WriteDroppableMessage
+	}
+
 	if c.IsCompress {
-		resultBuf, compressErr := c.longConnServer.CompressWithPool(encodedBuf)
+		resultBuf, compressErr := c.compressor.CompressWithPool(encodedBuf)
 		if compressErr != nil {
 			return compressErr
 		}
-		return c.conn.WriteMessage(resultBuf)
+		return write(resultBuf)
 	}
 
-	return c.conn.WriteMessage(encodedBuf)
+	return write(encodedBuf)
 }
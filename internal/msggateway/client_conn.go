@@ -10,14 +10,22 @@ import (
 
 	"github.com/gorilla/websocket"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
 	"github.com/openimsdk/tools/log"
 )
 
 var ErrWriteFull = fmt.Errorf("websocket write buffer full,close connection")
 
+// CloseSlowConsumer is the close code sent to a client evicted for not reading its frames fast enough.
+const CloseSlowConsumer = 4008
+
+const defaultWriteQueueSize = 256
+
 type ClientConn interface {
 	ReadMessage() ([]byte, error)
 	WriteMessage(message []byte) error
+	// WriteDroppableMessage writes a frame the client can miss, it is dropped when the client is falling behind.
+	WriteDroppableMessage(message []byte) error
 	Close() error
 }
 
@@ -26,12 +34,21 @@ type websocketMessage struct {
 	Data        []byte
 }
 
-func NewWebSocketClientConn(conn *websocket.Conn, readLimit int64, readTimeout time.Duration, pingInterval time.Duration) ClientConn {
+// NewWebSocketClientConn queues at most queueSize frames for the client, the droppable ones are dropped
+// from dropThreshold on, and the connection is closed with CloseSlowConsumer when the queue is full.
+func NewWebSocketClientConn(conn *websocket.Conn, readLimit int64, readTimeout time.Duration, pingInterval time.Duration, queueSize int, dropThreshold int) ClientConn {
+	if queueSize <= 0 {
+		queueSize = defaultWriteQueueSize
+	}
+	if dropThreshold <= 0 || dropThreshold > queueSize {
+		dropThreshold = queueSize / 2
+	}
 	c := &websocketClientConn{
-		readTimeout: readTimeout,
-		conn:        conn,
-		writer:      make(chan *websocketMessage, 256),
-		done:        make(chan struct{}),
+		readTimeout:   readTimeout,
+		conn:          conn,
+		writer:        make(chan *websocketMessage, queueSize),
+		dropThreshold: dropThreshold,
+		done:          make(chan struct{}),
 	}
 	if readLimit > 0 {
 		c.conn.SetReadLimit(readLimit)
@@ -47,11 +64,12 @@ func NewWebSocketClientConn(conn *websocket.Conn, readLimit int64, readTimeout t
 }
 
 type websocketClientConn struct {
-	readTimeout time.Duration
-	conn        *websocket.Conn
-	writer      chan *websocketMessage
-	done        chan struct{}
-	err         atomic.Pointer[error]
+	readTimeout   time.Duration
+	conn          *websocket.Conn
+	writer        chan *websocketMessage
+	dropThreshold int
+	done          chan struct{}
+	err           atomic.Pointer[error]
 }
 
 func (c *websocketClientConn) ReadMessage() ([]byte, error) {
@@ -66,6 +84,16 @@ func (c *websocketClientConn) WriteMessage(message []byte) error {
 	return c.writeMessage(websocket.BinaryMessage, message)
 }
 
+func (c *websocketClientConn) WriteDroppableMessage(message []byte) error {
+	if len(c.writer) >= c.dropThreshold {
+		prommetrics.WsWriteDroppedCounter.Inc()
+		log.ZDebug(context.Background(), "websocket write queue over threshold, drop frame", "remoteAddr", c.conn.RemoteAddr(),
+			"chan length", len(c.writer))
+		return nil
+	}
+	return c.writeMessage(websocket.BinaryMessage, message)
+}
+
 func (c *websocketClientConn) Close() error {
 	return c.closeBy(fmt.Errorf("websocket connection closed"))
 }
@@ -86,8 +114,10 @@ func (c *websocketClientConn) writeMessage(messageType int, data []byte) error {
 	}
 	select {
 	case c.writer <- &websocketMessage{MessageType: messageType, Data: data}:
+		prommetrics.WsWriteQueueDepthHistogram.Observe(float64(len(c.writer)))
 		return nil
 	default:
+		prommetrics.WsSlowConsumerEvictedCounter.Inc()
 		return c.closeBy(ErrWriteFull)
 	}
 }
@@ -96,20 +126,18 @@ func (c *websocketClientConn) loopSend() {
 	defer func() {
 		_ = c.conn.Close()
 	}()
-	var err error
 	for {
 		select {
 		case <-c.done:
+			if errors.Is(*c.err.Load(), ErrWriteFull) {
+				// the queued frames would not be read in time either, tell the client why it is evicted
+				_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(CloseSlowConsumer, "slow consumer"), time.Now().Add(writeWait))
+				return
+			}
 			for {
 				select {
 				case msg := <-c.writer:
-					switch msg.MessageType {
-					case websocket.TextMessage, websocket.BinaryMessage:
-						err = c.conn.WriteMessage(msg.MessageType, msg.Data)
-					default:
-						err = c.conn.WriteControl(msg.MessageType, msg.Data, time.Time{})
-					}
-					if err != nil {
+					if err := c.send(msg); err != nil {
 						_ = c.closeBy(err)
 						return
 					}
@@ -118,13 +146,7 @@ func (c *websocketClientConn) loopSend() {
 				}
 			}
 		case msg := <-c.writer:
-			switch msg.MessageType {
-			case websocket.TextMessage, websocket.BinaryMessage:
-				err = c.conn.WriteMessage(msg.MessageType, msg.Data)
-			default:
-				err = c.conn.WriteControl(msg.MessageType, msg.Data, time.Time{})
-			}
-			if err != nil {
+			if err := c.send(msg); err != nil {
 				_ = c.closeBy(err)
 				return
 			}
@@ -132,6 +154,20 @@ func (c *websocketClientConn) loopSend() {
 	}
 }
 
+// send writes a frame, a client not reading for writeWait fails the write instead of blocking it forever.
+func (c *websocketClientConn) send(msg *websocketMessage) error {
+	deadline := time.Now().Add(writeWait)
+	switch msg.MessageType {
+	case websocket.TextMessage, websocket.BinaryMessage:
+		if err := c.conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+		return c.conn.WriteMessage(msg.MessageType, msg.Data)
+	default:
+		return c.conn.WriteControl(msg.MessageType, msg.Data, deadline)
+	}
+}
+
 func (c *websocketClientConn) setReadDeadline() error {
 	deadline := time.Now().Add(c.readTimeout)
 	return c.conn.SetReadDeadline(deadline)
@@ -16,16 +16,25 @@ package msggateway
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
 	"io"
+	"os"
 	"sync"
 
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/tools/errs"
 )
 
 var (
-	gzipWriterPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
-	gzipReaderPool = sync.Pool{New: func() any { return new(gzip.Reader) }}
+	gzipWriterPool    = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+	gzipReaderPool    = sync.Pool{New: func() any { return new(gzip.Reader) }}
+	deflateWriterPool = sync.Pool{New: func() any { w, _ := flate.NewWriter(nil, flate.DefaultCompression); return w }}
+	deflateReaderPool = sync.Pool{New: func() any { return flate.NewReader(nil) }}
+	brotliWriterPool  = sync.Pool{New: func() any { return brotli.NewWriterLevel(nil, brotli.DefaultCompression) }}
+	brotliReaderPool  = sync.Pool{New: func() any { return brotli.NewReader(nil) }}
 )
 
 type Compressor interface {
@@ -111,3 +120,262 @@ func (g *GzipCompressor) DecompressWithPool(compressedData []byte) ([]byte, erro
 	}
 	return decompressedData, nil
 }
+
+type DeflateCompressor struct {
+	compressProtocol string
+}
+
+// NewDeflateCompressor returns a compressor producing raw deflate streams (RFC 1951) without the gzip header and trailer.
+func NewDeflateCompressor() *DeflateCompressor {
+	return &DeflateCompressor{compressProtocol: DeflateCompressionProtocol}
+}
+
+func (d *DeflateCompressor) Compress(rawData []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	fw, err := flate.NewWriter(&buffer, flate.DefaultCompression)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.Compress: creating flate writer failed")
+	}
+	if _, err := fw.Write(rawData); err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.Compress: writing to flate writer failed")
+	}
+	if err := fw.Close(); err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.Compress: closing flate writer failed")
+	}
+	return buffer.Bytes(), nil
+}
+
+func (d *DeflateCompressor) CompressWithPool(rawData []byte) ([]byte, error) {
+	fw := deflateWriterPool.Get().(*flate.Writer)
+	defer deflateWriterPool.Put(fw)
+
+	var buffer bytes.Buffer
+	fw.Reset(&buffer)
+	if _, err := fw.Write(rawData); err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.CompressWithPool: error writing data")
+	}
+	if err := fw.Close(); err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.CompressWithPool: error closing flate writer")
+	}
+	return buffer.Bytes(), nil
+}
+
+func (d *DeflateCompressor) DeCompress(compressedData []byte) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(compressedData))
+	defer reader.Close()
+	decompressedData, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.DeCompress: reading from flate reader failed")
+	}
+	return decompressedData, nil
+}
+
+func (d *DeflateCompressor) DecompressWithPool(compressedData []byte) ([]byte, error) {
+	reader := deflateReaderPool.Get().(io.ReadCloser)
+	defer deflateReaderPool.Put(reader)
+
+	if err := reader.(flate.Resetter).Reset(bytes.NewReader(compressedData), nil); err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.DecompressWithPool: resetting flate reader failed")
+	}
+	decompressedData, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "DeflateCompressor.DecompressWithPool: reading from pooled flate reader failed")
+	}
+	return decompressedData, nil
+}
+
+type BrotliCompressor struct {
+	compressProtocol string
+}
+
+func NewBrotliCompressor() *BrotliCompressor {
+	return &BrotliCompressor{compressProtocol: BrotliCompressionProtocol}
+}
+
+func (b *BrotliCompressor) Compress(rawData []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	bw := brotli.NewWriterLevel(&buffer, brotli.DefaultCompression)
+	if _, err := bw.Write(rawData); err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.Compress: writing to brotli writer failed")
+	}
+	if err := bw.Close(); err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.Compress: closing brotli writer failed")
+	}
+	return buffer.Bytes(), nil
+}
+
+func (b *BrotliCompressor) CompressWithPool(rawData []byte) ([]byte, error) {
+	bw := brotliWriterPool.Get().(*brotli.Writer)
+	defer brotliWriterPool.Put(bw)
+
+	var buffer bytes.Buffer
+	bw.Reset(&buffer)
+	if _, err := bw.Write(rawData); err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.CompressWithPool: error writing data")
+	}
+	if err := bw.Close(); err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.CompressWithPool: error closing brotli writer")
+	}
+	return buffer.Bytes(), nil
+}
+
+func (b *BrotliCompressor) DeCompress(compressedData []byte) ([]byte, error) {
+	decompressedData, err := io.ReadAll(brotli.NewReader(bytes.NewReader(compressedData)))
+	if err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.DeCompress: reading from brotli reader failed")
+	}
+	return decompressedData, nil
+}
+
+func (b *BrotliCompressor) DecompressWithPool(compressedData []byte) ([]byte, error) {
+	reader := brotliReaderPool.Get().(*brotli.Reader)
+	defer brotliReaderPool.Put(reader)
+
+	if err := reader.Reset(bytes.NewReader(compressedData)); err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.DecompressWithPool: resetting brotli reader failed")
+	}
+	decompressedData, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "BrotliCompressor.DecompressWithPool: reading from pooled brotli reader failed")
+	}
+	return decompressedData, nil
+}
+
+// ZstdCompressor compresses frames with zstd, optionally primed with a dictionary shared with the clients.
+// Small IM frames carry little history of their own, so a dictionary trained on typical payloads
+// (see tools/zstd-dict) is what makes zstd pay off for them.
+// The encoder and decoder are safe for concurrent use, so the pooled variants share them as well.
+type ZstdCompressor struct {
+	compressProtocol string
+	encoder          *zstd.Encoder
+	decoder          *zstd.Decoder
+}
+
+func NewZstdCompressor(dict []byte) (*ZstdCompressor, error) {
+	encoderOpts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+	decoderOpts := []zstd.DOption{zstd.WithDecoderConcurrency(0)}
+	if len(dict) > 0 {
+		encoderOpts = append(encoderOpts, zstd.WithEncoderDict(dict))
+		decoderOpts = append(decoderOpts, zstd.WithDecoderDicts(dict))
+	}
+	encoder, err := zstd.NewWriter(nil, encoderOpts...)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "NewZstdCompressor: creating zstd encoder failed")
+	}
+	decoder, err := zstd.NewReader(nil, decoderOpts...)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "NewZstdCompressor: creating zstd decoder failed")
+	}
+	return &ZstdCompressor{compressProtocol: ZstdCompressionProtocol, encoder: encoder, decoder: decoder}, nil
+}
+
+func (z *ZstdCompressor) Compress(rawData []byte) ([]byte, error) {
+	return z.encoder.EncodeAll(rawData, nil), nil
+}
+
+func (z *ZstdCompressor) CompressWithPool(rawData []byte) ([]byte, error) {
+	return z.encoder.EncodeAll(rawData, nil), nil
+}
+
+func (z *ZstdCompressor) DeCompress(compressedData []byte) ([]byte, error) {
+	decompressedData, err := z.decoder.DecodeAll(compressedData, nil)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "ZstdCompressor.DeCompress: decoding failed")
+	}
+	return decompressedData, nil
+}
+
+func (z *ZstdCompressor) DecompressWithPool(compressedData []byte) ([]byte, error) {
+	return z.DeCompress(compressedData)
+}
+
+const (
+	frameUncompressed byte = 0
+	frameCompressed   byte = 1
+)
+
+// thresholdCompressor prefixes every frame with a one-byte flag telling the peer whether the rest of
+// the frame is compressed, which lets frames smaller than minSize skip compression entirely.
+// Gzip connections keep the legacy framing without the flag, so only the newer protocols use it.
+type thresholdCompressor struct {
+	Compressor
+	minSize int
+}
+
+func (t *thresholdCompressor) Compress(rawData []byte) ([]byte, error) {
+	return t.compress(rawData, t.Compressor.Compress)
+}
+
+func (t *thresholdCompressor) CompressWithPool(rawData []byte) ([]byte, error) {
+	return t.compress(rawData, t.Compressor.CompressWithPool)
+}
+
+func (t *thresholdCompressor) DeCompress(compressedData []byte) ([]byte, error) {
+	return t.decompress(compressedData, t.Compressor.DeCompress)
+}
+
+func (t *thresholdCompressor) DecompressWithPool(compressedData []byte) ([]byte, error) {
+	return t.decompress(compressedData, t.Compressor.DecompressWithPool)
+}
+
+func (t *thresholdCompressor) compress(rawData []byte, fn func([]byte) ([]byte, error)) ([]byte, error) {
+	if len(rawData) < t.minSize {
+		return append([]byte{frameUncompressed}, rawData...), nil
+	}
+	compressedData, err := fn(rawData)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{frameCompressed}, compressedData...), nil
+}
+
+func (t *thresholdCompressor) decompress(data []byte, fn func([]byte) ([]byte, error)) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errs.New("compressed frame is empty")
+	}
+	switch data[0] {
+	case frameUncompressed:
+		return data[1:], nil
+	case frameCompressed:
+		return fn(data[1:])
+	default:
+		return nil, errs.New("unknown compressed frame flag", "flag", data[0])
+	}
+}
+
+// NewCompressors builds the compressors clients may negotiate in the handshake, keyed by protocol name.
+// Gzip is always available; the other protocols are enabled through the longConnSvr.compression config.
+func NewCompressors(conf *config.WebsocketCompression) (map[string]Compressor, error) {
+	compressors := map[string]Compressor{
+		GzipCompressionProtocol: NewGzipCompressor(),
+	}
+	for _, protocol := range conf.Protocols {
+		var compressor Compressor
+		switch protocol {
+		case GzipCompressionProtocol:
+			continue
+		case DeflateCompressionProtocol:
+			compressor = NewDeflateCompressor()
+		case BrotliCompressionProtocol:
+			compressor = NewBrotliCompressor()
+		case ZstdCompressionProtocol:
+			var dict []byte
+			if conf.ZstdDictionary != "" {
+				var err error
+				dict, err = os.ReadFile(conf.ZstdDictionary)
+				if err != nil {
+					return nil, errs.WrapMsg(err, "read zstd dictionary failed", "path", conf.ZstdDictionary)
+				}
+			}
+			zstdCompressor, err := NewZstdCompressor(dict)
+			if err != nil {
+				return nil, err
+			}
+			compressor = zstdCompressor
+		default:
+			return nil, errs.New("unsupported compression protocol", "protocol", protocol)
+		}
+		compressors[protocol] = &thresholdCompressor{Compressor: compressor, minSize: conf.MinSize}
+	}
+	return compressors, nil
+}
@@ -15,6 +15,7 @@
 package msggateway
 
 import (
+	"bytes"
 	"crypto/rand"
 	"github.com/stretchr/testify/assert"
 	"sync"
@@ -137,3 +138,57 @@ func TestName(t *testing.T) {
 	t.Log(unsafe.Sizeof(Client{}))
 
 }
+
+func TestCompressorsRoundTrip(t *testing.T) {
+	zstdCompressor, err := NewZstdCompressor(nil)
+	assert.NoError(t, err)
+	compressors := map[string]Compressor{
+		ZstdCompressionProtocol:    zstdCompressor,
+		DeflateCompressionProtocol: NewDeflateCompressor(),
+		BrotliCompressionProtocol:  NewBrotliCompressor(),
+	}
+	for name, compressor := range compressors {
+		t.Run(name, func(t *testing.T) {
+			src := mockRandom()
+
+			dest, err := compressor.CompressWithPool(src)
+			assert.NoError(t, err)
+			res, err := compressor.DecompressWithPool(dest)
+			assert.NoError(t, err)
+			assert.EqualValues(t, src, res)
+
+			dest, err = compressor.Compress(src)
+			assert.NoError(t, err)
+			res, err = compressor.DeCompress(dest)
+			assert.NoError(t, err)
+			assert.EqualValues(t, src, res)
+		})
+	}
+}
+
+func TestThresholdCompressor(t *testing.T) {
+	compressor := &thresholdCompressor{Compressor: NewDeflateCompressor(), minSize: 64}
+
+	small := mockRandom()
+	dest, err := compressor.CompressWithPool(small)
+	assert.NoError(t, err)
+	assert.Equal(t, frameUncompressed, dest[0])
+	assert.EqualValues(t, small, dest[1:])
+
+	large := bytes.Repeat([]byte(`{"sendID":"u1","recvID":"u2","contentType":101}`), 8)
+	dest, err = compressor.CompressWithPool(large)
+	assert.NoError(t, err)
+	assert.Equal(t, frameCompressed, dest[0])
+	assert.Less(t, len(dest), len(large))
+
+	for _, src := range [][]byte{small, large} {
+		dest, err := compressor.CompressWithPool(src)
+		assert.NoError(t, err)
+		res, err := compressor.DecompressWithPool(dest)
+		assert.NoError(t, err)
+		assert.EqualValues(t, src, res)
+	}
+
+	_, err = compressor.DecompressWithPool([]byte{7, 1, 2})
+	assert.Error(t, err)
+}
@@ -29,6 +29,9 @@ const (
 	SendResponse            = "isMsgResp"
 	SDKType                 = "sdkType"
 	SDKVersion              = "sdkVersion"
+	Encoding                = "encoding"
+	ResumeSessionID         = "resumeSessionID"
+	ResumeFrameSeq          = "resumeFrameSeq"
 )
 
 const (
@@ -36,6 +39,19 @@ const (
 	JsSDK = "js"
 )
 
+const (
+	ZstdCompressionProtocol    = "zstd"
+	DeflateCompressionProtocol = "deflate"
+	BrotliCompressionProtocol  = "br"
+)
+
+const (
+	GobEncoding      = "gob"
+	JsonEncoding     = "json"
+	ProtobufEncoding = "protobuf"
+	MsgpackEncoding  = "msgpack"
+)
+
 const (
 	WebSocket = iota + 1
 )
@@ -49,11 +65,16 @@ const (
 	WSPullMsg             = 1005
 	WSGetConvMaxReadSeq   = 1006
 	WsPullConvLastMessage = 1007
+	WsSendEphemeralEvent  = 1008
 	WSPushMsg             = 2001
 	WSKickOnlineMsg       = 2002
 	WsLogoutMsg           = 2003
 	WsSetBackgroundStatus = 2004
 	WsSubUserOnlineStatus = 2005
+	WsSessionInfo         = 2006
+	WsReconnect           = 2007
+	WsEphemeralEvent      = 2008
+	WsUserPresence        = 2009
 	WSDataError           = 3001
 )
 
@@ -39,6 +39,10 @@ type UserConnContextInfo struct {
 	SendResponse bool   `json:"sendResponse"`
 	Background   bool   `json:"background"`
 	SDKVersion   string `json:"sdkVersion"`
+	Encoding     string `json:"encoding"`
+	// ResumeSessionID and ResumeFrameSeq ask to resume a session, replaying the frames pushed after ResumeFrameSeq.
+	ResumeSessionID string `json:"resumeSessionID"`
+	ResumeFrameSeq  uint64 `json:"resumeFrameSeq"`
 }
 
 type UserConnContext struct {
@@ -115,12 +119,14 @@ func (c *UserConnContext) ParseEssentialArgs() error {
 
 func (c *UserConnContext) parseByQuery(query url.Values, header http.Header) error {
 	info := UserConnContextInfo{
-		Token:       query.Get(Token),
-		UserID:      query.Get(WsUserID),
-		OperationID: query.Get(OperationID),
-		Compression: query.Get(Compression),
-		SDKType:     query.Get(SDKType),
-		SDKVersion:  query.Get(SDKVersion),
+		Token:           query.Get(Token),
+		UserID:          query.Get(WsUserID),
+		OperationID:     query.Get(OperationID),
+		Compression:     query.Get(Compression),
+		SDKType:         query.Get(SDKType),
+		SDKVersion:      query.Get(SDKVersion),
+		Encoding:        query.Get(Encoding),
+		ResumeSessionID: query.Get(ResumeSessionID),
 	}
 	platformID, err := strconv.Atoi(query.Get(PlatformID))
 	if err != nil {
@@ -134,6 +140,13 @@ func (c *UserConnContext) parseByQuery(query url.Values, header http.Header) err
 		}
 		info.SendResponse = ok
 	}
+	if val := query.Get(ResumeFrameSeq); val != "" {
+		seq, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return servererrs.ErrConnArgsErr.WrapMsg("resumeFrameSeq is not uint")
+		}
+		info.ResumeFrameSeq = seq
+	}
 	if info.Compression == "" {
 		info.Compression = header.Get(Compression)
 	}
@@ -177,6 +190,11 @@ func (c *UserConnContext) checkInfo(info *UserConnContextInfo) error {
 	default:
 		return servererrs.ErrConnArgsErr.WrapMsg("sdkType is invalid")
 	}
+	switch info.Encoding {
+	case "", GobEncoding, JsonEncoding, ProtobufEncoding, MsgpackEncoding:
+	default:
+		return servererrs.ErrConnArgsErr.WrapMsg("encoding is invalid")
+	}
 	c.info = info
 	return nil
 }
@@ -233,7 +251,15 @@ func (c *UserConnContext) GetToken() string {
 }
 
 func (c *UserConnContext) GetCompression() bool {
-	return c != nil && c.info != nil && c.info.Compression == GzipCompressionProtocol
+	return c.GetCompressionProtocol() != ""
+}
+
+// GetCompressionProtocol returns the compression protocol requested in the handshake, or an empty string for none.
+func (c *UserConnContext) GetCompressionProtocol() string {
+	if c == nil || c.info == nil {
+		return ""
+	}
+	return c.info.Compression
 }
 
 func (c *UserConnContext) GetSDKType() string {
@@ -257,6 +283,13 @@ func (c *UserConnContext) GetSDKVersion() string {
 	return c.info.SDKVersion
 }
 
+func (c *UserConnContext) GetEncoding() string {
+	if c == nil || c.info == nil {
+		return ""
+	}
+	return c.info.Encoding
+}
+
 func (c *UserConnContext) ShouldSendResp() bool {
 	return c != nil && c.info != nil && c.info.SendResponse
 }
@@ -271,3 +304,17 @@ func (c *UserConnContext) SetToken(token string) {
 func (c *UserConnContext) GetBackground() bool {
 	return c != nil && c.info != nil && c.info.Background
 }
+
+func (c *UserConnContext) GetResumeSessionID() string {
+	if c == nil || c.info == nil {
+		return ""
+	}
+	return c.info.ResumeSessionID
+}
+
+func (c *UserConnContext) GetResumeFrameSeq() uint64 {
+	if c == nil || c.info == nil {
+		return 0
+	}
+	return c.info.ResumeFrameSeq
+}
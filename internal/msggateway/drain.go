@@ -0,0 +1,141 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/gatewayext"
+	"github.com/openimsdk/tools/log"
+)
+
+// ReconnectInfo is the data of the WsReconnect frame sent to every connection when the gateway drains.
+// The client closes the connection after Delay milliseconds and connects again, the new connection is then
+// served by another gateway. The delays are spread so the clients do not all reconnect at once.
+type ReconnectInfo struct {
+	Delay int64 `json:"delay"`
+}
+
+const (
+	defaultDrainTimeout = time.Second * 30
+	// drainCloseTimeout is how long the connections still open at the end of the drain get to unregister.
+	drainCloseTimeout = time.Second * 5
+)
+
+// Drain makes Run stop accepting connections, ask the clients to reconnect elsewhere and return once they left.
+func (ws *WsServer) Drain() {
+	ws.drainOnce.Do(func() {
+		close(ws.drainCh)
+	})
+}
+
+// NodeInfo describes this gateway, its NodeID is the host name and the websocket port.
+func (ws *WsServer) NodeInfo() *gatewayext.GatewayNode {
+	return &gatewayext.GatewayNode{
+		NodeID:        ws.nodeID,
+		OnlineUserNum: ws.onlineUserNum.Load(),
+		OnlineConnNum: ws.onlineUserConnNum.Load(),
+		Draining:      ws.draining.Load(),
+	}
+}
+
+// drain takes the gateway out of the discovery and waits for the clients to reconnect to the other gateways.
+// The online state changes are reported straight away meanwhile, so the offline of a connection that left
+// reaches the user service before the online of the connection that replaced it on another gateway.
+func (ws *WsServer) drain(ctx context.Context) {
+	ws.draining.Store(true)
+	if registry, ok := ws.disCov.(interface{ UnRegister() error }); ok {
+		if err := registry.UnRegister(); err != nil {
+			log.ZWarn(ctx, "msg gateway unregister failed", err)
+		}
+	}
+	timeout := ws.drainTimeout
+	if timeout <= 0 {
+		timeout = defaultDrainTimeout
+	}
+	clients := ws.clients.GetAllClients()
+	log.ZInfo(ctx, "msg gateway draining", "conns", len(clients), "timeout", timeout)
+	for _, client := range clients {
+		if ws.expireSession(client) {
+			// the client is already gone, its session cannot be resumed here
+			ws.UnRegister(client)
+			continue
+		}
+		if err := client.writeReconnect(ws.reconnectDelay()); err != nil {
+			log.ZWarn(client.ctx, "write reconnect failed", err)
+		}
+	}
+	if ws.waitConns(timeout) {
+		log.ZInfo(ctx, "msg gateway drained")
+		return
+	}
+	clients = ws.clients.GetAllClients()
+	log.ZWarn(ctx, "msg gateway drain timeout, closing the remaining conns", nil, "conns", len(clients))
+	for _, client := range clients {
+		client.close()
+	}
+	if !ws.waitConns(drainCloseTimeout) {
+		log.ZWarn(ctx, "msg gateway conns not unregistered", nil, "conns", ws.onlineUserConnNum.Load())
+	}
+}
+
+// waitConns waits for all the connections to unregister, false if the timeout passed first.
+func (ws *WsServer) waitConns(timeout time.Duration) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Millisecond * 200)
+	defer ticker.Stop()
+	for ws.onlineUserConnNum.Load() > 0 {
+		select {
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+		}
+	}
+	return true
+}
+
+func (ws *WsServer) reconnectDelay() time.Duration {
+	if ws.drainReconnectDelay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ws.drainReconnectDelay)))
+}
+
+// expireSession ends the wait of a detached client for its session to be resumed, true if the client was detached.
+func (ws *WsServer) expireSession(client *Client) bool {
+	s := client.session
+	if s == nil {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.owner != client || !s.detached || s.timer == nil {
+		return false
+	}
+	// the timer that fired already unregisters the client
+	return s.timer.Stop()
+}
+
+func (c *Client) writeReconnect(delay time.Duration) error {
+	data, err := json.Marshal(&ReconnectInfo{Delay: delay.Milliseconds()})
+	if err != nil {
+		return err
+	}
+	return c.writeBinaryMsg(Resp{ReqIdentifier: WsReconnect, Data: data})
+}
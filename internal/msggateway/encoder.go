@@ -20,6 +20,8 @@ import (
 	"encoding/json"
 
 	"github.com/openimsdk/tools/errs"
+	"github.com/ugorji/go/codec"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 type Encoder interface {
@@ -27,6 +29,26 @@ type Encoder interface {
 	Decode(encodeData []byte, decodeData any) error
 }
 
+// NewEncoder returns the frame encoder negotiated for a connection. When the client does not
+// request an encoding explicitly, the choice falls back to the SDK type: gob for the Go SDK and
+// JSON for everything else.
+func NewEncoder(encoding string, sdkType string) Encoder {
+	switch encoding {
+	case GobEncoding:
+		return NewGobEncoder()
+	case JsonEncoding:
+		return NewJsonEncoder()
+	case ProtobufEncoding:
+		return NewProtobufEncoder()
+	case MsgpackEncoding:
+		return NewMsgpackEncoder()
+	}
+	if sdkType == GoSDK {
+		return NewGobEncoder()
+	}
+	return NewJsonEncoder()
+}
+
 type GobEncoder struct{}
 
 func NewGobEncoder() Encoder {
@@ -72,3 +94,230 @@ func (g JsonEncoder) Decode(encodeData []byte, decodeData any) error {
 	}
 	return nil
 }
+
+// ProtobufEncoder frames Req and Resp as protobuf messages, so non-Go SDKs can generate their
+// envelope types from the schema below instead of reimplementing gob or parsing JSON.
+//
+//	message Req {
+//	  int32 reqIdentifier = 1;
+//	  string token = 2;
+//	  string sendID = 3;
+//	  string operationID = 4;
+//	  string msgIncr = 5;
+//	  bytes data = 6;
+//	}
+//
+//	message Resp {
+//	  int32 reqIdentifier = 1;
+//	  string msgIncr = 2;
+//	  string operationID = 3;
+//	  int32 errCode = 4;
+//	  string errMsg = 5;
+//	  bytes data = 6;
+//	}
+type ProtobufEncoder struct{}
+
+func NewProtobufEncoder() Encoder {
+	return ProtobufEncoder{}
+}
+
+func (p ProtobufEncoder) Encode(data any) ([]byte, error) {
+	switch v := data.(type) {
+	case Resp:
+		return p.encodeResp(&v), nil
+	case *Resp:
+		return p.encodeResp(v), nil
+	case Req:
+		return p.encodeReq(&v), nil
+	case *Req:
+		return p.encodeReq(v), nil
+	default:
+		return nil, errs.New("ProtobufEncoder.Encode failed", "action", "encode", "reason", "unsupported type")
+	}
+}
+
+func (p ProtobufEncoder) Decode(encodeData []byte, decodeData any) error {
+	var err error
+	switch v := decodeData.(type) {
+	case *Req:
+		err = p.decode(encodeData, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+			switch num {
+			case 1:
+				return consumeInt32(typ, b, &v.ReqIdentifier)
+			case 2:
+				return consumeString(typ, b, &v.Token)
+			case 3:
+				return consumeString(typ, b, &v.SendID)
+			case 4:
+				return consumeString(typ, b, &v.OperationID)
+			case 5:
+				return consumeString(typ, b, &v.MsgIncr)
+			case 6:
+				return consumeBytes(typ, b, &v.Data)
+			}
+			return -1, nil
+		})
+	case *Resp:
+		err = p.decode(encodeData, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+			switch num {
+			case 1:
+				return consumeInt32(typ, b, &v.ReqIdentifier)
+			case 2:
+				return consumeString(typ, b, &v.MsgIncr)
+			case 3:
+				return consumeString(typ, b, &v.OperationID)
+			case 4:
+				var code int32
+				n, err := consumeInt32(typ, b, &code)
+				v.ErrCode = int(code)
+				return n, err
+			case 5:
+				return consumeString(typ, b, &v.ErrMsg)
+			case 6:
+				return consumeBytes(typ, b, &v.Data)
+			}
+			return -1, nil
+		})
+	default:
+		return errs.New("ProtobufEncoder.Decode failed", "action", "decode", "reason", "unsupported type")
+	}
+	if err != nil {
+		return errs.WrapMsg(err, "ProtobufEncoder.Decode failed", "action", "decode")
+	}
+	return nil
+}
+
+func (p ProtobufEncoder) encodeReq(r *Req) []byte {
+	var b []byte
+	b = appendInt32(b, 1, r.ReqIdentifier)
+	b = appendString(b, 2, r.Token)
+	b = appendString(b, 3, r.SendID)
+	b = appendString(b, 4, r.OperationID)
+	b = appendString(b, 5, r.MsgIncr)
+	b = appendBytes(b, 6, r.Data)
+	return b
+}
+
+func (p ProtobufEncoder) encodeResp(r *Resp) []byte {
+	b := make([]byte, 0, len(r.Data)+len(r.OperationID)+len(r.MsgIncr)+len(r.ErrMsg)+32)
+	b = appendInt32(b, 1, r.ReqIdentifier)
+	b = appendString(b, 2, r.MsgIncr)
+	b = appendString(b, 3, r.OperationID)
+	b = appendInt32(b, 4, int32(r.ErrCode))
+	b = appendString(b, 5, r.ErrMsg)
+	b = appendBytes(b, 6, r.Data)
+	return b
+}
+
+// decode walks the fields of a protobuf message. field returns the number of bytes it consumed,
+// or a negative value for unknown fields, which are skipped as required by proto3.
+func (p ProtobufEncoder) decode(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func appendInt32(b []byte, num protowire.Number, v int32) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(int64(v)))
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func consumeInt32(typ protowire.Type, b []byte, v *int32) (int, error) {
+	if typ != protowire.VarintType {
+		return 0, errs.New("invalid wire type for int32 field", "type", typ)
+	}
+	x, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*v = int32(x)
+	return n, nil
+}
+
+func consumeString(typ protowire.Type, b []byte, v *string) (int, error) {
+	if typ != protowire.BytesType {
+		return 0, errs.New("invalid wire type for string field", "type", typ)
+	}
+	x, n := protowire.ConsumeString(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*v = x
+	return n, nil
+}
+
+func consumeBytes(typ protowire.Type, b []byte, v *[]byte) (int, error) {
+	if typ != protowire.BytesType {
+		return 0, errs.New("invalid wire type for bytes field", "type", typ)
+	}
+	x, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*v = append((*v)[:0], x...)
+	return n, nil
+}
+
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := new(codec.MsgpackHandle)
+	// Encode []byte with the msgpack bin family rather than raw strings.
+	h.WriteExt = true
+	return h
+}()
+
+// MsgpackEncoder frames Req and Resp as MessagePack maps keyed by the same names as the JSON encoding.
+type MsgpackEncoder struct{}
+
+func NewMsgpackEncoder() Encoder {
+	return MsgpackEncoder{}
+}
+
+func (m MsgpackEncoder) Encode(data any) ([]byte, error) {
+	var b []byte
+	if err := codec.NewEncoderBytes(&b, msgpackHandle).Encode(data); err != nil {
+		return nil, errs.WrapMsg(err, "MsgpackEncoder.Encode failed", "action", "encode")
+	}
+	return b, nil
+}
+
+func (m MsgpackEncoder) Decode(encodeData []byte, decodeData any) error {
+	if err := codec.NewDecoderBytes(encodeData, msgpackHandle).Decode(decodeData); err != nil {
+		return errs.WrapMsg(err, "MsgpackEncoder.Decode failed", "action", "decode")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package msggateway
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestEncoderRoundTrip(t *testing.T) {
+	encoders := map[string]Encoder{
+		GobEncoding:      NewGobEncoder(),
+		JsonEncoding:     NewJsonEncoder(),
+		ProtobufEncoding: NewProtobufEncoder(),
+		MsgpackEncoding:  NewMsgpackEncoder(),
+	}
+	for name, encoder := range encoders {
+		t.Run(name, func(t *testing.T) {
+			req := Req{
+				ReqIdentifier: WSSendMsg,
+				Token:         "token",
+				SendID:        "user1",
+				OperationID:   "op1",
+				MsgIncr:       "1",
+				Data:          []byte{0, 1, 2, 255},
+			}
+			data, err := encoder.Encode(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decodedReq Req
+			if err := encoder.Decode(data, &decodedReq); err != nil {
+				t.Fatal(err)
+			}
+			if decodedReq.String() != req.String() || !bytes.Equal(decodedReq.Data, req.Data) {
+				t.Fatalf("req mismatch: got %s, want %s", decodedReq.String(), req.String())
+			}
+
+			resp := Resp{
+				ReqIdentifier: WSPushMsg,
+				MsgIncr:       "1",
+				OperationID:   "op1",
+				ErrCode:       1001,
+				ErrMsg:        "ArgsError",
+				Data:          []byte("payload"),
+			}
+			data, err = encoder.Encode(resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decodedResp Resp
+			if err := encoder.Decode(data, &decodedResp); err != nil {
+				t.Fatal(err)
+			}
+			if decodedResp.String() != resp.String() || !bytes.Equal(decodedResp.Data, resp.Data) {
+				t.Fatalf("resp mismatch: got %s, want %s", decodedResp.String(), resp.String())
+			}
+		})
+	}
+}
+
+func TestProtobufEncoderSkipsUnknownFields(t *testing.T) {
+	data, err := NewProtobufEncoder().Encode(Req{ReqIdentifier: WSGetNewestSeq, SendID: "user1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = protowire.AppendTag(data, 100, protowire.BytesType)
+	data = protowire.AppendString(data, "from a newer client")
+
+	var req Req
+	if err := NewProtobufEncoder().Decode(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.ReqIdentifier != WSGetNewestSeq || req.SendID != "user1" {
+		t.Fatalf("unexpected req %s", req.String())
+	}
+}
+
+func TestProtobufEncoderRejectsWrongWireType(t *testing.T) {
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendString(data, "1001")
+
+	var req Req
+	if err := NewProtobufEncoder().Decode(data, &req); err == nil {
+		t.Fatal("expected error for mismatched wire type")
+	}
+}
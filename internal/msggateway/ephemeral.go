@@ -0,0 +1,217 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/gatewayext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultEphemeralContentLength = 4096
+
+// ephemeralGroupCache and ephemeralFriendCache are the local caches the recipients of an event are checked with.
+type ephemeralGroupCache interface {
+	GetGroupMemberIDs(ctx context.Context, groupID string) ([]string, error)
+}
+
+type ephemeralFriendCache interface {
+	IsFriend(ctx context.Context, possibleFriendUserID, userID string) (bool, error)
+	IsBlack(ctx context.Context, possibleBlackUserID, userID string) (bool, error)
+}
+
+// ephemeralEncoder encodes the event with the encoding of the connection,
+// protobuf connections carry it as json as the event has no protobuf schema.
+func ephemeralEncoder(encoder Encoder) Encoder {
+	if _, ok := encoder.(ProtobufEncoder); ok {
+		return NewJsonEncoder()
+	}
+	return encoder
+}
+
+// SendEphemeralEvent delivers the event of the client to the online members of the conversation, straight through the gateways
+// and without a seq, so the members that are offline never get it. The events of a sender in a conversation are throttled.
+func (ws *WsServer) SendEphemeralEvent(ctx context.Context, client *Client, data *Req) ([]byte, error) {
+	var event gatewayext.EphemeralEvent
+	if err := ephemeralEncoder(client.Encoder).Decode(data.Data, &event); err != nil {
+		return nil, errs.WrapMsg(err, "SendEphemeralEvent: error unmarshaling event", "action", "unmarshal", "dataType", "EphemeralEvent")
+	}
+	if event.Type == "" {
+		return nil, errs.ErrArgs.WrapMsg("event type is empty")
+	}
+	if len(event.Content) > ws.ephemeralMaxContentLength {
+		return nil, errs.ErrArgs.WrapMsg("event content is too long", "length", len(event.Content), "max", ws.ephemeralMaxContentLength)
+	}
+	event.SendID = client.UserID
+	event.SenderPlatformID = int32(client.PlatformID)
+	event.SendTime = time.Now().UnixMilli()
+
+	userIDs, err := ws.ephemeralRecvIDs(ctx, &event)
+	if err != nil {
+		return nil, err
+	}
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	// each gateway delivers to the members connected to it
+	return nil, ws.pushEphemeralEventToAllNode(ctx, &event, userIDs)
+}
+
+// ephemeralRecvIDs checks the event as a msg of the conversation would be checked and returns the users it goes to.
+func (ws *WsServer) ephemeralRecvIDs(ctx context.Context, event *gatewayext.EphemeralEvent) ([]string, error) {
+	var conversationID string
+	switch event.SessionType {
+	case constant.SingleChatType:
+		if event.RecvID == "" {
+			return nil, errs.ErrArgs.WrapMsg("recvID is empty")
+		}
+		conversationID = msgprocessor.GetConversationIDBySessionType(constant.SingleChatType, event.SendID, event.RecvID)
+	case constant.ReadGroupChatType:
+		if event.GroupID == "" {
+			return nil, errs.ErrArgs.WrapMsg("groupID is empty")
+		}
+		conversationID = msgprocessor.GetConversationIDBySessionType(constant.ReadGroupChatType, event.GroupID)
+	default:
+		return nil, errs.ErrArgs.WrapMsg("session type is not supported", "sessionType", event.SessionType)
+	}
+	if err := ws.allowEphemeralEvent(ctx, conversationID, event.SendID); err != nil {
+		return nil, err
+	}
+	if event.SessionType == constant.SingleChatType {
+		if event.RecvID == event.SendID {
+			return nil, nil
+		}
+		if err := ws.checkEphemeralSingleChat(ctx, event.SendID, event.RecvID); err != nil {
+			return nil, err
+		}
+		return []string{event.RecvID}, nil
+	}
+	memberIDs, err := ws.ephemeralGroupCache.GetGroupMemberIDs(ctx, event.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	if !datautil.Contain(event.SendID, memberIDs...) {
+		return nil, errs.ErrNoPermission.WrapMsg("not in group", "groupID", event.GroupID)
+	}
+	userIDs := make([]string, 0, len(memberIDs)-1)
+	for _, userID := range memberIDs {
+		if userID != event.SendID {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// checkEphemeralSingleChat applies the blacklist and, with friendVerify, the friendship checks of a single chat msg.
+func (ws *WsServer) checkEphemeralSingleChat(ctx context.Context, sendID string, recvID string) error {
+	if datautil.Contain(sendID, ws.msgGatewayConfig.Share.IMAdminUser.UserIDs...) {
+		return nil
+	}
+	black, err := ws.ephemeralFriendCache.IsBlack(ctx, sendID, recvID)
+	if err != nil {
+		return err
+	}
+	if black {
+		return servererrs.ErrBlockedByPeer.Wrap()
+	}
+	if ws.msgGatewayConfig.MsgConfig.FriendVerify {
+		friend, err := ws.ephemeralFriendCache.IsFriend(ctx, sendID, recvID)
+		if err != nil {
+			return err
+		}
+		if !friend {
+			return servererrs.ErrNotPeersFriend.Wrap()
+		}
+	}
+	return nil
+}
+
+// allowEphemeralEvent throttles the events of a sender in a conversation, the limit is held by the gateway the sender is connected to.
+func (ws *WsServer) allowEphemeralEvent(ctx context.Context, conversationID string, sendID string) error {
+	if ws.ephemeralRate <= 0 {
+		return nil
+	}
+	allowed, err := ws.ephemeralLimiter.Allow(ctx, conversationID+":"+sendID, ws.ephemeralRate, ws.ephemeralBurst)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return servererrs.ErrConnReqRateLimited.WrapMsg("too many ephemeral events", "conversationID", conversationID)
+	}
+	return nil
+}
+
+func (ws *WsServer) pushEphemeralEventToAllNode(ctx context.Context, event *gatewayext.EphemeralEvent, userIDs []string) error {
+	conns, err := ws.disCov.GetConns(ctx, ws.msgGatewayConfig.Discovery.RpcService.MessageGateway)
+	if err != nil {
+		return err
+	}
+	ws.PushEphemeralEvent(ctx, event, userIDs)
+
+	wg := errgroup.Group{}
+	wg.SetLimit(concurrentRequest)
+	req := &gatewayext.PushEphemeralEventReq{Event: event, PushToUserIDs: userIDs}
+	for _, conn := range conns {
+		if ws.disCov.IsSelfNode(conn) {
+			continue
+		}
+		wg.Go(func() error {
+			if _, err := gatewayext.NewMsgGatewayExtClient(conn).PushEphemeralEvent(ctx, req); err != nil {
+				log.ZWarn(ctx, "PushEphemeralEvent err", err)
+			}
+			return nil
+		})
+	}
+	_ = wg.Wait()
+	return nil
+}
+
+// PushEphemeralEvent writes the event to the connections of the users on this gateway, the clients falling behind miss it.
+func (ws *WsServer) PushEphemeralEvent(ctx context.Context, event *gatewayext.EphemeralEvent, userIDs []string) {
+	// encoded once per encoding of the connections
+	encoded := make(map[Encoder][]byte)
+	for _, userID := range userIDs {
+		clients, ok := ws.clients.GetAll(userID)
+		if !ok {
+			continue
+		}
+		for _, client := range clients {
+			encoder := ephemeralEncoder(client.Encoder)
+			data, ok := encoded[encoder]
+			if !ok {
+				var err error
+				data, err = encoder.Encode(event)
+				if err != nil {
+					log.ZError(ctx, "encode ephemeral event failed", err, "userID", userID, "platformID", client.PlatformID)
+					continue
+				}
+				encoded[encoder] = data
+			}
+			resp := Resp{ReqIdentifier: WsEphemeralEvent, Data: data}
+			// not kept by the session, a replayed event is stale
+			if err := client.writeFrame(resp, true); err != nil {
+				log.ZDebug(ctx, "push ephemeral event failed", "err", err, "userID", userID, "platformID", client.PlatformID)
+			}
+		}
+	}
+}
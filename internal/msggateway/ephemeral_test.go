@@ -0,0 +1,124 @@
+package msggateway
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/gatewayext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/errs"
+)
+
+type fakeEphemeralGroupCache struct {
+	members map[string][]string
+}
+
+func (f *fakeEphemeralGroupCache) GetGroupMemberIDs(ctx context.Context, groupID string) ([]string, error) {
+	return f.members[groupID], nil
+}
+
+type fakeEphemeralFriendCache struct {
+	// friends and blacks hold "userID:possibleUserID"
+	friends map[string]bool
+	blacks  map[string]bool
+}
+
+func (f *fakeEphemeralFriendCache) IsFriend(ctx context.Context, possibleFriendUserID, userID string) (bool, error) {
+	return f.friends[userID+":"+possibleFriendUserID], nil
+}
+
+func (f *fakeEphemeralFriendCache) IsBlack(ctx context.Context, possibleBlackUserID, userID string) (bool, error) {
+	return f.blacks[userID+":"+possibleBlackUserID], nil
+}
+
+func newEphemeralTestServer(friendVerify bool) *WsServer {
+	conf := &Config{}
+	conf.MsgConfig.FriendVerify = friendVerify
+	conf.Share.IMAdminUser.UserIDs = []string{"admin"}
+	return &WsServer{
+		msgGatewayConfig: conf,
+		ephemeralGroupCache: &fakeEphemeralGroupCache{members: map[string][]string{
+			"g1": {"u1", "u2", "u3"},
+		}},
+		ephemeralFriendCache: &fakeEphemeralFriendCache{
+			friends: map[string]bool{"u2:u1": true},
+			blacks:  map[string]bool{"u3:u1": true},
+		},
+	}
+}
+
+func TestEphemeralRecvIDs(t *testing.T) {
+	ctx := context.Background()
+	single := func(sendID, recvID string) *gatewayext.EphemeralEvent {
+		return &gatewayext.EphemeralEvent{Type: "typing", SendID: sendID, RecvID: recvID, SessionType: constant.SingleChatType}
+	}
+	group := func(sendID, groupID string) *gatewayext.EphemeralEvent {
+		return &gatewayext.EphemeralEvent{Type: "typing", SendID: sendID, GroupID: groupID, SessionType: constant.ReadGroupChatType}
+	}
+	tests := []struct {
+		name         string
+		friendVerify bool
+		event        *gatewayext.EphemeralEvent
+		want         []string
+		wantErr      errs.CodeError
+	}{
+		{name: "friend", friendVerify: true, event: single("u1", "u2"), want: []string{"u2"}},
+		{name: "not friend with friend verify", friendVerify: true, event: single("u1", "u4"), wantErr: servererrs.ErrNotPeersFriend},
+		{name: "not friend without friend verify", event: single("u1", "u4"), want: []string{"u4"}},
+		{name: "blacklisted", event: single("u1", "u3"), wantErr: servererrs.ErrBlockedByPeer},
+		{name: "admin", friendVerify: true, event: single("admin", "u4"), want: []string{"u4"}},
+		{name: "self", friendVerify: true, event: single("u1", "u1")},
+		{name: "group member", event: group("u1", "g1"), want: []string{"u2", "u3"}},
+		{name: "not group member", event: group("u4", "g1"), wantErr: errs.ErrNoPermission},
+		{name: "no recvID", event: single("u1", ""), wantErr: errs.ErrArgs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newEphemeralTestServer(tt.friendVerify)
+			userIDs, err := ws.ephemeralRecvIDs(ctx, tt.event)
+			if tt.wantErr != nil {
+				if !tt.wantErr.Is(err) {
+					t.Fatalf("got err %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(userIDs, tt.want) {
+				t.Fatalf("got %v, want %v", userIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestEphemeralEncoder(t *testing.T) {
+	encoders := map[string]Encoder{
+		GobEncoding:      NewGobEncoder(),
+		JsonEncoding:     NewJsonEncoder(),
+		ProtobufEncoding: NewProtobufEncoder(),
+		MsgpackEncoding:  NewMsgpackEncoder(),
+	}
+	for name, encoder := range encoders {
+		t.Run(name, func(t *testing.T) {
+			encoder := ephemeralEncoder(encoder)
+			if _, ok := encoder.(ProtobufEncoder); ok {
+				t.Fatal("protobuf connections should carry the event as json")
+			}
+			event := gatewayext.EphemeralEvent{Type: "typing", SendID: "u1", GroupID: "g1", SessionType: constant.ReadGroupChatType, Content: "{}", SendTime: 1}
+			data, err := encoder.Encode(&event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded gatewayext.EphemeralEvent
+			if err := encoder.Decode(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if decoded != event {
+				t.Fatalf("got %+v, want %+v", decoded, event)
+			}
+		})
+	}
+}
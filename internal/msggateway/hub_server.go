@@ -22,6 +22,7 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/gatewayext"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/msggateway"
 	"github.com/openimsdk/protocol/sdkws"
@@ -44,6 +45,7 @@ func (s *Server) InitServer(ctx context.Context, config *Config, disCov discover
 		return err
 	}
 	msggateway.RegisterMsgGatewayServer(server, s)
+	gatewayext.RegisterMsgGatewayExtServer(server, s)
 	if s.ready != nil {
 		return s.ready(s)
 	}
@@ -132,6 +134,37 @@ func (s *Server) GetUsersOnlineStatus(ctx context.Context, req *msggateway.GetUs
 	return &resp, nil
 }
 
+func (s *Server) GetGatewayNode(ctx context.Context, req *gatewayext.GetGatewayNodeReq) (*gatewayext.GetGatewayNodeResp, error) {
+	if !authverify.IsAdmin(ctx) {
+		return nil, errs.ErrNoPermission.WrapMsg("only app manager")
+	}
+	return &gatewayext.GetGatewayNodeResp{Node: s.LongConnServer.NodeInfo()}, nil
+}
+
+// DrainGateway drains this gateway if it is the node asked for, the request is sent to every gateway.
+func (s *Server) DrainGateway(ctx context.Context, req *gatewayext.DrainGatewayReq) (*gatewayext.DrainGatewayResp, error) {
+	if !authverify.IsAdmin(ctx) {
+		return nil, errs.ErrNoPermission.WrapMsg("only app manager")
+	}
+	node := s.LongConnServer.NodeInfo()
+	if node.NodeID != req.NodeID {
+		return &gatewayext.DrainGatewayResp{}, nil
+	}
+	log.ZInfo(ctx, "msg gateway drain requested", "nodeID", node.NodeID)
+	s.LongConnServer.Drain()
+	node.Draining = true
+	return &gatewayext.DrainGatewayResp{Node: node}, nil
+}
+
+// PushEphemeralEvent is called by the gateway the sender is connected to.
+func (s *Server) PushEphemeralEvent(ctx context.Context, req *gatewayext.PushEphemeralEventReq) (*gatewayext.PushEphemeralEventResp, error) {
+	if req.Event == nil {
+		return nil, errs.ErrArgs.WrapMsg("event is nil")
+	}
+	s.LongConnServer.PushEphemeralEvent(ctx, req.Event, req.PushToUserIDs)
+	return &gatewayext.PushEphemeralEventResp{}, nil
+}
+
 func (s *Server) pushToUser(ctx context.Context, userID string, msgData *sdkws.MsgData) *msggateway.SingleMsgToUserResults {
 	clients, ok := s.LongConnServer.GetUserAllCons(userID)
 	if !ok {
@@ -19,8 +19,8 @@ import (
 	"time"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	redis2 "github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/redis"
 	"github.com/openimsdk/open-im-server/v3/pkg/dbbuild"
-	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/tools/discovery"
 	"github.com/openimsdk/tools/utils/datautil"
 	"github.com/openimsdk/tools/utils/runtimeenv"
@@ -35,7 +35,10 @@ type Config struct {
 	RedisConfig    config.Redis
 	WebhooksConfig config.Webhooks
 	Discovery      config.Discovery
-	Index          config.Index
+	// MsgConfig and LocalCacheConfig check the ephemeral events as msgs are checked
+	MsgConfig        config.Msg
+	LocalCacheConfig config.LocalCache
+	Index            config.Index
 }
 
 // Start run ws server.
@@ -54,27 +57,50 @@ func Start(ctx context.Context, conf *Config, client discovery.SvcDiscoveryRegis
 		return err
 	}
 
+	compressors, err := NewCompressors(&conf.MsgGateway.LongConnSvr.Compression)
+	if err != nil {
+		return err
+	}
+
 	longServer := NewWsServer(
 		conf,
 		WithPort(wsPort),
 		WithMaxConnNum(int64(conf.MsgGateway.LongConnSvr.WebsocketMaxConnNum)),
 		WithHandshakeTimeout(time.Duration(conf.MsgGateway.LongConnSvr.WebsocketTimeout)*time.Second),
 		WithMessageMaxMsgLength(conf.MsgGateway.LongConnSvr.WebsocketMaxMsgLen),
+		WithCompressors(compressors),
+		WithWriteQueue(&conf.MsgGateway.LongConnSvr.WriteQueue),
+		WithSessionResume(&conf.MsgGateway.LongConnSvr.SessionResume),
+		WithDrain(&conf.MsgGateway.LongConnSvr.Drain),
+		WithReqRateLimit(&conf.MsgGateway.LongConnSvr.ReqRateLimit, rdb),
+		WithEphemeralEvent(&conf.MsgGateway.LongConnSvr.Ephemeral),
+		WithSSE(&conf.MsgGateway.LongConnSvr.SSE),
 	)
 
-	hubServer := NewServer(longServer, conf, func(srv *Server) error {
-		var err error
-		longServer.online, err = rpccache.NewOnlineCache(srv.userClient, nil, rdb, false, longServer.subscriberUserOnlineStatusChanges)
-		return err
-	})
+	longServer.onlineSub = redis2.NewOnlineSubCache(rdb)
+	longServer.rdb = rdb
+
+	hubServer := NewServer(longServer, conf, nil)
 
 	if err := hubServer.InitServer(ctx, conf, client, server); err != nil {
 		return err
 	}
 
-	go longServer.ChangeOnlineStatus(4)
+	go longServer.runOnlineSub(ctx, rdb)
+	go longServer.subscribePresence(ctx, rdb)
+
+	// the online status outlives ctx, the offline of the connections closed by the drain is reported too
+	statusCtx, stopStatus := context.WithCancel(context.Background())
+	statusDone := make(chan struct{})
+	go func() {
+		defer close(statusDone)
+		longServer.ChangeOnlineStatus(statusCtx, 4)
+	}()
 
-	return hubServer.LongConnServer.Run(ctx)
+	err = hubServer.LongConnServer.Run(ctx)
+	stopStatus()
+	<-statusDone
+	return err
 }
 
 //
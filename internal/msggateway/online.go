@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"os"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -18,7 +19,9 @@ import (
 	"github.com/openimsdk/tools/utils/datautil"
 )
 
-func (ws *WsServer) ChangeOnlineStatus(concurrent int) {
+// ChangeOnlineStatus reports the online state changes to the user service until ctx is done,
+// the pending changes are then reported before it returns.
+func (ws *WsServer) ChangeOnlineStatus(ctx context.Context, concurrent int) {
 	if concurrent < 1 {
 		concurrent = 1
 	}
@@ -106,16 +109,38 @@ func (ws *WsServer) ChangeOnlineStatus(concurrent int) {
 		}
 	}
 
+	var wg sync.WaitGroup
 	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
 		go func(ch <-chan *pbuser.SetUserOnlineStatusReq) {
+			defer wg.Done()
 			for req := range ch {
 				doRequest(req)
 			}
 		}(requestChs[i])
 	}
 
+	flush := func() {
+		for len(ws.clients.UserState()) > 0 {
+			pushUserState(<-ws.clients.UserState())
+		}
+		for i, status := range changeStatus {
+			if len(status) > 0 {
+				requestChs[i] <- &pbuser.SetUserOnlineStatusReq{Status: datautil.Slice(status, local2pb)}
+			}
+			close(requestChs[i])
+		}
+		wg.Wait()
+	}
+
 	for {
 		select {
+		case <-ctx.Done():
+			mergeTicker.Stop()
+			renewalTicker.Stop()
+			flush()
+			log.ZDebug(context.Background(), "user online status flushed")
+			return
 		case <-mergeTicker.C:
 			pushAllUserState()
 		case now := <-renewalTicker.C:
@@ -126,6 +151,10 @@ func (ws *WsServer) ChangeOnlineStatus(concurrent int) {
 		case state := <-ws.clients.UserState():
 			log.ZDebug(context.Background(), "OnlineCache user online change", "userID", state.UserID, "online", state.Online, "offline", state.Offline)
 			pushUserState(state)
+			if ws.draining.Load() {
+				// the state is not merged, see drain
+				pushAllUserState()
+			}
 		}
 	}
 }
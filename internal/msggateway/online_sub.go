@@ -0,0 +1,138 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
+	"github.com/openimsdk/open-im-server/v3/pkg/util/useronline"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+	"github.com/redis/go-redis/v9"
+)
+
+// The gateway watches the online status of the users its clients subscribed to and of the users connected to it,
+// the latter so it can restore the platforms an offline reported by another gateway removed (see UserMap.RecvSubChange).
+// The user service publishes a change only to the channels of the gateways watching the user, batched per gateway.
+const (
+	// onlineWatchRenewal keeps the watches well within cachekey.OnlineSubExpire.
+	onlineWatchRenewal = cachekey.OnlineSubExpire / 3
+	// onlineWatchInterval is how long the users connecting wait to be watched together.
+	onlineWatchInterval = time.Second
+	onlineWatchBatch    = 1000
+)
+
+// runOnlineSub receives the online status changes of the watched users and renews the watches until ctx is done.
+func (ws *WsServer) runOnlineSub(ctx context.Context, rdb redis.UniversalClient) {
+	sub := rdb.Subscribe(ctx, cachekey.GetOnlineNodeChannel(ws.nodeID))
+	defer sub.Close()
+	renewal := time.NewTicker(onlineWatchRenewal)
+	defer renewal.Stop()
+	flush := time.NewTicker(onlineWatchInterval)
+	defer flush.Stop()
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			ws.recvOnlineChanges(ctx, message.Payload)
+		case <-flush.C:
+			ws.flushOnlineWatch(ctx)
+		case <-renewal.C:
+			ws.watchOnline(ctx, ws.watchedUserIDs())
+		}
+	}
+}
+
+// watchOnlineLater watches the user with the next batch, without holding up the caller.
+func (ws *WsServer) watchOnlineLater(userID string) {
+	ws.onlineWatchPending.add([]string{userID})
+}
+
+func (ws *WsServer) flushOnlineWatch(ctx context.Context) {
+	if userIDs := ws.onlineWatchPending.take(); len(userIDs) > 0 {
+		ws.watchOnline(ctx, userIDs)
+	}
+}
+
+// watchOnline watches the users in batches, the users of a batch that failed are watched again with the next one.
+func (ws *WsServer) watchOnline(ctx context.Context, userIDs []string) {
+	for start := 0; start < len(userIDs); start += onlineWatchBatch {
+		end := min(start+onlineWatchBatch, len(userIDs))
+		if err := ws.onlineSub.Watch(ctx, ws.nodeID, userIDs[start:end]); err != nil {
+			log.ZWarn(ctx, "watch user online status failed", err, "count", end-start)
+			ws.onlineWatchPending.add(userIDs[start:end])
+		}
+	}
+}
+
+// onlineWatchQueue holds the users waiting to be watched, it grows with the users rather than dropping any.
+type onlineWatchQueue struct {
+	lock    sync.Mutex
+	userIDs map[string]struct{}
+}
+
+func (q *onlineWatchQueue) add(userIDs []string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.userIDs == nil {
+		q.userIDs = make(map[string]struct{}, len(userIDs))
+	}
+	for _, userID := range userIDs {
+		q.userIDs[userID] = struct{}{}
+	}
+}
+
+func (q *onlineWatchQueue) take() []string {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.userIDs) == 0 {
+		return nil
+	}
+	userIDs := datautil.Keys(q.userIDs)
+	q.userIDs = nil
+	return userIDs
+}
+
+// watchedUserIDs returns the users subscribed to and the users connected.
+func (ws *WsServer) watchedUserIDs() []string {
+	userIDs := ws.subscription.UserIDs()
+	for _, client := range ws.clients.GetAllClients() {
+		userIDs = append(userIDs, client.UserID)
+	}
+	return datautil.Distinct(userIDs)
+}
+
+func (ws *WsServer) recvOnlineChanges(ctx context.Context, payload string) {
+	lines := strings.Split(payload, "\n")
+	changes := make([]onlineChange, 0, len(lines))
+	for _, line := range lines {
+		userID, platformIDs, err := useronline.ParseUserOnlineStatus(line)
+		if err != nil {
+			log.ZError(ctx, "parse user online status failed", err, "payload", line)
+			continue
+		}
+		changes = append(changes, onlineChange{userID: userID, platformIDs: platformIDs})
+	}
+	ws.subscriberUserOnlineStatusChanges(ctx, changes)
+}
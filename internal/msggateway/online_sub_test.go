@@ -0,0 +1,77 @@
+package msggateway
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+)
+
+type fakeOnlineSub struct {
+	cache.OnlineSubCache
+	fail    int
+	batches [][]string
+}
+
+func (f *fakeOnlineSub) Watch(_ context.Context, _ string, userIDs []string) error {
+	if f.fail > 0 {
+		f.fail--
+		return errors.New("watch failed")
+	}
+	f.batches = append(f.batches, append([]string(nil), userIDs...))
+	return nil
+}
+
+func (f *fakeOnlineSub) watched() map[string]struct{} {
+	userIDs := make(map[string]struct{})
+	for _, batch := range f.batches {
+		for _, userID := range batch {
+			userIDs[userID] = struct{}{}
+		}
+	}
+	return userIDs
+}
+
+func TestWatchOnlineLater(t *testing.T) {
+	sub := &fakeOnlineSub{}
+	ws := &WsServer{onlineSub: sub, nodeID: "node1"}
+	const n = onlineWatchBatch*2 + 10
+	for i := 0; i < n; i++ {
+		ws.watchOnlineLater(strconv.Itoa(i))
+		// the users connecting again before the flush are watched once
+		ws.watchOnlineLater(strconv.Itoa(i))
+	}
+	ws.flushOnlineWatch(context.Background())
+	if len(sub.batches) != 3 {
+		t.Fatalf("%d batches, want 3", len(sub.batches))
+	}
+	for _, batch := range sub.batches {
+		if len(batch) > onlineWatchBatch {
+			t.Fatalf("batch of %d", len(batch))
+		}
+	}
+	if watched := sub.watched(); len(watched) != n {
+		t.Fatalf("%d users watched, want %d", len(watched), n)
+	}
+	ws.flushOnlineWatch(context.Background())
+	if len(sub.batches) != 3 {
+		t.Fatal("the users are watched again without connecting again")
+	}
+}
+
+func TestWatchOnlineRetry(t *testing.T) {
+	sub := &fakeOnlineSub{fail: 1}
+	ws := &WsServer{onlineSub: sub, nodeID: "node1"}
+	ws.watchOnlineLater("u1")
+	ws.watchOnlineLater("u2")
+	ws.flushOnlineWatch(context.Background())
+	if len(sub.batches) != 0 {
+		t.Fatalf("batches %v", sub.batches)
+	}
+	ws.flushOnlineWatch(context.Background())
+	if watched := sub.watched(); len(watched) != 2 {
+		t.Fatalf("watched %v after the retry", watched)
+	}
+}
@@ -14,7 +14,12 @@
 
 package msggateway
 
-import "time"
+import (
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/redis/go-redis/v9"
+)
 
 type (
 	Option  func(opt *configs)
@@ -29,6 +34,26 @@ type (
 		messageMaxMsgLength int
 		// Websocket write buffer, default: 4096, 4kb.
 		writeBufferSize int
+		// Compressors negotiable in the handshake besides gzip, keyed by protocol
+		compressors map[string]Compressor
+		// Frames queued for each connection, and the queue length from which droppable frames are dropped
+		writeQueueSize          int
+		writeQueueDropThreshold int
+		// Frames kept for each session resume, zero disables the resume
+		sessionBufferSize int
+		// How long a dropped connection can be resumed
+		sessionTTL time.Duration
+		// How long the clients get to leave on drain, and the longest delay they are told to reconnect after
+		drainTimeout        time.Duration
+		drainReconnectDelay time.Duration
+		// Limits of the requests of each user, nil if disabled
+		reqLimiter *reqLimiter
+		// Throttle of the ephemeral events of each sender in a conversation, and their longest content
+		ephemeralRate             float64
+		ephemeralBurst            int
+		ephemeralMaxContentLength int
+		// Origins allowed to use the sse transport from a browser
+		sseAllowOrigins []string
 	}
 )
 
@@ -61,3 +86,53 @@ func WithWriteBufferSize(size int) Option {
 		opt.writeBufferSize = size
 	}
 }
+
+func WithCompressors(compressors map[string]Compressor) Option {
+	return func(opt *configs) {
+		opt.compressors = compressors
+	}
+}
+
+func WithSessionResume(conf *config.SessionResume) Option {
+	return func(opt *configs) {
+		if !conf.Enable || conf.BufferSize <= 0 {
+			return
+		}
+		opt.sessionBufferSize = conf.BufferSize
+		opt.sessionTTL = time.Duration(conf.TTL) * time.Second
+	}
+}
+
+func WithWriteQueue(conf *config.WebsocketWriteQueue) Option {
+	return func(opt *configs) {
+		opt.writeQueueSize = conf.Size
+		opt.writeQueueDropThreshold = conf.DropThreshold
+	}
+}
+
+func WithDrain(conf *config.WebsocketDrain) Option {
+	return func(opt *configs) {
+		opt.drainTimeout = time.Duration(conf.Timeout) * time.Second
+		opt.drainReconnectDelay = time.Duration(conf.MaxReconnectDelay) * time.Second
+	}
+}
+
+func WithReqRateLimit(conf *config.ReqRateLimit, rdb redis.UniversalClient) Option {
+	return func(opt *configs) {
+		opt.reqLimiter = newReqLimiter(conf, rdb)
+	}
+}
+
+func WithEphemeralEvent(conf *config.EphemeralEvent) Option {
+	return func(opt *configs) {
+		opt.ephemeralRate = conf.Rate
+		opt.ephemeralBurst = conf.Burst
+		opt.ephemeralMaxContentLength = conf.MaxContentLength
+	}
+}
+
+func WithSSE(conf *config.SSE) Option {
+	return func(opt *configs) {
+		opt.sseAllowOrigins = conf.AllowOrigins
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
+	"github.com/openimsdk/tools/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// subscribePresence pushes the presence changes published by the user service to the clients subscribed to the users
+// on this gateway, the subscriptions to the online status cover the presence as well.
+// Redis drops an expired presence without publishing, the gateway pushes the clear at its ExpireAt itself.
+func (ws *WsServer) subscribePresence(ctx context.Context, rdb redis.UniversalClient) {
+	sub := rdb.Subscribe(ctx, cachekey.PresenceChannel)
+	defer sub.Close()
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var presence userext.UserPresence
+			if err := json.Unmarshal([]byte(message.Payload), &presence); err != nil {
+				log.ZError(ctx, "presence subscribe unmarshal failed", err, "payload", message.Payload)
+				continue
+			}
+			ws.presenceExpiry.observe(&presence)
+			ws.pushUserPresence(ctx, presence.UserID, []byte(message.Payload))
+		}
+	}
+}
+
+func (ws *WsServer) pushUserPresence(ctx context.Context, userID string, data []byte) {
+	for _, client := range ws.subscription.GetClient(userID) {
+		if err := client.PushUserPresence(data); err != nil {
+			log.ZDebug(ctx, "push user presence failed", "err", err, "userID", userID, "subscriber", client.UserID)
+		}
+	}
+}
+
+// pushPresenceExpired pushes the clear of the presence of userID that expired at expireAt.
+func (ws *WsServer) pushPresenceExpired(userID string, expireAt int64) {
+	data, err := json.Marshal(&userext.UserPresence{UserID: userID, UpdateTime: expireAt})
+	if err != nil {
+		log.ZError(context.Background(), "presence expired marshal failed", err, "userID", userID)
+		return
+	}
+	ws.pushUserPresence(context.Background(), userID, data)
+}
+
+// presenceExpiry calls onExpire at the ExpireAt of the latest presence seen of each user, unless a newer change
+// of the presence is seen before.
+type presenceExpiry struct {
+	mu       sync.Mutex
+	timers   map[string]*presenceTimer
+	onExpire func(userID string, expireAt int64)
+}
+
+type presenceTimer struct {
+	updateTime int64
+	timer      *time.Timer
+}
+
+func newPresenceExpiry(onExpire func(userID string, expireAt int64)) *presenceExpiry {
+	return &presenceExpiry{timers: make(map[string]*presenceTimer), onExpire: onExpire}
+}
+
+func (e *presenceExpiry) observe(presence *userext.UserPresence) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if t, ok := e.timers[presence.UserID]; ok {
+		if t.updateTime > presence.UpdateTime {
+			return
+		}
+		t.timer.Stop()
+		delete(e.timers, presence.UserID)
+	}
+	if presence.ExpireAt == 0 {
+		return
+	}
+	t := &presenceTimer{updateTime: presence.UpdateTime}
+	userID, expireAt := presence.UserID, presence.ExpireAt
+	t.timer = time.AfterFunc(time.Until(time.UnixMilli(expireAt)), func() {
+		e.mu.Lock()
+		current := e.timers[userID] == t
+		if current {
+			delete(e.timers, userID)
+		}
+		e.mu.Unlock()
+		if current {
+			e.onExpire(userID, expireAt)
+		}
+	})
+	e.timers[userID] = t
+}
+
+// pushSubscribedPresence pushes the presences the users newly subscribed to have, the later changes are pushed by subscribePresence.
+func (ws *WsServer) pushSubscribedPresence(ctx context.Context, client *Client, userIDs []string) error {
+	const batch = 1000
+	for start := 0; start < len(userIDs); start += batch {
+		end := min(start+batch, len(userIDs))
+		resp, err := ws.userExtClient.GetUsersPresence(ctx, &userext.GetUsersPresenceReq{UserIDs: userIDs[start:end]})
+		if err != nil {
+			return err
+		}
+		for _, presence := range resp.Presences {
+			ws.presenceExpiry.observe(presence)
+			data, err := json.Marshal(presence)
+			if err != nil {
+				return err
+			}
+			if err := client.PushUserPresence(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) PushUserPresence(data []byte) error {
+	return c.pushFrame(Resp{ReqIdentifier: WsUserPresence, Data: data}, true)
+}
@@ -0,0 +1,53 @@
+package msggateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
+)
+
+type expiredPresence struct {
+	userID   string
+	expireAt int64
+}
+
+func TestPresenceExpiry(t *testing.T) {
+	expired := make(chan expiredPresence, 10)
+	e := newPresenceExpiry(func(userID string, expireAt int64) {
+		expired <- expiredPresence{userID: userID, expireAt: expireAt}
+	})
+	now := time.Now().UnixMilli()
+
+	e.observe(&userext.UserPresence{UserID: "a", State: 1, ExpireAt: now + 30, UpdateTime: now})
+	// changed to a presence kept until changed before it expires
+	e.observe(&userext.UserPresence{UserID: "b", State: 1, ExpireAt: now + 30, UpdateTime: now})
+	e.observe(&userext.UserPresence{UserID: "b", State: 2, UpdateTime: now + 1})
+	// an older presence read after the newer change is ignored
+	e.observe(&userext.UserPresence{UserID: "c", UpdateTime: now + 1})
+	e.observe(&userext.UserPresence{UserID: "c", State: 1, ExpireAt: now + 60, UpdateTime: now + 2})
+	e.observe(&userext.UserPresence{UserID: "c", State: 1, ExpireAt: now + 30, UpdateTime: now})
+	// cleared before it expires
+	e.observe(&userext.UserPresence{UserID: "d", State: 1, ExpireAt: now + 30, UpdateTime: now})
+	e.observe(&userext.UserPresence{UserID: "d", UpdateTime: now + 1})
+
+	want := []expiredPresence{{userID: "a", expireAt: now + 30}, {userID: "c", expireAt: now + 60}}
+	for _, w := range want {
+		select {
+		case got := <-expired:
+			if got != w {
+				t.Fatalf("expired %+v, want %+v", got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s did not expire", w.userID)
+		}
+	}
+	select {
+	case got := <-expired:
+		t.Fatalf("unexpected expiry %+v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if n := len(e.timers); n != 0 {
+		t.Fatalf("%d timers left", n)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/mcache"
+	redis2 "github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/redis"
+	"github.com/openimsdk/tools/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// reqRuleKey finds the rule of a request type on a platform, platformID is zero for the rule of the other platforms.
+type reqRuleKey struct {
+	reqIdentifier int32
+	platformID    int
+}
+
+// reqLimiter limits the requests of each user by request type, with a token bucket for each user and request type
+// shared by all the platforms of the user without a rule of their own, so that more connections do not allow more
+// requests. A platform with a rule of its own has a bucket of its own, filled at the rate of its rule.
+type reqLimiter struct {
+	buckets cache.RateLimitCache
+	rules   map[reqRuleKey]config.ReqRateLimitRule
+}
+
+// newReqLimiter returns nil if the limit is disabled, rdb is only used if the buckets are shared through redis.
+func newReqLimiter(conf *config.ReqRateLimit, rdb redis.UniversalClient) *reqLimiter {
+	if !conf.Enable || len(conf.Rules) == 0 {
+		return nil
+	}
+	l := &reqLimiter{rules: make(map[reqRuleKey]config.ReqRateLimitRule)}
+	if conf.Redis && rdb != nil {
+		l.buckets = redis2.NewRateLimit(rdb)
+	} else {
+		l.buckets = mcache.NewRateLimit()
+	}
+	for _, rule := range conf.Rules {
+		if rule.Rate <= 0 || rule.Burst <= 0 {
+			continue
+		}
+		if len(rule.PlatformIDs) == 0 {
+			l.rules[reqRuleKey{reqIdentifier: rule.ReqIdentifier}] = rule
+			continue
+		}
+		for _, platformID := range rule.PlatformIDs {
+			l.rules[reqRuleKey{reqIdentifier: rule.ReqIdentifier, platformID: platformID}] = rule
+		}
+	}
+	return l
+}
+
+// rule returns the rule of the request type on the platform and the key it is found at,
+// whose platformID is zero for the rule of the other platforms.
+func (l *reqLimiter) rule(reqIdentifier int32, platformID int) (config.ReqRateLimitRule, reqRuleKey, bool) {
+	key := reqRuleKey{reqIdentifier: reqIdentifier, platformID: platformID}
+	if rule, ok := l.rules[key]; ok {
+		return rule, key, true
+	}
+	key.platformID = 0
+	rule, ok := l.rules[key]
+	return rule, key, ok
+}
+
+// allow returns ErrConnReqRateLimited once the user sent more requests of the type than the rule allows,
+// each request is checked against the rule of the platform it is sent from, in the bucket of that rule.
+// The requests are let through if the buckets cannot be reached, the limit is not worth failing them for.
+func (l *reqLimiter) allow(ctx context.Context, client *Client, reqIdentifier int32) error {
+	rule, key, ok := l.rule(reqIdentifier, client.PlatformID)
+	if !ok {
+		return nil
+	}
+	allowed, err := l.buckets.Allow(ctx, cachekey.GetReqRateLimitKey(client.UserID, reqIdentifier, key.platformID), rule.Rate, rule.Burst)
+	if err != nil {
+		log.ZWarn(ctx, "req rate limit failed", err, "reqIdentifier", reqIdentifier)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	prommetrics.WsReqRateLimitedCounter.WithLabelValues(strconv.Itoa(int(reqIdentifier))).Inc()
+	return servererrs.ErrConnReqRateLimited.WrapMsg("too many requests", "reqIdentifier", reqIdentifier, "rate", rule.Rate, "burst", rule.Burst)
+}
+
+// LimitReq checks the request against the rate limit of its type, if any.
+func (ws *WsServer) LimitReq(ctx context.Context, client *Client, req *Req) error {
+	if ws.reqLimiter == nil {
+		return nil
+	}
+	return ws.reqLimiter.allow(ctx, client, req.ReqIdentifier)
+}
@@ -0,0 +1,87 @@
+package msggateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/protocol/constant"
+)
+
+func newTestReqLimiter() *reqLimiter {
+	return newReqLimiter(&config.ReqRateLimit{
+		Enable: true,
+		Rules: []config.ReqRateLimitRule{
+			{ReqIdentifier: WSSendMsg, Rate: 1, Burst: 3},
+			{ReqIdentifier: WSSendMsg, PlatformIDs: []int{constant.WebPlatformID}, Rate: 1, Burst: 1},
+			{ReqIdentifier: WSPullMsgBySeqList, Rate: 1, Burst: 2},
+			// disabled rules are dropped
+			{ReqIdentifier: WSGetNewestSeq, Rate: 0, Burst: 5},
+		},
+	}, nil)
+}
+
+func TestReqLimiterRule(t *testing.T) {
+	l := newTestReqLimiter()
+	tests := []struct {
+		name          string
+		reqIdentifier int32
+		platformID    int
+		burst         int
+		ok            bool
+	}{
+		{name: "default rule", reqIdentifier: WSSendMsg, platformID: constant.IOSPlatformID, burst: 3, ok: true},
+		{name: "platform rule", reqIdentifier: WSSendMsg, platformID: constant.WebPlatformID, burst: 1, ok: true},
+		{name: "other req rule", reqIdentifier: WSPullMsgBySeqList, platformID: constant.WebPlatformID, burst: 2, ok: true},
+		{name: "disabled rule", reqIdentifier: WSGetNewestSeq, platformID: constant.IOSPlatformID},
+		{name: "no rule", reqIdentifier: WsLogoutMsg, platformID: constant.IOSPlatformID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, _, ok := l.rule(tt.reqIdentifier, tt.platformID)
+			if ok != tt.ok || rule.Burst != tt.burst {
+				t.Fatalf("got rule %+v %v, want burst %d %v", rule, ok, tt.burst, tt.ok)
+			}
+		})
+	}
+	if newReqLimiter(&config.ReqRateLimit{Rules: []config.ReqRateLimitRule{{ReqIdentifier: WSSendMsg, Rate: 1, Burst: 1}}}, nil) != nil {
+		t.Fatal("disabled limit should not limit")
+	}
+}
+
+func TestReqLimiterAllow(t *testing.T) {
+	l := newTestReqLimiter()
+	ctx := context.Background()
+	ios := &Client{UserID: "u1", PlatformID: constant.IOSPlatformID}
+	web := &Client{UserID: "u1", PlatformID: constant.WebPlatformID}
+	android := &Client{UserID: "u1", PlatformID: constant.AndroidPlatformID}
+
+	for i := 0; i < 3; i++ {
+		if err := l.allow(ctx, ios, WSSendMsg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the platforms without a rule of their own share the bucket, another connection gets no more requests
+	if err := l.allow(ctx, android, WSSendMsg); !servererrs.ErrConnReqRateLimited.Is(err) {
+		t.Fatalf("got %v, want the request limited", err)
+	}
+	// the web rule has a bucket of its own, with the burst of its rule
+	if err := l.allow(ctx, web, WSSendMsg); err != nil {
+		t.Fatal("the web bucket is shared with the other platforms", err)
+	}
+	if err := l.allow(ctx, web, WSSendMsg); !servererrs.ErrConnReqRateLimited.Is(err) {
+		t.Fatalf("got %v, want the web request limited by its rule", err)
+	}
+	if err := l.allow(ctx, &Client{UserID: "u2", PlatformID: constant.IOSPlatformID}, WSSendMsg); err != nil {
+		t.Fatal("the buckets of the users are not separate", err)
+	}
+	if err := l.allow(ctx, ios, WSPullMsgBySeqList); err != nil {
+		t.Fatal("the buckets of the request types are not separate", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := l.allow(ctx, ios, WsLogoutMsg); err != nil {
+			t.Fatal("request without a rule limited", err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// SessionInfo is the data of the WsSessionInfo frame sent when a connection is registered.
+// The client keeps SessionID and the largest frame seq it received, found in the MsgIncr of pushed frames,
+// and passes them back when reconnecting. If Resumed is false the missed frames are gone and the client syncs the seqs.
+type SessionInfo struct {
+	SessionID string `json:"sessionID"`
+	Resumed   bool   `json:"resumed"`
+	// FrameSeq is the seq of the last frame pushed before the live ones, replayed frames included.
+	FrameSeq uint64 `json:"frameSeq"`
+}
+
+// session outlives the connection it was issued to by the ttl, keeping the latest pushed frames
+// so that a client reconnecting after a short network drop gets the frames it missed replayed.
+type session struct {
+	id         string
+	userID     string
+	platformID int
+	token      string
+
+	lock sync.Mutex
+	// owner is the connection the frames are written to.
+	owner *Client
+	// frames is a ring of the latest pushed frames, frames[head] is the oldest.
+	frames  []Resp
+	head    int
+	count   int
+	lastSeq uint64
+	// detached is set when the owner dropped, the owner stays registered until the timer fires.
+	detached bool
+	timer    *time.Timer
+	// closed sessions cannot be resumed, the owner logged out, was kicked or the ttl passed.
+	closed bool
+}
+
+func newSession(client *Client, size int) *session {
+	return &session{
+		id:         uuid.New().String(),
+		userID:     client.UserID,
+		platformID: client.PlatformID,
+		token:      client.token,
+		owner:      client,
+		frames:     make([]Resp, size),
+	}
+}
+
+// push numbers the frame, keeps it and writes it to the owner, which is a no-op while the owner is detached.
+func (s *session) push(resp Resp, droppable bool) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.lastSeq++
+	resp.MsgIncr = strconv.FormatUint(s.lastSeq, 10)
+	if s.count < len(s.frames) {
+		s.frames[(s.head+s.count)%len(s.frames)] = resp
+		s.count++
+	} else {
+		s.frames[s.head] = resp
+		s.head = (s.head + 1) % len(s.frames)
+	}
+	return s.owner.writeFrame(resp, droppable)
+}
+
+// since returns the frames pushed after seq, false if some of them are no longer kept.
+func (s *session) since(seq uint64) ([]Resp, bool) {
+	if seq > s.lastSeq || s.lastSeq-seq > uint64(s.count) {
+		return nil, false
+	}
+	n := int(s.lastSeq - seq)
+	frames := make([]Resp, 0, n)
+	for i := s.count - n; i < s.count; i++ {
+		frames = append(frames, s.frames[(s.head+i)%len(s.frames)])
+	}
+	return frames, true
+}
+
+func (s *session) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+}
+
+type sessionManager struct {
+	size int
+	ttl  time.Duration
+
+	lock     sync.Mutex
+	sessions map[string]*session
+}
+
+func newSessionManager(size int, ttl time.Duration) *sessionManager {
+	return &sessionManager{
+		size:     size,
+		ttl:      ttl,
+		sessions: make(map[string]*session),
+	}
+}
+
+func (m *sessionManager) open(client *Client) *session {
+	s := newSession(client, m.size)
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.sessions[s.id] = s
+	return s
+}
+
+func (m *sessionManager) get(id string) *session {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.sessions[id]
+}
+
+func (m *sessionManager) remove(s *session) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.sessions, s.id)
+}
+
+func (c *Client) writeSessionInfo(info *SessionInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return c.writeBinaryMsg(Resp{ReqIdentifier: WsSessionInfo, Data: data})
+}
+
+// openSession issues a new session to the client before it is registered.
+func (ws *WsServer) openSession(client *Client) {
+	s := ws.sessions.open(client)
+	client.session = s
+	if err := client.writeSessionInfo(&SessionInfo{SessionID: s.id}); err != nil {
+		log.ZWarn(client.ctx, "write session info failed", err)
+	}
+}
+
+// resumeSession hands the session asked for in the handshake over to the client, replaying the frames it missed
+// and taking the place of the previous connection, so the user never goes offline.
+// It returns false if the session cannot be resumed, the client is then registered as a new connection.
+func (ws *WsServer) resumeSession(client *Client) bool {
+	id := client.ctx.GetResumeSessionID()
+	if id == "" {
+		return false
+	}
+	s := ws.sessions.get(id)
+	if s == nil || s.userID != client.UserID || s.platformID != client.PlatformID || s.token != client.token {
+		log.ZDebug(client.ctx, "session not resumable", "sessionID", id)
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	old := s.owner
+	if s.closed || !ws.isRegistered(old) {
+		log.ZDebug(client.ctx, "session closed", "sessionID", id)
+		return false
+	}
+	frames, ok := s.since(client.ctx.GetResumeFrameSeq())
+	if !ok {
+		log.ZDebug(client.ctx, "session frames not kept", "sessionID", id, "frameSeq", client.ctx.GetResumeFrameSeq(), "lastSeq", s.lastSeq)
+		return false
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.detached = false
+	s.owner = client
+	client.session = s
+	if err := client.writeSessionInfo(&SessionInfo{SessionID: s.id, Resumed: true, FrameSeq: s.lastSeq}); err != nil {
+		log.ZWarn(client.ctx, "write session info failed", err)
+	}
+	for _, frame := range frames {
+		if err := client.writeBinaryMsg(frame); err != nil {
+			log.ZWarn(client.ctx, "replay frame failed", err, "frameSeq", frame.MsgIncr)
+			break
+		}
+	}
+	old.replaced = true
+	ws.clients.Replace(client.UserID, old, client)
+	old.subLock.Lock()
+	subUserIDs := datautil.Keys(old.subUserIDs)
+	old.subLock.Unlock()
+	ws.subscription.DelClient(old)
+	ws.subscription.Sub(client, subUserIDs, nil)
+	// the client may notice the drop before the gateway does
+	old.close()
+	log.ZInfo(client.ctx, "session resumed", "sessionID", id, "replayFrames", len(frames), "old remote addr", old.ctx.GetRemoteAddr())
+	return true
+}
+
+// detachSession keeps the dropped client registered until the session ttl passes, so pushes meanwhile are kept for the resume.
+// It returns false once the client is to be unregistered.
+func (ws *WsServer) detachSession(client *Client) bool {
+	s := client.session
+	if s == nil {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.owner != client {
+		return false
+	}
+	if s.closed || s.detached || ws.draining.Load() || !ws.isRegistered(client) {
+		s.closed = true
+		s.timer = nil
+		ws.sessions.remove(s)
+		return false
+	}
+	s.detached = true
+	s.timer = time.AfterFunc(ws.sessions.ttl, func() {
+		ws.UnRegister(client)
+	})
+	log.ZDebug(client.ctx, "session detached", "sessionID", s.id, "close reason", client.closedErr)
+	return true
+}
+
+func (ws *WsServer) isRegistered(client *Client) bool {
+	clients, _, _ := ws.clients.Get(client.UserID, client.PlatformID)
+	for _, c := range clients {
+		if c == client {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package msggateway
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSessionReplay(t *testing.T) {
+	owner := &Client{UserID: "user1"}
+	// a detached owner, the frames are only kept
+	owner.closed.Store(true)
+	s := newSession(owner, 3)
+	if frames, ok := s.since(0); !ok || len(frames) != 0 {
+		t.Fatalf("since empty %v %v", frames, ok)
+	}
+	for i := 1; i <= 5; i++ {
+		if err := s.push(Resp{ReqIdentifier: WSPushMsg, Data: []byte{byte(i)}}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	frames, ok := s.since(3)
+	if !ok || len(frames) != 2 {
+		t.Fatalf("since 3 %v %v", frames, ok)
+	}
+	for i, frame := range frames {
+		if frame.MsgIncr != strconv.Itoa(4+i) || frame.Data[0] != byte(4+i) {
+			t.Fatalf("frame %d %+v", i, frame)
+		}
+	}
+	if frames, ok := s.since(2); !ok || len(frames) != 3 || frames[0].MsgIncr != "3" {
+		t.Fatalf("since 2 %v %v", frames, ok)
+	}
+	if _, ok := s.since(1); ok {
+		t.Fatal("frame 2 is no longer kept")
+	}
+	if frames, ok := s.since(5); !ok || len(frames) != 0 {
+		t.Fatalf("since 5 %v %v", frames, ok)
+	}
+	if _, ok := s.since(6); ok {
+		t.Fatal("frame 6 was never pushed")
+	}
+}
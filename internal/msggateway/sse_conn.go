@@ -0,0 +1,195 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/tools/log"
+)
+
+const (
+	// SSEConnEvent is the first event of the stream, its data is the connID the requests are posted with.
+	SSEConnEvent = "conn"
+	// SSECloseEvent ends the stream, its data is the close code and reason.
+	SSECloseEvent = "close"
+)
+
+// sseReadQueueSize is the number of posted requests waiting to be handled.
+const sseReadQueueSize = 16
+
+// sseClientConn carries the frames over a server-sent events stream, each frame is the base64 of a websocket binary message,
+// and the requests are posted to the gateway as the body of separate http requests.
+type sseClientConn struct {
+	w             http.ResponseWriter
+	rc            *http.ResponseController
+	token         string
+	reader        chan []byte
+	writer        chan []byte
+	dropThreshold int
+	done          chan struct{}
+	err           atomic.Pointer[error]
+}
+
+func newSSEClientConn(w http.ResponseWriter, token string, queueSize int, dropThreshold int) *sseClientConn {
+	if queueSize <= 0 {
+		queueSize = defaultWriteQueueSize
+	}
+	if dropThreshold <= 0 || dropThreshold > queueSize {
+		dropThreshold = queueSize / 2
+	}
+	return &sseClientConn{
+		w:             w,
+		rc:            http.NewResponseController(w),
+		token:         token,
+		reader:        make(chan []byte, sseReadQueueSize),
+		writer:        make(chan []byte, queueSize),
+		dropThreshold: dropThreshold,
+		done:          make(chan struct{}),
+	}
+}
+
+func (c *sseClientConn) ReadMessage() ([]byte, error) {
+	select {
+	case message := <-c.reader:
+		return message, nil
+	case <-c.done:
+		return nil, *c.err.Load()
+	}
+}
+
+func (c *sseClientConn) WriteMessage(message []byte) error {
+	if errPtr := c.err.Load(); errPtr != nil {
+		return *errPtr
+	}
+	select {
+	case c.writer <- message:
+		prommetrics.WsWriteQueueDepthHistogram.Observe(float64(len(c.writer)))
+		return nil
+	default:
+		prommetrics.WsSlowConsumerEvictedCounter.Inc()
+		return c.closeBy(ErrWriteFull)
+	}
+}
+
+func (c *sseClientConn) WriteDroppableMessage(message []byte) error {
+	if len(c.writer) >= c.dropThreshold {
+		prommetrics.WsWriteDroppedCounter.Inc()
+		return nil
+	}
+	return c.WriteMessage(message)
+}
+
+func (c *sseClientConn) Close() error {
+	return c.closeBy(fmt.Errorf("sse connection closed"))
+}
+
+func (c *sseClientConn) closeBy(err error) error {
+	if !c.err.CompareAndSwap(nil, &err) {
+		return *c.err.Load()
+	}
+	close(c.done)
+	log.ZWarn(context.Background(), "sse connection closed", err, "chan length", len(c.writer))
+	return err
+}
+
+// post hands a request posted by the client to the read loop.
+func (c *sseClientConn) post(ctx context.Context, message []byte) error {
+	select {
+	case c.reader <- message:
+		return nil
+	case <-c.done:
+		return *c.err.Load()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serve writes the stream until the connection is closed or the client goes away, it owns the response writer.
+func (c *sseClientConn) serve(ctx context.Context, connID string) {
+	connData, _ := json.Marshal(map[string]string{ConnID: connID})
+	if err := c.send(SSEConnEvent, connData); err != nil {
+		_ = c.closeBy(err)
+		return
+	}
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = c.closeBy(fmt.Errorf("sse client gone %w", context.Cause(ctx)))
+			return
+		case <-c.done:
+			if errors.Is(*c.err.Load(), ErrWriteFull) {
+				closeData, _ := json.Marshal(map[string]any{"code": CloseSlowConsumer, "reason": "slow consumer"})
+				_ = c.send(SSECloseEvent, closeData)
+				return
+			}
+			for {
+				select {
+				case message := <-c.writer:
+					if err := c.send("", message); err != nil {
+						return
+					}
+				default:
+					return
+				}
+			}
+		case message := <-c.writer:
+			if err := c.send("", message); err != nil {
+				_ = c.closeBy(err)
+				return
+			}
+		case <-ticker.C:
+			// a comment keeps proxies from closing the idle stream and finds the clients that are gone
+			if err := c.write([]byte(": ping\n\n")); err != nil {
+				_ = c.closeBy(err)
+				return
+			}
+		}
+	}
+}
+
+func (c *sseClientConn) send(event string, data []byte) error {
+	var buf []byte
+	if event != "" {
+		buf = append(buf, "event: "+event+"\n"...)
+		buf = append(buf, "data: "...)
+		buf = append(buf, data...)
+	} else {
+		buf = append(buf, "data: "...)
+		buf = base64.StdEncoding.AppendEncode(buf, data)
+	}
+	buf = append(buf, "\n\n"...)
+	return c.write(buf)
+}
+
+func (c *sseClientConn) write(buf []byte) error {
+	if err := c.rc.SetWriteDeadline(time.Now().Add(writeWait)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := c.w.Write(buf); err != nil {
+		return err
+	}
+	return c.rc.Flush()
+}
@@ -0,0 +1,137 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/tools/apiresp"
+	"github.com/openimsdk/tools/log"
+)
+
+// The server-sent events transport serves the clients whose network does not let websockets through.
+// The stream is opened with GET ssePath and the same query as the websocket handshake, the frames a websocket
+// would receive arrive as base64 data events, and the request frames are posted as the raw body of POST sseSendPath
+// with the connID of the conn event and the token in the query.
+const (
+	ssePath     = "/sse"
+	sseSendPath = "/sse/send"
+)
+
+func (ws *WsServer) sseHandler(w http.ResponseWriter, r *http.Request) {
+	ws.setCORSHeader(w, r)
+	connContext := newContext(w, r)
+	if err := ws.checkConn(connContext); err != nil {
+		httpError(connContext, err)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// keeps nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	log.ZDebug(connContext, "new sse conn", "token", connContext.GetToken())
+
+	conn := newSSEClientConn(w, connContext.GetToken(), ws.writeQueueSize, ws.writeQueueDrop)
+	connID := ws.storeSSEConn(conn)
+	defer ws.sseConns.Delete(connID)
+	connContext.ConnID = connID
+
+	client := new(Client)
+	client.ResetClient(connContext, conn, ws)
+	ws.registerChan <- client
+	go client.readMessage()
+	conn.serve(r.Context(), connID)
+}
+
+func (ws *WsServer) sseSendHandler(w http.ResponseWriter, r *http.Request) {
+	ws.setCORSHeader(w, r)
+	switch r.Method {
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case http.MethodPost:
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	value, ok := ws.sseConns.Load(query.Get(ConnID))
+	if !ok {
+		apiresp.HttpError(w, servererrs.ErrConnArgsErr.WrapMsg("sse conn not found", "connID", query.Get(ConnID)))
+		return
+	}
+	conn := value.(*sseClientConn)
+	token := query.Get(Token)
+	if token == "" {
+		token = r.Header.Get(Token)
+	}
+	if token != conn.token {
+		apiresp.HttpError(w, servererrs.ErrTokenInvalid.WrapMsg("token is not the token of the sse conn"))
+		return
+	}
+	message, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		apiresp.HttpError(w, servererrs.ErrConnArgsErr.WrapMsg("read body failed", "err", err.Error()))
+		return
+	}
+	if err := conn.post(r.Context(), message); err != nil {
+		apiresp.HttpError(w, servererrs.ErrConnArgsErr.WrapMsg("sse conn closed", "err", err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(wsSuccessResponse)
+}
+
+// storeSSEConn stores conn under a new random connID. The connID addresses the conn in the send requests, so it
+// must not be guessable nor shared with another conn.
+func (ws *WsServer) storeSSEConn(conn *sseClientConn) string {
+	for {
+		connID := newSSEConnID()
+		if _, loaded := ws.sseConns.LoadOrStore(connID, conn); !loaded {
+			return connID
+		}
+	}
+}
+
+func newSSEConnID() string {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(data)
+}
+
+// setCORSHeader lets the browser clients of the allowed origins use the transport, no origin is allowed by default.
+func (ws *WsServer) setCORSHeader(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !(slices.Contains(ws.sseAllowOrigins, origin) || slices.Contains(ws.sseAllowOrigins, "*")) {
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", origin)
+	header.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	header.Set("Access-Control-Allow-Headers", "*")
+}
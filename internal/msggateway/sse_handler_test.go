@@ -0,0 +1,73 @@
+package msggateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSECORSHeader(t *testing.T) {
+	cases := []struct {
+		name    string
+		allow   []string
+		origin  string
+		allowed string
+	}{
+		{name: "none allowed by default", origin: "https://im.example.com"},
+		{name: "allowed origin", allow: []string{"https://im.example.com"}, origin: "https://im.example.com", allowed: "https://im.example.com"},
+		{name: "other origin", allow: []string{"https://im.example.com"}, origin: "https://evil.example.com"},
+		{name: "any origin", allow: []string{"*"}, origin: "https://evil.example.com", allowed: "https://evil.example.com"},
+		{name: "no origin", allow: []string{"*"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ws := &WsServer{sseAllowOrigins: c.allow}
+			r := httptest.NewRequest(http.MethodOptions, sseSendPath, nil)
+			if c.origin != "" {
+				r.Header.Set("Origin", c.origin)
+			}
+			w := httptest.NewRecorder()
+			ws.sseSendHandler(w, r)
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status %d", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != c.allowed {
+				t.Fatalf("allow origin %q, want %q", got, c.allowed)
+			}
+			if got := w.Header().Get("Vary"); got != "Origin" {
+				t.Fatalf("vary %q", got)
+			}
+		})
+	}
+}
+
+func TestSSEConnID(t *testing.T) {
+	ws := &WsServer{}
+	ids := make(map[string]struct{})
+	for i := 0; i < 1000; i++ {
+		conn := &sseClientConn{}
+		connID := ws.storeSSEConn(conn)
+		if len(connID) != 32 || strings.Trim(connID, "0123456789abcdef") != "" {
+			t.Fatalf("connID %q", connID)
+		}
+		if _, ok := ids[connID]; ok {
+			t.Fatalf("connID %q reused", connID)
+		}
+		ids[connID] = struct{}{}
+		if value, ok := ws.sseConns.Load(connID); !ok || value.(*sseClientConn) != conn {
+			t.Fatalf("conn of %q not stored", connID)
+		}
+	}
+}
+
+func TestSSESendUnknownConn(t *testing.T) {
+	ws := &WsServer{}
+	ws.storeSSEConn(&sseClientConn{token: "token"})
+	r := httptest.NewRequest(http.MethodPost, sseSendPath+"?connID=guessed&token=token", strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+	ws.sseSendHandler(w, r)
+	if strings.Contains(w.Body.String(), string(wsSuccessResponse)) {
+		t.Fatalf("send to unknown conn accepted: %s", w.Body.String())
+	}
+}
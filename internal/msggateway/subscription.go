@@ -2,20 +2,28 @@ package msggateway
 
 import (
 	"context"
+	"sync"
+
 	"github.com/openimsdk/protocol/sdkws"
 	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/utils/datautil"
 	"google.golang.org/protobuf/proto"
-	"sync"
 )
 
-func (ws *WsServer) subscriberUserOnlineStatusChanges(ctx context.Context, userID string, platformIDs []int32) {
-	if ws.clients.RecvSubChange(userID, platformIDs) {
-		log.ZDebug(ctx, "gateway receive subscription message and go back online", "userID", userID, "platformIDs", platformIDs)
-	} else {
-		log.ZDebug(ctx, "gateway ignore user online status changes", "userID", userID, "platformIDs", platformIDs)
+type onlineChange struct {
+	userID      string
+	platformIDs []int32
+}
+
+func (ws *WsServer) subscriberUserOnlineStatusChanges(ctx context.Context, changes []onlineChange) {
+	for _, change := range changes {
+		if ws.clients.RecvSubChange(change.userID, change.platformIDs) {
+			log.ZDebug(ctx, "gateway receive subscription message and go back online", "userID", change.userID, "platformIDs", change.platformIDs)
+		} else {
+			log.ZDebug(ctx, "gateway ignore user online status changes", "userID", change.userID, "platformIDs", change.platformIDs)
+		}
 	}
-	ws.pushUserIDOnlineStatus(ctx, userID, platformIDs)
+	ws.pushUserIDOnlineStatus(ctx, changes)
 }
 
 func (ws *WsServer) SubUserOnlineStatus(ctx context.Context, client *Client, data *Req) ([]byte, error) {
@@ -23,18 +31,29 @@ func (ws *WsServer) SubUserOnlineStatus(ctx context.Context, client *Client, dat
 	if err := proto.Unmarshal(data.Data, &sub); err != nil {
 		return nil, err
 	}
+	// watched before the status is read, so no change in between is missed
+	if err := ws.onlineSub.Watch(ctx, ws.nodeID, sub.SubscribeUserID); err != nil {
+		return nil, err
+	}
 	ws.subscription.Sub(client, sub.SubscribeUserID, sub.UnsubscribeUserID)
+	if err := ws.pushSubscribedPresence(ctx, client, sub.SubscribeUserID); err != nil {
+		log.ZWarn(ctx, "push subscribed presence failed", err)
+	}
 	var resp sdkws.SubUserOnlineStatusTips
 	if len(sub.SubscribeUserID) > 0 {
+		status, err := ws.userClient.GetUsersOnlinePlatform(ctx, sub.SubscribeUserID)
+		if err != nil {
+			return nil, err
+		}
+		platformIDs := make(map[string][]int32, len(status))
+		for _, s := range status {
+			platformIDs[s.UserID] = s.PlatformIDs
+		}
 		resp.Subscribers = make([]*sdkws.SubUserOnlineStatusElem, 0, len(sub.SubscribeUserID))
 		for _, userID := range sub.SubscribeUserID {
-			platformIDs, err := ws.online.GetUserOnlinePlatform(ctx, userID)
-			if err != nil {
-				return nil, err
-			}
 			resp.Subscribers = append(resp.Subscribers, &sdkws.SubUserOnlineStatusElem{
 				UserID:            userID,
-				OnlinePlatformIDs: platformIDs,
+				OnlinePlatformIDs: platformIDs[userID],
 			})
 		}
 	}
@@ -146,21 +165,34 @@ func (s *Subscription) Sub(client *Client, addUserIDs, delUserIDs []string) {
 	}
 }
 
-func (ws *WsServer) pushUserIDOnlineStatus(ctx context.Context, userID string, platformIDs []int32) {
-	clients := ws.subscription.GetClient(userID)
-	if len(clients) == 0 {
-		return
-	}
-	onlineStatus, err := proto.Marshal(&sdkws.SubUserOnlineStatusTips{
-		Subscribers: []*sdkws.SubUserOnlineStatusElem{{UserID: userID, OnlinePlatformIDs: platformIDs}},
-	})
-	if err != nil {
-		log.ZError(ctx, "pushUserIDOnlineStatus json.Marshal", err)
-		return
+// UserIDs returns the users subscribed to.
+func (s *Subscription) UserIDs() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return datautil.Keys(s.userIDs)
+}
+
+// pushUserIDOnlineStatus pushes the changes to the subscribers, the changes a client subscribed to in one frame.
+func (ws *WsServer) pushUserIDOnlineStatus(ctx context.Context, changes []onlineChange) {
+	tips := make(map[*Client]*sdkws.SubUserOnlineStatusTips)
+	for _, change := range changes {
+		for _, client := range ws.subscription.GetClient(change.userID) {
+			tip, ok := tips[client]
+			if !ok {
+				tip = &sdkws.SubUserOnlineStatusTips{}
+				tips[client] = tip
+			}
+			tip.Subscribers = append(tip.Subscribers, &sdkws.SubUserOnlineStatusElem{UserID: change.userID, OnlinePlatformIDs: change.platformIDs})
+		}
 	}
-	for _, client := range clients {
+	for client, tip := range tips {
+		onlineStatus, err := proto.Marshal(tip)
+		if err != nil {
+			log.ZError(ctx, "pushUserIDOnlineStatus proto.Marshal", err)
+			return
+		}
 		if err := client.PushUserOnlineStatus(onlineStatus); err != nil {
-			log.ZError(ctx, "UserSubscribeOnlineStatusNotification push failed", err, "userID", client.UserID, "platformID", client.PlatformID, "changeUserID", userID, "changePlatformID", platformIDs)
+			log.ZError(ctx, "UserSubscribeOnlineStatusNotification push failed", err, "userID", client.UserID, "platformID", client.PlatformID, "changes", len(tip.Subscribers))
 		}
 	}
 }
@@ -11,6 +11,9 @@ type UserMap interface {
 	Get(userID string, platformID int) ([]*Client, bool, bool)
 	Set(userID string, v *Client)
 	DeleteClients(userID string, clients []*Client) (isDeleteUser bool)
+	// Replace swaps in the client resuming the session of old without any online state change.
+	Replace(userID string, old, client *Client) bool
+	GetAllClients() []*Client
 	UserState() <-chan UserState
 	GetAllUserStatus(deadline time.Time, nowtime time.Time) []UserState
 	RecvSubChange(userID string, platformIDs []int32) bool
@@ -131,6 +134,32 @@ func (u *userMap) Set(userID string, client *Client) {
 	u.push(client.UserID, result, nil)
 }
 
+func (u *userMap) Replace(userID string, old, client *Client) bool {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	result, ok := u.data[userID]
+	if !ok {
+		return false
+	}
+	for i, c := range result.Clients {
+		if c == old {
+			result.Clients[i] = client
+			return true
+		}
+	}
+	return false
+}
+
+func (u *userMap) GetAllClients() []*Client {
+	u.lock.RLock()
+	defer u.lock.RUnlock()
+	clients := make([]*Client, 0, len(u.data))
+	for _, userPlatform := range u.data {
+		clients = append(clients, userPlatform.Clients...)
+	}
+	return clients
+}
+
 func (u *userMap) DeleteClients(userID string, clients []*Client) (isDeleteUser bool) {
 	if len(clients) == 0 {
 		return false
@@ -4,17 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcli"
 	"github.com/openimsdk/tools/apiresp"
+	"github.com/redis/go-redis/v9"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/mcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/webhook"
-	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/gatewayext"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
 	pbAuth "github.com/openimsdk/protocol/auth"
 	"github.com/openimsdk/tools/mcontext"
 
@@ -41,34 +49,66 @@ type LongConnServer interface {
 	UnRegister(c *Client)
 	SetKickHandlerInfo(i *kickHandler)
 	SubUserOnlineStatus(ctx context.Context, client *Client, data *Req) ([]byte, error)
+	GetCompressor(protocol string) (Compressor, bool)
+	Drain()
+	LimitReq(ctx context.Context, client *Client, req *Req) error
+	SendEphemeralEvent(ctx context.Context, client *Client, data *Req) ([]byte, error)
+	PushEphemeralEvent(ctx context.Context, event *gatewayext.EphemeralEvent, userIDs []string)
+	NodeInfo() *gatewayext.GatewayNode
 	Compressor
 	MessageHandler
 }
 
 type WsServer struct {
-	websocket         *websocket.Upgrader
-	msgGatewayConfig  *Config
-	port              int
-	wsMaxConnNum      int64
-	registerChan      chan *Client
-	unregisterChan    chan *Client
-	kickHandlerChan   chan *kickHandler
-	clients           UserMap
-	online            rpccache.OnlineCache
-	subscription      *Subscription
-	clientPool        sync.Pool
-	onlineUserNum     atomic.Int64
-	onlineUserConnNum atomic.Int64
-	handshakeTimeout  time.Duration
-	writeBufferSize   int
-	validate          *validator.Validate
-	disCov            discovery.Conn
+	websocket                 *websocket.Upgrader
+	msgGatewayConfig          *Config
+	port                      int
+	nodeID                    string
+	wsMaxConnNum              int64
+	registerChan              chan *Client
+	unregisterChan            chan *Client
+	kickHandlerChan           chan *kickHandler
+	clients                   UserMap
+	onlineSub                 cache.OnlineSubCache
+	onlineWatchPending        onlineWatchQueue
+	subscription              *Subscription
+	clientPool                sync.Pool
+	onlineUserNum             atomic.Int64
+	onlineUserConnNum         atomic.Int64
+	handshakeTimeout          time.Duration
+	writeBufferSize           int
+	writeQueueSize            int
+	writeQueueDrop            int
+	validate                  *validator.Validate
+	disCov                    discovery.Conn
+	compressors               map[string]Compressor
+	sessions                  *sessionManager
+	sseConns                  sync.Map // connID -> *sseClientConn
+	sseAllowOrigins           []string
+	drainTimeout              time.Duration
+	drainReconnectDelay       time.Duration
+	drainCh                   chan struct{}
+	drainOnce                 sync.Once
+	draining                  atomic.Bool
+	reqLimiter                *reqLimiter
+	ephemeralLimiter          cache.RateLimitCache
+	ephemeralRate             float64
+	ephemeralBurst            int
+	ephemeralMaxContentLength int
+	ephemeralGroupCache       ephemeralGroupCache
+	ephemeralFriendCache      ephemeralFriendCache
+	// rdb invalidates the local caches
+	rdb redis.UniversalClient
 	Compressor
 	//Encoder
 	MessageHandler
 	webhookClient *webhook.Client
 	userClient    *rpcli.UserClient
 	authClient    *rpcli.AuthClient
+	groupClient   *rpcli.GroupClient
+	userExtClient userext.UserExtClient
+	// presenceExpiry pushes the clears of the expired presences
+	presenceExpiry *presenceExpiry
 
 	ready atomic.Bool
 }
@@ -96,8 +136,20 @@ func (ws *WsServer) SetDiscoveryRegistry(ctx context.Context, disCov discovery.C
 	if err != nil {
 		return err
 	}
+	groupConn, err := disCov.GetConn(ctx, config.Discovery.RpcService.Group)
+	if err != nil {
+		return err
+	}
+	friendConn, err := disCov.GetConn(ctx, config.Discovery.RpcService.Friend)
+	if err != nil {
+		return err
+	}
 	ws.userClient = rpcli.NewUserClient(userConn)
 	ws.authClient = rpcli.NewAuthClient(authConn)
+	ws.groupClient = rpcli.NewGroupClient(groupConn)
+	ws.ephemeralGroupCache = rpccache.NewGroupLocalCache(ws.groupClient, &config.LocalCacheConfig, ws.rdb)
+	ws.ephemeralFriendCache = rpccache.NewFriendLocalCache(rpcli.NewRelationClient(friendConn), &config.LocalCacheConfig, ws.rdb)
+	ws.userExtClient = userext.NewUserExtClient(userConn)
 	ws.MessageHandler = NewGrpcHandler(ws.validate, rpcli.NewMsgClient(msgConn), rpcli.NewPushMsgServiceClient(pushConn))
 	ws.disCov = disCov
 
@@ -134,6 +186,15 @@ func (ws *WsServer) GetUserPlatformCons(userID string, platform int) ([]*Client,
 	return ws.clients.Get(userID, platform)
 }
 
+// GetCompressor returns the compressor negotiated for the given protocol.
+func (ws *WsServer) GetCompressor(protocol string) (Compressor, bool) {
+	if protocol == GzipCompressionProtocol {
+		return ws.Compressor, true
+	}
+	compressor, ok := ws.compressors[protocol]
+	return compressor, ok
+}
+
 func NewWsServer(msgGatewayConfig *Config, opts ...Option) *WsServer {
 	var config configs
 	for _, o := range opts {
@@ -145,13 +206,34 @@ func NewWsServer(msgGatewayConfig *Config, opts ...Option) *WsServer {
 		CheckOrigin:      func(r *http.Request) bool { return true },
 	}
 	v := validator.New()
-	return &WsServer{
-		websocket:        upgrader,
-		msgGatewayConfig: msgGatewayConfig,
-		port:             config.port,
-		wsMaxConnNum:     config.maxConnNum,
-		writeBufferSize:  config.writeBufferSize,
-		handshakeTimeout: config.handshakeTimeout,
+	ephemeralMaxContentLength := config.ephemeralMaxContentLength
+	if ephemeralMaxContentLength <= 0 {
+		ephemeralMaxContentLength = defaultEphemeralContentLength
+	}
+	hostname, _ := os.Hostname()
+	var sessions *sessionManager
+	if config.sessionBufferSize > 0 {
+		sessions = newSessionManager(config.sessionBufferSize, config.sessionTTL)
+	}
+	ws := &WsServer{
+		websocket:                 upgrader,
+		msgGatewayConfig:          msgGatewayConfig,
+		port:                      config.port,
+		nodeID:                    net.JoinHostPort(hostname, strconv.Itoa(config.port)),
+		wsMaxConnNum:              config.maxConnNum,
+		writeBufferSize:           config.writeBufferSize,
+		writeQueueSize:            config.writeQueueSize,
+		writeQueueDrop:            config.writeQueueDropThreshold,
+		handshakeTimeout:          config.handshakeTimeout,
+		drainTimeout:              config.drainTimeout,
+		drainReconnectDelay:       config.drainReconnectDelay,
+		drainCh:                   make(chan struct{}),
+		reqLimiter:                config.reqLimiter,
+		ephemeralLimiter:          mcache.NewRateLimit(),
+		ephemeralRate:             config.ephemeralRate,
+		ephemeralBurst:            config.ephemeralBurst,
+		ephemeralMaxContentLength: ephemeralMaxContentLength,
+		sseAllowOrigins:           config.sseAllowOrigins,
 		clientPool: sync.Pool{
 			New: func() any {
 				return new(Client)
@@ -163,19 +245,25 @@ func NewWsServer(msgGatewayConfig *Config, opts ...Option) *WsServer {
 		validate:        v,
 		clients:         newUserMap(),
 		subscription:    newSubscription(),
+		compressors:     config.compressors,
+		sessions:        sessions,
 		Compressor:      NewGzipCompressor(),
 		webhookClient:   webhook.NewWebhookClient(msgGatewayConfig.WebhooksConfig.URL),
 	}
+	ws.presenceExpiry = newPresenceExpiry(ws.pushPresenceExpired)
+	return ws
 }
 
 func (ws *WsServer) Run(ctx context.Context) error {
 	var client *Client
 
-	ctx, cancel := context.WithCancelCause(ctx)
+	// the clients keep registering and unregistering while the gateway drains
+	loopCtx, stopLoop := context.WithCancel(context.Background())
+	defer stopLoop()
 	go func() {
 		for {
 			select {
-			case <-ctx.Done():
+			case <-loopCtx.Done():
 				return
 			case client = <-ws.registerChan:
 				ws.registerClient(client)
@@ -187,15 +275,20 @@ func (ws *WsServer) Run(ctx context.Context) error {
 		}
 	}()
 
-	done := make(chan struct{})
+	ctx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-ws.drainCh:
+			cancel(fmt.Errorf("msg gateway drained"))
+		}
+	}()
+
+	wsServer := http.Server{Addr: fmt.Sprintf(":%d", ws.port), Handler: nil}
+	http.HandleFunc("/", ws.wsHandler)
+	http.HandleFunc(ssePath, ws.sseHandler)
+	http.HandleFunc(sseSendPath, ws.sseSendHandler)
 	go func() {
-		wsServer := http.Server{Addr: fmt.Sprintf(":%d", ws.port), Handler: nil}
-		http.HandleFunc("/", ws.wsHandler)
-		go func() {
-			defer close(done)
-			<-ctx.Done()
-			_ = wsServer.Shutdown(context.Background())
-		}()
 		err := wsServer.ListenAndServe()
 		if err == nil {
 			err = fmt.Errorf("http server closed")
@@ -205,6 +298,14 @@ func (ws *WsServer) Run(ctx context.Context) error {
 
 	<-ctx.Done()
 
+	// the server keeps running while draining, the requests of the connections still open are served
+	ws.drain(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = wsServer.Shutdown(context.Background())
+	}()
+
 	timeout := time.NewTimer(time.Second * 15)
 	defer timeout.Stop()
 	select {
@@ -266,6 +367,12 @@ func (ws *WsServer) registerClient(client *Client) {
 		clientOK   bool
 		oldClients []*Client
 	)
+	if ws.sessions != nil {
+		if ws.resumeSession(client) {
+			return
+		}
+		ws.openSession(client)
+	}
 	oldClients, userOK, clientOK = ws.clients.Get(client.UserID, client.PlatformID)
 
 	log.ZInfo(client.ctx, "registerClient", "userID", client.UserID, "platformID", client.PlatformID)
@@ -294,6 +401,8 @@ func (ws *WsServer) registerClient(client *Client) {
 	wg := sync.WaitGroup{}
 	log.ZDebug(client.ctx, "ws.msgGatewayConfig.Discovery.Enable", "discoveryEnable", ws.msgGatewayConfig.Discovery.Enable)
 
+	ws.watchOnlineLater(client.UserID)
+
 	if ws.msgGatewayConfig.Discovery.Enable != "k8s" {
 		wg.Add(1)
 		go func() {
@@ -437,6 +546,14 @@ func (ws *WsServer) multiTerminalLoginChecker(clientOK bool, oldClients []*Clien
 }
 
 func (ws *WsServer) unregisterClient(client *Client) {
+	if client.replaced {
+		// its place was taken by the connection resuming the session
+		ws.subscription.DelClient(client)
+		return
+	}
+	if ws.sessions != nil && ws.detachSession(client) {
+		return
+	}
 	defer ws.clientPool.Put(client)
 	isDeleteUser := ws.clients.DeleteClients(client.UserID, []*Client{client})
 	if isDeleteUser {
@@ -488,36 +605,44 @@ func (ws *WsServer) handlerError(ctx *UserConnContext, w http.ResponseWriter, r
 	}
 }
 
-func (ws *WsServer) wsHandler(w http.ResponseWriter, r *http.Request) {
-	// Create a new connection context
-	connContext := newContext(w, r)
+// checkConn authenticates a new connection, whatever the transport it comes over.
+func (ws *WsServer) checkConn(connContext *UserConnContext) error {
+	if ws.draining.Load() {
+		return servererrs.ErrConnServerDraining.WrapMsg("msg gateway is draining, connect to another one")
+	}
 
 	// Check if the current number of online user connections exceeds the maximum limit
 	if ws.onlineUserConnNum.Load() >= ws.wsMaxConnNum {
-		// If it exceeds the maximum connection number, return an error via HTTP and stop processing
-		ws.handlerError(connContext, w, r, servererrs.ErrConnOverMaxNumLimit.WrapMsg("over max conn num limit"))
-		return
+		return servererrs.ErrConnOverMaxNumLimit.WrapMsg("over max conn num limit")
 	}
 
 	// Parse essential arguments (e.g., user ID, Token)
-	err := connContext.ParseEssentialArgs()
-	if err != nil {
-		// If there's an error during parsing, return an error via HTTP and stop processing
-		ws.handlerError(connContext, w, r, err)
-		return
+	if err := connContext.ParseEssentialArgs(); err != nil {
+		return err
+	}
+
+	if protocol := connContext.GetCompressionProtocol(); protocol != "" {
+		if _, ok := ws.GetCompressor(protocol); !ok {
+			return servererrs.ErrConnArgsErr.WrapMsg("compression is not supported", "compression", protocol)
+		}
 	}
 
 	// Call the authentication client to parse the Token obtained from the context
 	resp, err := ws.authClient.ParseToken(connContext, connContext.GetToken())
 	if err != nil {
-		ws.handlerError(connContext, w, r, err)
-		return
+		return err
 	}
 
 	// Validate the authentication response matches the request (e.g., user ID and platform ID)
-	err = ws.validateRespWithRequest(connContext, resp)
-	if err != nil {
-		// If validation fails, return an error via HTTP and stop processing
+	return ws.validateRespWithRequest(connContext, resp)
+}
+
+func (ws *WsServer) wsHandler(w http.ResponseWriter, r *http.Request) {
+	// Create a new connection context
+	connContext := newContext(w, r)
+
+	if err := ws.checkConn(connContext); err != nil {
+		// If the connection is refused, return an error via HTTP and stop processing
 		ws.handlerError(connContext, w, r, err)
 		return
 	}
@@ -541,7 +666,7 @@ func (ws *WsServer) wsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := new(Client)
-	client.ResetClient(connContext, NewWebSocketClientConn(conn, maxMessageSize, pongWait, pingInterval), ws)
+	client.ResetClient(connContext, NewWebSocketClientConn(conn, maxMessageSize, pongWait, pingInterval, ws.writeQueueSize, ws.writeQueueDrop), ws)
 
 	// Register the client with the server and start message processing
 	ws.registerChan <- client
@@ -24,6 +24,7 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
 	"github.com/openimsdk/open-im-server/v3/pkg/dbbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/mqbuild"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
 	"github.com/openimsdk/tools/discovery"
 	"github.com/openimsdk/tools/mq"
 	"github.com/openimsdk/tools/utils/runtimeenv"
@@ -54,6 +55,7 @@ type Config struct {
 	KafkaConfig    conf.Kafka
 	Share          conf.Share
 	WebhooksConfig conf.Webhooks
+	MsgSearch      conf.MsgSearch
 	Discovery      conf.Discovery
 	Index          conf.Index
 }
@@ -122,6 +124,20 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
+	msgThreadModel, err := mgo.NewMsgThreadMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	msgThreadDatabase := controller.NewMsgThreadDatabase(msgThreadModel, msgDocModel, msgModel)
+	var msgSearchIndex msgsearch.MsgSearchIndex
+	if config.MsgSearch.Enable {
+		searchIndex, err := msgsearch.NewMsgSearchIndex(ctx, &config.MsgSearch, mgocli.GetDB())
+		if err != nil {
+			return err
+		}
+		go searchIndex.RunPending(ctx)
+		msgSearchIndex = searchIndex
+	}
 	historyConsumer, err := builder.GetTopicConsumer(ctx, config.KafkaConfig.ToRedisTopic)
 	if err != nil {
 		return err
@@ -134,7 +150,7 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
-	historyMongoHandler := NewOnlineHistoryMongoConsumerHandler(msgTransferDatabase, config)
+	historyMongoHandler := NewOnlineHistoryMongoConsumerHandler(msgTransferDatabase, msgThreadDatabase, msgSearchIndex, config)
 
 	msgTransfer := &MsgTransfer{
 		historyConsumer:      historyConsumer,
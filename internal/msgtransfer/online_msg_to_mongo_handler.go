@@ -15,25 +15,38 @@
 package msgtransfer
 
 import (
+	"context"
+	"time"
+
 	"github.com/openimsdk/tools/mq"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/webhook"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
 	pbmsg "github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/tools/log"
 	"google.golang.org/protobuf/proto"
 )
 
+const (
+	afterSaveRetryTimes    = 3
+	afterSaveRetryInterval = time.Second
+)
+
 type OnlineHistoryMongoConsumerHandler struct {
 	msgTransferDatabase controller.MsgTransferDatabase
+	msgThreadDatabase   controller.MsgThreadDatabase
+	msgSearchIndex      msgsearch.MsgSearchIndex
 	config              *Config
 	webhookClient       *webhook.Client
 }
 
-func NewOnlineHistoryMongoConsumerHandler(database controller.MsgTransferDatabase, config *Config) *OnlineHistoryMongoConsumerHandler {
+func NewOnlineHistoryMongoConsumerHandler(database controller.MsgTransferDatabase, threadDatabase controller.MsgThreadDatabase, searchIndex msgsearch.MsgSearchIndex, config *Config) *OnlineHistoryMongoConsumerHandler {
 	return &OnlineHistoryMongoConsumerHandler{
 		msgTransferDatabase: database,
+		msgThreadDatabase:   threadDatabase,
+		msgSearchIndex:      searchIndex,
 		config:              config,
 		webhookClient:       webhook.NewWebhookClient(config.WebhooksConfig.URL),
 	}
@@ -60,6 +73,19 @@ func (mc *OnlineHistoryMongoConsumerHandler) HandleChatWs2Mongo(val mq.Message)
 		prommetrics.MsgInsertMongoFailedCounter.Inc()
 	} else {
 		prommetrics.MsgInsertMongoSuccessCounter.Inc()
+		// replies left unfinished by a failed attempt are finished by the next one
+		if err := retryAfterSave(ctx, func() error {
+			return mc.msgThreadDatabase.AddReplies(ctx, msgFromMQ.ConversationID, msgFromMQ.MsgData)
+		}); err != nil {
+			log.ZError(ctx, "add thread replies err", err, "conversationID", msgFromMQ.ConversationID)
+		}
+		if mc.msgSearchIndex != nil {
+			// the docs the backend fails to index are kept pending and indexed again later
+			docs := msgsearch.NewDocs(msgFromMQ.ConversationID, msgFromMQ.MsgData)
+			if err := retryAfterSave(ctx, func() error { return mc.msgSearchIndex.Index(ctx, docs) }); err != nil {
+				log.ZError(ctx, "index msg for search err", err, "conversationID", msgFromMQ.ConversationID)
+			}
+		}
 		val.Mark()
 	}
 
@@ -76,3 +102,21 @@ func (mc *OnlineHistoryMongoConsumerHandler) HandleChatWs2Mongo(val mq.Message)
 	//		msgFromMQ.MsgData, "conversationID", msgFromMQ.ConversationID)
 	//}
 }
+
+// retryAfterSave retries what follows saving a batch to mongo, the batch is marked consumed after it.
+func retryAfterSave(ctx context.Context, fn func() error) error {
+	var err error
+	for i := 0; i < afterSaveRetryTimes; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(afterSaveRetryInterval * time.Duration(i)):
+			}
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
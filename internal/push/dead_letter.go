@@ -0,0 +1,86 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/pushext"
+	"github.com/openimsdk/tools/errs"
+)
+
+func (p pushServer) checkOfflinePushRetry(ctx context.Context) error {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return err
+	}
+	if p.retryDatabase == nil {
+		return errs.ErrInternalServer.WrapMsg("offline push retry is not enabled")
+	}
+	return nil
+}
+
+func (p pushServer) PageOfflinePushDeadLetters(ctx context.Context, req *pushext.PageOfflinePushDeadLettersReq) (*pushext.PageOfflinePushDeadLettersResp, error) {
+	if err := p.checkOfflinePushRetry(ctx); err != nil {
+		return nil, err
+	}
+	total, retries, err := p.retryDatabase.PageDeadLetters(ctx, req.UserID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pushext.PageOfflinePushDeadLettersResp{Total: total, DeadLetters: make([]*pushext.OfflinePushDeadLetter, 0, len(retries))}
+	for _, retry := range retries {
+		resp.DeadLetters = append(resp.DeadLetters, convertDeadLetter(retry))
+	}
+	return resp, nil
+}
+
+func (p pushServer) RetryOfflinePushDeadLetters(ctx context.Context, req *pushext.RetryOfflinePushDeadLettersReq) (*pushext.RetryOfflinePushDeadLettersResp, error) {
+	if err := p.checkOfflinePushRetry(ctx); err != nil {
+		return nil, err
+	}
+	count, err := p.retryDatabase.RequeueDeadLetters(ctx, req.IDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &pushext.RetryOfflinePushDeadLettersResp{Count: count}, nil
+}
+
+func (p pushServer) DeleteOfflinePushDeadLetters(ctx context.Context, req *pushext.DeleteOfflinePushDeadLettersReq) (*pushext.DeleteOfflinePushDeadLettersResp, error) {
+	if err := p.checkOfflinePushRetry(ctx); err != nil {
+		return nil, err
+	}
+	if err := p.retryDatabase.DeleteRetries(ctx, req.IDs); err != nil {
+		return nil, err
+	}
+	return &pushext.DeleteOfflinePushDeadLettersResp{}, nil
+}
+
+func convertDeadLetter(retry *model.OfflinePushRetry) *pushext.OfflinePushDeadLetter {
+	return &pushext.OfflinePushDeadLetter{
+		ID:          retry.ID.Hex(),
+		UserIDs:     retry.UserIDs,
+		ClientMsgID: retry.ClientMsgID,
+		Title:       retry.Title,
+		Content:     retry.Content,
+		Providers:   retry.Providers,
+		Attempts:    retry.Attempts,
+		LastError:   retry.LastError,
+		CreateTime:  retry.CreateTime.UnixMilli(),
+		UpdateTime:  retry.UpdateTime.UnixMilli(),
+	}
+}
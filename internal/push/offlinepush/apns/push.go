@@ -0,0 +1,307 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	productionURL = "https://api.push.apple.com"
+	sandboxURL    = "https://api.sandbox.push.apple.com"
+
+	// tokenRefresh renews the provider token before APNs rejects it after an hour.
+	tokenRefresh = time.Minute * 50
+	// maxCollapseIDLength is the most bytes APNs accepts in apns-collapse-id.
+	maxCollapseIDLength = 64
+	concurrentPush      = 20
+	pushTimeout         = time.Second * 10
+)
+
+// Terminal are the platforms whose tokens, registered by FcmUpdateToken, are APNs device tokens.
+var Terminal = []int{constant.IOSPlatformID, constant.IPadPlatformID}
+
+// APNs pushes straight to the Apple Push Notification service over HTTP/2, authenticated by a provider token
+// signed with the .p8 key of the team.
+type APNs struct {
+	pushConf   *config.Push
+	cache      cache.ThirdCache
+	httpClient *http.Client
+	url        string
+	key        *ecdsa.PrivateKey
+
+	lock      sync.Mutex
+	token     string
+	tokenTime time.Time
+}
+
+type alert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type aps struct {
+	Alert            *alert `json:"alert,omitempty"`
+	Badge            *int   `json:"badge,omitempty"`
+	Sound            string `json:"sound,omitempty"`
+	ContentAvailable int    `json:"content-available,omitempty"`
+	MutableContent   int    `json:"mutable-content,omitempty"`
+}
+
+type payload struct {
+	Aps         aps    `json:"aps"`
+	Ex          string `json:"ex,omitempty"`
+	ClientMsgID string `json:"ClientMsgID,omitempty"`
+}
+
+// notification is a push to one device, with the headers APNs takes per message.
+type notification struct {
+	pushType   string
+	priority   int
+	collapseID string
+	payload    payload
+}
+
+// apnsError is the reason APNs rejected a push with.
+type apnsError struct {
+	status int
+	reason string
+}
+
+func (e *apnsError) Error() string {
+	return fmt.Sprintf("apns status %d reason %s", e.status, e.reason)
+}
+
+// NewClient loads the .p8 key from the config directory, the production or sandbox environment follows iosPush.production.
+func NewClient(pushConf *config.Push, cache cache.ThirdCache, configPath string) (*APNs, error) {
+	conf := pushConf.APNs
+	if conf.KeyFilePath == "" || conf.KeyID == "" || conf.TeamID == "" || conf.BundleID == "" {
+		return nil, errs.New("no APNs config").Wrap()
+	}
+	data, err := os.ReadFile(filepath.Join(configPath, conf.KeyFilePath))
+	if err != nil {
+		return nil, errs.WrapMsg(err, "read APNs key failed", "keyFilePath", conf.KeyFilePath)
+	}
+	key, err := parseKey(data)
+	if err != nil {
+		return nil, err
+	}
+	url := sandboxURL
+	if pushConf.IOSPush.Production {
+		url = productionURL
+	}
+	return &APNs{
+		pushConf: pushConf,
+		cache:    cache,
+		httpClient: &http.Client{
+			Timeout: pushTimeout,
+			Transport: &http.Transport{
+				ForceAttemptHTTP2:   true,
+				TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS12},
+				MaxIdleConnsPerHost: concurrentPush,
+				IdleConnTimeout:     time.Minute * 10,
+			},
+		},
+		url: url,
+		key: key,
+	}, nil
+}
+
+func parseKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errs.New("APNs key is not PEM encoded").Wrap()
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "parse APNs key failed")
+	}
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, errs.New("APNs key is not an ECDSA key").Wrap()
+	}
+	return ecKey, nil
+}
+
+func (a *APNs) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	var failures failure.Collector
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	for _, userID := range userIDs {
+		tokens := make(map[int]string)
+		for _, platformID := range opts.Terminal(Terminal) {
+			token, err := a.cache.GetFcmToken(ctx, userID, platformID)
+			if err == nil && token != "" {
+				tokens[platformID] = token
+			}
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		n, err := a.notification(ctx, userID, title, content, opts)
+		if err != nil {
+			failures.Add([]string{userID}, 1, err)
+			continue
+		}
+		for platformID, token := range tokens {
+			g.Go(func() error {
+				err := a.send(ctx, token, n)
+				if err == nil {
+					return nil
+				}
+				var apnsErr *apnsError
+				if errors.As(err, &apnsErr) && apnsErr.status == http.StatusGone {
+					// the app was uninstalled or the token is no longer valid
+					log.ZInfo(ctx, "apns token unregistered", "userID", userID, "platformID", platformID)
+					if err := a.cache.DelFcmToken(ctx, userID, platformID); err != nil {
+						log.ZWarn(ctx, "delete apns token failed", err, "userID", userID, "platformID", platformID)
+					}
+					return nil
+				}
+				failures.Add([]string{userID}, 1, err)
+				return nil
+			})
+		}
+	}
+	_ = g.Wait()
+	return failures.Err("apns")
+}
+
+// notification builds the push of a message to the user. A message with neither title nor content is pushed
+// in the background at low priority, and the pushes of the same message collapse into one on the device.
+func (a *APNs) notification(ctx context.Context, userID string, title, content string, opts *options.Opts) (*notification, error) {
+	n := &notification{payload: payload{Ex: opts.Ex}}
+	if opts.Signal != nil && opts.Signal.ClientMsgID != "" {
+		n.payload.ClientMsgID = opts.Signal.ClientMsgID
+		if len(opts.Signal.ClientMsgID) <= maxCollapseIDLength {
+			n.collapseID = opts.Signal.ClientMsgID
+		}
+	}
+	if title == "" && content == "" {
+		n.pushType = "background"
+		n.priority = 5
+		n.payload.Aps.ContentAvailable = 1
+		return n, nil
+	}
+	n.pushType = "alert"
+	n.priority = 10
+	n.payload.Aps.Alert = &alert{Title: title, Body: content}
+	n.payload.Aps.Sound = opts.IOSPushSound
+	n.payload.Aps.MutableContent = 1
+	if opts.IOSBadgeCount {
+		badge, err := a.cache.IncrUserBadgeUnreadCountSum(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		n.payload.Aps.Badge = &badge
+	} else if badge, err := a.cache.GetUserBadgeUnreadCountSum(ctx, userID); err == nil && badge > 0 {
+		n.payload.Aps.Badge = &badge
+	}
+	return n, nil
+}
+
+func (a *APNs) send(ctx context.Context, deviceToken string, n *notification) error {
+	body, err := json.Marshal(&n.payload)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	token, err := a.providerToken()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+"/3/device/"+deviceToken, bytes.NewReader(body))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	req.Header.Set("authorization", "bearer "+token)
+	req.Header.Set("apns-topic", a.pushConf.APNs.BundleID)
+	req.Header.Set("apns-push-type", n.pushType)
+	req.Header.Set("apns-priority", strconv.Itoa(n.priority))
+	if n.collapseID != "" {
+		req.Header.Set("apns-collapse-id", n.collapseID)
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	var reason struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&reason)
+	if resp.StatusCode == http.StatusForbidden && (reason.Reason == "ExpiredProviderToken" || reason.Reason == "InvalidProviderToken") {
+		a.resetProviderToken(token)
+	}
+	return &apnsError{status: resp.StatusCode, reason: reason.Reason}
+}
+
+// providerToken returns the JWT the pushes are authorized with, APNs refuses a token renewed more often than every 20 minutes.
+func (a *APNs) providerToken() (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.token != "" && time.Since(a.tokenTime) < tokenRefresh {
+		return a.token, nil
+	}
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.pushConf.APNs.TeamID,
+		"iat": now.Unix(),
+	})
+	t.Header["kid"] = a.pushConf.APNs.KeyID
+	token, err := t.SignedString(a.key)
+	if err != nil {
+		return "", errs.WrapMsg(err, "sign APNs provider token failed")
+	}
+	a.token = token
+	a.tokenTime = now
+	return token, nil
+}
+
+func (a *APNs) resetProviderToken(token string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.token == token {
+		a.token = ""
+	}
+}
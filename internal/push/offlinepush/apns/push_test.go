@@ -0,0 +1,178 @@
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/protocol/constant"
+)
+
+type apnsRequest struct {
+	path   string
+	header http.Header
+	body   payload
+}
+
+func newTestAPNs(t *testing.T, thirdCache cache.ThirdCache, handler func(w http.ResponseWriter, deviceToken string)) (*APNs, *ecdsa.PrivateKey, chan apnsRequest) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := make(chan apnsRequest, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		req := apnsRequest{path: r.URL.Path, header: r.Header}
+		if err := json.Unmarshal(data, &req.body); err != nil {
+			t.Errorf("body %s: %v", data, err)
+		}
+		requests <- req
+		handler(w, strings.TrimPrefix(r.URL.Path, "/3/device/"))
+	}))
+	t.Cleanup(srv.Close)
+	pushConf := &config.Push{}
+	pushConf.APNs.KeyID = "KEY123"
+	pushConf.APNs.TeamID = "TEAM123"
+	pushConf.APNs.BundleID = "io.openim.app"
+	return &APNs{pushConf: pushConf, cache: thirdCache, httpClient: srv.Client(), url: srv.URL, key: key}, key, requests
+}
+
+func TestPushHeaders(t *testing.T) {
+	thirdCache := oemtest.NewThirdCache().SetToken("u1", constant.IOSPlatformID, "", "device1").SetBadge(3)
+	a, key, requests := newTestAPNs(t, thirdCache, func(w http.ResponseWriter, _ string) {
+		w.WriteHeader(http.StatusOK)
+	})
+	opts := &options.Opts{Signal: &options.Signal{ClientMsgID: "msg1"}, IOSPushSound: "default", Ex: "ex"}
+	if err := a.Push(context.Background(), []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	if req.path != "/3/device/device1" {
+		t.Fatalf("path %s", req.path)
+	}
+	for name, value := range map[string]string{
+		"apns-topic":       "io.openim.app",
+		"apns-push-type":   "alert",
+		"apns-priority":    "10",
+		"apns-collapse-id": "msg1",
+	} {
+		if got := req.header.Get(name); got != value {
+			t.Errorf("%s %q, want %q", name, got, value)
+		}
+	}
+	auth := req.header.Get("authorization")
+	if !strings.HasPrefix(auth, "bearer ") {
+		t.Fatalf("authorization %q", auth)
+	}
+	token, err := jwt.Parse(strings.TrimPrefix(auth, "bearer "), func(token *jwt.Token) (any, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+	if err != nil {
+		t.Fatalf("provider token: %v", err)
+	}
+	if kid := token.Header["kid"]; kid != "KEY123" {
+		t.Errorf("kid %v", kid)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	if claims["iss"] != "TEAM123" || claims["iat"] == nil {
+		t.Errorf("claims %v", claims)
+	}
+	aps := req.body.Aps
+	if aps.Alert == nil || aps.Alert.Title != "title" || aps.Alert.Body != "content" || aps.Sound != "default" ||
+		aps.Badge == nil || *aps.Badge != 3 || req.body.Ex != "ex" || req.body.ClientMsgID != "msg1" {
+		t.Errorf("payload %+v", req.body)
+	}
+
+	// the provider token is reused between the pushes
+	if err := a.Push(context.Background(), []string{"u1"}, "", "", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	req = <-requests
+	if got := req.header.Get("authorization"); got != auth {
+		t.Errorf("provider token renewed")
+	}
+	if req.header.Get("apns-push-type") != "background" || req.header.Get("apns-priority") != "5" || req.body.Aps.ContentAvailable != 1 {
+		t.Errorf("background push %v %+v", req.header, req.body)
+	}
+}
+
+func TestPushUnregisteredToken(t *testing.T) {
+	thirdCache := oemtest.NewThirdCache().
+		SetToken("u1", constant.IOSPlatformID, "", "gone").
+		SetToken("u1", constant.IPadPlatformID, "", "valid").
+		SetToken("u2", constant.IOSPlatformID, "", "bad")
+	a, _, _ := newTestAPNs(t, thirdCache, func(w http.ResponseWriter, deviceToken string) {
+		switch deviceToken {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+	err := a.Push(context.Background(), []string{"u1", "u2"}, "title", "content", &options.Opts{})
+	if err == nil {
+		t.Fatal("the push rejected with 400 did not fail")
+	}
+	if deleted := thirdCache.Deleted(); len(deleted) != 1 || deleted[0] != (oemtest.Device{UserID: "u1", PlatformID: constant.IOSPlatformID}) {
+		t.Fatalf("deleted %v", deleted)
+	}
+	if thirdCache.Token("u2", constant.IOSPlatformID) != "bad" {
+		t.Fatal("token rejected with 400 deleted")
+	}
+}
+
+func TestExpiredProviderToken(t *testing.T) {
+	thirdCache := oemtest.NewThirdCache().SetToken("u1", constant.IOSPlatformID, "", "device1")
+	a, _, _ := newTestAPNs(t, thirdCache, func(w http.ResponseWriter, _ string) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"reason":"ExpiredProviderToken"}`))
+	})
+	if err := a.Push(context.Background(), []string{"u1"}, "title", "content", &options.Opts{}); err == nil {
+		t.Fatal("push rejected with 403 did not fail")
+	}
+	if a.token != "" {
+		t.Fatal("expired provider token kept")
+	}
+	if len(thirdCache.Deleted()) != 0 {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := parseKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(key) {
+		t.Fatal("parsed key differs")
+	}
+	if _, err := parseKey([]byte("not a key")); err == nil {
+		t.Fatal("parsed a non PEM key")
+	}
+}
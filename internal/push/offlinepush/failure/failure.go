@@ -0,0 +1,91 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package failure tells the users an offline push failed for, so that only they are pushed again.
+package failure
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/openimsdk/tools/errs"
+)
+
+// UsersError is the error of a push that failed for some of its users, the others got it.
+type UsersError struct {
+	// UserIDs are the users the push failed for, those the pusher cannot tell are missing.
+	UserIDs []string
+	Err     error
+}
+
+func (e *UsersError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsersError) Unwrap() error {
+	return e.Err
+}
+
+// UserIDs returns the users of the push the error is of that did not get it, all of them unless the error tells.
+func UserIDs(err error, userIDs []string) []string {
+	var usersErr *UsersError
+	if errors.As(err, &usersErr) {
+		return usersErr.UserIDs
+	}
+	return userIDs
+}
+
+// Collector collects the failures of the parts of a push into the UsersError the pusher returns.
+type Collector struct {
+	lock       sync.Mutex
+	fail       int
+	userIDs    []string
+	users      map[string]struct{}
+	errBuilder strings.Builder
+}
+
+// Add records a part of the push that failed on count devices of the users, userIDs is empty if the part
+// failed on devices the pusher cannot tell.
+func (c *Collector) Add(userIDs []string, count int, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.fail += count
+	for _, userID := range userIDs {
+		if _, ok := c.users[userID]; ok {
+			continue
+		}
+		if c.users == nil {
+			c.users = make(map[string]struct{})
+		}
+		c.users[userID] = struct{}{}
+		c.userIDs = append(c.userIDs, userID)
+	}
+	c.errBuilder.WriteString(err.Error())
+	c.errBuilder.WriteByte('.')
+}
+
+// Err returns the error of the push, nil if no part of it failed.
+func (c *Collector) Err(name string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.fail == 0 {
+		return nil
+	}
+	return &UsersError{
+		UserIDs: c.userIDs,
+		Err:     errs.New(fmt.Sprintf("%d %s push failed;err:%s", c.fail, name, c.errBuilder.String())).Wrap(),
+	}
+}
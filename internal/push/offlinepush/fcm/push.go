@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
 	"github.com/openimsdk/tools/utils/httputil"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/api/option"
 )
@@ -81,13 +83,21 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 	allTokens := make(map[string][]string, 0)
 	for _, account := range userIDs {
 		var personTokens []string
-		for _, v := range Terminal {
+		for _, v := range opts.Terminal(Terminal) {
 			Token, err := f.cache.GetFcmToken(ctx, account, v)
-			if err == nil {
-				personTokens = append(personTokens, Token)
+			if err != nil {
+				continue
+			}
+			// the tokens issued by a vendor push service are pushed through the vendor
+			if vendor, err := f.cache.GetFcmTokenVendor(ctx, account, v); err != nil || vendor != "" {
+				continue
 			}
+			personTokens = append(personTokens, Token)
+		}
+		// a user without devices of the platforms is left to the other pushers, and their badge unchanged
+		if len(personTokens) > 0 {
+			allTokens[account] = personTokens
 		}
-		allTokens[account] = personTokens
 	}
 	Success := 0
 	Fail := 0
@@ -95,6 +105,9 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 	notification.Body = content
 	notification.Title = title
 	var messages []*messaging.Message
+	// messageUsers are the users of the messages, to tell whom the failed ones were for
+	var messageUsers []string
+	var failUserIDs []string
 	var sendErrBuilder strings.Builder
 	var msgErrBuilder strings.Builder
 	for userID, personTokens := range allTokens {
@@ -104,6 +117,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 			response, err := f.fcmMsgCli.SendEach(ctx, messages)
 			if err != nil {
 				Fail = Fail + messageCount
+				failUserIDs = append(failUserIDs, messageUsers...)
 				// Record push error
 				sendErrBuilder.WriteString(err.Error())
 				sendErrBuilder.WriteByte('.')
@@ -114,6 +128,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 					// Record message error
 					for i := range response.Responses {
 						if !response.Responses[i].Success {
+							failUserIDs = append(failUserIDs, messageUsers[i])
 							msgErrBuilder.WriteString(response.Responses[i].Error.Error())
 							msgErrBuilder.WriteByte('.')
 						}
@@ -121,6 +136,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 				}
 			}
 			messages = messages[0:0]
+			messageUsers = messageUsers[0:0]
 		}
 		if opts.IOSBadgeCount {
 			unreadCountSum, err := f.cache.IncrUserBadgeUnreadCountSum(ctx, userID)
@@ -129,6 +145,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 			} else {
 				// log.Error(operationID, "IncrUserBadgeUnreadCountSum redis err", err.Error(), uid)
 				Fail++
+				failUserIDs = append(failUserIDs, userID)
 				continue
 			}
 		} else {
@@ -141,6 +158,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 			} else {
 				// log.Error(operationID, "GetUserBadgeUnreadCountSum redis err", err.Error(), uid)
 				Fail++
+				failUserIDs = append(failUserIDs, userID)
 				continue
 			}
 		}
@@ -152,6 +170,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 				APNS:         apns,
 			}
 			messages = append(messages, temp)
+			messageUsers = append(messageUsers, userID)
 		}
 	}
 	messageCount := len(messages)
@@ -159,14 +178,23 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 		response, err := f.fcmMsgCli.SendEach(ctx, messages)
 		if err != nil {
 			Fail = Fail + messageCount
+			failUserIDs = append(failUserIDs, messageUsers...)
 		} else {
 			Success = Success + response.SuccessCount
 			Fail = Fail + response.FailureCount
+			for i := range response.Responses {
+				if !response.Responses[i].Success {
+					failUserIDs = append(failUserIDs, messageUsers[i])
+				}
+			}
 		}
 	}
 	if Fail != 0 {
-		return errs.New(fmt.Sprintf("%d message send failed;send err:%s;message err:%s",
-			Fail, sendErrBuilder.String(), msgErrBuilder.String())).Wrap()
+		return &failure.UsersError{
+			UserIDs: datautil.Distinct(failUserIDs),
+			Err: errs.New(fmt.Sprintf("%d message send failed;send err:%s;message err:%s",
+				Fail, sendErrBuilder.String(), msgErrBuilder.String())).Wrap(),
+		}
 	}
 	return nil
 }
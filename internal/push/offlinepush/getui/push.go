@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
@@ -87,26 +88,20 @@ func (g *Client) Push(ctx context.Context, userIDs []string, title, content stri
 		maxNum := 999
 		if len(userIDs) > maxNum {
 			s := splitter.NewSplitter(maxNum, userIDs)
+			var failures failure.Collector
 			wg := sync.WaitGroup{}
 			wg.Add(len(s.GetSplitResult()))
 			for i, v := range s.GetSplitResult() {
 				go func(index int, userIDs []string) {
 					defer wg.Done()
-					for i := 0; i < len(userIDs); i += maxNum {
-						end := i + maxNum
-						if end > len(userIDs) {
-							end = len(userIDs)
-						}
-						if err = g.batchPush(ctx, token, userIDs[i:end], pushReq); err != nil {
-							log.ZError(ctx, "batchPush failed", err, "index", index, "token", token, "req", pushReq)
-						}
-					}
-					if err = g.batchPush(ctx, token, userIDs, pushReq); err != nil {
+					if err := g.batchPush(ctx, token, userIDs, pushReq); err != nil {
 						log.ZError(ctx, "batchPush failed", err, "index", index, "token", token, "req", pushReq)
+						failures.Add(userIDs, len(userIDs), err)
 					}
 				}(i, v.Item)
 			}
 			wg.Wait()
+			err = failures.Err("getui")
 		} else {
 			err = g.batchPush(ctx, token, userIDs, pushReq)
 		}
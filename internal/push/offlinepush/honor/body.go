@@ -0,0 +1,90 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package honor
+
+import (
+	"strconv"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+)
+
+const (
+	// clickOpenApp opens the app when the notification is tapped.
+	clickOpenApp = 3
+)
+
+type authResp struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type sendReq struct {
+	Data    string   `json:"data,omitempty"`
+	Android android  `json:"android"`
+	Token   []string `json:"token"`
+}
+
+type android struct {
+	TTL          string        `json:"ttl"`
+	Notification *notification `json:"notification,omitempty"`
+}
+
+type notification struct {
+	Title       string      `json:"title"`
+	Body        string      `json:"body"`
+	Tag         string      `json:"tag,omitempty"`
+	Importance  string      `json:"importance"`
+	ClickAction clickAction `json:"clickAction"`
+}
+
+type clickAction struct {
+	Type int `json:"type"`
+}
+
+type sendResp struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		SendResult   bool     `json:"sendResult"`
+		RequestID    string   `json:"requestId"`
+		FailTokens   []string `json:"failTokens"`
+		ExpireTokens []string `json:"expireTokens"`
+	} `json:"data"`
+}
+
+// newSendReq builds the push of a message, one with neither title nor content is only delivered as data to the app.
+func newSendReq(title, content string, opts *options.Opts) *sendReq {
+	req := &sendReq{
+		Data:    oem.NewData(opts),
+		Android: android{TTL: strconv.Itoa(int(oem.TTL.Seconds())) + "s"},
+	}
+	if title == "" && content == "" {
+		return req
+	}
+	req.Android.Notification = &notification{
+		Title:       title,
+		Body:        content,
+		Importance:  "NORMAL",
+		ClickAction: clickAction{Type: clickOpenApp},
+	}
+	if opts.Signal != nil {
+		// the notifications of the same message replace each other
+		req.Android.Notification.Tag = opts.Signal.ClientMsgID
+	}
+	return req
+}
@@ -0,0 +1,138 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package honor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/tools/errs"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultAuthURL = "https://iam.developer.honor.com/auth/token"
+	defaultPushURL = "https://push-api.cloud.honor.com/api/v1/"
+
+	successCode    = 200
+	concurrentPush = 4
+)
+
+// Honor pushes through the Honor Push service to the devices whose tokens it issued.
+type Honor struct {
+	pushConf    *config.Push
+	cache       cache.ThirdCache
+	client      *oem.Client
+	authURL     string
+	pushURL     string
+	accessToken *oem.AccessToken
+}
+
+func NewClient(pushConf *config.Push, cache cache.ThirdCache) (*Honor, error) {
+	conf := pushConf.Honor
+	if conf.AppID == "" || conf.ClientID == "" || conf.ClientSecret == "" {
+		return nil, errs.New("no honor push config").Wrap()
+	}
+	h := &Honor{
+		pushConf: pushConf,
+		cache:    cache,
+		client:   oem.NewClient(),
+		authURL:  defaultAuthURL,
+		pushURL:  defaultPushURL,
+	}
+	h.accessToken = oem.NewAccessToken(h.auth)
+	return h, nil
+}
+
+func (h *Honor) auth(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {h.pushConf.Honor.ClientID},
+		"client_secret": {h.pushConf.Honor.ClientSecret},
+	}
+	var resp authResp
+	if err := h.client.PostForm(ctx, h.authURL, nil, form, &resp); err != nil {
+		return "", 0, errs.WrapMsg(err, "honor auth failed")
+	}
+	if resp.AccessToken == "" {
+		return "", 0, errs.New("honor auth failed", "error", resp.Error, "description", resp.ErrorDescription).Wrap()
+	}
+	return resp.AccessToken, time.Duration(resp.ExpiresIn) * time.Second, nil
+}
+
+func (h *Honor) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	tokens := oem.Tokens(ctx, h.cache, thirdext.VendorHonor, userIDs, opts)
+	if len(tokens) == 0 {
+		return nil
+	}
+	var failures oem.Failures
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	for _, batch := range oem.Batches(tokens) {
+		g.Go(func() error {
+			if err := h.send(ctx, batch, title, content, opts); err != nil {
+				failures.Add(batch, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return failures.Err(thirdext.VendorHonor)
+}
+
+func (h *Honor) send(ctx context.Context, tokens []oem.Token, title, content string, opts *options.Opts) error {
+	req := newSendReq(title, content, opts)
+	for _, token := range tokens {
+		req.Token = append(req.Token, token.Token)
+	}
+	accessToken, err := h.accessToken.Get(ctx)
+	if err != nil {
+		return err
+	}
+	header := map[string]string{
+		"Authorization": "Bearer " + accessToken,
+		"timestamp":     strconv.FormatInt(time.Now().UnixMilli(), 10),
+	}
+	var resp sendResp
+	err = h.client.PostJSON(ctx, h.pushURL+h.pushConf.Honor.AppID+"/sendMessage", header, req, &resp)
+	if err != nil {
+		var statusErr *oem.StatusError
+		if errors.As(err, &statusErr) && statusErr.Status == http.StatusUnauthorized {
+			h.accessToken.Reset(accessToken)
+		}
+		return err
+	}
+	if resp.Code != successCode {
+		return errs.New("honor push failed", "code", resp.Code, "message", resp.Message).Wrap()
+	}
+	oem.DelTokens(ctx, h.cache, thirdext.VendorHonor, tokens, resp.Data.ExpireTokens)
+	if len(resp.Data.FailTokens) > 0 {
+		return &oem.PartialError{
+			Tokens: resp.Data.FailTokens,
+			Fail:   len(resp.Data.FailTokens),
+			Err:    errs.New("honor push partly failed", "failure", len(resp.Data.FailTokens), "requestID", resp.Data.RequestID).Wrap(),
+		}
+	}
+	return nil
+}
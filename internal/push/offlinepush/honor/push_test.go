@@ -0,0 +1,120 @@
+package honor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+)
+
+// fakeHonor serves the Honor Push API, the tokens starting with "expired" are not known and those starting with
+// "fail" fail to be pushed.
+type fakeHonor struct {
+	lock    sync.Mutex
+	reqs    []sendReq
+	headers []http.Header
+}
+
+func (f *fakeHonor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	switch r.URL.Path {
+	case "/auth":
+		if r.PostFormValue("client_id") != "client" || r.PostFormValue("client_secret") != "secret" {
+			_ = json.NewEncoder(w).Encode(&authResp{Error: "invalid_client"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&authResp{AccessToken: "at", ExpiresIn: 3600})
+	case "/api/v1/app/sendMessage":
+		if r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req sendReq
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.reqs = append(f.reqs, req)
+		f.headers = append(f.headers, r.Header)
+		resp := sendResp{Code: successCode}
+		for _, token := range req.Token {
+			switch {
+			case strings.HasPrefix(token, "expired"):
+				resp.Data.ExpireTokens = append(resp.Data.ExpireTokens, token)
+			case strings.HasPrefix(token, "fail"):
+				resp.Data.FailTokens = append(resp.Data.FailTokens, token)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestHonor(t *testing.T, tokens map[string]string) (*Honor, *fakeHonor, *oemtest.ThirdCache) {
+	api := &fakeHonor{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	pushConf := &config.Push{}
+	pushConf.Honor.AppID = "app"
+	pushConf.Honor.ClientID = "client"
+	pushConf.Honor.ClientSecret = "secret"
+	thirdCache := oemtest.NewAndroidThirdCache(thirdext.VendorHonor, tokens)
+	h, err := NewClient(pushConf, thirdCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.authURL = srv.URL + "/auth"
+	h.pushURL = srv.URL + "/api/v1/"
+	return h, api, thirdCache
+}
+
+func TestPushBody(t *testing.T) {
+	h, api, thirdCache := newTestHonor(t, map[string]string{"u1": "t1"})
+	opts := &options.Opts{Ex: "ex", Signal: &options.Signal{ClientMsgID: "msg1"}}
+	if err := h.Push(context.Background(), []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	req := api.reqs[0]
+	n := req.Android.Notification
+	if len(req.Token) != 1 || req.Token[0] != "t1" || n == nil || n.Title != "title" || n.Body != "content" ||
+		n.Tag != "msg1" || n.ClickAction.Type != clickOpenApp {
+		t.Fatalf("request %+v", req)
+	}
+	if api.headers[0].Get("timestamp") == "" {
+		t.Fatal("no timestamp header")
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(req.Data), &data); err != nil || data["ex"] != "ex" || data["clientMsgID"] != "msg1" {
+		t.Fatalf("data %s", req.Data)
+	}
+	if err := h.Push(context.Background(), []string{"u1"}, "", "", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if req := api.reqs[1]; req.Android.Notification != nil {
+		t.Fatalf("data push %+v", req)
+	}
+	if len(thirdCache.Deleted()) != 0 {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushInvalidTokens(t *testing.T) {
+	h, _, thirdCache := newTestHonor(t, map[string]string{"u1": "t1", "u2": "expired2", "u3": "fail3"})
+	err := h.Push(context.Background(), []string{"u1", "u2", "u3"}, "title", "content", &options.Opts{})
+	var usersErr *failure.UsersError
+	if !errors.As(err, &usersErr) || len(usersErr.UserIDs) != 1 || usersErr.UserIDs[0] != "u3" {
+		t.Fatalf("err %v", err)
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u2" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
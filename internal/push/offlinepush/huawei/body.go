@@ -0,0 +1,104 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package huawei
+
+import (
+	"strconv"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+)
+
+const (
+	// clickOpenApp opens the app when the notification is tapped.
+	clickOpenApp = 3
+)
+
+type authResp struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            int    `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type sendReq struct {
+	ValidateOnly bool    `json:"validate_only"`
+	Message      message `json:"message"`
+}
+
+type message struct {
+	Data    string   `json:"data,omitempty"`
+	Android android  `json:"android"`
+	Token   []string `json:"token"`
+}
+
+type android struct {
+	Urgency      string        `json:"urgency"`
+	Category     string        `json:"category,omitempty"`
+	TTL          string        `json:"ttl"`
+	Notification *notification `json:"notification,omitempty"`
+}
+
+type notification struct {
+	Title       string      `json:"title"`
+	Body        string      `json:"body"`
+	Tag         string      `json:"tag,omitempty"`
+	Importance  string      `json:"importance"`
+	ClickAction clickAction `json:"click_action"`
+}
+
+type clickAction struct {
+	Type int `json:"type"`
+}
+
+type sendResp struct {
+	Code      string `json:"code"`
+	Msg       string `json:"msg"`
+	RequestID string `json:"requestId"`
+}
+
+// partialResult is the msg of a push that reached only some of the tokens.
+type partialResult struct {
+	Success       int      `json:"success"`
+	Failure       int      `json:"failure"`
+	IllegalTokens []string `json:"illegal_tokens"`
+}
+
+// newSendReq builds the push of a message, one with neither title nor content is only delivered as data to the app.
+func newSendReq(category string, title, content string, opts *options.Opts) *sendReq {
+	req := &sendReq{Message: message{
+		Data: oem.NewData(opts),
+		Android: android{
+			Urgency: "NORMAL",
+			TTL:     strconv.Itoa(int(oem.TTL.Seconds())) + "s",
+		},
+	}}
+	if title == "" && content == "" {
+		return req
+	}
+	req.Message.Android.Urgency = "HIGH"
+	req.Message.Android.Category = category
+	req.Message.Android.Notification = &notification{
+		Title:       title,
+		Body:        content,
+		Importance:  "NORMAL",
+		ClickAction: clickAction{Type: clickOpenApp},
+	}
+	if opts.Signal != nil {
+		// the notifications of the same message replace each other
+		req.Message.Android.Notification.Tag = opts.Signal.ClientMsgID
+	}
+	return req
+}
@@ -0,0 +1,150 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package huawei
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/tools/errs"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultAuthURL = "https://oauth-login.cloud.huawei.com/oauth2/v3/token"
+	defaultPushURL = "https://push-api.cloud.huawei.com/v1/"
+
+	// Codes.
+	successCode      = "80000000"
+	partialCode      = "80100000"
+	tokenInvalidCode = "80300007"
+	authFailedCode   = "80200001"
+	authExpiredCode  = "80200003"
+	concurrentPush   = 4
+)
+
+// Huawei pushes through the Huawei Push Kit to the devices whose tokens it issued.
+type Huawei struct {
+	pushConf    *config.Push
+	cache       cache.ThirdCache
+	client      *oem.Client
+	authURL     string
+	pushURL     string
+	accessToken *oem.AccessToken
+}
+
+func NewClient(pushConf *config.Push, cache cache.ThirdCache) (*Huawei, error) {
+	if pushConf.Huawei.AppID == "" || pushConf.Huawei.AppSecret == "" {
+		return nil, errs.New("no huawei push config").Wrap()
+	}
+	h := &Huawei{
+		pushConf: pushConf,
+		cache:    cache,
+		client:   oem.NewClient(),
+		authURL:  defaultAuthURL,
+		pushURL:  defaultPushURL,
+	}
+	h.accessToken = oem.NewAccessToken(h.auth)
+	return h, nil
+}
+
+func (h *Huawei) auth(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {h.pushConf.Huawei.AppID},
+		"client_secret": {h.pushConf.Huawei.AppSecret},
+	}
+	var resp authResp
+	if err := h.client.PostForm(ctx, h.authURL, nil, form, &resp); err != nil {
+		return "", 0, errs.WrapMsg(err, "huawei auth failed")
+	}
+	if resp.AccessToken == "" {
+		return "", 0, errs.New("huawei auth failed", "error", resp.Error, "description", resp.ErrorDescription).Wrap()
+	}
+	return resp.AccessToken, time.Duration(resp.ExpiresIn) * time.Second, nil
+}
+
+func (h *Huawei) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	tokens := oem.Tokens(ctx, h.cache, thirdext.VendorHuawei, userIDs, opts)
+	if len(tokens) == 0 {
+		return nil
+	}
+	var failures oem.Failures
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	for _, batch := range oem.Batches(tokens) {
+		g.Go(func() error {
+			if err := h.send(ctx, batch, title, content, opts); err != nil {
+				failures.Add(batch, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return failures.Err(thirdext.VendorHuawei)
+}
+
+func (h *Huawei) send(ctx context.Context, tokens []oem.Token, title, content string, opts *options.Opts) error {
+	req := newSendReq(h.pushConf.Huawei.Category, title, content, opts)
+	for _, token := range tokens {
+		req.Message.Token = append(req.Message.Token, token.Token)
+	}
+	accessToken, err := h.accessToken.Get(ctx)
+	if err != nil {
+		return err
+	}
+	header := map[string]string{"Authorization": "Bearer " + accessToken}
+	var resp sendResp
+	err = h.client.PostJSON(ctx, h.pushURL+h.pushConf.Huawei.AppID+"/messages:send", header, req, &resp)
+	if err != nil {
+		var statusErr *oem.StatusError
+		if errors.As(err, &statusErr) && statusErr.Status == http.StatusUnauthorized {
+			h.accessToken.Reset(accessToken)
+		}
+		return err
+	}
+	switch resp.Code {
+	case successCode:
+		return nil
+	case partialCode:
+		var result partialResult
+		if err := json.Unmarshal([]byte(resp.Msg), &result); err != nil {
+			return errs.WrapMsg(err, "huawei partial result", "msg", resp.Msg)
+		}
+		oem.DelTokens(ctx, h.cache, thirdext.VendorHuawei, tokens, result.IllegalTokens)
+		if result.Failure > len(result.IllegalTokens) {
+			return &oem.PartialError{
+				Fail: result.Failure - len(result.IllegalTokens),
+				Err:  errs.New("huawei push partly failed", "failure", result.Failure, "requestID", resp.RequestID).Wrap(),
+			}
+		}
+		return nil
+	case tokenInvalidCode:
+		oem.DelTokens(ctx, h.cache, thirdext.VendorHuawei, tokens, req.Message.Token)
+		return nil
+	case authFailedCode, authExpiredCode:
+		h.accessToken.Reset(accessToken)
+	}
+	return errs.New("huawei push failed", "code", resp.Code, "msg", resp.Msg, "requestID", resp.RequestID).Wrap()
+}
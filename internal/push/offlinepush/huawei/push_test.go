@@ -0,0 +1,131 @@
+package huawei
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+)
+
+// fakeHuawei serves the Push Kit API, the tokens starting with "illegal" are not known and those starting with
+// "fail" fail to be pushed.
+type fakeHuawei struct {
+	lock sync.Mutex
+	reqs []sendReq
+}
+
+func (f *fakeHuawei) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	switch r.URL.Path {
+	case "/auth":
+		if r.PostFormValue("client_id") != "app" || r.PostFormValue("client_secret") != "secret" {
+			_ = json.NewEncoder(w).Encode(&authResp{Error: 1101, ErrorDescription: "invalid client"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&authResp{AccessToken: "at", ExpiresIn: 3600})
+	case "/v1/app/messages:send":
+		if r.Header.Get("Authorization") != "Bearer at" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req sendReq
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.reqs = append(f.reqs, req)
+		var result partialResult
+		for _, token := range req.Message.Token {
+			switch {
+			case strings.HasPrefix(token, "illegal"):
+				result.IllegalTokens = append(result.IllegalTokens, token)
+				result.Failure++
+			case strings.HasPrefix(token, "fail"):
+				result.Failure++
+			default:
+				result.Success++
+			}
+		}
+		resp := sendResp{Code: successCode, RequestID: "req1"}
+		switch {
+		case result.Success == 0 && len(result.IllegalTokens) == len(req.Message.Token):
+			resp.Code = tokenInvalidCode
+		case result.Failure > 0:
+			msg, _ := json.Marshal(&result)
+			resp.Code, resp.Msg = partialCode, string(msg)
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestHuawei(t *testing.T, tokens map[string]string) (*Huawei, *fakeHuawei, *oemtest.ThirdCache) {
+	api := &fakeHuawei{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	pushConf := &config.Push{}
+	pushConf.Huawei.AppID = "app"
+	pushConf.Huawei.AppSecret = "secret"
+	pushConf.Huawei.Category = "IM"
+	thirdCache := oemtest.NewAndroidThirdCache(thirdext.VendorHuawei, tokens)
+	h, err := NewClient(pushConf, thirdCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.authURL = srv.URL + "/auth"
+	h.pushURL = srv.URL + "/v1/"
+	return h, api, thirdCache
+}
+
+func TestPushBody(t *testing.T) {
+	h, api, thirdCache := newTestHuawei(t, map[string]string{"u1": "t1"})
+	opts := &options.Opts{Ex: "ex", Signal: &options.Signal{ClientMsgID: "msg1"}}
+	if err := h.Push(context.Background(), []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	req := api.reqs[0]
+	n := req.Message.Android.Notification
+	if len(req.Message.Token) != 1 || req.Message.Token[0] != "t1" || req.Message.Android.Urgency != "HIGH" ||
+		req.Message.Android.Category != "IM" || n == nil || n.Title != "title" || n.Body != "content" || n.Tag != "msg1" {
+		t.Fatalf("request %+v", req)
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(req.Message.Data), &data); err != nil || data["ex"] != "ex" || data["clientMsgID"] != "msg1" {
+		t.Fatalf("data %s", req.Message.Data)
+	}
+	if err := h.Push(context.Background(), []string{"u1"}, "", "", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if req := api.reqs[1]; req.Message.Android.Notification != nil || req.Message.Android.Urgency != "NORMAL" {
+		t.Fatalf("data push %+v", req)
+	}
+	if len(thirdCache.Deleted()) != 0 {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushInvalidTokens(t *testing.T) {
+	h, _, thirdCache := newTestHuawei(t, map[string]string{"u1": "t1", "u2": "illegal2", "u3": "fail3"})
+	err := h.Push(context.Background(), []string{"u1", "u2", "u3"}, "title", "content", &options.Opts{})
+	if err == nil {
+		t.Fatal("the push that failed on a device did not fail")
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u2" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+
+	h, _, thirdCache = newTestHuawei(t, map[string]string{"u1": "illegal1"})
+	if err := h.Push(context.Background(), []string{"u1"}, "title", "content", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u1" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
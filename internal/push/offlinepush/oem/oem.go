@@ -0,0 +1,271 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oem holds what the pushers of the Android vendor push services share: the device tokens tagged
+// with the vendor, the OAuth access tokens they authorize with and the HTTP calls.
+package oem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+const (
+	// MaxTokens is the most device tokens the vendors accept in one push.
+	MaxTokens = 1000
+	// TTL is how long the vendors keep a push for a device that is offline.
+	TTL         = time.Hour * 24
+	pushTimeout = time.Second * 10
+	// tokenRenew renews an access token this long before it expires.
+	tokenRenew = time.Minute * 5
+)
+
+// Terminal are the platforms whose devices register vendor tokens.
+var Terminal = []int{constant.AndroidPlatformID, constant.AndroidPadPlatformID}
+
+// Token is a device token of a user.
+type Token struct {
+	UserID     string
+	PlatformID int
+	Token      string
+}
+
+// Tokens returns the device tokens of the users issued by the vendor.
+func Tokens(ctx context.Context, cache cache.ThirdCache, vendor string, userIDs []string, opts *options.Opts) []Token {
+	var tokens []Token
+	platformIDs := opts.Terminal(Terminal)
+	for _, userID := range userIDs {
+		for _, platformID := range platformIDs {
+			if v, err := cache.GetFcmTokenVendor(ctx, userID, platformID); err != nil || v != vendor {
+				continue
+			}
+			token, err := cache.GetFcmToken(ctx, userID, platformID)
+			if err != nil || token == "" {
+				continue
+			}
+			tokens = append(tokens, Token{UserID: userID, PlatformID: platformID, Token: token})
+		}
+	}
+	return tokens
+}
+
+// Batches splits the tokens into the batches pushed at once.
+func Batches(tokens []Token) [][]Token {
+	var batches [][]Token
+	for len(tokens) > MaxTokens {
+		batches = append(batches, tokens[:MaxTokens])
+		tokens = tokens[MaxTokens:]
+	}
+	if len(tokens) > 0 {
+		batches = append(batches, tokens)
+	}
+	return batches
+}
+
+// DelTokens removes the tokens the vendor no longer knows, the app was uninstalled or the token expired.
+func DelTokens(ctx context.Context, cache cache.ThirdCache, vendor string, tokens []Token, invalid []string) {
+	if len(invalid) == 0 {
+		return
+	}
+	set := make(map[string]struct{}, len(invalid))
+	for _, token := range invalid {
+		set[token] = struct{}{}
+	}
+	for _, token := range tokens {
+		if _, ok := set[token.Token]; !ok {
+			continue
+		}
+		log.ZInfo(ctx, "vendor push token invalid", "vendor", vendor, "userID", token.UserID, "platformID", token.PlatformID)
+		if err := cache.DelFcmToken(ctx, token.UserID, token.PlatformID); err != nil {
+			log.ZWarn(ctx, "delete vendor push token failed", err, "vendor", vendor, "userID", token.UserID, "platformID", token.PlatformID)
+		}
+	}
+}
+
+// PartialError is the error of a batch the vendor pushed to some of the devices only.
+type PartialError struct {
+	// Tokens are the device tokens the push failed on, empty if the vendor does not tell them.
+	Tokens []string
+	Fail   int
+	Err    error
+}
+
+func (e *PartialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// Failures collects the failed batches into the error the pusher returns.
+type Failures struct {
+	collector failure.Collector
+}
+
+// Add records the batch failed on the devices the PartialError tells, on all of them for any other error.
+func (f *Failures) Add(batch []Token, err error) {
+	fail := len(batch)
+	var partialErr *PartialError
+	if errors.As(err, &partialErr) {
+		failed := make(map[string]struct{}, len(partialErr.Tokens))
+		for _, token := range partialErr.Tokens {
+			failed[token] = struct{}{}
+		}
+		tokens := make([]Token, 0, len(partialErr.Tokens))
+		for _, token := range batch {
+			if _, ok := failed[token.Token]; ok {
+				tokens = append(tokens, token)
+			}
+		}
+		batch = tokens
+		fail = partialErr.Fail
+	}
+	userIDs := make([]string, 0, len(batch))
+	for _, token := range batch {
+		userIDs = append(userIDs, token.UserID)
+	}
+	f.collector.Add(userIDs, fail, err)
+}
+
+func (f *Failures) Err(vendor string) error {
+	return f.collector.Err(vendor)
+}
+
+// AccessToken keeps the access token a vendor authorizes the pushes with, renewing it before it expires.
+type AccessToken struct {
+	refresh func(ctx context.Context) (token string, expiresIn time.Duration, err error)
+
+	lock   sync.Mutex
+	token  string
+	expire time.Time
+}
+
+func NewAccessToken(refresh func(ctx context.Context) (string, time.Duration, error)) *AccessToken {
+	return &AccessToken{refresh: refresh}
+}
+
+func (a *AccessToken) Get(ctx context.Context) (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.token != "" && time.Now().Before(a.expire) {
+		return a.token, nil
+	}
+	token, expiresIn, err := a.refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	a.token = token
+	a.expire = time.Now().Add(max(expiresIn-tokenRenew, expiresIn/2))
+	return token, nil
+}
+
+// Reset drops the token the vendor rejected, unless it was renewed since.
+func (a *AccessToken) Reset(token string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.token == token {
+		a.token = ""
+	}
+}
+
+// Client posts to the vendor APIs.
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{Timeout: pushTimeout}}
+}
+
+// StatusError is the HTTP status a vendor rejected a request with.
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d body %s", e.Status, e.Body)
+}
+
+// PostJSON posts the body as JSON and decodes the JSON response into resp.
+func (c *Client) PostJSON(ctx context.Context, url string, header map[string]string, body any, resp any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return c.post(ctx, url, "application/json", header, data, resp)
+}
+
+// PostForm posts the form and decodes the JSON response into resp.
+func (c *Client) PostForm(ctx context.Context, url string, header map[string]string, form url.Values, resp any) error {
+	return c.post(ctx, url, "application/x-www-form-urlencoded", header, []byte(form.Encode()), resp)
+}
+
+func (c *Client) post(ctx context.Context, url string, contentType string, header map[string]string, body []byte, resp any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	response, err := c.httpClient.Do(req)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return &StatusError{Status: response.StatusCode, Body: string(data)}
+	}
+	if err := json.Unmarshal(data, resp); err != nil {
+		return errs.WrapMsg(err, "decode vendor response failed", "body", string(data))
+	}
+	return nil
+}
+
+// Data is the payload the app receives with the notification.
+type Data struct {
+	Ex          string `json:"ex,omitempty"`
+	ClientMsgID string `json:"clientMsgID,omitempty"`
+}
+
+func NewData(opts *options.Opts) string {
+	data := Data{Ex: opts.Ex}
+	if opts.Signal != nil {
+		data.ClientMsgID = opts.Signal.ClientMsgID
+	}
+	b, _ := json.Marshal(&data)
+	return string(b)
+}
@@ -0,0 +1,144 @@
+package oem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/protocol/constant"
+)
+
+func TestTokens(t *testing.T) {
+	c := oemtest.NewThirdCache().
+		SetToken("u1", constant.AndroidPlatformID, "huawei", "t1").
+		SetToken("u1", constant.AndroidPadPlatformID, "huawei", "t2").
+		SetToken("u2", constant.AndroidPlatformID, "xiaomi", "t3").
+		SetToken("u3", constant.IOSPlatformID, "huawei", "t4")
+	ctx := context.Background()
+	tokens := Tokens(ctx, c, "huawei", []string{"u1", "u2", "u3", "u4"}, &options.Opts{})
+	want := []Token{
+		{UserID: "u1", PlatformID: constant.AndroidPlatformID, Token: "t1"},
+		{UserID: "u1", PlatformID: constant.AndroidPadPlatformID, Token: "t2"},
+	}
+	if len(tokens) != len(want) || tokens[0] != want[0] || tokens[1] != want[1] {
+		t.Fatalf("tokens %v, want %v", tokens, want)
+	}
+	tokens = Tokens(ctx, c, "huawei", []string{"u1"}, &options.Opts{Platforms: []int{constant.AndroidPadPlatformID}})
+	if len(tokens) != 1 || tokens[0] != want[1] {
+		t.Fatalf("tokens of the routed platforms %v", tokens)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	for _, n := range []int{0, 1, MaxTokens, MaxTokens + 1, MaxTokens*2 + 5} {
+		tokens := make([]Token, n)
+		for i := range tokens {
+			tokens[i].Token = strconv.Itoa(i)
+		}
+		batches := Batches(tokens)
+		if len(batches) != (n+MaxTokens-1)/MaxTokens {
+			t.Fatalf("%d tokens in %d batches", n, len(batches))
+		}
+		var i int
+		for _, batch := range batches {
+			if len(batch) == 0 || len(batch) > MaxTokens {
+				t.Fatalf("%d tokens: batch of %d", n, len(batch))
+			}
+			for _, token := range batch {
+				if token.Token != strconv.Itoa(i) {
+					t.Fatalf("%d tokens: token %s at %d", n, token.Token, i)
+				}
+				i++
+			}
+		}
+		if i != n {
+			t.Fatalf("%d tokens: %d in the batches", n, i)
+		}
+	}
+}
+
+func TestDelTokens(t *testing.T) {
+	batch := []Token{
+		{UserID: "u1", PlatformID: constant.AndroidPlatformID, Token: "t1"},
+		{UserID: "u2", PlatformID: constant.AndroidPlatformID, Token: "t2"},
+	}
+	c := oemtest.NewThirdCache()
+	for _, token := range batch {
+		c.SetToken(token.UserID, token.PlatformID, "vivo", token.Token)
+	}
+	DelTokens(context.Background(), c, "vivo", batch, []string{"t2", "unknown"})
+	if deleted := c.Deleted(); len(deleted) != 1 || deleted[0] != (oemtest.Device{UserID: "u2", PlatformID: constant.AndroidPlatformID}) {
+		t.Fatalf("deleted %v", deleted)
+	}
+	DelTokens(context.Background(), c, "vivo", batch, nil)
+	if deleted := c.Deleted(); len(deleted) != 1 {
+		t.Fatalf("deleted %v", deleted)
+	}
+}
+
+func TestFailures(t *testing.T) {
+	batch := []Token{{UserID: "u1", Token: "t1"}, {UserID: "u2", Token: "t2"}}
+	var failures Failures
+	if err := failures.Err("vendor"); err != nil {
+		t.Fatalf("no failure: %v", err)
+	}
+	failures.Add(batch, &PartialError{Tokens: []string{"t2"}, Fail: 1, Err: errors.New("partly failed")})
+	var usersErr *failure.UsersError
+	if err := failures.Err("vendor"); !errors.As(err, &usersErr) || len(usersErr.UserIDs) != 1 || usersErr.UserIDs[0] != "u2" {
+		t.Fatalf("partial failure %v", err)
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	var refreshed int
+	a := NewAccessToken(func(context.Context) (string, time.Duration, error) {
+		refreshed++
+		return "token" + strconv.Itoa(refreshed), time.Hour, nil
+	})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if token, err := a.Get(ctx); err != nil || token != "token1" {
+			t.Fatalf("token %s %v", token, err)
+		}
+	}
+	a.Reset("stale")
+	if token, _ := a.Get(ctx); token != "token1" {
+		t.Fatalf("reset by a stale token: %s", token)
+	}
+	a.Reset("token1")
+	if token, _ := a.Get(ctx); token != "token2" {
+		t.Fatalf("token after reset %s", token)
+	}
+}
+
+func TestClientPost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("unauthorized"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}))
+	defer srv.Close()
+	c := NewClient()
+	ctx := context.Background()
+	var resp struct {
+		Code int `json:"code"`
+	}
+	if err := c.PostJSON(ctx, srv.URL, map[string]string{"Authorization": "Bearer token"}, map[string]string{}, &resp); err != nil {
+		t.Fatal(err)
+	}
+	err := c.PostJSON(ctx, srv.URL, nil, map[string]string{}, &resp)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != http.StatusUnauthorized || statusErr.Body != "unauthorized" {
+		t.Fatalf("err %v", err)
+	}
+}
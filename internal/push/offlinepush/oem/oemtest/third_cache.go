@@ -0,0 +1,117 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oemtest has the fakes shared by the tests of the offline pushers.
+package oemtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/protocol/constant"
+)
+
+// Device is a device of a user, the key of the push tokens.
+type Device struct {
+	UserID     string
+	PlatformID int
+}
+
+type pushToken struct {
+	vendor string
+	token  string
+}
+
+// ThirdCache keeps the push token of each device with the vendor it was registered for,
+// the methods of cache.ThirdCache it does not override panic.
+type ThirdCache struct {
+	cache.ThirdCache
+	lock    sync.Mutex
+	tokens  map[Device]pushToken
+	deleted []Device
+	badge   int
+}
+
+func NewThirdCache() *ThirdCache {
+	return &ThirdCache{tokens: make(map[Device]pushToken)}
+}
+
+// NewAndroidThirdCache keeps the token of the android device of each user in tokens, registered for vendor.
+func NewAndroidThirdCache(vendor string, tokens map[string]string) *ThirdCache {
+	c := NewThirdCache()
+	for userID, token := range tokens {
+		c.SetToken(userID, constant.AndroidPlatformID, vendor, token)
+	}
+	return c
+}
+
+// SetToken registers the token of the device for vendor, empty for the default pusher of the platform.
+func (c *ThirdCache) SetToken(userID string, platformID int, vendor string, token string) *ThirdCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.tokens[Device{UserID: userID, PlatformID: platformID}] = pushToken{vendor: vendor, token: token}
+	return c
+}
+
+// SetBadge sets the unread count sum of every user.
+func (c *ThirdCache) SetBadge(badge int) *ThirdCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.badge = badge
+	return c
+}
+
+// Token returns the token the device still has.
+func (c *ThirdCache) Token(userID string, platformID int) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tokens[Device{UserID: userID, PlatformID: platformID}].token
+}
+
+// Deleted returns the devices whose token was deleted, in order.
+func (c *ThirdCache) Deleted() []Device {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]Device(nil), c.deleted...)
+}
+
+func (c *ThirdCache) GetFcmTokenVendor(_ context.Context, userID string, platformID int) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tokens[Device{UserID: userID, PlatformID: platformID}].vendor, nil
+}
+
+func (c *ThirdCache) GetFcmToken(_ context.Context, userID string, platformID int) (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tokens[Device{UserID: userID, PlatformID: platformID}].token, nil
+}
+
+func (c *ThirdCache) DelFcmToken(_ context.Context, userID string, platformID int) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	device := Device{UserID: userID, PlatformID: platformID}
+	if _, ok := c.tokens[device]; ok {
+		delete(c.tokens, device)
+		c.deleted = append(c.deleted, device)
+	}
+	return nil
+}
+
+func (c *ThirdCache) GetUserBadgeUnreadCountSum(context.Context, string) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.badge, nil
+}
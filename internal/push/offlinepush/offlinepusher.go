@@ -16,13 +16,21 @@ package offlinepush
 
 import (
 	"context"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/apns"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/dummy"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/fcm"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/getui"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/honor"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/huawei"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/jpush"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oppo"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/vivo"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/webpush"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/xiaomi"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
 	"strings"
 )
 
@@ -30,6 +38,14 @@ const (
 	geTUI    = "getui"
 	firebase = "fcm"
 	jPush    = "jpush"
+	apple    = "apns"
+	webPush  = "webpush"
+	// the Android vendor push services
+	huaweiPush = thirdext.VendorHuawei
+	honorPush  = thirdext.VendorHonor
+	xiaomiPush = thirdext.VendorXiaomi
+	oppoPush   = thirdext.VendorOPPO
+	vivoPush   = thirdext.VendorVivo
 )
 
 // OfflinePusher Offline Pusher.
@@ -37,16 +53,44 @@ type OfflinePusher interface {
 	Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error
 }
 
+// ProviderPusher is the OfflinePusher of the routes, which can push through some of the providers only.
+// Its Push returns a *ProvidersError naming the providers that failed.
+type ProviderPusher interface {
+	OfflinePusher
+	PushProviders(ctx context.Context, providers []string, userIDs []string, title, content string, opts *options.Opts) error
+}
+
 func NewOfflinePusher(pushConf *config.Push, cache cache.ThirdCache, fcmConfigPath string) (OfflinePusher, error) {
-	var offlinePusher OfflinePusher
+	if len(pushConf.Routes) > 0 {
+		return newRoutingPusher(pushConf, cache, fcmConfigPath)
+	}
 	pushConf.Enable = strings.ToLower(pushConf.Enable)
-	switch pushConf.Enable {
+	return newPusher(pushConf.Enable, pushConf, cache, fcmConfigPath)
+}
+
+func newPusher(provider string, pushConf *config.Push, cache cache.ThirdCache, fcmConfigPath string) (OfflinePusher, error) {
+	var offlinePusher OfflinePusher
+	switch provider {
 	case geTUI:
 		offlinePusher = getui.NewClient(pushConf, cache)
 	case firebase:
 		return fcm.NewClient(pushConf, cache, fcmConfigPath)
 	case jPush:
 		offlinePusher = jpush.NewClient(pushConf)
+	case apple:
+		return apns.NewClient(pushConf, cache, fcmConfigPath)
+	case webPush:
+		return webpush.NewClient(pushConf, cache)
+	case huaweiPush:
+		return huawei.NewClient(pushConf, cache)
+	case honorPush:
+		return honor.NewClient(pushConf, cache)
+	case xiaomiPush:
+		return xiaomi.NewClient(pushConf, cache)
+	case oppoPush:
+		return oppo.NewClient(pushConf, cache)
+	case vivoPush:
+		return vivo.NewClient(pushConf, cache)
 	default:
 		offlinePusher = dummy.NewClient()
 	}
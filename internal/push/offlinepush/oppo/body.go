@@ -0,0 +1,75 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oppo
+
+import (
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+const (
+	// targetRegistrationID pushes to a registration ID.
+	targetRegistrationID = 2
+	// clickOpenApp opens the app when the notification is tapped.
+	clickOpenApp = 0
+)
+
+type authResp struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		AuthToken  string `json:"auth_token"`
+		CreateTime int64  `json:"create_time"`
+	} `json:"data"`
+}
+
+type message struct {
+	TargetType   int           `json:"target_type"`
+	TargetValue  string        `json:"target_value"`
+	Notification *notification `json:"notification"`
+}
+
+type notification struct {
+	Title            string `json:"title"`
+	Content          string `json:"content"`
+	ClickActionType  int    `json:"click_action_type"`
+	ActionParameters string `json:"action_parameters,omitempty"`
+	ChannelID        string `json:"channel_id,omitempty"`
+	OffLineTTL       int    `json:"off_line_ttl"`
+}
+
+type sendResp struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    []struct {
+		MessageID      string `json:"messageId"`
+		RegistrationID string `json:"registrationId"`
+		ErrorCode      int    `json:"errorCode"`
+		ErrorMessage   string `json:"errorMessage"`
+	} `json:"data"`
+}
+
+// newNotification builds the notification of a message, the data for the app is passed in the action parameters.
+func newNotification(pushConf *config.Push, title, content string, opts *options.Opts) *notification {
+	return &notification{
+		Title:            title,
+		Content:          content,
+		ClickActionType:  clickOpenApp,
+		ActionParameters: oem.NewData(opts),
+		ChannelID:        pushConf.OPPO.ChannelID,
+		OffLineTTL:       int(oem.TTL.Seconds()),
+	}
+}
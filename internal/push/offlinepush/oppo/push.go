@@ -0,0 +1,153 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oppo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/tools/errs"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultURL = "https://api.push.oppomobile.com"
+	authPath   = "/server/v1/auth"
+	pushPath   = "/server/v1/message/notification/unicast_batch"
+
+	// authTokenExpire is how long an auth token is valid for.
+	authTokenExpire = time.Hour * 24
+
+	// Codes.
+	successCode          = 0
+	invalidAuthTokenCode = 11
+	invalidRegIDCode     = 10000
+	concurrentPush       = 4
+)
+
+// OPPO pushes through the OPPO Push service to the devices whose registration IDs it issued.
+// It only shows notifications, a push with neither title nor content is not sent.
+type OPPO struct {
+	pushConf    *config.Push
+	cache       cache.ThirdCache
+	client      *oem.Client
+	url         string
+	accessToken *oem.AccessToken
+}
+
+func NewClient(pushConf *config.Push, cache cache.ThirdCache) (*OPPO, error) {
+	if pushConf.OPPO.AppKey == "" || pushConf.OPPO.MasterSecret == "" {
+		return nil, errs.New("no oppo push config").Wrap()
+	}
+	o := &OPPO{pushConf: pushConf, cache: cache, client: oem.NewClient(), url: defaultURL}
+	o.accessToken = oem.NewAccessToken(o.auth)
+	return o, nil
+}
+
+func (o *OPPO) auth(ctx context.Context) (string, time.Duration, error) {
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	sign := sha256.Sum256([]byte(o.pushConf.OPPO.AppKey + timestamp + o.pushConf.OPPO.MasterSecret))
+	form := url.Values{
+		"app_key":   {o.pushConf.OPPO.AppKey},
+		"sign":      {hex.EncodeToString(sign[:])},
+		"timestamp": {timestamp},
+	}
+	var resp authResp
+	if err := o.client.PostForm(ctx, o.url+authPath, nil, form, &resp); err != nil {
+		return "", 0, errs.WrapMsg(err, "oppo auth failed")
+	}
+	if resp.Code != successCode || resp.Data.AuthToken == "" {
+		return "", 0, errs.New("oppo auth failed", "code", resp.Code, "message", resp.Message).Wrap()
+	}
+	return resp.Data.AuthToken, authTokenExpire, nil
+}
+
+func (o *OPPO) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	if title == "" && content == "" {
+		return nil
+	}
+	tokens := oem.Tokens(ctx, o.cache, thirdext.VendorOPPO, userIDs, opts)
+	if len(tokens) == 0 {
+		return nil
+	}
+	n := newNotification(o.pushConf, title, content, opts)
+	var failures oem.Failures
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	for _, batch := range oem.Batches(tokens) {
+		g.Go(func() error {
+			if err := o.send(ctx, batch, n); err != nil {
+				failures.Add(batch, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return failures.Err(thirdext.VendorOPPO)
+}
+
+func (o *OPPO) send(ctx context.Context, tokens []oem.Token, n *notification) error {
+	messages := make([]message, 0, len(tokens))
+	for _, token := range tokens {
+		messages = append(messages, message{TargetType: targetRegistrationID, TargetValue: token.Token, Notification: n})
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	accessToken, err := o.accessToken.Get(ctx)
+	if err != nil {
+		return err
+	}
+	var resp sendResp
+	if err := o.client.PostForm(ctx, o.url+pushPath, map[string]string{"auth_token": accessToken}, url.Values{"messages": {string(data)}}, &resp); err != nil {
+		return err
+	}
+	if resp.Code != successCode {
+		if resp.Code == invalidAuthTokenCode {
+			o.accessToken.Reset(accessToken)
+		}
+		return errs.New("oppo push failed", "code", resp.Code, "message", resp.Message).Wrap()
+	}
+	var invalid, fail []string
+	for _, result := range resp.Data {
+		switch result.ErrorCode {
+		case successCode:
+		case invalidRegIDCode:
+			invalid = append(invalid, result.RegistrationID)
+		default:
+			fail = append(fail, result.RegistrationID)
+		}
+	}
+	oem.DelTokens(ctx, o.cache, thirdext.VendorOPPO, tokens, invalid)
+	if len(fail) > 0 {
+		return &oem.PartialError{
+			Tokens: fail,
+			Fail:   len(fail),
+			Err:    errs.New("oppo push partly failed", "failure", len(fail)).Wrap(),
+		}
+	}
+	return nil
+}
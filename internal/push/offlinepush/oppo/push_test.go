@@ -0,0 +1,125 @@
+package oppo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+)
+
+// fakeOPPO serves the OPPO Push API, the registration IDs starting with "invalid" are not known and those starting
+// with "fail" fail to be pushed.
+type fakeOPPO struct {
+	lock     sync.Mutex
+	messages [][]message
+}
+
+func (f *fakeOPPO) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	switch r.URL.Path {
+	case authPath:
+		var resp authResp
+		if r.PostFormValue("app_key") != "key" || r.PostFormValue("sign") == "" || r.PostFormValue("timestamp") == "" {
+			resp.Code, resp.Message = 1, "bad sign"
+		} else {
+			resp.Data.AuthToken = "at"
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	case pushPath:
+		if r.Header.Get("auth_token") != "at" {
+			_ = json.NewEncoder(w).Encode(&sendResp{Code: invalidAuthTokenCode})
+			return
+		}
+		var messages []message
+		_ = json.Unmarshal([]byte(r.PostFormValue("messages")), &messages)
+		f.messages = append(f.messages, messages)
+		var resp sendResp
+		for _, m := range messages {
+			result := struct {
+				MessageID      string `json:"messageId"`
+				RegistrationID string `json:"registrationId"`
+				ErrorCode      int    `json:"errorCode"`
+				ErrorMessage   string `json:"errorMessage"`
+			}{RegistrationID: m.TargetValue}
+			switch {
+			case strings.HasPrefix(m.TargetValue, "invalid"):
+				result.ErrorCode = invalidRegIDCode
+			case strings.HasPrefix(m.TargetValue, "fail"):
+				result.ErrorCode = 10001
+			}
+			resp.Data = append(resp.Data, result)
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestOPPO(t *testing.T, tokens map[string]string) (*OPPO, *fakeOPPO, *oemtest.ThirdCache) {
+	api := &fakeOPPO{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	pushConf := &config.Push{}
+	pushConf.OPPO.AppKey = "key"
+	pushConf.OPPO.MasterSecret = "secret"
+	pushConf.OPPO.ChannelID = "im"
+	thirdCache := oemtest.NewAndroidThirdCache(thirdext.VendorOPPO, tokens)
+	o, err := NewClient(pushConf, thirdCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.url = srv.URL
+	return o, api, thirdCache
+}
+
+func TestPushBody(t *testing.T) {
+	o, api, thirdCache := newTestOPPO(t, map[string]string{"u1": "reg1"})
+	opts := &options.Opts{Ex: "ex", Signal: &options.Signal{ClientMsgID: "msg1"}}
+	if err := o.Push(context.Background(), []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	messages := api.messages[0]
+	if len(messages) != 1 || messages[0].TargetType != targetRegistrationID || messages[0].TargetValue != "reg1" {
+		t.Fatalf("messages %+v", messages)
+	}
+	n := messages[0].Notification
+	if n == nil || n.Title != "title" || n.Content != "content" || n.ChannelID != "im" || n.OffLineTTL == 0 {
+		t.Fatalf("notification %+v", n)
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(n.ActionParameters), &data); err != nil || data["ex"] != "ex" || data["clientMsgID"] != "msg1" {
+		t.Fatalf("action parameters %s", n.ActionParameters)
+	}
+	if err := o.Push(context.Background(), []string{"u1"}, "", "", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.messages) != 1 {
+		t.Fatal("a push with neither title nor content sent")
+	}
+	if len(thirdCache.Deleted()) != 0 {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushInvalidRegIDs(t *testing.T) {
+	o, _, thirdCache := newTestOPPO(t, map[string]string{"u1": "reg1", "u2": "invalid2", "u3": "fail3"})
+	err := o.Push(context.Background(), []string{"u1", "u2", "u3"}, "title", "content", &options.Opts{})
+	var usersErr *failure.UsersError
+	if !errors.As(err, &usersErr) || len(usersErr.UserIDs) != 1 || usersErr.UserIDs[0] != "u3" {
+		t.Fatalf("err %v", err)
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u2" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
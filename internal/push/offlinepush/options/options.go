@@ -6,9 +6,28 @@ type Opts struct {
 	IOSPushSound  string
 	IOSBadgeCount bool
 	Ex            string
+	// Platforms limits the push to the devices of these platforms, set when the platforms are routed to different pushers.
+	Platforms []int
 }
 
 // Signal message id.
 type Signal struct {
 	ClientMsgID string
 }
+
+// Terminal returns the platforms of terminal the push is limited to.
+func (o *Opts) Terminal(terminal []int) []int {
+	if o == nil || len(o.Platforms) == 0 {
+		return terminal
+	}
+	var platformIDs []int
+	for _, platformID := range terminal {
+		for _, p := range o.Platforms {
+			if p == platformID {
+				platformIDs = append(platformIDs, platformID)
+				break
+			}
+		}
+	}
+	return platformIDs
+}
@@ -0,0 +1,144 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offlinepush
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"golang.org/x/sync/errgroup"
+)
+
+type route struct {
+	provider  string
+	platforms []int
+	pusher    OfflinePusher
+}
+
+// routingPusher pushes through every routed provider at once, each limited to the devices of its platforms,
+// so the users get the push on the devices of every platform through the provider serving it.
+type routingPusher struct {
+	routes []*route
+}
+
+func newRoutingPusher(pushConf *config.Push, cache cache.ThirdCache, fcmConfigPath string) (*routingPusher, error) {
+	r := &routingPusher{}
+	providers := make(map[string]struct{})
+	for _, conf := range pushConf.Routes {
+		provider := strings.ToLower(conf.Provider)
+		if _, ok := providers[provider]; ok {
+			return nil, errs.New("push provider routed more than once", "provider", provider).Wrap()
+		}
+		providers[provider] = struct{}{}
+		if len(conf.Platforms) == 0 {
+			return nil, errs.New("push route has no platforms", "provider", provider).Wrap()
+		}
+		platforms := make([]int, 0, len(conf.Platforms))
+		for _, name := range conf.Platforms {
+			platformID := constant.PlatformNameToID(name)
+			if platformID == 0 {
+				return nil, errs.New("invalid push route platform", "provider", provider, "platform", name).Wrap()
+			}
+			platforms = append(platforms, platformID)
+		}
+		switch provider {
+		case firebase, apple, webPush, huaweiPush, honorPush, xiaomiPush, oppoPush, vivoPush:
+		case geTUI, jPush:
+			// they push to the aliases of the users, which are every device of them, and cannot be limited to the platforms
+			return nil, errs.New("push provider cannot be limited to platforms, set it as enable instead of routing it", "provider", provider).Wrap()
+		default:
+			return nil, errs.New("invalid push route provider", "provider", provider).Wrap()
+		}
+		pusher, err := newPusher(provider, pushConf, cache, fcmConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		r.routes = append(r.routes, &route{provider: provider, platforms: platforms, pusher: pusher})
+	}
+	return r, nil
+}
+
+func (r *routingPusher) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	return r.push(ctx, r.routes, userIDs, title, content, opts)
+}
+
+func (r *routingPusher) PushProviders(ctx context.Context, providers []string, userIDs []string, title, content string, opts *options.Opts) error {
+	var routes []*route
+	for _, rt := range r.routes {
+		if slices.Contains(providers, rt.provider) {
+			routes = append(routes, rt)
+		}
+	}
+	return r.push(ctx, routes, userIDs, title, content, opts)
+}
+
+func (r *routingPusher) push(ctx context.Context, routes []*route, userIDs []string, title, content string, opts *options.Opts) error {
+	var (
+		lock       sync.Mutex
+		fail       []string
+		failErrs   = make(map[string]error)
+		errBuilder strings.Builder
+	)
+	g := errgroup.Group{}
+	for _, rt := range routes {
+		routeOpts := *opts
+		routeOpts.Platforms = rt.platforms
+		g.Go(func() error {
+			start := time.Now()
+			err := rt.pusher.Push(ctx, userIDs, title, content, &routeOpts)
+			prommetrics.OfflinePushProviderDuration.WithLabelValues(rt.provider).Observe(time.Since(start).Seconds())
+			if err == nil {
+				prommetrics.OfflinePushProviderCounter.WithLabelValues(rt.provider, "success").Inc()
+				return nil
+			}
+			prommetrics.OfflinePushProviderCounter.WithLabelValues(rt.provider, "failed").Inc()
+			log.ZWarn(ctx, "offline push provider failed", err, "provider", rt.provider, "userIDs", len(userIDs))
+			lock.Lock()
+			defer lock.Unlock()
+			fail = append(fail, rt.provider)
+			failErrs[rt.provider] = err
+			errBuilder.WriteString(fmt.Sprintf("%s: %s.", rt.provider, err.Error()))
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if len(fail) != 0 {
+		return &ProvidersError{Providers: fail, Errs: failErrs, msg: errBuilder.String()}
+	}
+	return nil
+}
+
+// ProvidersError is the error of a routed push that failed on some of the providers, the others pushed it.
+type ProvidersError struct {
+	Providers []string
+	// Errs are the errors of the providers, telling the users each failed for.
+	Errs map[string]error
+	msg  string
+}
+
+func (e *ProvidersError) Error() string {
+	return fmt.Sprintf("offline push failed on %s;err:%s", strings.Join(e.Providers, ","), e.msg)
+}
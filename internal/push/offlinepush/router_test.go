@@ -0,0 +1,96 @@
+package offlinepush
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/protocol/constant"
+)
+
+type fakePusher struct {
+	lock      sync.Mutex
+	platforms [][]int
+	err       error
+}
+
+func (f *fakePusher) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.platforms = append(f.platforms, opts.Platforms)
+	return f.err
+}
+
+func TestNewRoutingPusherRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []config.PushRoute
+	}{
+		{name: "getui", routes: []config.PushRoute{{Provider: "getui", Platforms: []string{"Android"}}}},
+		{name: "jpush", routes: []config.PushRoute{{Provider: "JPush", Platforms: []string{"IOS"}}}},
+		{name: "no platforms", routes: []config.PushRoute{{Provider: "apns"}}},
+		{name: "invalid platform", routes: []config.PushRoute{{Provider: "apns", Platforms: []string{"Symbian"}}}},
+		{name: "invalid provider", routes: []config.PushRoute{{Provider: "unknown", Platforms: []string{"IOS"}}}},
+		{name: "routed twice", routes: []config.PushRoute{
+			{Provider: "webpush", Platforms: []string{"Web"}},
+			{Provider: "webpush", Platforms: []string{"MiniWeb"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pushConf := &config.Push{Routes: tt.routes}
+			if _, err := newRoutingPusher(pushConf, nil, ""); err == nil {
+				t.Fatal("expected the routes to be rejected")
+			}
+		})
+	}
+}
+
+func TestRoutingPusherPlatforms(t *testing.T) {
+	android := &fakePusher{}
+	ios := &fakePusher{err: errors.New("apns down")}
+	web := &fakePusher{}
+	r := &routingPusher{routes: []*route{
+		{provider: huaweiPush, platforms: []int{constant.AndroidPlatformID, constant.AndroidPadPlatformID}, pusher: android},
+		{provider: apple, platforms: []int{constant.IOSPlatformID}, pusher: ios},
+		{provider: webPush, platforms: []int{constant.WebPlatformID}, pusher: web},
+	}}
+	opts := &options.Opts{Signal: &options.Signal{}}
+	err := r.Push(context.Background(), []string{"u1"}, "title", "content", opts)
+	var providersErr *ProvidersError
+	if !errors.As(err, &providersErr) || !reflect.DeepEqual(providersErr.Providers, []string{apple}) {
+		t.Fatalf("got err %v, want the apns route failed", err)
+	}
+	if opts.Platforms != nil {
+		t.Fatalf("opts of the caller changed: %v", opts.Platforms)
+	}
+	for _, rt := range r.routes {
+		pusher := rt.pusher.(*fakePusher)
+		if len(pusher.platforms) != 1 || !slices.Equal(pusher.platforms[0], rt.platforms) {
+			t.Errorf("%s pushed with platforms %v, want %v", rt.provider, pusher.platforms, rt.platforms)
+		}
+	}
+
+	if err := r.PushProviders(context.Background(), []string{webPush}, []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(android.platforms) != 1 || len(ios.platforms) != 1 || len(web.platforms) != 2 {
+		t.Fatalf("only the webpush route should be retried, pushed %d, %d, %d", len(android.platforms), len(ios.platforms), len(web.platforms))
+	}
+}
+
+func TestOptsTerminal(t *testing.T) {
+	terminal := []int{constant.IOSPlatformID, constant.AndroidPlatformID, constant.WebPlatformID}
+	if got := (&options.Opts{}).Terminal(terminal); !slices.Equal(got, terminal) {
+		t.Fatalf("unrouted push got %v, want %v", got, terminal)
+	}
+	opts := &options.Opts{Platforms: []int{constant.AndroidPlatformID, constant.AndroidPadPlatformID}}
+	if got := opts.Terminal(terminal); !slices.Equal(got, []int{constant.AndroidPlatformID}) {
+		t.Fatalf("routed push got %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vivo
+
+import (
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+const (
+	// notifyAll rings, vibrates and lights for the notification.
+	notifyAll = 4
+	// skipOpenApp opens the app when the notification is tapped.
+	skipOpenApp = 1
+	// networkAny delivers the notification on any network.
+	networkAny = -1
+
+	maxTitleLength   = 40
+	maxContentLength = 100
+)
+
+type authReq struct {
+	AppID     string `json:"appId"`
+	AppKey    string `json:"appKey"`
+	Timestamp int64  `json:"timestamp"`
+	Sign      string `json:"sign"`
+}
+
+type authResp struct {
+	Result    int    `json:"result"`
+	Desc      string `json:"desc"`
+	AuthToken string `json:"authToken"`
+}
+
+// message is the notification pushed to a registration ID, or saved to be pushed to a list of them when RegID is empty.
+type message struct {
+	RegID           string            `json:"regId,omitempty"`
+	NotifyType      int               `json:"notifyType"`
+	Title           string            `json:"title"`
+	Content         string            `json:"content"`
+	TimeToLive      int               `json:"timeToLive"`
+	SkipType        int               `json:"skipType"`
+	NetworkType     int               `json:"networkType"`
+	Classification  int               `json:"classification"`
+	ClientCustomMap map[string]string `json:"clientCustomMap,omitempty"`
+	RequestID       string            `json:"requestId"`
+}
+
+type sendResp struct {
+	Result int    `json:"result"`
+	Desc   string `json:"desc"`
+	TaskID string `json:"taskId"`
+	// InvalidUser is the registration ID a single push was sent to if vivo no longer knows it.
+	InvalidUser *invalidUser `json:"invalidUser"`
+}
+
+type invalidUser struct {
+	Status int    `json:"status"`
+	UserID string `json:"userid"`
+}
+
+type pushToListReq struct {
+	RegIDs    []string `json:"regIds"`
+	TaskID    string   `json:"taskId"`
+	RequestID string   `json:"requestId"`
+}
+
+type pushToListResp struct {
+	Result       int           `json:"result"`
+	Desc         string        `json:"desc"`
+	InvalidUsers []invalidUser `json:"invalidUsers"`
+}
+
+// newMessage builds the notification of a message, shortened to the lengths vivo accepts.
+func newMessage(pushConf *config.Push, title, content string, opts *options.Opts) *message {
+	if title == "" {
+		title = content
+	}
+	m := &message{
+		NotifyType:      notifyAll,
+		Title:           truncate(title, maxTitleLength),
+		Content:         truncate(content, maxContentLength),
+		TimeToLive:      int(oem.TTL.Seconds()),
+		SkipType:        skipOpenApp,
+		NetworkType:     networkAny,
+		Classification:  pushConf.Vivo.Classification,
+		ClientCustomMap: map[string]string{"ex": opts.Ex},
+	}
+	if opts.Signal != nil {
+		m.ClientCustomMap["clientMsgID"] = opts.Signal.ClientMsgID
+	}
+	return m
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
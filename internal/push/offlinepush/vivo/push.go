@@ -0,0 +1,163 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vivo
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/tools/errs"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultURL     = "https://api-push.vivo.com.cn"
+	authPath       = "/message/auth"
+	sendPath       = "/message/send"
+	saveListPath   = "/message/saveListPayload"
+	pushToListPath = "/message/pushToList"
+
+	// authTokenExpire is how long an auth token is valid for.
+	authTokenExpire = time.Hour * 24
+
+	// Codes.
+	successCode          = 0
+	invalidAuthTokenCode = 10000
+	concurrentPush       = 4
+)
+
+// Vivo pushes through the vivo Push service to the devices whose registration IDs it issued.
+// It only shows notifications, a push with neither title nor content is not sent.
+type Vivo struct {
+	pushConf    *config.Push
+	cache       cache.ThirdCache
+	client      *oem.Client
+	url         string
+	accessToken *oem.AccessToken
+}
+
+func NewClient(pushConf *config.Push, cache cache.ThirdCache) (*Vivo, error) {
+	conf := pushConf.Vivo
+	if conf.AppID == "" || conf.AppKey == "" || conf.AppSecret == "" {
+		return nil, errs.New("no vivo push config").Wrap()
+	}
+	v := &Vivo{pushConf: pushConf, cache: cache, client: oem.NewClient(), url: defaultURL}
+	v.accessToken = oem.NewAccessToken(v.auth)
+	return v, nil
+}
+
+func (v *Vivo) auth(ctx context.Context) (string, time.Duration, error) {
+	conf := v.pushConf.Vivo
+	req := authReq{AppID: conf.AppID, AppKey: conf.AppKey, Timestamp: time.Now().UnixMilli()}
+	sign := md5.Sum([]byte(conf.AppID + conf.AppKey + strconv.FormatInt(req.Timestamp, 10) + conf.AppSecret))
+	req.Sign = hex.EncodeToString(sign[:])
+	var resp authResp
+	if err := v.client.PostJSON(ctx, v.url+authPath, nil, &req, &resp); err != nil {
+		return "", 0, errs.WrapMsg(err, "vivo auth failed")
+	}
+	if resp.Result != successCode || resp.AuthToken == "" {
+		return "", 0, errs.New("vivo auth failed", "result", resp.Result, "desc", resp.Desc).Wrap()
+	}
+	return resp.AuthToken, authTokenExpire, nil
+}
+
+func (v *Vivo) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	if title == "" && content == "" {
+		return nil
+	}
+	tokens := oem.Tokens(ctx, v.cache, thirdext.VendorVivo, userIDs, opts)
+	if len(tokens) == 0 {
+		return nil
+	}
+	var failures oem.Failures
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	for _, batch := range oem.Batches(tokens) {
+		g.Go(func() error {
+			if err := v.send(ctx, batch, newMessage(v.pushConf, title, content, opts)); err != nil {
+				failures.Add(batch, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return failures.Err(thirdext.VendorVivo)
+}
+
+// send pushes a single device directly, and more by saving the message and pushing it to their list.
+func (v *Vivo) send(ctx context.Context, tokens []oem.Token, m *message) error {
+	accessToken, err := v.accessToken.Get(ctx)
+	if err != nil {
+		return err
+	}
+	header := map[string]string{"authToken": accessToken}
+	m.RequestID = uuid.NewString()
+	if len(tokens) == 1 {
+		m.RegID = tokens[0].Token
+	}
+	var resp sendResp
+	path := saveListPath
+	if m.RegID != "" {
+		path = sendPath
+	}
+	if err := v.client.PostJSON(ctx, v.url+path, header, m, &resp); err != nil {
+		return err
+	}
+	if err := v.checkResult(accessToken, resp.Result, resp.Desc); err != nil {
+		return err
+	}
+	if m.RegID != "" {
+		if resp.InvalidUser != nil {
+			oem.DelTokens(ctx, v.cache, thirdext.VendorVivo, tokens, []string{resp.InvalidUser.UserID})
+		}
+		return nil
+	}
+	req := pushToListReq{TaskID: resp.TaskID, RequestID: uuid.NewString()}
+	for _, token := range tokens {
+		req.RegIDs = append(req.RegIDs, token.Token)
+	}
+	var listResp pushToListResp
+	if err := v.client.PostJSON(ctx, v.url+pushToListPath, header, &req, &listResp); err != nil {
+		return err
+	}
+	if err := v.checkResult(accessToken, listResp.Result, listResp.Desc); err != nil {
+		return err
+	}
+	invalid := make([]string, 0, len(listResp.InvalidUsers))
+	for _, user := range listResp.InvalidUsers {
+		invalid = append(invalid, user.UserID)
+	}
+	oem.DelTokens(ctx, v.cache, thirdext.VendorVivo, tokens, invalid)
+	return nil
+}
+
+func (v *Vivo) checkResult(accessToken string, result int, desc string) error {
+	if result == successCode {
+		return nil
+	}
+	if result == invalidAuthTokenCode {
+		v.accessToken.Reset(accessToken)
+	}
+	return errs.New("vivo push failed", "result", result, "desc", desc).Wrap()
+}
@@ -0,0 +1,167 @@
+package vivo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+)
+
+// fakeVivo serves the vivo push API, the registration IDs starting with "invalid" are not known.
+type fakeVivo struct {
+	lock sync.Mutex
+	// authToken is the auth token issued and accepted
+	authToken string
+	paths     []string
+	messages  []message
+	lists     []pushToListReq
+}
+
+func (f *fakeVivo) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.paths = append(f.paths, r.URL.Path)
+	if r.URL.Path != authPath && r.Header.Get("authToken") != f.authToken {
+		_ = json.NewEncoder(w).Encode(&sendResp{Result: invalidAuthTokenCode})
+		return
+	}
+	switch r.URL.Path {
+	case authPath:
+		var req authReq
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.AppID != "app" || req.AppKey != "key" || req.Sign == "" {
+			_ = json.NewEncoder(w).Encode(&authResp{Result: 1, Desc: "bad sign"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&authResp{AuthToken: f.authToken})
+	case sendPath, saveListPath:
+		var m message
+		_ = json.NewDecoder(r.Body).Decode(&m)
+		f.messages = append(f.messages, m)
+		resp := sendResp{TaskID: "task1"}
+		if m.RegID == "invalid" {
+			resp.InvalidUser = &invalidUser{Status: 1, UserID: m.RegID}
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	case pushToListPath:
+		var req pushToListReq
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		f.lists = append(f.lists, req)
+		var resp pushToListResp
+		for _, regID := range req.RegIDs {
+			if regID == "invalid" {
+				resp.InvalidUsers = append(resp.InvalidUsers, invalidUser{Status: 1, UserID: regID})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(&resp)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestVivo(t *testing.T, tokens map[string]string) (*Vivo, *fakeVivo, *oemtest.ThirdCache) {
+	api := &fakeVivo{authToken: "auth1"}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	pushConf := &config.Push{}
+	pushConf.Vivo.AppID = "app"
+	pushConf.Vivo.AppKey = "key"
+	pushConf.Vivo.AppSecret = "secret"
+	pushConf.Vivo.Classification = 1
+	thirdCache := oemtest.NewAndroidThirdCache(thirdext.VendorVivo, tokens)
+	v, err := NewClient(pushConf, thirdCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.url = srv.URL
+	return v, api, thirdCache
+}
+
+func TestPushSingle(t *testing.T) {
+	v, api, thirdCache := newTestVivo(t, map[string]string{"u1": "reg1"})
+	opts := &options.Opts{Ex: "ex", Signal: &options.Signal{ClientMsgID: "msg1"}}
+	if err := v.Push(context.Background(), []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.paths) != 2 || api.paths[0] != authPath || api.paths[1] != sendPath {
+		t.Fatalf("paths %v", api.paths)
+	}
+	m := api.messages[0]
+	if m.RegID != "reg1" || m.Title != "title" || m.Content != "content" || m.Classification != 1 || m.RequestID == "" ||
+		m.ClientCustomMap["ex"] != "ex" || m.ClientCustomMap["clientMsgID"] != "msg1" {
+		t.Fatalf("message %+v", m)
+	}
+	if len(thirdCache.Deleted()) != 0 {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushSingleInvalid(t *testing.T) {
+	v, api, thirdCache := newTestVivo(t, map[string]string{"u1": "invalid"})
+	if err := v.Push(context.Background(), []string{"u1"}, "title", "content", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if api.paths[len(api.paths)-1] != sendPath {
+		t.Fatalf("paths %v", api.paths)
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u1" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushList(t *testing.T) {
+	v, api, thirdCache := newTestVivo(t, map[string]string{"u1": "reg1", "u2": "invalid"})
+	if err := v.Push(context.Background(), []string{"u1", "u2"}, "", "content", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.paths) != 3 || api.paths[1] != saveListPath || api.paths[2] != pushToListPath {
+		t.Fatalf("paths %v", api.paths)
+	}
+	if m := api.messages[0]; m.RegID != "" || m.Title != "content" {
+		t.Fatalf("saved message %+v", m)
+	}
+	if list := api.lists[0]; list.TaskID != "task1" || len(list.RegIDs) != 2 {
+		t.Fatalf("list %+v", list)
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u2" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushInvalidAuthToken(t *testing.T) {
+	v, api, _ := newTestVivo(t, map[string]string{"u1": "reg1"})
+	ctx := context.Background()
+	if err := v.Push(ctx, []string{"u1"}, "title", "content", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	api.lock.Lock()
+	api.authToken = "auth2"
+	api.lock.Unlock()
+	if err := v.Push(ctx, []string{"u1"}, "title", "content", &options.Opts{}); err == nil {
+		t.Fatal("push with a revoked auth token did not fail")
+	}
+	// the token vivo rejected is dropped and renewed by the next push
+	if err := v.Push(ctx, []string{"u1"}, "title", "content", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(api.paths); n != 5 || api.paths[3] != authPath {
+		t.Fatalf("paths %v", api.paths)
+	}
+}
+
+func TestPushEmpty(t *testing.T) {
+	v, api, _ := newTestVivo(t, map[string]string{"u1": "reg1"})
+	if err := v.Push(context.Background(), []string{"u1"}, "", "", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(api.paths) != 0 {
+		t.Fatalf("a push with neither title nor content sent: %v", api.paths)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// recordSize is the rs of the single record a push is sent as.
+	recordSize = 4096
+	saltLength = 16
+	// headerLength is the salt, rs, idlen and the 65 bytes key id of the aes128gcm header.
+	headerLength = saltLength + 4 + 1 + 65
+	tagLength    = 16
+	// maxPlaintextLength keeps the body within the 4096 bytes every push service accepts.
+	maxPlaintextLength = recordSize - headerLength - tagLength - 1
+)
+
+// encrypt seals the plaintext to the browser as RFC 8291 describes, in the aes128gcm content coding of RFC 8188.
+// uaPublic is the p256dh of the subscription and authSecret its auth.
+func encrypt(plaintext []byte, uaPublic []byte, authSecret []byte) ([]byte, error) {
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encryptWith(plaintext, uaPublic, authSecret, asPrivate, salt)
+}
+
+func encryptWith(plaintext []byte, uaPublic []byte, authSecret []byte, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(plaintext) > maxPlaintextLength {
+		return nil, errors.New("web push payload is too large")
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	ecdhSecret, err := asPrivate.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	// the shared secret is mixed with the auth secret and both public keys into the input keying material
+	prkKey, err := hkdf.Extract(sha256.New, ecdhSecret, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	body := make([]byte, headerLength, headerLength+len(plaintext)+1+tagLength)
+	copy(body, salt)
+	binary.BigEndian.PutUint32(body[saltLength:], recordSize)
+	body[saltLength+4] = byte(len(asPublic))
+	copy(body[saltLength+5:], asPublic)
+	// 0x02 delimits the last and only record
+	record := append(append(make([]byte, 0, len(plaintext)+1), plaintext...), 2)
+	return gcm.Seal(body, nonce, record, nil), nil
+}
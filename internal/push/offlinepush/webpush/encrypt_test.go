@@ -0,0 +1,82 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+)
+
+// decrypt is what the browser does with the push, following RFC 8291.
+func decrypt(t *testing.T, body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) []byte {
+	salt := body[:saltLength]
+	if rs := binary.BigEndian.Uint32(body[saltLength:]); rs != recordSize {
+		t.Fatalf("rs %d", rs)
+	}
+	idLength := int(body[saltLength+4])
+	asPublic := body[saltLength+5 : saltLength+5+idLength]
+	asKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdhSecret, err := uaPrivate.ECDH(asKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uaPublic := uaPrivate.PublicKey().Bytes()
+	ikm, err := hkdf.Key(sha256.New, ecdhSecret, authSecret, "WebPush: info\x00"+string(uaPublic)+string(asPublic), 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := gcm.Open(nil, nonce, body[saltLength+5+idLength:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record[len(record)-1] != 2 {
+		t.Fatalf("record delimiter %d", record[len(record)-1])
+	}
+	return record[:len(record)-1]
+}
+
+func TestEncrypt(t *testing.T) {
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authSecret := make([]byte, 16)
+	if _, err := rand.Read(authSecret); err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte(`{"title":"hello","body":"world"}`)
+	body, err := encrypt(plaintext, uaPrivate.PublicKey().Bytes(), authSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decrypt(t, body, uaPrivate, authSecret); !bytes.Equal(got, plaintext) {
+		t.Fatalf("decrypted %q", got)
+	}
+	if _, err := encrypt(make([]byte, maxPlaintextLength+1), uaPrivate.PublicKey().Bytes(), authSecret); err == nil {
+		t.Fatal("a payload over the record size is encrypted")
+	}
+}
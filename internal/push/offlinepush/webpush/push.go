@@ -0,0 +1,299 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// vapidExpire is how long a VAPID token is valid for, push services refuse tokens valid for more than 24 hours.
+	vapidExpire = time.Hour * 12
+	// vapidRefresh renews a VAPID token well before it expires.
+	vapidRefresh = time.Hour * 6
+	// maxTopicLength is the most characters push services accept in the Topic header.
+	maxTopicLength = 32
+	defaultTTL     = 86400
+	concurrentPush = 20
+	pushTimeout    = time.Second * 10
+)
+
+// Terminal are the platforms whose users subscribe to the pushes in their browsers.
+var Terminal = []int{constant.WebPlatformID, constant.MiniWebPlatformID}
+
+// WebPush pushes to the browsers of the users through their push services, as RFC 8030 describes,
+// identifying the application server with VAPID (RFC 8292).
+type WebPush struct {
+	pushConf   *config.Push
+	cache      cache.ThirdCache
+	httpClient *http.Client
+	key        *ecdsa.PrivateKey
+	publicKey  string
+
+	lock   sync.Mutex
+	tokens map[string]vapidToken
+}
+
+type vapidToken struct {
+	token string
+	time  time.Time
+}
+
+// payload is what the service worker of the app receives in the push event.
+type payload struct {
+	Title       string `json:"title,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Ex          string `json:"ex,omitempty"`
+	ClientMsgID string `json:"clientMsgID,omitempty"`
+}
+
+// pushError is the status a push service rejected a push with.
+type pushError struct {
+	status int
+	body   string
+}
+
+func (e *pushError) Error() string {
+	return fmt.Sprintf("web push status %d body %s", e.status, e.body)
+}
+
+func NewClient(pushConf *config.Push, cache cache.ThirdCache) (*WebPush, error) {
+	conf := pushConf.WebPush
+	if conf.PublicKey == "" || conf.PrivateKey == "" || conf.Subject == "" {
+		return nil, errs.New("no web push config").Wrap()
+	}
+	publicKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(conf.PublicKey, "="))
+	if err != nil {
+		return nil, errs.WrapMsg(err, "decode VAPID public key failed")
+	}
+	privateKey, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(conf.PrivateKey, "="))
+	if err != nil {
+		return nil, errs.WrapMsg(err, "decode VAPID private key failed")
+	}
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), privateKey)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "parse VAPID private key failed")
+	}
+	if ecdhKey, err := key.PublicKey.ECDH(); err != nil || !bytes.Equal(ecdhKey.Bytes(), publicKey) {
+		return nil, errs.New("VAPID public key does not match the private key").Wrap()
+	}
+	return &WebPush{
+		pushConf: pushConf,
+		cache:    cache,
+		httpClient: &http.Client{
+			Timeout: pushTimeout,
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost: concurrentPush,
+				IdleConnTimeout:     time.Minute * 10,
+			},
+		},
+		key:       key,
+		publicKey: base64.RawURLEncoding.EncodeToString(publicKey),
+		tokens:    make(map[string]vapidToken),
+	}, nil
+}
+
+func (w *WebPush) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	if len(opts.Terminal(Terminal)) == 0 {
+		return nil
+	}
+	var failures failure.Collector
+	p := payload{Title: title, Body: content, Ex: opts.Ex}
+	if opts.Signal != nil {
+		p.ClientMsgID = opts.Signal.ClientMsgID
+	}
+	plaintext, err := marshalPayload(&p)
+	if err != nil {
+		return err
+	}
+	urgency := "normal"
+	if title != "" || content != "" {
+		urgency = "high"
+	}
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	now := time.Now().UnixMilli()
+	for _, userID := range userIDs {
+		subscriptions, err := w.cache.GetWebPushSubscriptions(ctx, userID)
+		if err != nil {
+			failures.Add([]string{userID}, 1, err)
+			continue
+		}
+		for _, subscription := range subscriptions {
+			if subscription.ExpirationTime > 0 && subscription.ExpirationTime < now {
+				w.delSubscription(ctx, userID, subscription.Endpoint)
+				continue
+			}
+			if err := thirdext.CheckWebPushEndpoint(subscription.Endpoint); err != nil {
+				// registered before the push services were restricted
+				w.delSubscription(ctx, userID, subscription.Endpoint)
+				continue
+			}
+			g.Go(func() error {
+				err := w.send(ctx, subscription, plaintext, urgency, p.ClientMsgID)
+				if err == nil {
+					return nil
+				}
+				var pushErr *pushError
+				if errors.As(err, &pushErr) && (pushErr.status == http.StatusNotFound || pushErr.status == http.StatusGone) {
+					// the browser unsubscribed or the subscription expired
+					w.delSubscription(ctx, userID, subscription.Endpoint)
+					return nil
+				}
+				failures.Add([]string{userID}, 1, err)
+				return nil
+			})
+		}
+	}
+	_ = g.Wait()
+	return failures.Err("web")
+}
+
+func (w *WebPush) delSubscription(ctx context.Context, userID string, endpoint string) {
+	log.ZInfo(ctx, "web push subscription gone", "userID", userID, "endpoint", endpoint)
+	if err := w.cache.DelWebPushSubscription(ctx, userID, endpoint); err != nil {
+		log.ZWarn(ctx, "delete web push subscription failed", err, "userID", userID, "endpoint", endpoint)
+	}
+}
+
+// marshalPayload keeps the payload within a single record, dropping ex and then shortening the body if it is too large.
+func marshalPayload(p *payload) ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if len(data) <= maxPlaintextLength {
+		return data, nil
+	}
+	p.Ex = ""
+	for {
+		data, err = json.Marshal(p)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		if len(data) <= maxPlaintextLength || p.Body == "" {
+			break
+		}
+		body := p.Body[:max(len(p.Body)-(len(data)-maxPlaintextLength), 0)]
+		for len(body) > 0 && !utf8.ValidString(body) {
+			body = body[:len(body)-1]
+		}
+		p.Body = body
+	}
+	if len(data) > maxPlaintextLength {
+		return nil, errs.New("web push payload is too large").Wrap()
+	}
+	return data, nil
+}
+
+func (w *WebPush) send(ctx context.Context, subscription *model.WebPushSubscription, plaintext []byte, urgency string, topic string) error {
+	uaPublic, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(subscription.P256dh, "="))
+	if err != nil {
+		return errs.WrapMsg(err, "decode p256dh failed", "endpoint", subscription.Endpoint)
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(subscription.Auth, "="))
+	if err != nil {
+		return errs.WrapMsg(err, "decode auth failed", "endpoint", subscription.Endpoint)
+	}
+	body, err := encrypt(plaintext, uaPublic, authSecret)
+	if err != nil {
+		return errs.WrapMsg(err, "encrypt web push failed", "endpoint", subscription.Endpoint)
+	}
+	token, err := w.vapidToken(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+	ttl := w.pushConf.WebPush.TTL
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	req.Header.Set("Authorization", "vapid t="+token+", k="+w.publicKey)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(ttl))
+	req.Header.Set("Urgency", urgency)
+	if topic != "" && len(topic) <= maxTopicLength {
+		// a newer push of the same message replaces the one still waiting for the browser
+		req.Header.Set("Topic", topic)
+	}
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return &pushError{status: resp.StatusCode, body: string(data)}
+}
+
+// vapidToken returns the JWT identifying the application server to the push service of the endpoint,
+// a token is bound to the origin of the push service.
+func (w *WebPush) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", errs.New("invalid web push endpoint", "endpoint", endpoint).Wrap()
+	}
+	audience := u.Scheme + "://" + u.Host
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if t, ok := w.tokens[audience]; ok && time.Since(t.time) < vapidRefresh {
+		return t.token, nil
+	}
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": now.Add(vapidExpire).Unix(),
+		"sub": w.pushConf.WebPush.Subject,
+	}).SignedString(w.key)
+	if err != nil {
+		return "", errs.WrapMsg(err, "sign VAPID token failed")
+	}
+	w.tokens[audience] = vapidToken{token: token, time: now}
+	return token, nil
+}
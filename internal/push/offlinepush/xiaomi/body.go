@@ -0,0 +1,72 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xiaomi
+
+import (
+	"hash/fnv"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+const (
+	// notifyDefaultAll rings, vibrates and lights for the notification.
+	notifyDefaultAll = "-1"
+)
+
+type sendResp struct {
+	Result      string `json:"result"`
+	Code        int    `json:"code"`
+	Description string `json:"description"`
+	Reason      string `json:"reason"`
+	Data        struct {
+		ID string `json:"id"`
+		// BadRegIDs are the comma separated registration IDs that are no longer valid.
+		BadRegIDs string `json:"bad_regids"`
+	} `json:"data"`
+}
+
+// newSendForm builds the push of a message to the registration IDs, one with neither title nor content
+// is passed through to the app without a notification.
+func newSendForm(pushConf *config.Push, regIDs []string, title, content string, opts *options.Opts) url.Values {
+	form := url.Values{
+		"registration_id":         {strings.Join(regIDs, ",")},
+		"restricted_package_name": {pushConf.Xiaomi.PackageName},
+		"payload":                 {oem.NewData(opts)},
+		"time_to_live":            {strconv.FormatInt(oem.TTL.Milliseconds(), 10)},
+	}
+	if title == "" && content == "" {
+		form.Set("pass_through", "1")
+		return form
+	}
+	form.Set("pass_through", "0")
+	form.Set("title", title)
+	form.Set("description", content)
+	form.Set("notify_type", notifyDefaultAll)
+	if pushConf.Xiaomi.ChannelID != "" {
+		form.Set("extra.channel_id", pushConf.Xiaomi.ChannelID)
+	}
+	if opts.Signal != nil && opts.Signal.ClientMsgID != "" {
+		// the notifications of the same message replace each other
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(opts.Signal.ClientMsgID))
+		form.Set("notify_id", strconv.Itoa(int(h.Sum32()&0x7fffffff)))
+	}
+	return form
+}
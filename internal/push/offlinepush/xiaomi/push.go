@@ -0,0 +1,95 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xiaomi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/tools/errs"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultPushURL = "https://api.xmpush.xiaomi.com"
+	sendURL        = "/v3/message/regid"
+
+	successCode    = 0
+	concurrentPush = 4
+)
+
+// Xiaomi pushes through the Mi Push service to the devices whose registration IDs it issued,
+// the pushes are authorized by the app secret.
+type Xiaomi struct {
+	pushConf *config.Push
+	cache    cache.ThirdCache
+	client   *oem.Client
+	url      string
+}
+
+func NewClient(pushConf *config.Push, cache cache.ThirdCache) (*Xiaomi, error) {
+	if pushConf.Xiaomi.AppSecret == "" || pushConf.Xiaomi.PackageName == "" {
+		return nil, errs.New("no xiaomi push config").Wrap()
+	}
+	url := strings.TrimRight(pushConf.Xiaomi.PushURL, "/")
+	if url == "" {
+		url = defaultPushURL
+	}
+	return &Xiaomi{pushConf: pushConf, cache: cache, client: oem.NewClient(), url: url + sendURL}, nil
+}
+
+func (x *Xiaomi) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	tokens := oem.Tokens(ctx, x.cache, thirdext.VendorXiaomi, userIDs, opts)
+	if len(tokens) == 0 {
+		return nil
+	}
+	var failures oem.Failures
+	g := errgroup.Group{}
+	g.SetLimit(concurrentPush)
+	for _, batch := range oem.Batches(tokens) {
+		g.Go(func() error {
+			if err := x.send(ctx, batch, title, content, opts); err != nil {
+				failures.Add(batch, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return failures.Err(thirdext.VendorXiaomi)
+}
+
+func (x *Xiaomi) send(ctx context.Context, tokens []oem.Token, title, content string, opts *options.Opts) error {
+	regIDs := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		regIDs = append(regIDs, token.Token)
+	}
+	header := map[string]string{"Authorization": "key=" + x.pushConf.Xiaomi.AppSecret}
+	var resp sendResp
+	if err := x.client.PostForm(ctx, x.url, header, newSendForm(x.pushConf, regIDs, title, content, opts), &resp); err != nil {
+		return err
+	}
+	if resp.Code != successCode {
+		return errs.New("xiaomi push failed", "code", resp.Code, "description", resp.Description, "reason", resp.Reason).Wrap()
+	}
+	if resp.Data.BadRegIDs != "" {
+		oem.DelTokens(ctx, x.cache, thirdext.VendorXiaomi, tokens, strings.Split(resp.Data.BadRegIDs, ","))
+	}
+	return nil
+}
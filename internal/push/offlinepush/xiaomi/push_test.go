@@ -0,0 +1,114 @@
+package xiaomi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/oem/oemtest"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+)
+
+// fakeXiaomi serves the Mi Push API, the registration IDs starting with "bad" are not known.
+type fakeXiaomi struct {
+	lock  sync.Mutex
+	forms []url.Values
+}
+
+func (f *fakeXiaomi) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if r.URL.Path != sendURL {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Header.Get("Authorization") != "key=secret" {
+		_ = json.NewEncoder(w).Encode(&sendResp{Result: "error", Code: 22006, Description: "invalid secret"})
+		return
+	}
+	_ = r.ParseForm()
+	f.forms = append(f.forms, r.PostForm)
+	var bad []string
+	for _, regID := range strings.Split(r.PostForm.Get("registration_id"), ",") {
+		if strings.HasPrefix(regID, "bad") {
+			bad = append(bad, regID)
+		}
+	}
+	resp := sendResp{Result: "ok", Code: successCode}
+	resp.Data.BadRegIDs = strings.Join(bad, ",")
+	_ = json.NewEncoder(w).Encode(&resp)
+}
+
+func newTestXiaomi(t *testing.T, tokens map[string]string) (*Xiaomi, *fakeXiaomi, *oemtest.ThirdCache) {
+	api := &fakeXiaomi{}
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	pushConf := &config.Push{}
+	pushConf.Xiaomi.AppSecret = "secret"
+	pushConf.Xiaomi.PackageName = "io.openim.app"
+	pushConf.Xiaomi.ChannelID = "im"
+	pushConf.Xiaomi.PushURL = srv.URL + "/"
+	thirdCache := oemtest.NewAndroidThirdCache(thirdext.VendorXiaomi, tokens)
+	x, err := NewClient(pushConf, thirdCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x, api, thirdCache
+}
+
+func TestPushBody(t *testing.T) {
+	x, api, thirdCache := newTestXiaomi(t, map[string]string{"u1": "reg1"})
+	opts := &options.Opts{Ex: "ex", Signal: &options.Signal{ClientMsgID: "msg1"}}
+	if err := x.Push(context.Background(), []string{"u1"}, "title", "content", opts); err != nil {
+		t.Fatal(err)
+	}
+	form := api.forms[0]
+	for key, value := range map[string]string{
+		"registration_id":         "reg1",
+		"restricted_package_name": "io.openim.app",
+		"pass_through":            "0",
+		"title":                   "title",
+		"description":             "content",
+		"extra.channel_id":        "im",
+	} {
+		if got := form.Get(key); got != value {
+			t.Errorf("%s %q, want %q", key, got, value)
+		}
+	}
+	if form.Get("notify_id") == "" {
+		t.Error("no notify_id")
+	}
+	var data map[string]string
+	if err := json.Unmarshal([]byte(form.Get("payload")), &data); err != nil || data["ex"] != "ex" || data["clientMsgID"] != "msg1" {
+		t.Fatalf("payload %s", form.Get("payload"))
+	}
+	if err := x.Push(context.Background(), []string{"u1"}, "", "", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if form := api.forms[1]; form.Get("pass_through") != "1" || form.Has("title") {
+		t.Fatalf("pass through form %v", form)
+	}
+	if len(thirdCache.Deleted()) != 0 {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
+
+func TestPushBadRegIDs(t *testing.T) {
+	x, api, thirdCache := newTestXiaomi(t, map[string]string{"u1": "reg1", "u2": "bad2"})
+	if err := x.Push(context.Background(), []string{"u1", "u2"}, "title", "content", &options.Opts{}); err != nil {
+		t.Fatal(err)
+	}
+	if regIDs := api.forms[0].Get("registration_id"); regIDs != "reg1,bad2" {
+		t.Fatalf("registration_id %s", regIDs)
+	}
+	if len(thirdCache.Deleted()) != 1 || thirdCache.Deleted()[0].UserID != "u2" {
+		t.Fatalf("deleted %v", thirdCache.Deleted())
+	}
+}
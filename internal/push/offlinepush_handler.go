@@ -16,12 +16,14 @@ import (
 )
 
 type OfflinePushConsumerHandler struct {
-	offlinePusher offlinepush.OfflinePusher
+	offlinePusher    offlinepush.OfflinePusher
+	offlinePushRetry *offlinePushRetry
 }
 
-func NewOfflinePushConsumerHandler(offlinePusher offlinepush.OfflinePusher) *OfflinePushConsumerHandler {
+func NewOfflinePushConsumerHandler(offlinePusher offlinepush.OfflinePusher, offlinePushRetry *offlinePushRetry) *OfflinePushConsumerHandler {
 	return &OfflinePushConsumerHandler{
-		offlinePusher: offlinePusher,
+		offlinePusher:    offlinePusher,
+		offlinePushRetry: offlinePushRetry,
 	}
 }
 
@@ -99,6 +101,7 @@ func (o *OfflinePushConsumerHandler) offlinePushMsg(ctx context.Context, msg *sd
 	err = o.offlinePusher.Push(ctx, offlinePushUserIDs, title, content, opts)
 	if err != nil {
 		prommetrics.MsgOfflinePushFailedCounter.Inc()
+		o.offlinePushRetry.add(ctx, offlinePushUserIDs, title, content, opts, err)
 		return err
 	}
 	return nil
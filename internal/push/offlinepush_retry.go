@@ -0,0 +1,261 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/mcontext"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultRetryMaxAttempts      = 8
+	defaultRetryInitialBackoff   = time.Second * 10
+	defaultRetryMaxBackoff       = time.Minute * 10
+	defaultRetryDeadLetterExpire = time.Hour * 24 * 7
+
+	// retryWorkers is how many retries a push service runs at once.
+	retryWorkers      = 4
+	retryPollInterval = time.Second
+	retryPushTimeout  = time.Minute
+	// retryClaimExpire is how long a claimed retry is left to its worker before another takes it over,
+	// in case the push service that claimed it was stopped.
+	retryClaimExpire = time.Minute * 5
+)
+
+// offlinePushRetry persists the offline pushes that failed and retries them in the background with exponential
+// backoff, so that an outage of a provider delays the notifications instead of losing them.
+// A nil offlinePushRetry drops the failed pushes.
+type offlinePushRetry struct {
+	database         controller.OfflinePushRetryDatabase
+	pusher           offlinepush.OfflinePusher
+	maxAttempts      int32
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	deadLetterExpire time.Duration
+}
+
+func newOfflinePushRetry(conf *config.Push, database controller.OfflinePushRetryDatabase, pusher offlinepush.OfflinePusher) *offlinePushRetry {
+	r := &offlinePushRetry{
+		database:         database,
+		pusher:           pusher,
+		maxAttempts:      int32(conf.OfflinePushRetry.MaxAttempts),
+		initialBackoff:   time.Duration(conf.OfflinePushRetry.InitialBackoff) * time.Second,
+		maxBackoff:       time.Duration(conf.OfflinePushRetry.MaxBackoff) * time.Second,
+		deadLetterExpire: time.Duration(conf.OfflinePushRetry.DeadLetterExpire) * time.Hour * 24,
+	}
+	if r.maxAttempts <= 0 {
+		r.maxAttempts = defaultRetryMaxAttempts
+	}
+	if r.initialBackoff <= 0 {
+		r.initialBackoff = defaultRetryInitialBackoff
+	}
+	if r.maxBackoff < r.initialBackoff {
+		r.maxBackoff = max(defaultRetryMaxBackoff, r.initialBackoff)
+	}
+	if r.deadLetterExpire <= 0 {
+		r.deadLetterExpire = defaultRetryDeadLetterExpire
+	}
+	return r
+}
+
+// backoff is the wait before the attempt, doubled after every failed attempt with a fifth of jitter,
+// so that the pushes failed together during an outage are not all retried at once. It never exceeds maxBackoff.
+func (r *offlinePushRetry) backoff(attempt int32) time.Duration {
+	d := r.initialBackoff
+	for i := int32(1); i < attempt && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, r.maxBackoff)
+	return min(d-d/10+time.Duration(rand.Int63n(int64(d/5)+1)), r.maxBackoff)
+}
+
+// pushFailure is a part of a failed push that is retried on its own.
+type pushFailure struct {
+	providers []string
+	userIDs   []string
+}
+
+// failures splits the failed push into the parts retried: each routed provider that failed with the users it failed for,
+// the users the push failed for otherwise. The users a pusher cannot tell are left out, pushing to all the users again
+// would repeat the push to those that got it.
+func failures(err error, providers []string, userIDs []string) []pushFailure {
+	var providersErr *offlinepush.ProvidersError
+	if errors.As(err, &providersErr) {
+		res := make([]pushFailure, 0, len(providersErr.Providers))
+		for _, provider := range providersErr.Providers {
+			if failUserIDs := failure.UserIDs(providersErr.Errs[provider], userIDs); len(failUserIDs) > 0 {
+				res = append(res, pushFailure{providers: []string{provider}, userIDs: failUserIDs})
+			}
+		}
+		return res
+	}
+	if failUserIDs := failure.UserIDs(err, userIDs); len(failUserIDs) > 0 {
+		return []pushFailure{{providers: providers, userIDs: failUserIDs}}
+	}
+	return nil
+}
+
+// add persists the failed push to be retried.
+func (r *offlinePushRetry) add(ctx context.Context, userIDs []string, title, content string, opts *options.Opts, pushErr error) {
+	if r == nil || len(userIDs) == 0 {
+		return
+	}
+	parts := failures(pushErr, nil, userIDs)
+	if len(parts) == 0 {
+		prommetrics.OfflinePushRetryCounter.WithLabelValues("dropped").Inc()
+		log.ZWarn(ctx, "offline push partly failed for unknown users, not retried", pushErr, "userIDs", len(userIDs))
+		return
+	}
+	now := time.Now()
+	for _, part := range parts {
+		retry := &model.OfflinePushRetry{
+			ID:            primitive.NewObjectID(),
+			UserIDs:       part.userIDs,
+			Title:         title,
+			Content:       content,
+			Ex:            opts.Ex,
+			IOSPushSound:  opts.IOSPushSound,
+			IOSBadgeCount: opts.IOSBadgeCount,
+			Providers:     part.providers,
+			Status:        model.OfflinePushRetryStatusPending,
+			LastError:     pushErr.Error(),
+			NextTime:      now.Add(r.backoff(1)),
+			CreateTime:    now,
+			UpdateTime:    now,
+		}
+		if opts.Signal != nil {
+			retry.ClientMsgID = opts.Signal.ClientMsgID
+		}
+		if err := r.database.AddRetry(ctx, retry); err != nil {
+			log.ZError(ctx, "persist offline push retry failed, the push is dropped", err, "userIDs", len(part.userIDs), "clientMsgID", retry.ClientMsgID)
+			continue
+		}
+		prommetrics.OfflinePushRetryCounter.WithLabelValues("scheduled").Inc()
+	}
+}
+
+// run claims the due retries and pushes them until ctx is done.
+func (r *offlinePushRetry) run(ctx context.Context) {
+	for {
+		retry, err := r.database.ClaimRetry(ctx, time.Now(), time.Now().Add(-retryClaimExpire))
+		if err != nil {
+			log.ZError(ctx, "claim offline push retry failed", err)
+		}
+		if retry == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryPollInterval):
+			}
+			continue
+		}
+		r.retry(retry)
+	}
+}
+
+func (r *offlinePushRetry) retry(retry *model.OfflinePushRetry) {
+	ctx := mcontext.SetOperationID(context.Background(), "retry_push_"+retry.ID.Hex())
+	ctx, cancel := context.WithTimeout(ctx, retryPushTimeout)
+	defer cancel()
+	opts := &options.Opts{
+		Signal:        &options.Signal{ClientMsgID: retry.ClientMsgID},
+		IOSPushSound:  retry.IOSPushSound,
+		IOSBadgeCount: retry.IOSBadgeCount,
+		Ex:            retry.Ex,
+	}
+	var err error
+	if pusher, ok := r.pusher.(offlinepush.ProviderPusher); ok && len(retry.Providers) > 0 {
+		err = pusher.PushProviders(ctx, retry.Providers, retry.UserIDs, retry.Title, retry.Content, opts)
+	} else {
+		err = r.pusher.Push(ctx, retry.UserIDs, retry.Title, retry.Content, opts)
+	}
+	id := retry.ID.Hex()
+	if err == nil {
+		prommetrics.OfflinePushRetryCounter.WithLabelValues("success").Inc()
+		log.ZInfo(ctx, "offline push retry succeeded", "id", id, "attempts", retry.Attempts+1)
+		if err := r.database.DeleteRetries(ctx, []string{id}); err != nil {
+			log.ZWarn(ctx, "delete offline push retry failed", err, "id", id)
+		}
+		return
+	}
+	now := time.Now()
+	attempts := retry.Attempts + 1
+	parts := failures(err, retry.Providers, retry.UserIDs)
+	if len(parts) == 0 {
+		prommetrics.OfflinePushRetryCounter.WithLabelValues("dropped").Inc()
+		log.ZWarn(ctx, "offline push retry partly failed for unknown users, not retried", err, "id", id, "attempts", attempts)
+		if err := r.database.DeleteRetries(ctx, []string{id}); err != nil {
+			log.ZWarn(ctx, "delete offline push retry failed", err, "id", id)
+		}
+		return
+	}
+	// the users and providers that got the push this time are not retried again
+	next := *retry
+	next.Attempts = attempts
+	next.LastError = err.Error()
+	next.UpdateTime = now
+	if attempts >= r.maxAttempts {
+		next.Status = model.OfflinePushRetryStatusDead
+		expireTime := now.Add(r.deadLetterExpire)
+		next.ExpireTime = &expireTime
+		prommetrics.OfflinePushRetryCounter.WithLabelValues("dead").Add(float64(len(parts)))
+		log.ZError(ctx, "offline push dead letter", err, "id", id, "attempts", attempts, "userIDs", len(retry.UserIDs))
+	} else {
+		next.Status = model.OfflinePushRetryStatusPending
+		next.NextTime = now.Add(r.backoff(attempts + 1))
+		prommetrics.OfflinePushRetryCounter.WithLabelValues("failed").Add(float64(len(parts)))
+		log.ZWarn(ctx, "offline push retry failed", err, "id", id, "attempts", attempts)
+	}
+	data := map[string]any{
+		"user_ids":    parts[0].userIDs,
+		"providers":   parts[0].providers,
+		"attempts":    next.Attempts,
+		"last_error":  next.LastError,
+		"status":      next.Status,
+		"update_time": next.UpdateTime,
+	}
+	if next.ExpireTime != nil {
+		data["expire_time"] = *next.ExpireTime
+	} else {
+		data["next_time"] = next.NextTime
+	}
+	if err := r.database.UpdateRetry(ctx, id, data); err != nil {
+		log.ZError(ctx, "update offline push retry failed", err, "id", id)
+	}
+	// the providers that failed for different users are retried apart
+	for _, part := range parts[1:] {
+		split := next
+		split.ID = primitive.NewObjectID()
+		split.UserIDs = part.userIDs
+		split.Providers = part.providers
+		split.ClaimTime = time.Time{}
+		if err := r.database.AddRetry(ctx, &split); err != nil {
+			log.ZError(ctx, "persist offline push retry failed, the push is dropped", err, "id", id, "providers", part.providers)
+		}
+	}
+}
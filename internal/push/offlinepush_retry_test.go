@@ -0,0 +1,320 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/failure"
+	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+// fakeRetryDatabase keeps the retries in memory, claiming them like the mongo one.
+type fakeRetryDatabase struct {
+	retries map[string]*model.OfflinePushRetry
+}
+
+func newFakeRetryDatabase() *fakeRetryDatabase {
+	return &fakeRetryDatabase{retries: make(map[string]*model.OfflinePushRetry)}
+}
+
+func (f *fakeRetryDatabase) AddRetry(_ context.Context, retry *model.OfflinePushRetry) error {
+	r := *retry
+	f.retries[retry.ID.Hex()] = &r
+	return nil
+}
+
+func (f *fakeRetryDatabase) ClaimRetry(_ context.Context, now time.Time, claimExpire time.Time) (*model.OfflinePushRetry, error) {
+	var claim *model.OfflinePushRetry
+	for _, r := range f.retries {
+		due := (r.Status == model.OfflinePushRetryStatusPending && !r.NextTime.After(now)) ||
+			(r.Status == model.OfflinePushRetryStatusRetrying && r.ClaimTime.Before(claimExpire))
+		if due && (claim == nil || r.NextTime.Before(claim.NextTime)) {
+			claim = r
+		}
+	}
+	if claim == nil {
+		return nil, nil
+	}
+	claim.Status = model.OfflinePushRetryStatusRetrying
+	claim.ClaimTime = now
+	r := *claim
+	return &r, nil
+}
+
+func (f *fakeRetryDatabase) UpdateRetry(_ context.Context, id string, data map[string]any) error {
+	r, ok := f.retries[id]
+	if !ok {
+		return errors.New("retry not found")
+	}
+	for k, v := range data {
+		switch k {
+		case "user_ids":
+			r.UserIDs = v.([]string)
+		case "providers":
+			r.Providers = v.([]string)
+		case "attempts":
+			r.Attempts = v.(int32)
+		case "last_error":
+			r.LastError = v.(string)
+		case "status":
+			r.Status = v.(int32)
+		case "next_time":
+			r.NextTime = v.(time.Time)
+		case "update_time":
+			r.UpdateTime = v.(time.Time)
+		case "expire_time":
+			t := v.(time.Time)
+			r.ExpireTime = &t
+		default:
+			return errors.New("unknown field " + k)
+		}
+	}
+	return nil
+}
+
+func (f *fakeRetryDatabase) DeleteRetries(_ context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(f.retries, id)
+	}
+	return nil
+}
+
+func (f *fakeRetryDatabase) PageDeadLetters(_ context.Context, _ string, _ pagination.Pagination) (int64, []*model.OfflinePushRetry, error) {
+	var dead []*model.OfflinePushRetry
+	for _, r := range f.retries {
+		if r.Status == model.OfflinePushRetryStatusDead {
+			dead = append(dead, r)
+		}
+	}
+	return int64(len(dead)), dead, nil
+}
+
+func (f *fakeRetryDatabase) RequeueDeadLetters(_ context.Context, ids []string, now time.Time) (int64, error) {
+	var count int64
+	for _, id := range ids {
+		r, ok := f.retries[id]
+		if !ok || r.Status != model.OfflinePushRetryStatusDead {
+			continue
+		}
+		r.Status = model.OfflinePushRetryStatusPending
+		r.Attempts = 0
+		r.NextTime = now
+		r.UpdateTime = now
+		r.ExpireTime = nil
+		count++
+	}
+	return count, nil
+}
+
+func (f *fakeRetryDatabase) only(t *testing.T) *model.OfflinePushRetry {
+	t.Helper()
+	if len(f.retries) != 1 {
+		t.Fatalf("retries %d, want 1", len(f.retries))
+	}
+	for _, r := range f.retries {
+		return r
+	}
+	return nil
+}
+
+type fakePush struct {
+	providers []string
+	userIDs   []string
+}
+
+// fakePusher is a routed pusher whose providers fail as failProviders tells, for the users listed or for all.
+type fakePusher struct {
+	pushes        []fakePush
+	failProviders map[string][]string
+}
+
+func (f *fakePusher) Push(ctx context.Context, userIDs []string, title, content string, opts *options.Opts) error {
+	return f.PushProviders(ctx, nil, userIDs, title, content, opts)
+}
+
+func (f *fakePusher) PushProviders(_ context.Context, providers []string, userIDs []string, _, _ string, _ *options.Opts) error {
+	f.pushes = append(f.pushes, fakePush{providers: providers, userIDs: userIDs})
+	if providers == nil {
+		for provider := range f.failProviders {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+	}
+	res := &offlinepush.ProvidersError{Errs: make(map[string]error)}
+	for _, provider := range providers {
+		failUserIDs, ok := f.failProviders[provider]
+		if !ok {
+			continue
+		}
+		res.Providers = append(res.Providers, provider)
+		if failUserIDs == nil {
+			res.Errs[provider] = errors.New(provider + " down")
+		} else {
+			res.Errs[provider] = &failure.UsersError{UserIDs: failUserIDs, Err: errors.New(provider + " partly failed")}
+		}
+	}
+	if len(res.Providers) == 0 {
+		return nil
+	}
+	return res
+}
+
+func newTestRetry(database *fakeRetryDatabase, pusher offlinepush.OfflinePusher) *offlinePushRetry {
+	var conf config.Push
+	conf.OfflinePushRetry.MaxAttempts = 2
+	conf.OfflinePushRetry.InitialBackoff = 10
+	conf.OfflinePushRetry.MaxBackoff = 60
+	return newOfflinePushRetry(&conf, database, pusher)
+}
+
+func TestOfflinePushRetryBackoff(t *testing.T) {
+	r := newTestRetry(newFakeRetryDatabase(), &fakePusher{})
+	for i := 0; i < 100; i++ {
+		if d := r.backoff(1); d < time.Second*9 || d > time.Second*11 {
+			t.Fatalf("first backoff %s", d)
+		}
+		if d := r.backoff(2); d < time.Second*18 || d > time.Second*22 {
+			t.Fatalf("second backoff %s", d)
+		}
+		for _, attempt := range []int32{4, 10, 100} {
+			if d := r.backoff(attempt); d > r.maxBackoff || d < r.maxBackoff-r.maxBackoff/10 {
+				t.Fatalf("backoff %d %s not capped at %s", attempt, d, r.maxBackoff)
+			}
+		}
+	}
+}
+
+func TestOfflinePushRetryProviders(t *testing.T) {
+	ctx := context.Background()
+	database := newFakeRetryDatabase()
+	pusher := &fakePusher{failProviders: map[string][]string{"apns": nil, "fcm": {"u2"}}}
+	r := newTestRetry(database, pusher)
+	userIDs := []string{"u1", "u2", "u3"}
+	opts := &options.Opts{Signal: &options.Signal{ClientMsgID: "msg1"}}
+	r.add(ctx, userIDs, "title", "content", opts, pusher.Push(ctx, userIDs, "title", "content", opts))
+	if len(database.retries) != 2 {
+		t.Fatalf("retries %d, want one per failed provider", len(database.retries))
+	}
+
+	// none is due before its backoff
+	if retry, _ := database.ClaimRetry(ctx, time.Now(), time.Now().Add(-retryClaimExpire)); retry != nil {
+		t.Fatalf("claimed before due %+v", retry)
+	}
+	// fcm recovers, apns is still down
+	pusher.failProviders = map[string][]string{"apns": nil}
+	pusher.pushes = nil
+	now := time.Now().Add(r.initialBackoff * 2)
+	for i := 0; i < 2; i++ {
+		retry, err := database.ClaimRetry(ctx, now, now.Add(-retryClaimExpire))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retry == nil || retry.ClientMsgID != "msg1" {
+			t.Fatalf("claimed retry %d %+v", i, retry)
+		}
+		r.retry(retry)
+	}
+	if len(pusher.pushes) != 2 {
+		t.Fatalf("pushes %+v", pusher.pushes)
+	}
+	for _, push := range pusher.pushes {
+		switch {
+		case slices.Equal(push.providers, []string{"fcm"}):
+			if !slices.Equal(push.userIDs, []string{"u2"}) {
+				t.Fatalf("fcm retried to %v, only u2 failed", push.userIDs)
+			}
+		case slices.Equal(push.providers, []string{"apns"}):
+			if !slices.Equal(push.userIDs, userIDs) {
+				t.Fatalf("apns retried to %v", push.userIDs)
+			}
+		default:
+			t.Fatalf("retried through %v", push.providers)
+		}
+	}
+	// the fcm one succeeded
+	retry := database.only(t)
+	if retry.Status != model.OfflinePushRetryStatusPending || retry.Attempts != 1 || !retry.NextTime.After(time.Now()) {
+		t.Fatalf("failed retry not scheduled again %+v", retry)
+	}
+
+	// the last attempt makes it a dead letter
+	now = retry.NextTime
+	claim, _ := database.ClaimRetry(ctx, now, now.Add(-retryClaimExpire))
+	if claim == nil {
+		t.Fatal("due retry not claimed")
+	}
+	r.retry(claim)
+	if retry.Status != model.OfflinePushRetryStatusDead || retry.Attempts != 2 || retry.ExpireTime == nil {
+		t.Fatalf("retry out of attempts %+v", retry)
+	}
+	if claim, _ := database.ClaimRetry(ctx, now.Add(time.Hour), now); claim != nil {
+		t.Fatal("dead letter claimed")
+	}
+
+	// requeued it is retried again from the first attempt
+	if count, _ := database.RequeueDeadLetters(ctx, []string{retry.ID.Hex()}, now); count != 1 {
+		t.Fatalf("requeued %d", count)
+	}
+	if retry.Status != model.OfflinePushRetryStatusPending || retry.Attempts != 0 || retry.ExpireTime != nil {
+		t.Fatalf("requeued %+v", retry)
+	}
+	pusher.failProviders = nil
+	claim, _ = database.ClaimRetry(ctx, now, now.Add(-retryClaimExpire))
+	if claim == nil {
+		t.Fatal("requeued retry not claimed")
+	}
+	r.retry(claim)
+	if len(database.retries) != 0 {
+		t.Fatalf("retries %d after the push succeeded", len(database.retries))
+	}
+}
+
+func TestOfflinePushRetryClaimExpire(t *testing.T) {
+	ctx := context.Background()
+	database := newFakeRetryDatabase()
+	r := newTestRetry(database, &fakePusher{})
+	r.add(ctx, []string{"u1"}, "title", "content", &options.Opts{}, errors.New("push failed"))
+	now := time.Now().Add(r.maxBackoff)
+	if claim, _ := database.ClaimRetry(ctx, now, now.Add(-retryClaimExpire)); claim == nil {
+		t.Fatal("due retry not claimed")
+	}
+	// the worker that claimed it stopped
+	if claim, _ := database.ClaimRetry(ctx, now, now.Add(-retryClaimExpire)); claim != nil {
+		t.Fatal("claimed retry claimed again")
+	}
+	later := now.Add(retryClaimExpire + time.Second)
+	if claim, _ := database.ClaimRetry(ctx, later, later.Add(-retryClaimExpire)); claim == nil {
+		t.Fatal("expired claim not taken over")
+	}
+}
+
+func TestOfflinePushRetryPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	database := newFakeRetryDatabase()
+	r := newTestRetry(database, &fakePusher{})
+	userIDs := []string{"u1", "u2", "u3"}
+	// a pusher telling the users it failed for retries only them
+	r.add(ctx, userIDs, "title", "content", &options.Opts{}, &failure.UsersError{UserIDs: []string{"u3"}, Err: errors.New("partly failed")})
+	if retry := database.only(t); !slices.Equal(retry.UserIDs, []string{"u3"}) || retry.Providers != nil {
+		t.Fatalf("retry %+v", retry)
+	}
+	// nor is any retried when it cannot tell them
+	database.retries = make(map[string]*model.OfflinePushRetry)
+	r.add(ctx, userIDs, "title", "content", &options.Opts{}, &failure.UsersError{Err: errors.New("partly failed")})
+	if len(database.retries) != 0 {
+		t.Fatalf("retries %d for unknown users", len(database.retries))
+	}
+	// a push that failed as a whole is retried to all
+	r.add(ctx, userIDs, "title", "content", &options.Opts{}, errors.New("push failed"))
+	if retry := database.only(t); !slices.Equal(retry.UserIDs, userIDs) {
+		t.Fatalf("retry %+v", retry)
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
 	"github.com/openimsdk/open-im-server/v3/pkg/dbbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/mqbuild"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/pushext"
 	pbpush "github.com/openimsdk/protocol/push"
+	"github.com/openimsdk/tools/db/mongoutil"
 	"github.com/openimsdk/tools/discovery"
 	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/mcontext"
@@ -25,9 +27,11 @@ import (
 
 type pushServer struct {
 	pbpush.UnimplementedPushMsgServiceServer
+	pushext.UnimplementedPushExtServer
 	database      controller.PushDatabase
 	disCov        discovery.Conn
 	offlinePusher offlinepush.OfflinePusher
+	retryDatabase controller.OfflinePushRetryDatabase
 }
 
 type Config struct {
@@ -57,12 +61,15 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
-	var cacheModel cache.ThirdCache
-	if rdb == nil {
-		mdb, err := dbb.Mongo(ctx)
+	var mdb *mongoutil.Client
+	if rdb == nil || config.RpcConfig.OfflinePushRetry.Enable {
+		mdb, err = dbb.Mongo(ctx)
 		if err != nil {
 			return err
 		}
+	}
+	var cacheModel cache.ThirdCache
+	if rdb == nil {
 		mc, err := mgo.NewCacheMgo(mdb.GetDB())
 		if err != nil {
 			return err
@@ -92,18 +99,41 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 		return err
 	}
 
-	pushHandler, err := NewConsumerHandler(ctx, config, database, offlinePusher, rdb, client)
+	var (
+		retryDatabase controller.OfflinePushRetryDatabase
+		retry         *offlinePushRetry
+	)
+	if config.RpcConfig.OfflinePushRetry.Enable {
+		retryDB, err := mgo.NewOfflinePushRetryMongo(mdb.GetDB())
+		if err != nil {
+			return err
+		}
+		retryDatabase = controller.NewOfflinePushRetryDatabase(retryDB)
+		retry = newOfflinePushRetry(&config.RpcConfig, retryDatabase, offlinePusher)
+	}
+
+	pushHandler, err := NewConsumerHandler(ctx, config, database, offlinePusher, retry, rdb, client)
 	if err != nil {
 		return err
 	}
 
-	offlineHandler := NewOfflinePushConsumerHandler(offlinePusher)
+	offlineHandler := NewOfflinePushConsumerHandler(offlinePusher, retry)
 
-	pbpush.RegisterPushMsgServiceServer(server, &pushServer{
+	srv := &pushServer{
 		database:      database,
 		disCov:        client,
 		offlinePusher: offlinePusher,
-	})
+		retryDatabase: retryDatabase,
+	}
+	pbpush.RegisterPushMsgServiceServer(server, srv)
+	pushext.RegisterPushExtServer(server, srv)
+
+	if retry != nil {
+		retryCtx := mcontext.SetOperationID(context.Background(), "push_retry_"+strconv.Itoa(int(rand.Uint32())))
+		for i := 0; i < retryWorkers; i++ {
+			go retry.run(retryCtx)
+		}
+	}
 
 	go func() {
 		pushHandler.WaitCache()
@@ -8,9 +8,12 @@ import (
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/options"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	redis2 "github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/redis"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/webhook"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcli"
 	"github.com/openimsdk/open-im-server/v3/pkg/util/conversationutil"
@@ -31,9 +34,11 @@ import (
 type ConsumerHandler struct {
 	//pushConsumerGroup      mq.Consumer
 	offlinePusher          offlinepush.OfflinePusher
+	offlinePushRetry       *offlinePushRetry
 	onlinePusher           OnlinePusher
 	pushDatabase           controller.PushDatabase
 	onlineCache            rpccache.OnlineCache
+	presenceCache          cache.PresenceCache
 	groupLocalCache        *rpccache.GroupLocalCache
 	conversationLocalCache *rpccache.ConversationLocalCache
 	webhookClient          *webhook.Client
@@ -44,7 +49,7 @@ type ConsumerHandler struct {
 	conversationClient     *rpcli.ConversationClient
 }
 
-func NewConsumerHandler(ctx context.Context, config *Config, database controller.PushDatabase, offlinePusher offlinepush.OfflinePusher, rdb redis.UniversalClient, client discovery.Conn) (*ConsumerHandler, error) {
+func NewConsumerHandler(ctx context.Context, config *Config, database controller.PushDatabase, offlinePusher offlinepush.OfflinePusher, offlinePushRetry *offlinePushRetry, rdb redis.UniversalClient, client discovery.Conn) (*ConsumerHandler, error) {
 	userConn, err := client.GetConn(ctx, config.Discovery.RpcService.User)
 	if err != nil {
 		return nil, err
@@ -72,12 +77,14 @@ func NewConsumerHandler(ctx context.Context, config *Config, database controller
 	consumerHandler.conversationClient = rpcli.NewConversationClient(conversationConn)
 
 	consumerHandler.offlinePusher = offlinePusher
+	consumerHandler.offlinePushRetry = offlinePushRetry
 	consumerHandler.onlinePusher = onlinePusher
 	consumerHandler.groupLocalCache = rpccache.NewGroupLocalCache(consumerHandler.groupClient, &config.LocalCacheConfig, rdb)
 	consumerHandler.conversationLocalCache = rpccache.NewConversationLocalCache(consumerHandler.conversationClient, &config.LocalCacheConfig, rdb)
 	consumerHandler.webhookClient = webhook.NewWebhookClient(config.WebhooksConfig.URL)
 	consumerHandler.config = config
 	consumerHandler.pushDatabase = database
+	consumerHandler.presenceCache = redis2.NewPresenceCache(rdb)
 	consumerHandler.onlineCache, err = rpccache.NewOnlineCache(consumerHandler.userClient, consumerHandler.groupLocalCache, rdb, config.RpcConfig.FullUserCache, nil)
 	if err != nil {
 		return nil, err
@@ -263,6 +270,10 @@ func (c *ConsumerHandler) Push2Group(ctx context.Context, groupID string, msg *s
 }
 
 func (c *ConsumerHandler) asyncOfflinePush(ctx context.Context, needOfflinePushUserIDs []string, msg *sdkws.MsgData) {
+	needOfflinePushUserIDs = c.filterDoNotDisturb(ctx, needOfflinePushUserIDs)
+	if len(needOfflinePushUserIDs) == 0 {
+		return
+	}
 	var offlinePushUserIDs []string
 	err := c.webhookBeforeOfflinePush(ctx, &c.config.WebhooksConfig.BeforeOfflinePush, needOfflinePushUserIDs, msg, &offlinePushUserIDs)
 	if err != nil {
@@ -283,6 +294,32 @@ func (c *ConsumerHandler) asyncOfflinePush(ctx context.Context, needOfflinePushU
 	}
 }
 
+// filterDoNotDisturb removes the users whose presence is do not disturb, they are not pushed offline until it is cleared or expires.
+func (c *ConsumerHandler) filterDoNotDisturb(ctx context.Context, userIDs []string) []string {
+	presences, err := c.presenceCache.GetPresences(ctx, userIDs)
+	if err != nil {
+		log.ZWarn(ctx, "get presences failed", err, "userIDs length", len(userIDs))
+		return userIDs
+	}
+	dnd := make(map[string]struct{})
+	for _, presence := range presences {
+		if presence.State == userext.PresenceDoNotDisturb {
+			dnd[presence.UserID] = struct{}{}
+		}
+	}
+	if len(dnd) == 0 {
+		return userIDs
+	}
+	log.ZDebug(ctx, "offline push filter do not disturb", "userIDs", datautil.Keys(dnd))
+	res := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := dnd[userID]; !ok {
+			res = append(res, userID)
+		}
+	}
+	return res
+}
+
 func (c *ConsumerHandler) groupMessagesHandler(ctx context.Context, groupID string, pushToUserIDs *[]string, msg *sdkws.MsgData) (err error) {
 	if len(*pushToUserIDs) == 0 {
 		*pushToUserIDs, err = c.groupLocalCache.GetGroupMemberIDs(ctx, groupID)
@@ -340,6 +377,7 @@ func (c *ConsumerHandler) offlinePushMsg(ctx context.Context, msg *sdkws.MsgData
 	err = c.offlinePusher.Push(ctx, offlinePushUserIDs, title, content, opts)
 	if err != nil {
 		prommetrics.MsgOfflinePushFailedCounter.Inc()
+		c.offlinePushRetry.add(ctx, offlinePushUserIDs, title, content, opts, err)
 		return err
 	}
 	return nil
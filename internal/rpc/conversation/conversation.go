@@ -48,6 +48,7 @@ import (
 type conversationServer struct {
 	pbconversation.UnimplementedConversationServer
 	conversationDatabase controller.ConversationDatabase
+	legalHoldDatabase    controller.LegalHoldDatabase
 
 	conversationNotificationSender *ConversationNotificationSender
 	config                         *Config
@@ -83,6 +84,10 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
+	legalHoldDB, err := mgo.NewLegalHoldMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
 	userConn, err := client.GetConn(ctx, config.Discovery.RpcService.User)
 	if err != nil {
 		return err
@@ -105,6 +110,7 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 		groupClient:   rpcli.NewGroupClient(groupConn),
 		msgClient:     msgClient,
 	}
+	cs.legalHoldDatabase = controller.NewLegalHoldDatabase(legalHoldDB, mgocli.GetTx())
 
 	cs.conversationNotificationSender = NewConversationNotificationSender(&config.NotificationConfig, msgClient)
 	cs.conversationDatabase = controller.NewConversationDatabase(
@@ -736,11 +742,19 @@ func (c *conversationServer) ClearUserConversationMsg(ctx context.Context, req *
 	if err != nil {
 		return nil, err
 	}
+	heldConversationIDs, err := c.getHeldConversationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 	latestMsgDestructTime := time.UnixMilli(req.Timestamp)
 	for i, conversation := range conversations {
 		if !conversation.IsMsgDestruct || conversation.MsgDestructTime == 0 {
 			continue
 		}
+		if _, ok := heldConversationIDs[conversation.ConversationID]; ok {
+			log.ZDebug(ctx, "ClearUserConversationMsg skip conversation under legal hold", "index", i, "conversationID", conversation.ConversationID, "ownerUserID", conversation.OwnerUserID)
+			continue
+		}
 		seq, err := c.msgClient.GetLastMessageSeqByTime(ctx, conversation.ConversationID, req.Timestamp-(conversation.MsgDestructTime*1000))
 		if err != nil {
 			return nil, err
@@ -761,6 +775,22 @@ func (c *conversationServer) ClearUserConversationMsg(ctx context.Context, req *
 	return &pbconversation.ClearUserConversationMsgResp{Count: int32(len(conversations))}, nil
 }
 
+// getHeldConversationIDs returns the conversations under legal hold, including every conversation of a held user.
+func (c *conversationServer) getHeldConversationIDs(ctx context.Context) (map[string]struct{}, error) {
+	userIDs, conversationIDs, err := c.legalHoldDatabase.GetHeldTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		ids, err := c.conversationDatabase.GetConversationIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		conversationIDs = append(conversationIDs, ids...)
+	}
+	return datautil.SliceSet(conversationIDs), nil
+}
+
 func (c *conversationServer) setConversationMinSeqAndLatestMsgDestructTime(ctx context.Context, conversationID string, ownerUserID string, minSeq int64, latestMsgDestructTime time.Time) error {
 	update := map[string]any{
 		"latest_msg_destruct_time": latestMsgDestructTime,
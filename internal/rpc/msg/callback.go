@@ -206,6 +206,18 @@ func (m *msgServer) webhookAfterRevokeMsg(ctx context.Context, after *config.Aft
 	m.webhookClient.AsyncPost(ctx, callbackReq.GetCallbackCommand(), callbackReq, &cbapi.CallbackAfterRevokeMsgResp{}, after)
 }
 
+func (m *msgServer) webhookAfterScheduledMsgDropped(ctx context.Context, after *config.AfterConfig, scheduleID string, msg *pbchat.SendMsgReq, codeErr errs.CodeError) {
+	cbReq := &cbapi.CallbackAfterScheduledMsgDroppedReq{
+		CommonCallbackReq: toCommonCallback(ctx, msg, cbapi.CallbackAfterScheduledMsgDroppedCommand),
+		ScheduleID:        scheduleID,
+		RecvID:            msg.MsgData.RecvID,
+		GroupID:           msg.MsgData.GroupID,
+		ErrCode:           codeErr.Code(),
+		ErrMsg:            codeErr.Msg(),
+	}
+	m.webhookClient.AsyncPostWithQuery(ctx, cbReq.GetCallbackCommand(), cbReq, &cbapi.CallbackAfterScheduledMsgDroppedResp{}, after, buildKeyMsgDataQuery(msg.MsgData))
+}
+
 func buildKeyMsgDataQuery(msg *sdkws.MsgData) map[string]string {
 	keyMsgData := apistruct.KeyMsgData{
 		SendID:  msg.SendID,
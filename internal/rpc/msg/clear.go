@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/tools/log"
 )
@@ -14,42 +16,55 @@ func (m *msgServer) DestructMsgs(ctx context.Context, req *msg.DestructMsgsReq)
 	if err := authverify.CheckAdmin(ctx); err != nil {
 		return nil, err
 	}
-	docs, err := m.MsgDatabase.GetRandBeforeMsg(ctx, req.Timestamp, int(req.Limit))
+	heldConversationIDs, err := m.getHeldConversationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := m.MsgDatabase.GetRandBeforeMsgByScope(ctx, req.Timestamp, &database.MsgDocScope{ExcludeConversationIDs: heldConversationIDs}, int(req.Limit))
 	if err != nil {
 		return nil, err
 	}
 	for i, doc := range docs {
-		if err := m.MsgDatabase.DeleteDoc(ctx, doc.DocID); err != nil {
+		if err := m.destructDoc(ctx, doc); err != nil {
 			return nil, err
 		}
 		log.ZDebug(ctx, "DestructMsgs delete doc", "index", i, "docID", doc.DocID)
-		index := strings.LastIndex(doc.DocID, ":")
-		if index < 0 {
-			continue
-		}
-		var minSeq int64
-		for _, model := range doc.Msg {
-			if model.Msg == nil {
-				continue
-			}
-			if model.Msg.Seq > minSeq {
-				minSeq = model.Msg.Seq
-			}
-		}
-		if minSeq <= 0 {
-			continue
-		}
-		conversationID := doc.DocID[:index]
-		if conversationID == "" {
+	}
+	return &msg.DestructMsgsResp{Count: int32(len(docs))}, nil
+}
+
+// destructDoc deletes the doc and raises the min seq of its conversation past the deleted msgs.
+func (m *msgServer) destructDoc(ctx context.Context, doc *model.MsgDocModel) error {
+	if err := m.MsgDatabase.DeleteDoc(ctx, doc.DocID); err != nil {
+		return err
+	}
+	index := strings.LastIndex(doc.DocID, ":")
+	if index < 0 {
+		return nil
+	}
+	var minSeq int64
+	for _, msgInfo := range doc.Msg {
+		if msgInfo.Msg == nil {
 			continue
 		}
-		minSeq++
-		if err := m.MsgDatabase.SetMinSeq(ctx, conversationID, minSeq); err != nil {
-			return nil, err
+		if msgInfo.Msg.Seq > minSeq {
+			minSeq = msgInfo.Msg.Seq
 		}
-		log.ZDebug(ctx, "DestructMsgs delete doc set min seq", "index", i, "docID", doc.DocID, "conversationID", conversationID, "setMinSeq", minSeq)
 	}
-	return &msg.DestructMsgsResp{Count: int32(len(docs))}, nil
+	if minSeq <= 0 {
+		return nil
+	}
+	conversationID := doc.DocID[:index]
+	if conversationID == "" {
+		return nil
+	}
+	minSeq++
+	if err := m.MsgDatabase.SetMinSeq(ctx, conversationID, minSeq); err != nil {
+		return err
+	}
+	m.unindexMsgsBefore(ctx, conversationID, minSeq)
+	log.ZDebug(ctx, "destruct doc set min seq", "docID", doc.DocID, "conversationID", conversationID, "setMinSeq", minSeq)
+	return nil
 }
 
 func (m *msgServer) GetLastMessageSeqByTime(ctx context.Context, req *msg.GetLastMessageSeqByTimeReq) (*msg.GetLastMessageSeqByTimeResp, error) {
@@ -44,6 +44,9 @@ func (m *msgServer) ClearConversationsMsg(ctx context.Context, req *msg.ClearCon
 	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
 		return nil, err
 	}
+	if err := m.checkLegalHold(ctx, req.UserID, req.ConversationIDs); err != nil {
+		return nil, err
+	}
 	if err := m.clearConversation(ctx, req.ConversationIDs, req.UserID, req.DeleteSyncOpt); err != nil {
 		return nil, err
 	}
@@ -58,6 +61,9 @@ func (m *msgServer) UserClearAllMsg(ctx context.Context, req *msg.UserClearAllMs
 	if err != nil {
 		return nil, err
 	}
+	if err := m.checkLegalHold(ctx, req.UserID, conversationIDs); err != nil {
+		return nil, err
+	}
 	if err := m.clearConversation(ctx, conversationIDs, req.UserID, req.DeleteSyncOpt); err != nil {
 		return nil, err
 	}
@@ -68,11 +74,15 @@ func (m *msgServer) DeleteMsgs(ctx context.Context, req *msg.DeleteMsgsReq) (*ms
 	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
 		return nil, err
 	}
+	if err := m.checkLegalHold(ctx, req.UserID, []string{req.ConversationID}); err != nil {
+		return nil, err
+	}
 	isSyncSelf, isSyncOther := m.validateDeleteSyncOpt(req.DeleteSyncOpt)
 	if isSyncOther {
 		if err := m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs); err != nil {
 			return nil, err
 		}
+		m.unindexMsgs(ctx, req.ConversationID, req.Seqs)
 		conv, err := m.conversationClient.GetConversation(ctx, req.ConversationID, req.UserID)
 		if err != nil {
 			return nil, err
@@ -84,6 +94,7 @@ func (m *msgServer) DeleteMsgs(ctx context.Context, req *msg.DeleteMsgsReq) (*ms
 		if err := m.MsgDatabase.DeleteUserMsgsBySeqs(ctx, req.UserID, req.ConversationID, req.Seqs); err != nil {
 			return nil, err
 		}
+		m.unindexUserMsgs(ctx, req.UserID, req.ConversationID, req.Seqs)
 		if isSyncSelf {
 			tips := &sdkws.DeleteMsgsTips{UserID: req.UserID, ConversationID: req.ConversationID, Seqs: req.Seqs}
 			m.notificationSender.NotificationWithSessionType(ctx, req.UserID, req.UserID, constant.DeleteMsgsNotification, constant.SingleChatType, tips)
@@ -96,10 +107,14 @@ func (m *msgServer) DeleteMsgPhysicalBySeq(ctx context.Context, req *msg.DeleteM
 	if err := authverify.CheckAdmin(ctx); err != nil {
 		return nil, err
 	}
+	if err := m.checkLegalHold(ctx, "", []string{req.ConversationID}); err != nil {
+		return nil, err
+	}
 	err := m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs)
 	if err != nil {
 		return nil, err
 	}
+	m.unindexMsgs(ctx, req.ConversationID, req.Seqs)
 	return &msg.DeleteMsgPhysicalBySeqResp{}, nil
 }
 
@@ -146,9 +161,13 @@ func (m *msgServer) clearConversation(ctx context.Context, conversationIDs []str
 			m.notificationSender.NotificationWithSessionType(ctx, userID, userID, constant.ClearConversationNotification, constant.SingleChatType, tips)
 		}
 	} else {
-		if err := m.MsgDatabase.SetMinSeqs(ctx, m.getMinSeqs(maxSeqs)); err != nil {
+		minSeqs := m.getMinSeqs(maxSeqs)
+		if err := m.MsgDatabase.SetMinSeqs(ctx, minSeqs); err != nil {
 			return err
 		}
+		for conversationID, seq := range minSeqs {
+			m.unindexMsgsBefore(ctx, conversationID, seq)
+		}
 		for _, conversation := range conversations {
 			tips := &sdkws.ClearConversationTips{UserID: userID, ConversationIDs: []string{conversation.ConversationID}}
 			m.notificationSender.NotificationWithSessionType(ctx, userID, m.conversationAndGetRecvID(conversation, userID), constant.ClearConversationNotification, conversation.ConversationType, tips)
@@ -0,0 +1,54 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+)
+
+func (m *msgServer) GetExportConversation(ctx context.Context, req *msgext.GetExportConversationReq) (*msgext.GetExportConversationResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	minSeq, maxSeq, err := m.MsgExportDatabase.GetSeqRange(ctx, req.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	readStates, err := m.MsgExportDatabase.GetReadStates(ctx, req.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.GetExportConversationResp{
+		ConversationID: req.ConversationID,
+		MinSeq:         minSeq,
+		MaxSeq:         maxSeq,
+		ReadStates:     msgexport.ReadStates(readStates),
+	}, nil
+}
+
+func (m *msgServer) GetExportMsgDoc(ctx context.Context, req *msgext.GetExportMsgDocReq) (*msgext.GetExportMsgDocResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	doc, err := m.MsgExportDatabase.GetMsgDoc(ctx, req.ConversationID, req.Index)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.GetExportMsgDocResp{Msgs: msgexport.DocMsgs(doc)}, nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/errs"
+)
+
+func importCheckpointDB2Pb(checkpoint *model.MsgImportCheckpoint) *msgext.ImportCheckpoint {
+	return &msgext.ImportCheckpoint{
+		ImportID:       checkpoint.ImportID,
+		ConversationID: checkpoint.ConversationID,
+		Offset:         checkpoint.Offset,
+		LastSeq:        checkpoint.LastSeq,
+		UpdateTime:     checkpoint.UpdateTime.UnixMilli(),
+	}
+}
+
+// ImportMsgs writes history into a conversation keeping the original send time, without pushing it.
+func (m *msgServer) ImportMsgs(ctx context.Context, req *msgext.ImportMsgsReq) (*msgext.ImportMsgsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	msgs := make([]*model.MsgInfoModel, 0, len(req.Msgs))
+	for i, msg := range req.Msgs {
+		info := msgexport.MsgInfo(msg)
+		if conversationID := msgprocessor.GetConversationIDByMsg(convert.MsgDB2Pb(info.Msg)); conversationID != req.ConversationID {
+			return nil, errs.ErrArgs.WrapMsg("msg does not belong to the conversation", "index", i, "conversationID", conversationID)
+		}
+		msgs = append(msgs, info)
+	}
+	checkpoint, err := m.MsgImportDatabase.ImportMsgs(ctx, req.ImportID, req.ConversationID, req.Offset, msgs)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.ImportMsgsResp{Checkpoint: importCheckpointDB2Pb(checkpoint)}, nil
+}
+
+func (m *msgServer) GetImportCheckpoints(ctx context.Context, req *msgext.GetImportCheckpointsReq) (*msgext.GetImportCheckpointsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.ConversationID != "" {
+		checkpoint, err := m.MsgImportDatabase.GetCheckpoint(ctx, req.ImportID, req.ConversationID)
+		if err != nil {
+			return nil, err
+		}
+		return &msgext.GetImportCheckpointsResp{Total: 1, Checkpoints: []*msgext.ImportCheckpoint{importCheckpointDB2Pb(checkpoint)}}, nil
+	}
+	total, checkpoints, err := m.MsgImportDatabase.PageCheckpoints(ctx, req.ImportID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetImportCheckpointsResp{Total: total, Checkpoints: make([]*msgext.ImportCheckpoint, 0, len(checkpoints))}
+	for _, checkpoint := range checkpoints {
+		resp.Checkpoints = append(resp.Checkpoints, importCheckpointDB2Pb(checkpoint))
+	}
+	return resp, nil
+}
@@ -0,0 +1,217 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/mcontext"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// legalHeld is what active legal holds protect, a held user protects all of their conversations.
+type legalHeld struct {
+	userIDs         map[string]struct{}
+	conversationIDs map[string]struct{}
+}
+
+func (m *msgServer) getLegalHeld(ctx context.Context) (*legalHeld, error) {
+	userIDs, conversationIDs, err := m.LegalHoldDatabase.GetHeldTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, userID := range userIDs {
+		ids, err := m.ConversationLocalCache.GetConversationIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		conversationIDs = append(conversationIDs, ids...)
+	}
+	return &legalHeld{userIDs: datautil.SliceSet(userIDs), conversationIDs: datautil.SliceSet(conversationIDs)}, nil
+}
+
+// getHeldConversationIDs returns the conversations whose msgs must not be deleted.
+func (m *msgServer) getHeldConversationIDs(ctx context.Context) ([]string, error) {
+	held, err := m.getLegalHeld(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return datautil.Keys(held.conversationIDs), nil
+}
+
+// checkLegalHold rejects deleting msgs of userID, or of any of the conversations, while they are held.
+func (m *msgServer) checkLegalHold(ctx context.Context, userID string, conversationIDs []string) error {
+	held, err := m.getLegalHeld(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := held.userIDs[userID]; ok && userID != "" {
+		return servererrs.ErrMsgLegalHold.WrapMsg("user is under legal hold", "userID", userID)
+	}
+	for _, conversationID := range conversationIDs {
+		if _, ok := held.conversationIDs[conversationID]; ok {
+			return servererrs.ErrMsgLegalHold.WrapMsg("conversation is under legal hold", "conversationID", conversationID)
+		}
+	}
+	return nil
+}
+
+// SyncLegalHeldObjects records the objects referenced by the msgs of held conversations stored since the previous call,
+// the third service keeps the recorded objects when deleting outdated objects.
+func (m *msgServer) SyncLegalHeldObjects(ctx context.Context, req *msgext.SyncLegalHeldObjectsReq) (*msgext.SyncLegalHeldObjectsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	conversationIDs, err := m.getHeldConversationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, conversationID := range conversationIDs {
+		if err := syncHeldObjects(ctx, m.MsgExportDatabase, m.LegalHoldDatabase, conversationID); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.LegalHoldDatabase.KeepHeldObjects(ctx, conversationIDs); err != nil {
+		return nil, err
+	}
+	return &msgext.SyncLegalHeldObjectsResp{}, nil
+}
+
+// syncHeldObjects reads the msg docs of a held conversation from the seq it was read up to, including the msgs below
+// min seq that the hold keeps. The scan seq only moves to stored msgs, msgs not yet written to mongo are read next time.
+func syncHeldObjects(ctx context.Context, msgDB controller.MsgExportDatabase, holdDB controller.LegalHoldDatabase, conversationID string) error {
+	scanSeq, err := holdDB.GetHeldScanSeq(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	_, maxSeq, err := msgDB.GetSeqRange(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if maxSeq <= scanSeq {
+		return nil
+	}
+	var (
+		doc     model.MsgDocModel
+		names   = make(map[string]struct{})
+		lastSeq = scanSeq
+	)
+	for index := doc.GetDocIndex(scanSeq + 1); index <= doc.GetDocIndex(maxSeq); index++ {
+		msgDoc, err := msgDB.GetMsgDoc(ctx, conversationID, index)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgexport.DocMsgs(msgDoc) {
+			if msg.Seq <= scanSeq {
+				continue
+			}
+			for _, ref := range msgexport.AttachmentRefs(msg.Content) {
+				names[ref.Name] = struct{}{}
+			}
+			lastSeq = max(lastSeq, msg.Seq)
+		}
+	}
+	if lastSeq == scanSeq {
+		return nil
+	}
+	return holdDB.AddHeldObjects(ctx, conversationID, datautil.Keys(names), lastSeq)
+}
+
+func (m *msgServer) PlaceLegalHold(ctx context.Context, req *msgext.PlaceLegalHoldReq) (*msgext.PlaceLegalHoldResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	opUserID := mcontext.GetOpUserID(ctx)
+	hold := &model.LegalHold{
+		HoldID:      GetMsgID(opUserID),
+		Type:        req.Type,
+		Target:      req.Target,
+		Reason:      req.Reason,
+		PlaceUserID: opUserID,
+		PlaceTime:   time.Now(),
+	}
+	if err := m.LegalHoldDatabase.PlaceHold(ctx, hold); err != nil {
+		return nil, err
+	}
+	return &msgext.PlaceLegalHoldResp{HoldID: hold.HoldID}, nil
+}
+
+func (m *msgServer) ReleaseLegalHold(ctx context.Context, req *msgext.ReleaseLegalHoldReq) (*msgext.ReleaseLegalHoldResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.LegalHoldDatabase.ReleaseHold(ctx, req.HoldID, mcontext.GetOpUserID(ctx), req.Reason); err != nil {
+		return nil, err
+	}
+	return &msgext.ReleaseLegalHoldResp{}, nil
+}
+
+func (m *msgServer) GetLegalHolds(ctx context.Context, req *msgext.GetLegalHoldsReq) (*msgext.GetLegalHoldsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, holds, err := m.LegalHoldDatabase.PageHolds(ctx, req.IncludeReleased, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetLegalHoldsResp{Total: total, Holds: make([]*msgext.LegalHold, 0, len(holds))}
+	for _, hold := range holds {
+		h := &msgext.LegalHold{
+			HoldID:        hold.HoldID,
+			Type:          hold.Type,
+			Target:        hold.Target,
+			Reason:        hold.Reason,
+			PlaceUserID:   hold.PlaceUserID,
+			PlaceTime:     hold.PlaceTime.UnixMilli(),
+			Released:      hold.Released,
+			ReleaseUserID: hold.ReleaseUserID,
+		}
+		if hold.Released {
+			h.ReleaseTime = hold.ReleaseTime.UnixMilli()
+		}
+		resp.Holds = append(resp.Holds, h)
+	}
+	return resp, nil
+}
+
+func (m *msgServer) GetLegalHoldLogs(ctx context.Context, req *msgext.GetLegalHoldLogsReq) (*msgext.GetLegalHoldLogsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, logs, err := m.LegalHoldDatabase.PageHoldLogs(ctx, req.HoldID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetLegalHoldLogsResp{Total: total, Logs: make([]*msgext.LegalHoldLog, 0, len(logs))}
+	for _, log := range logs {
+		resp.Logs = append(resp.Logs, &msgext.LegalHoldLog{
+			HoldID:         log.HoldID,
+			Action:         log.Action,
+			Type:           log.Type,
+			Target:         log.Target,
+			Reason:         log.Reason,
+			OperatorUserID: log.OperatorUserID,
+			Time:           log.Time.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
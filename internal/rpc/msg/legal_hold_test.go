@@ -0,0 +1,111 @@
+package msg
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+type fakeMsgDocs struct {
+	maxSeq map[string]int64
+	docs   map[string]map[int64]*model.MsgDocModel
+	reads  int
+}
+
+func (f *fakeMsgDocs) GetSeqRange(_ context.Context, conversationID string) (int64, int64, error) {
+	// min seq is past the referenced msgs, held msgs below it are still read
+	return f.maxSeq[conversationID], f.maxSeq[conversationID], nil
+}
+
+func (f *fakeMsgDocs) GetMsgDoc(_ context.Context, conversationID string, index int64) (*model.MsgDocModel, error) {
+	f.reads++
+	return f.docs[conversationID][index], nil
+}
+
+func (f *fakeMsgDocs) GetReadStates(context.Context, string) ([]*model.SeqUser, error) {
+	return nil, nil
+}
+
+type fakeHeldObjects struct {
+	controller.LegalHoldDatabase
+	scanSeq map[string]int64
+	names   map[string]map[string]struct{}
+}
+
+func (f *fakeHeldObjects) GetHeldScanSeq(_ context.Context, conversationID string) (int64, error) {
+	return f.scanSeq[conversationID], nil
+}
+
+func (f *fakeHeldObjects) AddHeldObjects(_ context.Context, conversationID string, names []string, seq int64) error {
+	if f.names[conversationID] == nil {
+		f.names[conversationID] = make(map[string]struct{})
+	}
+	for _, name := range names {
+		f.names[conversationID][name] = struct{}{}
+	}
+	f.scanSeq[conversationID] = seq
+	return nil
+}
+
+func (f *fakeHeldObjects) heldNames(conversationID string) []string {
+	names := datautil.Keys(f.names[conversationID])
+	sort.Strings(names)
+	return names
+}
+
+func msgDoc(startSeq int64, contents ...string) *model.MsgDocModel {
+	doc := &model.MsgDocModel{}
+	for i, content := range contents {
+		doc.Msg = append(doc.Msg, &model.MsgInfoModel{Msg: &model.MsgDataModel{Seq: startSeq + int64(i), Content: content}})
+	}
+	return doc
+}
+
+func TestSyncHeldObjects(t *testing.T) {
+	ctx := context.Background()
+	msgDB := &fakeMsgDocs{
+		maxSeq: map[string]int64{"sg_1": 101},
+		docs: map[string]map[int64]*model.MsgDocModel{
+			"sg_1": {
+				0: msgDoc(1, `{"url":"http://api/object/a.png"}`, "hello"),
+				1: msgDoc(101, `{"sourceUrl":"http://api/object/b.mp4","snapshotUrl":"http://api/object/a.png"}`),
+			},
+		},
+	}
+	holdDB := &fakeHeldObjects{scanSeq: make(map[string]int64), names: make(map[string]map[string]struct{})}
+	if err := syncHeldObjects(ctx, msgDB, holdDB, "sg_1"); err != nil {
+		t.Fatal(err)
+	}
+	if names := holdDB.heldNames("sg_1"); len(names) != 2 || names[0] != "a.png" || names[1] != "b.mp4" {
+		t.Fatalf("names %v", names)
+	}
+	if holdDB.scanSeq["sg_1"] != 101 || msgDB.reads != 2 {
+		t.Fatalf("scan seq %d, reads %d", holdDB.scanSeq["sg_1"], msgDB.reads)
+	}
+
+	// nothing stored since the previous sync
+	msgDB.reads = 0
+	if err := syncHeldObjects(ctx, msgDB, holdDB, "sg_1"); err != nil {
+		t.Fatal(err)
+	}
+	if msgDB.reads != 0 {
+		t.Fatalf("reads %d", msgDB.reads)
+	}
+
+	// only the doc holding the new msgs is read, a msg not stored yet is read next time
+	msgDB.maxSeq["sg_1"] = 103
+	msgDB.docs["sg_1"][1] = msgDoc(101, `{"sourceUrl":"http://api/object/b.mp4"}`, `{"url":"http://api/object/c.png"}`)
+	if err := syncHeldObjects(ctx, msgDB, holdDB, "sg_1"); err != nil {
+		t.Fatal(err)
+	}
+	if names := holdDB.heldNames("sg_1"); len(names) != 3 || names[2] != "c.png" {
+		t.Fatalf("names %v", names)
+	}
+	if holdDB.scanSeq["sg_1"] != 102 || msgDB.reads != 1 {
+		t.Fatalf("scan seq %d, reads %d", holdDB.scanSeq["sg_1"], msgDB.reads)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/protocol/msg"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// getVisibleMsg returns the message at seq as seen by userID, rejecting deleted and revoked messages.
+func (m *msgServer) getVisibleMsg(ctx context.Context, userID, conversationID string, seq int64) (*sdkws.MsgData, error) {
+	_, _, msgs, err := m.MsgDatabase.GetMsgBySeqs(ctx, userID, conversationID, []int64{seq})
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 || msgs[0] == nil || msgs[0].Status == constant.MsgStatusHasDeleted || msgs[0].Status == constant.MsgDeleted {
+		return nil, errs.ErrRecordNotFound.WrapMsg("msg not found")
+	}
+	if msgs[0].ContentType == constant.MsgRevokeNotification {
+		return nil, servererrs.ErrMsgAlreadyRevoke.WrapMsg("msg already revoke")
+	}
+	return msgs[0], nil
+}
+
+// getMsgRecvID returns the recvID notifications about msgData are sent to, checking userID is still in its group.
+func (m *msgServer) getMsgRecvID(ctx context.Context, userID string, msgData *sdkws.MsgData) (string, error) {
+	switch msgData.SessionType {
+	case constant.SingleChatType:
+		if msgData.SendID == userID {
+			return msgData.RecvID, nil
+		}
+		return msgData.SendID, nil
+	case constant.ReadGroupChatType:
+		if !authverify.IsAdmin(ctx) {
+			if _, err := m.GroupLocalCache.GetGroupMember(ctx, msgData.GroupID, userID); err != nil {
+				return "", err
+			}
+		}
+		return msgData.GroupID, nil
+	default:
+		return "", errs.ErrArgs.WrapMsg("msg sessionType not supported", "sessionType", msgData.SessionType)
+	}
+}
+
+func (m *msgServer) ModifyMsg(ctx context.Context, req *msgext.ModifyMsgReq) (*msgext.ModifyMsgResp, error) {
+	if !m.config.RpcConfig.ModifyMsg.Enable {
+		return nil, errs.ErrNoPermission.WrapMsg("modify msg is disabled")
+	}
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(req.Content), &struct{}{}); err != nil {
+		return nil, errs.ErrArgs.WrapMsg("content is not json", "content", req.Content)
+	}
+	msgData, err := m.getVisibleMsg(ctx, req.UserID, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	if msgData.SendID != req.UserID {
+		return nil, errs.ErrNoPermission.WrapMsg("only the sender can modify the msg")
+	}
+	if msgData.ContentType >= constant.NotificationBegin && msgData.ContentType <= constant.NotificationEnd {
+		return nil, errs.ErrArgs.WrapMsg("notification msg can not be modified", "contentType", msgData.ContentType)
+	}
+	recvID, err := m.getMsgRecvID(ctx, req.UserID, msgData)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if window := m.config.RpcConfig.ModifyMsg.TimeWindow; window > 0 && !authverify.IsAdmin(ctx) {
+		if now.Sub(time.UnixMilli(msgData.SendTime)) > window {
+			return nil, servererrs.ErrMsgModifyExpired.WrapMsg("msg modify time window has passed", "sendTime", msgData.SendTime)
+		}
+	}
+	msgData.Content = []byte(req.Content)
+	sendReq := &msg.SendMsgReq{MsgData: msgData}
+	if err := m.webhookBeforeMsgModify(ctx, &m.config.WebhooksConfig.BeforeMsgModify, sendReq, nil); err != nil {
+		return nil, err
+	}
+	content := string(sendReq.MsgData.Content)
+	modify := &model.ModifyModel{
+		UserID: req.UserID,
+		Time:   now.UnixMilli(),
+	}
+	if err := m.MsgDatabase.ModifyMsg(ctx, req.ConversationID, req.Seq, content, modify, m.config.RpcConfig.ModifyMsg.MaxHistory); err != nil {
+		return nil, err
+	}
+	if m.msgSearchIndex != nil {
+		if err := m.msgSearchIndex.Index(ctx, msgsearch.NewDocs(req.ConversationID, []*sdkws.MsgData{sendReq.MsgData})); err != nil {
+			log.ZError(ctx, "reindex modified msg err", err, "conversationID", req.ConversationID, "seq", req.Seq)
+		}
+	}
+	tips := msgext.ModifyMsgTips{
+		ModifierUserID: req.UserID,
+		ConversationID: req.ConversationID,
+		Seq:            req.Seq,
+		ClientMsgID:    msgData.ClientMsgID,
+		SessionType:    msgData.SessionType,
+		Content:        content,
+		ModifyTime:     modify.Time,
+	}
+	m.notificationSender.NotificationWithSessionType(ctx, req.UserID, recvID, msgext.MsgModifyNotification, msgData.SessionType, &tips)
+	return &msgext.ModifyMsgResp{ModifyTime: modify.Time}, nil
+}
+
+func (m *msgServer) GetMsgModifyHistory(ctx context.Context, req *msgext.GetMsgModifyHistoryReq) (*msgext.GetMsgModifyHistoryResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := m.getVisibleMsg(ctx, req.UserID, req.ConversationID, req.Seq); err != nil {
+		return nil, err
+	}
+	msgInfo, err := m.MsgDatabase.GetMsgInfoBySeq(ctx, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.GetMsgModifyHistoryResp{
+		Content: msgInfo.Msg.Content,
+		Versions: datautil.Slice(msgInfo.Modify, func(e *model.ModifyModel) *msgext.MsgVersion {
+			return &msgext.MsgVersion{
+				Content:        e.Content,
+				ModifierUserID: e.UserID,
+				ModifyTime:     e.Time,
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+func (m *msgServer) AddMsgReaction(ctx context.Context, req *msgext.AddMsgReactionReq) (*msgext.AddMsgReactionResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	msgData, recvID, err := m.checkMsgReaction(ctx, req.UserID, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	reaction := &model.ReactionModel{
+		UserID: req.UserID,
+		Emoji:  req.Emoji,
+		Time:   time.Now().UnixMilli(),
+	}
+	limit := m.config.RpcConfig.Reaction
+	if err := m.MsgDatabase.AddMsgReaction(ctx, req.ConversationID, req.Seq, reaction, limit.MaxPerMsg, limit.MaxPerUser); err != nil {
+		return nil, err
+	}
+	reactions, err := m.msgReactionNotification(ctx, req.UserID, recvID, req.ConversationID, req.Emoji, false, msgData)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.AddMsgReactionResp{Reactions: reactions}, nil
+}
+
+func (m *msgServer) DeleteMsgReaction(ctx context.Context, req *msgext.DeleteMsgReactionReq) (*msgext.DeleteMsgReactionResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	msgData, recvID, err := m.checkMsgReaction(ctx, req.UserID, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.MsgDatabase.DeleteMsgReaction(ctx, req.ConversationID, req.Seq, req.UserID, req.Emoji); err != nil {
+		return nil, err
+	}
+	reactions, err := m.msgReactionNotification(ctx, req.UserID, recvID, req.ConversationID, req.Emoji, true, msgData)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.DeleteMsgReactionResp{Reactions: reactions}, nil
+}
+
+func (m *msgServer) checkMsgReaction(ctx context.Context, userID, conversationID string, seq int64) (*sdkws.MsgData, string, error) {
+	msgData, err := m.getVisibleMsg(ctx, userID, conversationID, seq)
+	if err != nil {
+		return nil, "", err
+	}
+	recvID, err := m.getMsgRecvID(ctx, userID, msgData)
+	if err != nil {
+		return nil, "", err
+	}
+	return msgData, recvID, nil
+}
+
+// msgReactionNotification pushes the new per-emoji counts of msgData to the conversation and returns them as seen by userID.
+func (m *msgServer) msgReactionNotification(ctx context.Context, userID, recvID, conversationID, emoji string, isDelete bool, msgData *sdkws.MsgData) ([]*msgext.MsgReaction, error) {
+	msgInfo, err := m.MsgDatabase.GetMsgInfoBySeq(ctx, conversationID, msgData.Seq)
+	if err != nil {
+		return nil, err
+	}
+	tips := &msgext.MsgReactionTips{
+		UserID:         userID,
+		ConversationID: conversationID,
+		Seq:            msgData.Seq,
+		ClientMsgID:    msgData.ClientMsgID,
+		SessionType:    msgData.SessionType,
+		Emoji:          emoji,
+		IsDelete:       isDelete,
+		Reactions:      convert.MsgReactionsDB2Pb(msgInfo.Reactions, ""),
+	}
+	m.msgNotificationSender.MsgReactionNotification(ctx, userID, recvID, msgData.SessionType, tips)
+	return convert.MsgReactionsDB2Pb(msgInfo.Reactions, userID), nil
+}
@@ -0,0 +1,237 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/log"
+)
+
+// retentionSessionTypePrefixes are the conversation ID prefixes of the session types a rule can target.
+var retentionSessionTypePrefixes = map[string][]string{
+	strconv.Itoa(constant.SingleChatType):       {"si_"},
+	strconv.Itoa(constant.ReadGroupChatType):    {"sg_", "g_"},
+	strconv.Itoa(constant.NotificationChatType): {"sn_"},
+}
+
+// retentionScope is what a rule, or the default retention, deletes after overrides are applied.
+type retentionScope struct {
+	report *msgext.RetentionReport
+	// docScope is nil when nothing is deleted.
+	docScope *database.MsgDocScope
+}
+
+func newRetentionScope(now time.Time, scope string, target string, retainDays int64, docScope *database.MsgDocScope) *retentionScope {
+	r := &retentionScope{report: &msgext.RetentionReport{Scope: scope, Target: target, RetainDays: retainDays}}
+	if retainDays > 0 && docScope != nil {
+		r.report.Deadline = now.Add(-time.Hour * 24 * time.Duration(retainDays)).UnixMilli()
+		r.docScope = docScope
+	}
+	return r
+}
+
+// retentionScopes resolves the rules with the conversations under legal hold.
+func (m *msgServer) retentionScopes(ctx context.Context, defaultRetainDays int64) ([]*retentionScope, error) {
+	rules, err := m.RetentionRuleDatabase.GetAllRetentionRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	heldConversationIDs, err := m.getHeldConversationIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resolveRetentionScopes(time.Now(), rules, heldConversationIDs, defaultRetainDays), nil
+}
+
+// resolveRetentionScopes resolves the rules from the most to the least specific scope,
+// each rule claiming the conversations it covers so broader rules skip them.
+// Conversations under legal hold are claimed first so no rule deletes them.
+// A group rule covers the chat of the group, its notifications are left to the default retention as no type rule covers them.
+func resolveRetentionScopes(now time.Time, rules []*model.RetentionRule, heldConversationIDs []string, defaultRetainDays int64) []*retentionScope {
+	var (
+		scopes       []*retentionScope
+		claimed      = make(map[string]struct{})
+		claimedIDs   []string
+		typePrefixes []string
+	)
+	claim := func(conversationIDs ...string) []string {
+		var ids []string
+		for _, conversationID := range conversationIDs {
+			if _, ok := claimed[conversationID]; ok {
+				continue
+			}
+			claimed[conversationID] = struct{}{}
+			claimedIDs = append(claimedIDs, conversationID)
+			ids = append(ids, conversationID)
+		}
+		return ids
+	}
+	claim(heldConversationIDs...)
+	for _, scope := range []string{msgext.RetentionScopeConversation, msgext.RetentionScopeGroup} {
+		for _, rule := range rules {
+			if rule.Scope != scope {
+				continue
+			}
+			var ids []string
+			if scope == msgext.RetentionScopeConversation {
+				ids = claim(rule.Target)
+			} else {
+				ids = claim(msgprocessor.GetConversationIDBySessionType(constant.ReadGroupChatType, rule.Target))
+			}
+			var docScope *database.MsgDocScope
+			if len(ids) > 0 {
+				docScope = &database.MsgDocScope{ConversationIDs: ids}
+			}
+			scopes = append(scopes, newRetentionScope(now, rule.Scope, rule.Target, rule.RetainDays, docScope))
+		}
+	}
+	for _, rule := range rules {
+		if rule.Scope != msgext.RetentionScopeConversationType {
+			continue
+		}
+		prefixes := retentionSessionTypePrefixes[rule.Target]
+		if len(prefixes) == 0 {
+			continue
+		}
+		typePrefixes = append(typePrefixes, prefixes...)
+		var exclude []string
+		for _, conversationID := range claimedIDs {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(conversationID, prefix) {
+					exclude = append(exclude, conversationID)
+					break
+				}
+			}
+		}
+		docScope := &database.MsgDocScope{Prefixes: prefixes, ExcludeConversationIDs: exclude}
+		scopes = append(scopes, newRetentionScope(now, rule.Scope, rule.Target, rule.RetainDays, docScope))
+	}
+	if defaultRetainDays > 0 {
+		docScope := &database.MsgDocScope{ExcludeConversationIDs: claimedIDs, ExcludePrefixes: typePrefixes}
+		scopes = append(scopes, newRetentionScope(now, msgext.RetentionScopeDefault, "", defaultRetainDays, docScope))
+	}
+	return scopes
+}
+
+func (m *msgServer) SetRetentionRule(ctx context.Context, req *msgext.SetRetentionRuleReq) (*msgext.SetRetentionRuleResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rule := &model.RetentionRule{
+		Scope:      req.Scope,
+		Target:     req.Target,
+		RetainDays: req.RetainDays,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := m.RetentionRuleDatabase.SetRetentionRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return &msgext.SetRetentionRuleResp{}, nil
+}
+
+func (m *msgServer) DeleteRetentionRule(ctx context.Context, req *msgext.DeleteRetentionRuleReq) (*msgext.DeleteRetentionRuleResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.RetentionRuleDatabase.DeleteRetentionRule(ctx, req.Scope, req.Target); err != nil {
+		return nil, err
+	}
+	return &msgext.DeleteRetentionRuleResp{}, nil
+}
+
+func (m *msgServer) GetRetentionRules(ctx context.Context, req *msgext.GetRetentionRulesReq) (*msgext.GetRetentionRulesResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, rules, err := m.RetentionRuleDatabase.PageRetentionRules(ctx, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetRetentionRulesResp{Total: total, Rules: make([]*msgext.RetentionRule, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, &msgext.RetentionRule{
+			Scope:      rule.Scope,
+			Target:     rule.Target,
+			RetainDays: rule.RetainDays,
+			CreateTime: rule.CreateTime.UnixMilli(),
+			UpdateTime: rule.UpdateTime.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
+
+func (m *msgServer) GetRetentionReport(ctx context.Context, req *msgext.GetRetentionReportReq) (*msgext.GetRetentionReportResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	scopes, err := m.retentionScopes(ctx, req.DefaultRetainDays)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetRetentionReportResp{Reports: make([]*msgext.RetentionReport, 0, len(scopes))}
+	for _, scope := range scopes {
+		if scope.docScope != nil {
+			scope.report.DocCount, err = m.MsgDatabase.CountBeforeMsgByScope(ctx, scope.report.Deadline, scope.docScope)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resp.Reports = append(resp.Reports, scope.report)
+	}
+	return resp, nil
+}
+
+func (m *msgServer) ApplyRetentionRules(ctx context.Context, req *msgext.ApplyRetentionRulesReq) (*msgext.ApplyRetentionRulesResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	scopes, err := m.retentionScopes(ctx, req.DefaultRetainDays)
+	if err != nil {
+		return nil, err
+	}
+	var count int
+	for _, scope := range scopes {
+		if scope.docScope == nil {
+			continue
+		}
+		docs, err := m.MsgDatabase.GetRandBeforeMsgByScope(ctx, scope.report.Deadline, scope.docScope, int(req.Limit)-count)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			if err := m.destructDoc(ctx, doc); err != nil {
+				return nil, err
+			}
+			log.ZDebug(ctx, "ApplyRetentionRules delete doc", "scope", scope.report.Scope, "target", scope.report.Target, "docID", doc.DocID)
+		}
+		count += len(docs)
+		if count >= int(req.Limit) {
+			break
+		}
+	}
+	return &msgext.ApplyRetentionRulesResp{Count: int32(count)}, nil
+}
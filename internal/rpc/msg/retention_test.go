@@ -0,0 +1,62 @@
+package msg
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+)
+
+func TestResolveRetentionScopes(t *testing.T) {
+	rules := []*model.RetentionRule{
+		{Scope: msgext.RetentionScopeConversationType, Target: strconv.Itoa(constant.ReadGroupChatType), RetainDays: 30},
+		{Scope: msgext.RetentionScopeGroup, Target: "g1", RetainDays: 7},
+		{Scope: msgext.RetentionScopeConversation, Target: "sg_g1", RetainDays: 1},
+		{Scope: msgext.RetentionScopeGroup, Target: "g2", RetainDays: 14},
+		{Scope: msgext.RetentionScopeConversation, Target: "si_u1_u2", RetainDays: 3},
+	}
+	scopes := resolveRetentionScopes(time.Now(), rules, []string{"si_u3_u4", "sg_g3"}, 90)
+	// the scope that deletes each conversation, empty for none
+	tests := map[string]string{
+		"sg_g1":    msgext.RetentionScopeConversation + ":sg_g1",
+		"sg_g2":    msgext.RetentionScopeGroup + ":g2",
+		"sg_g4":    msgext.RetentionScopeConversationType + ":3",
+		"g_g4":     msgext.RetentionScopeConversationType + ":3",
+		"si_u1_u2": msgext.RetentionScopeConversation + ":si_u1_u2",
+		"si_u5_u6": msgext.RetentionScopeDefault + ":",
+		"n_g2":     msgext.RetentionScopeDefault + ":",
+		"sn_u1_u2": msgext.RetentionScopeDefault + ":",
+		"si_u3_u4": "",
+		"sg_g3":    "",
+	}
+	for conversationID, want := range tests {
+		var got []string
+		for _, scope := range scopes {
+			if scope.docScope != nil && scope.docScope.Matcher()(conversationID) {
+				got = append(got, scope.report.Scope+":"+scope.report.Target)
+			}
+		}
+		switch {
+		case want == "" && len(got) != 0:
+			t.Errorf("%s is held but deleted by %v", conversationID, got)
+		case want != "" && (len(got) != 1 || got[0] != want):
+			t.Errorf("%s deleted by %v, want %s", conversationID, got, want)
+		}
+	}
+	for _, scope := range scopes {
+		if scope.report.Scope == msgext.RetentionScopeGroup && scope.report.Target == "g1" && scope.docScope != nil {
+			t.Errorf("group rule of g1 claims %v, which the conversation rule has", scope.docScope.ConversationIDs)
+		}
+	}
+}
+
+func TestResolveRetentionScopesDisabled(t *testing.T) {
+	rules := []*model.RetentionRule{{Scope: msgext.RetentionScopeConversation, Target: "si_u1_u2", RetainDays: 0}}
+	scopes := resolveRetentionScopes(time.Now(), rules, nil, 0)
+	if len(scopes) != 1 || scopes[0].docScope != nil {
+		t.Fatalf("a rule retaining forever should delete nothing, got %+v", scopes)
+	}
+}
@@ -106,6 +106,7 @@ func (m *msgServer) RevokeMsg(ctx context.Context, req *msg.RevokeMsgReq) (*msg.
 	if err != nil {
 		return nil, err
 	}
+	m.unindexMsgs(ctx, req.ConversationID, []int64{req.Seq})
 	revokerUserID := mcontext.GetOpUserID(ctx)
 	var flag bool
 
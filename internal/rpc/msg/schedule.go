@@ -0,0 +1,226 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+	pbmsg "github.com/openimsdk/protocol/msg"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// scheduledMsgClaimTimeout is how long a claimed scheduled msg waits before another dispatch retries it.
+	scheduledMsgClaimTimeout = 5 * time.Minute
+	// scheduledMsgRetryExpire is how long after its send time a scheduled msg that fails to send is retried.
+	scheduledMsgRetryExpire = 24 * time.Hour
+)
+
+// permanentSendErrCodes are the codes of the send errors that sending again does not change,
+// the msg no longer passes verification.
+var permanentSendErrCodes = map[int]struct{}{
+	servererrs.ArgsError:             {},
+	servererrs.NoPermissionError:     {},
+	servererrs.RecordNotFoundError:   {},
+	servererrs.UserIDNotFoundError:   {},
+	servererrs.GroupIDNotFoundError:  {},
+	servererrs.NotInGroupYetError:    {},
+	servererrs.DismissedAlreadyError: {},
+	servererrs.BlockedByPeer:         {},
+	servererrs.NotPeersFriend:        {},
+	servererrs.MutedInGroup:          {},
+	servererrs.MutedGroup:            {},
+}
+
+// permanentSendErr returns the code error of err if sending the msg again would fail with it as well.
+func permanentSendErr(err error) (errs.CodeError, bool) {
+	codeErr, ok := errs.Unwrap(err).(errs.CodeError)
+	if !ok {
+		return nil, false
+	}
+	_, ok = permanentSendErrCodes[codeErr.Code()]
+	return codeErr, ok
+}
+
+func (m *msgServer) ScheduleMsg(ctx context.Context, req *msgext.ScheduleMsgReq) (*msgext.ScheduleMsgResp, error) {
+	if !m.config.RpcConfig.ScheduleMsg.Enable {
+		return nil, errs.ErrNoPermission.WrapMsg("schedule msg is disabled")
+	}
+	if err := authverify.CheckAccess(ctx, req.MsgData.SendID); err != nil {
+		return nil, err
+	}
+	switch req.MsgData.SessionType {
+	case constant.SingleChatType, constant.ReadGroupChatType:
+	default:
+		return nil, errs.ErrArgs.WrapMsg("msg sessionType not supported", "sessionType", req.MsgData.SessionType)
+	}
+	if req.MsgData.ContentType >= constant.NotificationBegin && req.MsgData.ContentType <= constant.NotificationEnd {
+		return nil, errs.ErrArgs.WrapMsg("notification msg can not be scheduled", "contentType", req.MsgData.ContentType)
+	}
+	now := time.Now()
+	sendTime := time.UnixMilli(req.SendTime)
+	if !sendTime.After(now) {
+		return nil, errs.ErrArgs.WrapMsg("sendTime must be in the future", "sendTime", req.SendTime)
+	}
+	if maxDelay := m.config.RpcConfig.ScheduleMsg.MaxDelay; maxDelay > 0 && sendTime.Sub(now) > maxDelay {
+		return nil, errs.ErrArgs.WrapMsg("sendTime is too far in the future", "sendTime", req.SendTime, "maxDelay", maxDelay.String())
+	}
+	if maxPending := m.config.RpcConfig.ScheduleMsg.MaxPending; maxPending > 0 {
+		count, err := m.ScheduledMsgDatabase.CountScheduledMsgs(ctx, req.MsgData.SendID)
+		if err != nil {
+			return nil, err
+		}
+		if count >= maxPending {
+			return nil, errs.ErrArgs.WrapMsg("too many scheduled msgs", "count", count, "maxPending", maxPending)
+		}
+	}
+	msgData := proto.Clone(req.MsgData).(*sdkws.MsgData)
+	// verified as if sent now, it is verified again when it is sent
+	if err := m.messageVerification(ctx, &pbmsg.SendMsgReq{MsgData: proto.Clone(msgData).(*sdkws.MsgData)}); err != nil {
+		return nil, err
+	}
+	scheduleID := GetMsgID(req.MsgData.SendID)
+	msgData.ServerMsgID = ""
+	msgData.SendTime = 0
+	msgData.Seq = 0
+	// the client msg id tells whether an earlier dispatch already sent the msg
+	if msgData.ClientMsgID == "" {
+		msgData.ClientMsgID = scheduleID
+	}
+	data, err := proto.Marshal(msgData)
+	if err != nil {
+		return nil, errs.WrapMsg(err, "marshal msg data failed")
+	}
+	scheduled := &model.ScheduledMsg{
+		ScheduleID: scheduleID,
+		SendID:     req.MsgData.SendID,
+		MsgData:    data,
+		SendTime:   sendTime,
+		Status:     model.ScheduledMsgStatusPending,
+		CreateTime: now,
+	}
+	if err := m.ScheduledMsgDatabase.CreateScheduledMsg(ctx, scheduled); err != nil {
+		return nil, err
+	}
+	return &msgext.ScheduleMsgResp{ScheduleID: scheduled.ScheduleID, SendTime: req.SendTime}, nil
+}
+
+func (m *msgServer) CancelScheduledMsg(ctx context.Context, req *msgext.CancelScheduledMsgReq) (*msgext.CancelScheduledMsgResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	ok, err := m.ScheduledMsgDatabase.CancelScheduledMsg(ctx, req.UserID, req.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errs.ErrRecordNotFound.WrapMsg("scheduled msg not found or already sending", "scheduleID", req.ScheduleID)
+	}
+	return &msgext.CancelScheduledMsgResp{}, nil
+}
+
+func (m *msgServer) ListScheduledMsgs(ctx context.Context, req *msgext.ListScheduledMsgsReq) (*msgext.ListScheduledMsgsResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	total, msgs, err := m.ScheduledMsgDatabase.FindScheduledMsgs(ctx, req.UserID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.ListScheduledMsgsResp{Total: total, Msgs: make([]*msgext.ScheduledMsg, 0, len(msgs))}
+	for _, msg := range msgs {
+		var msgData sdkws.MsgData
+		if err := proto.Unmarshal(msg.MsgData, &msgData); err != nil {
+			return nil, errs.WrapMsg(err, "unmarshal scheduled msg data failed", "scheduleID", msg.ScheduleID)
+		}
+		resp.Msgs = append(resp.Msgs, &msgext.ScheduledMsg{
+			ScheduleID: msg.ScheduleID,
+			SendTime:   msg.SendTime.UnixMilli(),
+			CreateTime: msg.CreateTime.UnixMilli(),
+			MsgData:    &msgData,
+		})
+	}
+	return resp, nil
+}
+
+func (m *msgServer) DispatchScheduledMsgs(ctx context.Context, req *msgext.DispatchScheduledMsgsReq) (*msgext.DispatchScheduledMsgsResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	var count int32
+	for count < req.Limit {
+		scheduled, err := m.ScheduledMsgDatabase.ClaimDueScheduledMsg(ctx, scheduledMsgClaimTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if scheduled == nil {
+			break
+		}
+		count++
+		// A msg that failed to send stays claimed and is retried once the claim times out.
+		if err := m.dispatchScheduledMsg(ctx, scheduled); err != nil {
+			log.ZError(ctx, "dispatch scheduled msg failed", err, "scheduleID", scheduled.ScheduleID)
+		}
+	}
+	return &msgext.DispatchScheduledMsgsResp{Count: count}, nil
+}
+
+// dispatchScheduledMsg sends scheduled through the normal send path, dropping it when it no longer passes verification.
+// A msg failing for another reason, such as a timeout, is left claimed and sent again once the claim times out,
+// until scheduledMsgRetryExpire after its send time. Before sending again, the conversation is checked for the msg
+// by its client msg id, an earlier dispatch may have sent it and failed after.
+func (m *msgServer) dispatchScheduledMsg(ctx context.Context, scheduled *model.ScheduledMsg) error {
+	var msgData sdkws.MsgData
+	if err := proto.Unmarshal(scheduled.MsgData, &msgData); err != nil {
+		log.ZError(ctx, "unmarshal scheduled msg data failed, drop it", err, "scheduleID", scheduled.ScheduleID)
+		return m.ScheduledMsgDatabase.DeleteScheduledMsg(ctx, scheduled.ScheduleID)
+	}
+	if scheduled.Attempts > 1 {
+		conversationID := msgprocessor.GetConversationIDByMsg(&msgData)
+		seq, err := m.MsgDatabase.FindSeqByClientMsgID(ctx, conversationID, msgData.SendID, msgData.ClientMsgID, scheduled.SendTime.UnixMilli())
+		if err != nil {
+			return err
+		}
+		if seq > 0 {
+			log.ZInfo(ctx, "scheduled msg already sent", "scheduleID", scheduled.ScheduleID, "conversationID", conversationID, "seq", seq)
+			return m.ScheduledMsgDatabase.DeleteScheduledMsg(ctx, scheduled.ScheduleID)
+		}
+	}
+	req := &pbmsg.SendMsgReq{MsgData: &msgData}
+	if _, err := m.sendMsg(ctx, req, new(*sdkws.MsgData)); err != nil {
+		codeErr, ok := permanentSendErr(err)
+		if !ok {
+			if time.Since(scheduled.SendTime) < scheduledMsgRetryExpire {
+				return err
+			}
+			if codeErr, ok = errs.Unwrap(err).(errs.CodeError); !ok {
+				codeErr = errs.ErrInternalServer
+			}
+		}
+		log.ZWarn(ctx, "scheduled msg dropped", err, "scheduleID", scheduled.ScheduleID, "sendID", msgData.SendID)
+		m.webhookAfterScheduledMsgDropped(ctx, &m.config.WebhooksConfig.AfterScheduledMsgDropped, scheduled.ScheduleID, req, codeErr)
+	}
+	return m.ScheduledMsgDatabase.DeleteScheduledMsg(ctx, scheduled.ScheduleID)
+}
@@ -0,0 +1,40 @@
+package msg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/tools/errs"
+)
+
+func TestPermanentSendErr(t *testing.T) {
+	permanent := []error{
+		errs.ErrArgs.WrapMsg("unknown sessionType"),
+		errs.ErrNoPermission.Wrap(),
+		servererrs.ErrBlockedByPeer.Wrap(),
+		servererrs.ErrNotPeersFriend.Wrap(),
+		servererrs.ErrNotInGroupYet.WrapMsg("record not found"),
+		servererrs.ErrDismissedAlready.Wrap(),
+		servererrs.ErrMutedInGroup.Wrap(),
+		servererrs.ErrMutedGroup.Wrap(),
+	}
+	for _, err := range permanent {
+		if codeErr, ok := permanentSendErr(err); !ok || codeErr == nil {
+			t.Errorf("%v not permanent", err)
+		}
+	}
+	transient := []error{
+		errs.ErrInternalServer.WrapMsg("malloc seq failed"),
+		servererrs.ErrDatabase.Wrap(),
+		servererrs.ErrNetwork.WrapMsg("post url"),
+		context.DeadlineExceeded,
+		errors.New("rpc error: code = Unavailable"),
+	}
+	for _, err := range transient {
+		if _, ok := permanentSendErr(err); ok {
+			t.Errorf("%v permanent", err)
+		}
+	}
+}
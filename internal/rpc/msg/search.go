@@ -0,0 +1,141 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// searchMsgMaxShowNumber caps the page size of a search.
+const searchMsgMaxShowNumber = 100
+
+func (m *msgServer) SearchMsg(ctx context.Context, req *msgext.SearchMsgReq) (*msgext.SearchMsgResp, error) {
+	if m.msgSearchIndex == nil {
+		return nil, errs.ErrNoPermission.WrapMsg("msg search is disabled")
+	}
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	conversationIDs, err := m.ConversationLocalCache.GetConversationIDs(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.ConversationIDs) > 0 {
+		owned := datautil.SliceSet(conversationIDs)
+		conversationIDs = datautil.Filter(datautil.Distinct(req.ConversationIDs), func(conversationID string) (string, bool) {
+			_, ok := owned[conversationID]
+			return conversationID, ok
+		})
+	}
+	if len(conversationIDs) == 0 {
+		return &msgext.SearchMsgResp{}, nil
+	}
+	showNumber := int(req.Pagination.ShowNumber)
+	if showNumber <= 0 || showNumber > searchMsgMaxShowNumber {
+		showNumber = searchMsgMaxShowNumber
+	}
+	pageNumber := int(req.Pagination.PageNumber)
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+	minSeqs, err := m.MsgDatabase.GetUserConversationsMinSeqs(ctx, req.UserID, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	total, hits, err := m.msgSearchIndex.Search(ctx, &msgsearch.Query{
+		ConversationIDs: conversationIDs,
+		Keyword:         req.Keyword,
+		SendID:          req.SendID,
+		ContentTypes:    req.ContentTypes,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		UserID:          req.UserID,
+		MinSeqs:         minSeqs,
+		Offset:          (pageNumber - 1) * showNumber,
+		Limit:           showNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+	seqs := make(map[string][]int64)
+	for _, hit := range hits {
+		seqs[hit.ConversationID] = append(seqs[hit.ConversationID], hit.Seq)
+	}
+	type msgKey struct {
+		conversationID string
+		seq            int64
+	}
+	// the index is kept in step with deletes and revokes, the msgs are read again in case a purge failed
+	found := make(map[msgKey]*sdkws.MsgData, len(hits))
+	for conversationID, conversationSeqs := range seqs {
+		_, _, msgs, err := m.MsgDatabase.GetMsgBySeqs(ctx, req.UserID, conversationID, conversationSeqs)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if msg == nil || msg.Status == constant.MsgStatusHasDeleted || msg.Status == constant.MsgDeleted || msg.ContentType == constant.MsgRevokeNotification {
+				continue
+			}
+			found[msgKey{conversationID: conversationID, seq: msg.Seq}] = msg
+		}
+	}
+	resp := &msgext.SearchMsgResp{Total: total, Msgs: make([]*msgext.SearchedMsg, 0, len(found))}
+	for _, hit := range hits {
+		if msg, ok := found[msgKey{conversationID: hit.ConversationID, seq: hit.Seq}]; ok {
+			resp.Msgs = append(resp.Msgs, &msgext.SearchedMsg{ConversationID: hit.ConversationID, MsgData: msg})
+		}
+	}
+	return resp, nil
+}
+
+// unindexMsgs removes msgs deleted for everyone or revoked from the search index, a failure is only logged
+// as SearchMsg still drops the hits it can not read.
+func (m *msgServer) unindexMsgs(ctx context.Context, conversationID string, seqs []int64) {
+	if m.msgSearchIndex == nil {
+		return
+	}
+	if err := m.msgSearchIndex.Delete(ctx, conversationID, seqs); err != nil {
+		log.ZError(ctx, "unindex msgs failed", err, "conversationID", conversationID, "seqs", seqs)
+	}
+}
+
+// unindexMsgsBefore removes the msgs below the new min seq of the conversation from the search index.
+func (m *msgServer) unindexMsgsBefore(ctx context.Context, conversationID string, minSeq int64) {
+	if m.msgSearchIndex == nil {
+		return
+	}
+	if err := m.msgSearchIndex.DeleteBefore(ctx, conversationID, minSeq); err != nil {
+		log.ZError(ctx, "unindex msgs before failed", err, "conversationID", conversationID, "minSeq", minSeq)
+	}
+}
+
+// unindexUserMsgs hides the msgs the user deleted for themselves from their searches.
+func (m *msgServer) unindexUserMsgs(ctx context.Context, userID string, conversationID string, seqs []int64) {
+	if m.msgSearchIndex == nil {
+		return
+	}
+	if err := m.msgSearchIndex.DeleteForUser(ctx, userID, conversationID, seqs); err != nil {
+		log.ZError(ctx, "unindex user msgs failed", err, "userID", userID, "conversationID", conversationID, "seqs", seqs)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/mcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/dbbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/mqbuild"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcli"
 	"google.golang.org/grpc"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/webhook"
 	"github.com/openimsdk/open-im-server/v3/pkg/notification"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/conversation"
@@ -52,14 +54,22 @@ type Config struct {
 	Share              config.Share
 	WebhooksConfig     config.Webhooks
 	LocalCacheConfig   config.LocalCache
+	MsgSearchConfig    config.MsgSearch
 	Discovery          config.Discovery
 }
 
 // MsgServer encapsulates dependencies required for message handling.
 type msgServer struct {
 	msg.UnimplementedMsgServer
+	msgext.UnimplementedMsgExtServer
 	RegisterCenter         discovery.Conn                   // Service discovery registry for service registration.
 	MsgDatabase            controller.CommonMsgDatabase     // Interface for message database operations.
+	MsgThreadDatabase      controller.MsgThreadDatabase     // Interface for thread reply operations.
+	ScheduledMsgDatabase   controller.ScheduledMsgDatabase  // Interface for scheduled message operations.
+	RetentionRuleDatabase  controller.RetentionRuleDatabase // Interface for message retention rule operations.
+	LegalHoldDatabase      controller.LegalHoldDatabase     // Registry of legal holds exempt from deletion.
+	MsgExportDatabase      controller.MsgExportDatabase     // Reads conversations doc by doc for export.
+	MsgImportDatabase      controller.MsgImportDatabase     // Writes imported history straight into the msg docs.
 	UserLocalCache         *rpccache.UserLocalCache         // Local cache for user data.
 	FriendLocalCache       *rpccache.FriendLocalCache       // Local cache for friend data.
 	GroupLocalCache        *rpccache.GroupLocalCache        // Local cache for group data.
@@ -70,6 +80,7 @@ type msgServer struct {
 	config                 *Config                          // Global configuration settings.
 	webhookClient          *webhook.Client
 	conversationClient     *rpcli.ConversationClient
+	msgSearchIndex         msgsearch.MsgSearchIndex
 
 	adminUserIDs []string
 }
@@ -136,8 +147,45 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	}
 	conversationClient := rpcli.NewConversationClient(conversationConn)
 	msgDatabase := controller.NewCommonMsgDatabase(msgDocModel, msgModel, seqUserCache, seqConversationCache, redisProducer)
+	msgThreadModel, err := mgo.NewMsgThreadMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	scheduledMsgModel, err := mgo.NewScheduledMsgMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	retentionRuleModel, err := mgo.NewRetentionRuleMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	legalHoldModel, err := mgo.NewLegalHoldMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	msgImportCheckpointModel, err := mgo.NewMsgImportCheckpointMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	importLocker, err := mgo.NewCacheMgo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	var msgSearchIndex msgsearch.MsgSearchIndex
+	if config.MsgSearchConfig.Enable {
+		msgSearchIndex, err = msgsearch.NewMsgSearchIndex(ctx, &config.MsgSearchConfig, mgocli.GetDB())
+		if err != nil {
+			return err
+		}
+	}
 	s := &msgServer{
 		MsgDatabase:            msgDatabase,
+		MsgThreadDatabase:      controller.NewMsgThreadDatabase(msgThreadModel, msgDocModel, msgModel),
+		ScheduledMsgDatabase:   controller.NewScheduledMsgDatabase(scheduledMsgModel),
+		RetentionRuleDatabase:  controller.NewRetentionRuleDatabase(retentionRuleModel),
+		LegalHoldDatabase:      controller.NewLegalHoldDatabase(legalHoldModel, mgocli.GetTx()),
+		MsgExportDatabase:      controller.NewMsgExportDatabase(msgDocModel, seqConversation, seqUser),
+		MsgImportDatabase:      controller.NewMsgImportDatabase(msgDocModel, seqConversationCache, msgImportCheckpointModel, importLocker, msgSearchIndex),
 		RegisterCenter:         client,
 		UserLocalCache:         rpccache.NewUserLocalCache(rpcli.NewUserClient(userConn), &config.LocalCacheConfig, rdb),
 		GroupLocalCache:        rpccache.NewGroupLocalCache(rpcli.NewGroupClient(groupConn), &config.LocalCacheConfig, rdb),
@@ -146,6 +194,7 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 		config:                 config,
 		webhookClient:          webhook.NewWebhookClient(config.WebhooksConfig.URL),
 		conversationClient:     conversationClient,
+		msgSearchIndex:         msgSearchIndex,
 		adminUserIDs:           config.Share.IMAdminUser.UserIDs,
 	}
 
@@ -153,6 +202,7 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	s.msgNotificationSender = NewMsgNotificationSender(config, notification.WithLocalSendMsg(s.SendMsg))
 
 	msg.RegisterMsgServer(server, s)
+	msgext.RegisterMsgExtServer(server, s)
 
 	return nil
 }
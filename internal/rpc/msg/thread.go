@@ -0,0 +1,123 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// getVisibleThread returns the thread rooted at parentServerMsgID, checking userID can still see its root message.
+func (m *msgServer) getVisibleThread(ctx context.Context, userID, conversationID, parentServerMsgID string) (*model.MsgThread, error) {
+	threads, err := m.MsgThreadDatabase.GetThreads(ctx, conversationID, []string{parentServerMsgID})
+	if err != nil {
+		return nil, err
+	}
+	if len(threads) == 0 {
+		return nil, errs.ErrRecordNotFound.WrapMsg("thread not found", "parentServerMsgID", parentServerMsgID)
+	}
+	msgData, err := m.getVisibleMsg(ctx, userID, conversationID, threads[0].ParentSeq)
+	if err != nil {
+		return nil, err
+	}
+	if msgData.ServerMsgID != parentServerMsgID {
+		return nil, errs.ErrRecordNotFound.WrapMsg("thread not found", "parentServerMsgID", parentServerMsgID)
+	}
+	if _, err := m.getMsgRecvID(ctx, userID, msgData); err != nil {
+		return nil, err
+	}
+	return threads[0], nil
+}
+
+func (m *msgServer) GetThreadReplies(ctx context.Context, req *msgext.GetThreadRepliesReq) (*msgext.GetThreadRepliesResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := m.getVisibleThread(ctx, req.UserID, req.ConversationID, req.ParentServerMsgID); err != nil {
+		return nil, err
+	}
+	total, replies, err := m.MsgThreadDatabase.GetReplies(ctx, req.ConversationID, req.ParentServerMsgID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetThreadRepliesResp{Total: total}
+	if len(replies) == 0 {
+		return resp, nil
+	}
+	seqs := datautil.Slice(replies, func(e *model.MsgThreadReply) int64 { return e.Seq })
+	_, _, msgs, err := m.MsgDatabase.GetMsgBySeqs(ctx, req.UserID, req.ConversationID, seqs)
+	if err != nil {
+		return nil, err
+	}
+	msgMap := datautil.SliceToMap(msgs, func(e *sdkws.MsgData) int64 { return e.Seq })
+	for _, reply := range replies {
+		msgData, ok := msgMap[reply.Seq]
+		if !ok {
+			msgData = &sdkws.MsgData{Seq: reply.Seq, Status: constant.MsgStatusHasDeleted}
+		}
+		resp.Replies = append(resp.Replies, &msgext.ThreadReply{ThreadSeq: reply.ThreadSeq, Msg: msgData})
+	}
+	return resp, nil
+}
+
+func (m *msgServer) SetThreadHasReadSeq(ctx context.Context, req *msgext.SetThreadHasReadSeqReq) (*msgext.SetThreadHasReadSeqResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	thread, err := m.getVisibleThread(ctx, req.UserID, req.ConversationID, req.ParentServerMsgID)
+	if err != nil {
+		return nil, err
+	}
+	if req.HasReadSeq > thread.ReplyCount {
+		return nil, errs.ErrArgs.WrapMsg("hasReadSeq must not be bigger than maxSeq", "hasReadSeq", req.HasReadSeq, "maxSeq", thread.ReplyCount)
+	}
+	if err := m.MsgThreadDatabase.SetHasReadSeq(ctx, req.UserID, req.ConversationID, req.ParentServerMsgID, req.HasReadSeq); err != nil {
+		return nil, err
+	}
+	return &msgext.SetThreadHasReadSeqResp{}, nil
+}
+
+func (m *msgServer) GetThreadsHasReadAndMaxSeq(ctx context.Context, req *msgext.GetThreadsHasReadAndMaxSeqReq) (*msgext.GetThreadsHasReadAndMaxSeqResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := m.ConversationLocalCache.GetConversation(ctx, req.UserID, req.ConversationID); err != nil {
+		return nil, err
+	}
+	threads, err := m.MsgThreadDatabase.GetThreads(ctx, req.ConversationID, req.ParentServerMsgIDs)
+	if err != nil {
+		return nil, err
+	}
+	hasReadSeqs, err := m.MsgThreadDatabase.GetHasReadSeqs(ctx, req.UserID, req.ConversationID, req.ParentServerMsgIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp := &msgext.GetThreadsHasReadAndMaxSeqResp{Seqs: make(map[string]*msgext.ThreadSeqs)}
+	for _, thread := range threads {
+		resp.Seqs[thread.ParentServerMsgID] = &msgext.ThreadSeqs{
+			HasReadSeq:    hasReadSeqs[thread.ParentServerMsgID],
+			MaxSeq:        thread.ReplyCount,
+			LastReplyTime: thread.LastReplyTime,
+		}
+	}
+	return resp, nil
+}
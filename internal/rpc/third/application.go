@@ -0,0 +1,175 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package third
+
+import (
+	"context"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+	"github.com/openimsdk/tools/mcontext"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (t *thirdServer) AddApplicationVersion(ctx context.Context, req *thirdext.AddApplicationVersionReq) (*thirdext.AddApplicationVersionResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rollout := int32(100)
+	if req.Rollout != nil {
+		rollout = *req.Rollout
+	}
+	application := &model.Application{
+		ID:         primitive.NewObjectID(),
+		Platform:   req.Platform,
+		Hot:        req.Hot,
+		Version:    req.Version,
+		Url:        req.Url,
+		Text:       req.Text,
+		Force:      req.Force,
+		Rollout:    rollout,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := t.applicationDatabase.AddApplication(ctx, application); err != nil {
+		return nil, err
+	}
+	return &thirdext.AddApplicationVersionResp{ID: application.ID.Hex()}, nil
+}
+
+func (t *thirdServer) UpdateApplicationVersion(ctx context.Context, req *thirdext.UpdateApplicationVersionReq) (*thirdext.UpdateApplicationVersionResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	data := make(map[string]any)
+	if req.Url != nil {
+		data["url"] = *req.Url
+	}
+	if req.Text != nil {
+		data["text"] = *req.Text
+	}
+	if req.Force != nil {
+		data["force"] = *req.Force
+	}
+	if req.Hot != nil {
+		data["hot"] = *req.Hot
+	}
+	if req.Rollout != nil {
+		data["rollout"] = *req.Rollout
+	}
+	if len(data) == 0 {
+		return &thirdext.UpdateApplicationVersionResp{}, nil
+	}
+	data["update_time"] = time.Now()
+	if err := t.applicationDatabase.UpdateApplication(ctx, req.ID, data); err != nil {
+		return nil, err
+	}
+	return &thirdext.UpdateApplicationVersionResp{}, nil
+}
+
+func (t *thirdServer) DeleteApplicationVersion(ctx context.Context, req *thirdext.DeleteApplicationVersionReq) (*thirdext.DeleteApplicationVersionResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := t.applicationDatabase.DeleteApplications(ctx, req.IDs); err != nil {
+		return nil, err
+	}
+	return &thirdext.DeleteApplicationVersionResp{}, nil
+}
+
+func (t *thirdServer) PageApplicationVersion(ctx context.Context, req *thirdext.PageApplicationVersionReq) (*thirdext.PageApplicationVersionResp, error) {
+	if err := authverify.CheckAdmin(ctx); err != nil {
+		return nil, err
+	}
+	total, applications, err := t.applicationDatabase.PageApplications(ctx, req.Platform, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	resp := &thirdext.PageApplicationVersionResp{Total: total, Versions: make([]*thirdext.ApplicationVersion, 0, len(applications))}
+	for _, application := range applications {
+		resp.Versions = append(resp.Versions, convertApplication(application))
+	}
+	return resp, nil
+}
+
+// LatestApplicationVersion offers the newest release of the platform rolled out to the user of the token, if any,
+// forcing the update if any release between the client's version and it is a force release.
+func (t *thirdServer) LatestApplicationVersion(ctx context.Context, req *thirdext.LatestApplicationVersionReq) (*thirdext.LatestApplicationVersionResp, error) {
+	applications, err := t.applicationDatabase.GetPlatformApplications(ctx, req.Platform)
+	if err != nil {
+		return nil, err
+	}
+	return latestApplication(applications, req.Version, mcontext.GetOpUserID(ctx)), nil
+}
+
+// latestApplication picks the release offered to a client on version, force releases are offered to every user
+// whatever their rollout, as the clients before them must not keep running.
+func latestApplication(applications []*model.Application, version string, userID string) *thirdext.LatestApplicationVersionResp {
+	sort.Slice(applications, func(i, j int) bool {
+		return thirdext.CompareVersion(applications[i].Version, applications[j].Version) > 0
+	})
+	resp := &thirdext.LatestApplicationVersionResp{}
+	for _, application := range applications {
+		if version != "" && thirdext.CompareVersion(application.Version, version) <= 0 {
+			break
+		}
+		if !application.Force && !inRollout(application, userID) {
+			continue
+		}
+		if resp.Version == nil {
+			resp.Version = convertApplication(application)
+		}
+		if application.Force && version != "" {
+			resp.Force = true
+			break
+		}
+	}
+	return resp
+}
+
+// inRollout tells whether the release is offered to the user, each user falls in a bucket from 0 to 99 per release
+// and gets the release once Rollout passes the bucket.
+func inRollout(application *model.Application, userID string) bool {
+	if application.Rollout >= 100 {
+		return true
+	}
+	if application.Rollout <= 0 || userID == "" {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(application.ID[:])
+	_, _ = h.Write([]byte(userID))
+	return int32(h.Sum32()%100) < application.Rollout
+}
+
+func convertApplication(application *model.Application) *thirdext.ApplicationVersion {
+	return &thirdext.ApplicationVersion{
+		ID:         application.ID.Hex(),
+		Platform:   application.Platform,
+		Version:    application.Version,
+		Url:        application.Url,
+		Text:       application.Text,
+		Force:      application.Force,
+		Hot:        application.Hot,
+		Rollout:    application.Rollout,
+		CreateTime: application.CreateTime.UnixMilli(),
+		UpdateTime: application.UpdateTime.UnixMilli(),
+	}
+}
@@ -0,0 +1,77 @@
+package third
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInRollout(t *testing.T) {
+	application := &model.Application{ID: primitive.NewObjectID(), Rollout: 30}
+	var offered int
+	for i := 0; i < 10000; i++ {
+		userID := strconv.Itoa(i)
+		ok := inRollout(application, userID)
+		if ok != inRollout(application, userID) {
+			t.Fatal("rollout not stable for a user")
+		}
+		if ok {
+			offered++
+			application.Rollout = 60
+			if !inRollout(application, userID) {
+				t.Fatal("raising the rollout dropped a user")
+			}
+			application.Rollout = 30
+		}
+	}
+	if offered < 2700 || offered > 3300 {
+		t.Fatalf("rollout of 30%% offered to %d of 10000 users", offered)
+	}
+	if inRollout(application, "") {
+		t.Fatal("partial rollout offered without a user")
+	}
+	application.Rollout = 100
+	if !inRollout(application, "") {
+		t.Fatal("full rollout not offered without a user")
+	}
+	application.Rollout = 0
+	if inRollout(application, "1") {
+		t.Fatal("release of no rollout offered")
+	}
+}
+
+func TestLatestApplication(t *testing.T) {
+	release := func(version string, force bool, rollout int32) *model.Application {
+		return &model.Application{ID: primitive.NewObjectID(), Version: version, Force: force, Rollout: rollout}
+	}
+	tests := []struct {
+		name         string
+		applications []*model.Application
+		version      string
+		userID       string
+		want         string
+		force        bool
+	}{
+		{name: "up to date", applications: []*model.Application{release("1.0", false, 100)}, version: "1.0"},
+		{name: "newest", applications: []*model.Application{release("1.1", false, 100), release("1.2", false, 100)}, version: "1.0", want: "1.2"},
+		{name: "not rolled out", applications: []*model.Application{release("1.1", false, 100), release("1.2", false, 0)}, version: "1.0", userID: "u1", want: "1.1"},
+		{name: "force between", applications: []*model.Application{release("1.1", true, 100), release("1.2", false, 100)}, version: "1.0", want: "1.2", force: true},
+		{name: "force not rolled out", applications: []*model.Application{release("1.1", true, 0)}, version: "1.0", want: "1.1", force: true},
+		{name: "force already installed", applications: []*model.Application{release("1.0", true, 100), release("1.1", false, 100)}, version: "1.0", want: "1.1"},
+		{name: "no version", applications: []*model.Application{release("1.1", true, 100)}, want: "1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := latestApplication(tt.applications, tt.version, tt.userID)
+			var got string
+			if resp.Version != nil {
+				got = resp.Version.Version
+			}
+			if got != tt.want || resp.Force != tt.force {
+				t.Fatalf("got %q force %v, want %q force %v", got, resp.Force, tt.want, tt.force)
+			}
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/protocol/third"
 	"github.com/openimsdk/tools/errs"
 	"github.com/openimsdk/tools/log"
@@ -294,8 +295,17 @@ func (t *thirdServer) DeleteOutdatedData(ctx context.Context, req *third.DeleteO
 	}
 	engine := t.config.RpcConfig.Object.Enable
 	expireTime := time.UnixMilli(req.ExpireTime)
+	// Files uploaded by users under legal hold, or referenced by the msgs of held conversations, are kept.
+	heldUserIDs, _, err := t.legalHoldDatabase.GetHeldTargets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// records the objects of the msgs stored since the previous call, FindExpirationObject skips the recorded ones
+	if _, err := t.msgExtClient.SyncLegalHeldObjects(ctx, &msgext.SyncLegalHeldObjectsReq{}); err != nil {
+		return nil, err
+	}
 	// Find all expired data in S3 database
-	models, err := t.s3dataBase.FindExpirationObject(ctx, engine, expireTime, req.ObjectGroup, int64(req.Limit))
+	models, err := t.s3dataBase.FindExpirationObject(ctx, engine, expireTime, req.ObjectGroup, heldUserIDs, int64(req.Limit))
 	if err != nil {
 		return nil, err
 	}
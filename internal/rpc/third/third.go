@@ -30,6 +30,8 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/redis"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
 	"github.com/openimsdk/open-im-server/v3/pkg/localcache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
 	"github.com/openimsdk/tools/s3/aws"
 	"github.com/openimsdk/tools/s3/kodo"
 
@@ -45,12 +47,16 @@ import (
 
 type thirdServer struct {
 	third.UnimplementedThirdServer
-	thirdDatabase controller.ThirdDatabase
-	s3dataBase    controller.S3Database
-	defaultExpire time.Duration
-	config        *Config
-	s3            s3.Interface
-	userClient    *rpcli.UserClient
+	thirdext.UnimplementedThirdExtServer
+	thirdDatabase       controller.ThirdDatabase
+	s3dataBase          controller.S3Database
+	legalHoldDatabase   controller.LegalHoldDatabase
+	applicationDatabase controller.ApplicationDatabase
+	defaultExpire       time.Duration
+	config              *Config
+	s3                  s3.Interface
+	userClient          *rpcli.UserClient
+	msgExtClient        msgext.MsgExtClient
 }
 
 type Config struct {
@@ -83,6 +89,14 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
+	legalHoldDB, err := mgo.NewLegalHoldMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	applicationDB, err := mgo.NewApplicationMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
 	var thirdCache cache.ThirdCache
 	if rdb == nil {
 		tc, err := mgo.NewCacheMgo(mgocli.GetDB())
@@ -128,15 +142,24 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
+	msgConn, err := client.GetConn(ctx, config.Discovery.RpcService.Msg)
+	if err != nil {
+		return err
+	}
 	localcache.InitLocalCache(&config.LocalCacheConfig)
-	third.RegisterThirdServer(server, &thirdServer{
-		thirdDatabase: controller.NewThirdDatabase(thirdCache, logdb),
-		s3dataBase:    controller.NewS3Database(rdb, o, s3db),
-		defaultExpire: time.Hour * 24 * 7,
-		config:        config,
-		s3:            o,
-		userClient:    rpcli.NewUserClient(userConn),
-	})
+	srv := &thirdServer{
+		thirdDatabase:       controller.NewThirdDatabase(thirdCache, logdb),
+		s3dataBase:          controller.NewS3Database(rdb, o, s3db),
+		legalHoldDatabase:   controller.NewLegalHoldDatabase(legalHoldDB, mgocli.GetTx()),
+		applicationDatabase: controller.NewApplicationDatabase(applicationDB),
+		defaultExpire:       time.Hour * 24 * 7,
+		config:              config,
+		s3:                  o,
+		userClient:          rpcli.NewUserClient(userConn),
+		msgExtClient:        msgext.NewMsgExtClient(msgConn),
+	}
+	third.RegisterThirdServer(server, srv)
+	thirdext.RegisterThirdExtServer(server, srv)
 	return nil
 }
 
@@ -148,6 +171,16 @@ func (t *thirdServer) FcmUpdateToken(ctx context.Context, req *third.FcmUpdateTo
 	return &third.FcmUpdateTokenResp{}, nil
 }
 
+func (t *thirdServer) UpdateVendorToken(ctx context.Context, req *thirdext.UpdateVendorTokenReq) (*thirdext.UpdateVendorTokenResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := t.thirdDatabase.VendorUpdateToken(ctx, req.UserID, int(req.PlatformID), req.Vendor, req.Token, req.ExpireTime); err != nil {
+		return nil, err
+	}
+	return &thirdext.UpdateVendorTokenResp{}, nil
+}
+
 func (t *thirdServer) SetAppBadge(ctx context.Context, req *third.SetAppBadgeReq) (resp *third.SetAppBadgeResp, err error) {
 	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
 		return nil, err
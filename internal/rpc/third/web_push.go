@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package third
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/thirdext"
+)
+
+func (t *thirdServer) RegisterWebPushSubscription(ctx context.Context, req *thirdext.RegisterWebPushSubscriptionReq) (*thirdext.RegisterWebPushSubscriptionResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	subscription := &model.WebPushSubscription{
+		Endpoint:       req.Endpoint,
+		P256dh:         req.P256dh,
+		Auth:           req.Auth,
+		ExpirationTime: req.ExpirationTime,
+		CreateTime:     time.Now().UnixMilli(),
+	}
+	if err := t.thirdDatabase.SetWebPushSubscription(ctx, req.UserID, subscription); err != nil {
+		return nil, err
+	}
+	return &thirdext.RegisterWebPushSubscriptionResp{}, nil
+}
+
+func (t *thirdServer) UnregisterWebPushSubscription(ctx context.Context, req *thirdext.UnregisterWebPushSubscriptionReq) (*thirdext.UnregisterWebPushSubscriptionResp, error) {
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := t.thirdDatabase.DelWebPushSubscription(ctx, req.UserID, req.Endpoint); err != nil {
+		return nil, err
+	}
+	return &thirdext.UnregisterWebPushSubscriptionResp{}, nil
+}
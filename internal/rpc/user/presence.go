@@ -0,0 +1,65 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+func (s *userServer) SetUserPresence(ctx context.Context, req *userext.SetUserPresenceReq) (*userext.SetUserPresenceResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.WrapMsg(err.Error())
+	}
+	if err := authverify.CheckAccess(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	if req.ExpireAt != 0 && req.ExpireAt <= now {
+		return nil, errs.ErrArgs.WrapMsg("expireAt has passed")
+	}
+	if req.State == userext.PresenceAvailable && req.Text == "" && req.Emoji == "" {
+		if err := s.presence.DelPresence(ctx, req.UserID, now); err != nil {
+			return nil, err
+		}
+		return &userext.SetUserPresenceResp{}, nil
+	}
+	presence := &model.Presence{
+		UserID:     req.UserID,
+		State:      req.State,
+		Text:       req.Text,
+		Emoji:      req.Emoji,
+		ExpireAt:   req.ExpireAt,
+		UpdateTime: now,
+	}
+	if err := s.presence.SetPresence(ctx, presence); err != nil {
+		return nil, err
+	}
+	return &userext.SetUserPresenceResp{}, nil
+}
+
+func (s *userServer) GetUsersPresence(ctx context.Context, req *userext.GetUsersPresenceReq) (*userext.GetUsersPresenceResp, error) {
+	if err := req.Check(); err != nil {
+		return nil, errs.ErrArgs.WrapMsg(err.Error())
+	}
+	presences, err := s.presence.GetPresences(ctx, datautil.Distinct(req.UserIDs))
+	if err != nil {
+		return nil, err
+	}
+	return &userext.GetUsersPresenceResp{Presences: datautil.Slice(presences, presenceDB2Pb)}, nil
+}
+
+func presenceDB2Pb(presence *model.Presence) *userext.UserPresence {
+	return &userext.UserPresence{
+		UserID:     presence.UserID,
+		State:      presence.State,
+		Text:       presence.Text,
+		Emoji:      presence.Emoji,
+		ExpireAt:   presence.ExpireAt,
+		UpdateTime: presence.UpdateTime,
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/webhook"
 	"github.com/openimsdk/open-im-server/v3/pkg/dbbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/localcache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/userext"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcli"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/group"
@@ -55,7 +56,9 @@ const (
 
 type userServer struct {
 	pbuser.UnimplementedUserServer
+	userext.UnimplementedUserExtServer
 	online                   cache.OnlineCache
+	presence                 cache.PresenceCache
 	db                       controller.UserDatabase
 	friendNotificationSender *relation.FriendNotificationSender
 	userNotificationSender   *UserNotificationSender
@@ -127,6 +130,7 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 	localcache.InitLocalCache(&config.LocalCacheConfig)
 	u := &userServer{
 		online:                   redis.NewUserOnline(rdb),
+		presence:                 redis.NewPresenceCache(rdb),
 		db:                       database,
 		RegisterCenter:           client,
 		friendNotificationSender: relation.NewFriendNotificationSender(&config.NotificationConfig, msgClient, relation.WithDBFunc(database.FindWithError)),
@@ -139,6 +143,7 @@ func Start(ctx context.Context, config *Config, client discovery.SvcDiscoveryReg
 		adminUserIDs:             config.Share.IMAdminUser.UserIDs,
 	}
 	pbuser.RegisterUserServer(server, u)
+	userext.RegisterUserExtServer(server, u)
 	return u.db.InitOnce(context.Background(), users)
 }
 
@@ -5,6 +5,7 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	disetcd "github.com/openimsdk/open-im-server/v3/pkg/common/discovery/etcd"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	pbconversation "github.com/openimsdk/protocol/conversation"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/third"
@@ -26,11 +27,6 @@ type Config struct {
 
 func Start(ctx context.Context, conf *Config, client discovery.SvcDiscoveryRegistry, service grpc.ServiceRegistrar) error {
 	log.CInfo(ctx, "CRON-TASK server is initializing", "runTimeEnv", runtimeenv.RuntimeEnvironment(), "chatRecordsClearTime", conf.CronTask.CronExecuteTime, "msgDestructTime", conf.CronTask.RetainChatRecords)
-	if conf.CronTask.RetainChatRecords < 1 {
-		log.ZInfo(ctx, "disable cron")
-		<-ctx.Done()
-		return nil
-	}
 	ctx = mcontext.SetOpUserID(ctx, conf.Share.IMAdminUser.UserIDs[0])
 
 	msgConn, err := client.GetConn(ctx, conf.Discovery.RpcService.Msg)
@@ -71,18 +67,25 @@ func Start(ctx context.Context, conf *Config, client discovery.SvcDiscoveryRegis
 		config:             conf,
 		cron:               cron.New(),
 		msgClient:          msg.NewMsgClient(msgConn),
+		msgExtClient:       msgext.NewMsgExtClient(msgConn),
 		conversationClient: pbconversation.NewConversationClient(conversationConn),
 		thirdClient:        third.NewThirdClient(thirdConn),
 		locker:             locker,
 	}
 
-	if err := srv.registerClearS3(); err != nil {
-		return err
+	if conf.CronTask.RetainChatRecords >= 1 {
+		if err := srv.registerClearS3(); err != nil {
+			return err
+		}
+		if err := srv.registerClearUserMsg(); err != nil {
+			return err
+		}
 	}
+	// Retention rules may keep or delete chat records even when retainChatRecords is disabled.
 	if err := srv.registerDeleteMsg(); err != nil {
 		return err
 	}
-	if err := srv.registerClearUserMsg(); err != nil {
+	if err := srv.registerDispatchScheduledMsg(); err != nil {
 		return err
 	}
 	log.ZDebug(ctx, "start cron task", "CronExecuteTime", conf.CronTask.CronExecuteTime)
@@ -110,6 +113,7 @@ type cronServer struct {
 	config             *Config
 	cron               *cron.Cron
 	msgClient          msg.MsgClient
+	msgExtClient       msgext.MsgExtClient
 	conversationClient pbconversation.ConversationClient
 	thirdClient        third.ThirdClient
 	locker             Locker
@@ -127,10 +131,6 @@ func (c *cronServer) registerClearS3() error {
 }
 
 func (c *cronServer) registerDeleteMsg() error {
-	if c.config.CronTask.RetainChatRecords <= 0 {
-		log.ZInfo(c.ctx, "disable scheduled cleanup of chat records", "retainChatRecords", c.config.CronTask.RetainChatRecords)
-		return nil
-	}
 	_, err := c.cron.AddFunc(c.config.CronTask.CronExecuteTime, func() {
 		c.locker.ExecuteWithLock(c.ctx, "deleteMsg", c.deleteMsg)
 	})
@@ -143,3 +143,14 @@ func (c *cronServer) registerClearUserMsg() error {
 	})
 	return errs.WrapMsg(err, "failed to register clear user msg cron task")
 }
+
+func (c *cronServer) registerDispatchScheduledMsg() error {
+	if c.config.CronTask.ScheduleMsgExecuteTime == "" {
+		log.ZInfo(c.ctx, "disable dispatch of scheduled msgs")
+		return nil
+	}
+	_, err := c.cron.AddFunc(c.config.CronTask.ScheduleMsgExecuteTime, func() {
+		c.locker.ExecuteWithLock(c.ctx, "dispatchScheduledMsg", c.dispatchScheduledMsg)
+	})
+	return errs.WrapMsg(err, "failed to register dispatch scheduled msg cron task")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discovery"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	pbconversation "github.com/openimsdk/protocol/conversation"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/third"
@@ -55,6 +56,7 @@ func TestName(t *testing.T) {
 		},
 		cron:               cron.New(),
 		msgClient:          msg.NewMsgClient(msgConn),
+		msgExtClient:       msgext.NewMsgExtClient(msgConn),
 		conversationClient: pbconversation.NewConversationClient(conversationConn),
 		thirdClient:        third.NewThirdClient(thirdConn),
 	}
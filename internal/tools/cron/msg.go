@@ -5,17 +5,18 @@ import (
 	"os"
 	"time"
 
-	"github.com/openimsdk/protocol/msg"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/tools/log"
 	"github.com/openimsdk/tools/mcontext"
 )
 
+// deleteMsg deletes chat records past the retention rules, retainChatRecords applies to conversations no rule covers.
 func (c *cronServer) deleteMsg() {
 	now := time.Now()
-	deltime := now.Add(-time.Hour * 24 * time.Duration(c.config.CronTask.RetainChatRecords))
-	operationID := fmt.Sprintf("cron_msg_%d_%d", os.Getpid(), deltime.UnixMilli())
+	retainDays := max(int64(c.config.CronTask.RetainChatRecords), 0)
+	operationID := fmt.Sprintf("cron_msg_%d_%d", os.Getpid(), now.UnixMilli())
 	ctx := mcontext.SetOperationID(c.ctx, operationID)
-	log.ZDebug(ctx, "Destruct chat records", "deltime", deltime, "timestamp", deltime.UnixMilli())
+	log.ZDebug(ctx, "Destruct chat records", "retainChatRecords", retainDays)
 	const (
 		deleteCount = 10000
 		deleteLimit = 50
@@ -23,7 +24,7 @@ func (c *cronServer) deleteMsg() {
 	var count int
 	for i := 1; i <= deleteCount; i++ {
 		ctx := mcontext.SetOperationID(c.ctx, fmt.Sprintf("%s_%d", operationID, i))
-		resp, err := c.msgClient.DestructMsgs(ctx, &msg.DestructMsgsReq{Timestamp: deltime.UnixMilli(), Limit: deleteLimit})
+		resp, err := c.msgExtClient.ApplyRetentionRules(ctx, &msgext.ApplyRetentionRulesReq{DefaultRetainDays: retainDays, Limit: deleteLimit})
 		if err != nil {
 			log.ZError(ctx, "cron destruct chat records failed", err)
 			break
@@ -33,5 +34,5 @@ func (c *cronServer) deleteMsg() {
 			break
 		}
 	}
-	log.ZDebug(ctx, "cron destruct chat records end", "deltime", deltime, "cont", time.Since(now), "count", count)
+	log.ZDebug(ctx, "cron destruct chat records end", "retainChatRecords", retainDays, "cont", time.Since(now), "count", count)
 }
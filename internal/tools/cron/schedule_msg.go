@@ -0,0 +1,37 @@
+package cron
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/mcontext"
+)
+
+func (c *cronServer) dispatchScheduledMsg() {
+	now := time.Now()
+	operationID := fmt.Sprintf("cron_schedule_msg_%d_%d", os.Getpid(), now.UnixMilli())
+	ctx := mcontext.SetOperationID(c.ctx, operationID)
+	const (
+		dispatchCount = 1000
+		dispatchLimit = 100
+	)
+	var count int
+	for i := 1; i <= dispatchCount; i++ {
+		ctx := mcontext.SetOperationID(c.ctx, fmt.Sprintf("%s_%d", operationID, i))
+		resp, err := c.msgExtClient.DispatchScheduledMsgs(ctx, &msgext.DispatchScheduledMsgsReq{Limit: dispatchLimit})
+		if err != nil {
+			log.ZError(ctx, "cron dispatch scheduled msgs failed", err)
+			break
+		}
+		count += int(resp.Count)
+		if resp.Count < dispatchLimit {
+			break
+		}
+	}
+	if count > 0 {
+		log.ZDebug(ctx, "cron dispatch scheduled msgs end", "cost", time.Since(now), "count", count)
+	}
+}
@@ -152,3 +152,12 @@ type SendMsgResp struct {
 	// Modify fields modified via webhook.
 	Modify map[string]any `json:"modify,omitempty"`
 }
+
+// ExportConversationsReq selects the conversations streamed back as a zip archive.
+type ExportConversationsReq struct {
+	// ConversationIDs are the conversations to export, required field.
+	ConversationIDs []string `json:"conversationIDs" binding:"required"`
+
+	// WithAttachments downloads the files referenced by the messages into the archive.
+	WithAttachments bool `json:"withAttachments"`
+}
@@ -67,4 +67,5 @@ const (
 	CallbackBeforeCreateGroupChatConversationsCommand  = "callbackBeforeCreateGroupChatConversationsCommand"
 	CallbackAfterCreateGroupChatConversationsCommand   = "callbackAfterCreateGroupChatConversationsCommand"
 	CallbackAfterMsgSaveDBCommand                      = "callbackAfterMsgSaveDBCommand"
+	CallbackAfterScheduledMsgDroppedCommand            = "callbackAfterScheduledMsgDroppedCommand"
 )
@@ -113,3 +113,16 @@ type CallbackAfterMsgSaveDBReq struct {
 type CallbackAfterMsgSaveDBResp struct {
 	CommonCallbackResp
 }
+
+type CallbackAfterScheduledMsgDroppedReq struct {
+	CommonCallbackReq
+	ScheduleID string `json:"scheduleID"`
+	RecvID     string `json:"recvID"`
+	GroupID    string `json:"groupID"`
+	ErrCode    int    `json:"errCode"`
+	ErrMsg     string `json:"errMsg"`
+}
+
+type CallbackAfterScheduledMsgDroppedResp struct {
+	CommonCallbackResp
+}
@@ -44,6 +44,7 @@ func NewMsgRpcCmd() *MsgRpcCmd {
 		config.NotificationFileName:     &msgConfig.NotificationConfig,
 		config.WebhooksConfigFileName:   &msgConfig.WebhooksConfig,
 		config.LocalCacheConfigFileName: &msgConfig.LocalCacheConfig,
+		config.MsgSearchConfigFileName:  &msgConfig.MsgSearchConfig,
 		config.DiscoveryConfigFilename:  &msgConfig.Discovery,
 	}
 	ret.RootCmd = NewRootCmd(program.GetProcessName(), WithConfigMap(ret.configMap))
@@ -71,6 +72,7 @@ func (a *MsgRpcCmd) runE() error {
 			a.msgConfig.Share.GetConfigFileName(),
 			a.msgConfig.WebhooksConfig.GetConfigFileName(),
 			a.msgConfig.LocalCacheConfig.GetConfigFileName(),
+			a.msgConfig.MsgSearchConfig.GetConfigFileName(),
 			a.msgConfig.Discovery.GetConfigFileName(),
 		}, nil,
 		msg.Start)
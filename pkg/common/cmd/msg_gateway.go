@@ -42,6 +42,8 @@ func NewMsgGatewayCmd() *MsgGatewayCmd {
 		config.RedisConfigFileName:         &msgGatewayConfig.RedisConfig,
 		config.WebhooksConfigFileName:      &msgGatewayConfig.WebhooksConfig,
 		config.DiscoveryConfigFilename:     &msgGatewayConfig.Discovery,
+		config.OpenIMRPCMsgCfgFileName:     &msgGatewayConfig.MsgConfig,
+		config.LocalCacheConfigFileName:    &msgGatewayConfig.LocalCacheConfig,
 	}
 	ret.RootCmd = NewRootCmd(program.GetProcessName(), WithConfigMap(ret.configMap))
 	ret.ctx = context.WithValue(context.Background(), "version", version.Version)
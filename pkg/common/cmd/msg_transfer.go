@@ -43,6 +43,7 @@ func NewMsgTransferCmd() *MsgTransferCmd {
 		config.KafkaConfigFileName:          &msgTransferConfig.KafkaConfig,
 		config.ShareFileName:                &msgTransferConfig.Share,
 		config.WebhooksConfigFileName:       &msgTransferConfig.WebhooksConfig,
+		config.MsgSearchConfigFileName:      &msgTransferConfig.MsgSearch,
 		config.DiscoveryConfigFilename:      &msgTransferConfig.Discovery,
 	}
 	ret.RootCmd = NewRootCmd(program.GetProcessName(), WithConfigMap(ret.configMap))
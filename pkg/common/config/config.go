@@ -71,6 +71,17 @@ type Minio struct {
 	PublicRead      bool   `yaml:"publicRead"`
 }
 
+type MsgSearch struct {
+	Enable        bool   `yaml:"enable"`
+	Backend       string `yaml:"backend"`
+	Elasticsearch struct {
+		Address  []string `yaml:"address"`
+		Index    string   `yaml:"index"`
+		Username string   `yaml:"username"`
+		Password string   `yaml:"password"`
+	} `yaml:"elasticsearch"`
+}
+
 type Mongo struct {
 	URI            string   `yaml:"uri"`
 	Address        []string `yaml:"address"`
@@ -167,6 +178,8 @@ type CronTask struct {
 	RetainChatRecords int      `yaml:"retainChatRecords"`
 	FileExpireTime    int      `yaml:"fileExpireTime"`
 	DeleteObjectType  []string `yaml:"deleteObjectType"`
+	// ScheduleMsgExecuteTime is the cron spec due scheduled messages are dispatched on, empty disables the dispatcher.
+	ScheduleMsgExecuteTime string `yaml:"scheduleMsgExecuteTime"`
 }
 
 type OfflinePushConfig struct {
@@ -229,15 +242,79 @@ type MsgGateway struct {
 	Prometheus  Prometheus `yaml:"prometheus"`
 	ListenIP    string     `yaml:"listenIP"`
 	LongConnSvr struct {
-		Ports               []int `yaml:"ports"`
-		WebsocketMaxConnNum int   `yaml:"websocketMaxConnNum"`
-		WebsocketMaxMsgLen  int   `yaml:"websocketMaxMsgLen"`
-		WebsocketTimeout    int   `yaml:"websocketTimeout"`
+		Ports               []int                `yaml:"ports"`
+		WebsocketMaxConnNum int                  `yaml:"websocketMaxConnNum"`
+		WebsocketMaxMsgLen  int                  `yaml:"websocketMaxMsgLen"`
+		WebsocketTimeout    int                  `yaml:"websocketTimeout"`
+		Compression         WebsocketCompression `yaml:"compression"`
+		WriteQueue          WebsocketWriteQueue  `yaml:"writeQueue"`
+		SessionResume       SessionResume        `yaml:"sessionResume"`
+		Drain               WebsocketDrain       `yaml:"drain"`
+		ReqRateLimit        ReqRateLimit         `yaml:"reqRateLimit"`
+		Ephemeral           EphemeralEvent       `yaml:"ephemeral"`
+		SSE                 SSE                  `yaml:"sse"`
 	} `yaml:"longConnSvr"`
 	RateLimiter    RateLimiter    `yaml:"rateLimiter"`
 	CircuitBreaker CircuitBreaker `yaml:"circuitBreaker"`
 }
 
+type WebsocketCompression struct {
+	Protocols      []string `yaml:"protocols"`
+	MinSize        int      `yaml:"minSize"`
+	ZstdDictionary string   `yaml:"zstdDictionary"`
+}
+
+type WebsocketWriteQueue struct {
+	// Size is the number of frames queued for a connection, a client falling further behind is disconnected.
+	Size int `yaml:"size"`
+	// DropThreshold is the queue length from which frames the client can miss, such as typing, are dropped.
+	DropThreshold int `yaml:"dropThreshold"`
+}
+
+type WebsocketDrain struct {
+	// Timeout is how many seconds the clients get to reconnect elsewhere before the remaining conns are closed.
+	Timeout int `yaml:"timeout"`
+	// MaxReconnectDelay spreads the reconnects, each client waits a random delay of up to this many seconds.
+	MaxReconnectDelay int `yaml:"maxReconnectDelay"`
+}
+
+type ReqRateLimit struct {
+	Enable bool `yaml:"enable"`
+	// Redis shares the buckets between the gateway instances, otherwise each instance limits on its own.
+	Redis bool               `yaml:"redis"`
+	Rules []ReqRateLimitRule `yaml:"rules"`
+}
+
+type ReqRateLimitRule struct {
+	ReqIdentifier int32 `yaml:"reqIdentifier"`
+	// PlatformIDs the rule applies to, each with a bucket of its own, empty for the platforms without a rule of their own,
+	// which share one bucket.
+	PlatformIDs []int `yaml:"platformIDs"`
+	// Rate is the number of requests allowed per second, Burst the number allowed at once.
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type EphemeralEvent struct {
+	// Rate and Burst throttle the events of each sender in a conversation, per second and at once.
+	Rate             float64 `yaml:"rate"`
+	Burst            int     `yaml:"burst"`
+	MaxContentLength int     `yaml:"maxContentLength"`
+}
+
+type SSE struct {
+	// AllowOrigins are the origins of the browser clients allowed to use the transport, "*" allows any origin.
+	AllowOrigins []string `yaml:"allowOrigins"`
+}
+
+type SessionResume struct {
+	Enable bool `yaml:"enable"`
+	// BufferSize is the number of pushed frames kept for each connection.
+	BufferSize int `yaml:"bufferSize"`
+	// TTL is how many seconds a dropped connection can be resumed.
+	TTL int `yaml:"ttl"`
+}
+
 type MsgTransfer struct {
 	Prometheus struct {
 		Enable       bool  `yaml:"enable"`
@@ -249,10 +326,11 @@ type MsgTransfer struct {
 }
 
 type Push struct {
-	RPC                  RPC        `yaml:"rpc"`
-	Prometheus           Prometheus `yaml:"prometheus"`
-	MaxConcurrentWorkers int        `yaml:"maxConcurrentWorkers"`
-	Enable               string     `yaml:"enable"`
+	RPC                  RPC         `yaml:"rpc"`
+	Prometheus           Prometheus  `yaml:"prometheus"`
+	MaxConcurrentWorkers int         `yaml:"maxConcurrentWorkers"`
+	Enable               string      `yaml:"enable"`
+	Routes               []PushRoute `yaml:"routes"`
 	GeTui                struct {
 		PushUrl      string `yaml:"pushUrl"`
 		MasterSecret string `yaml:"masterSecret"`
@@ -271,16 +349,72 @@ type Push struct {
 		PushURL      string `yaml:"pushURL"`
 		PushIntent   string `yaml:"pushIntent"`
 	} `yaml:"jpush"`
+	APNs struct {
+		KeyFilePath string `yaml:"keyFilePath"`
+		KeyID       string `yaml:"keyID"`
+		TeamID      string `yaml:"teamID"`
+		BundleID    string `yaml:"bundleID"`
+	} `yaml:"apns"`
+	WebPush struct {
+		PublicKey  string `yaml:"publicKey"`
+		PrivateKey string `yaml:"privateKey"`
+		Subject    string `yaml:"subject"`
+		TTL        int    `yaml:"ttl"`
+	} `yaml:"webPush"`
+	Huawei struct {
+		AppID     string `yaml:"appID"`
+		AppSecret string `yaml:"appSecret"`
+		Category  string `yaml:"category"`
+	} `yaml:"huawei"`
+	Honor struct {
+		AppID        string `yaml:"appID"`
+		ClientID     string `yaml:"clientID"`
+		ClientSecret string `yaml:"clientSecret"`
+	} `yaml:"honor"`
+	Xiaomi struct {
+		AppSecret   string `yaml:"appSecret"`
+		PackageName string `yaml:"packageName"`
+		ChannelID   string `yaml:"channelID"`
+		PushURL     string `yaml:"pushURL"`
+	} `yaml:"xiaomi"`
+	OPPO struct {
+		AppKey       string `yaml:"appKey"`
+		MasterSecret string `yaml:"masterSecret"`
+		ChannelID    string `yaml:"channelID"`
+	} `yaml:"oppo"`
+	Vivo struct {
+		AppID          string `yaml:"appID"`
+		AppKey         string `yaml:"appKey"`
+		AppSecret      string `yaml:"appSecret"`
+		Classification int    `yaml:"classification"`
+	} `yaml:"vivo"`
 	IOSPush struct {
 		PushSound  string `yaml:"pushSound"`
 		BadgeCount bool   `yaml:"badgeCount"`
 		Production bool   `yaml:"production"`
 	} `yaml:"iosPush"`
+	OfflinePushRetry struct {
+		Enable bool `yaml:"enable"`
+		// MaxAttempts is how many times a failed push is retried before it is kept as a dead letter.
+		MaxAttempts int `yaml:"maxAttempts"`
+		// InitialBackoff and MaxBackoff are the seconds before the first retry and the most between two retries,
+		// the backoff doubles after every failed attempt.
+		InitialBackoff int `yaml:"initialBackoff"`
+		MaxBackoff     int `yaml:"maxBackoff"`
+		// DeadLetterExpire is the days the dead letters are kept for.
+		DeadLetterExpire int `yaml:"deadLetterExpire"`
+	} `yaml:"offlinePushRetry"`
 	FullUserCache  bool           `yaml:"fullUserCache"`
 	RateLimiter    RateLimiter    `yaml:"rateLimiter"`
 	CircuitBreaker CircuitBreaker `yaml:"circuitBreaker"`
 }
 
+// PushRoute sends the offline pushes to the devices of Platforms, named as in constant.PlatformNameToID, through Provider.
+type PushRoute struct {
+	Provider  string   `yaml:"provider"`
+	Platforms []string `yaml:"platforms"`
+}
+
 type Auth struct {
 	RPC         RPC        `yaml:"rpc"`
 	Prometheus  Prometheus `yaml:"prometheus"`
@@ -317,10 +451,30 @@ type Msg struct {
 	RPC            RPC            `yaml:"rpc"`
 	Prometheus     Prometheus     `yaml:"prometheus"`
 	FriendVerify   bool           `yaml:"friendVerify"`
+	ModifyMsg      ModifyMsg      `yaml:"modifyMsg"`
+	ScheduleMsg    ScheduleMsg    `yaml:"scheduleMsg"`
+	Reaction       Reaction       `yaml:"reaction"`
 	RateLimiter    RateLimiter    `yaml:"rateLimiter"`
 	CircuitBreaker CircuitBreaker `yaml:"circuitBreaker"`
 }
 
+type ModifyMsg struct {
+	Enable     bool          `yaml:"enable"`
+	TimeWindow time.Duration `yaml:"timeWindow"`
+	MaxHistory int           `yaml:"maxHistory"`
+}
+
+type ScheduleMsg struct {
+	Enable     bool          `yaml:"enable"`
+	MaxDelay   time.Duration `yaml:"maxDelay"`
+	MaxPending int64         `yaml:"maxPending"`
+}
+
+type Reaction struct {
+	MaxPerMsg  int `yaml:"maxPerMsg"`
+	MaxPerUser int `yaml:"maxPerUser"`
+}
+
 type Third struct {
 	RPC        RPC        `yaml:"rpc"`
 	Prometheus Prometheus `yaml:"prometheus"`
@@ -507,6 +661,7 @@ type Webhooks struct {
 	AfterSetGroupInfoEx                 AfterConfig  `yaml:"afterSetGroupInfoEx"`
 	BeforeSetGroupInfoEx                BeforeConfig `yaml:"beforeSetGroupInfoEx"`
 	AfterRevokeMsg                      AfterConfig  `yaml:"afterRevokeMsg"`
+	AfterScheduledMsgDropped            AfterConfig  `yaml:"afterScheduledMsgDropped"`
 	BeforeAddBlack                      BeforeConfig `yaml:"beforeAddBlack"`
 	AfterAddFriend                      AfterConfig  `yaml:"afterAddFriend"`
 	BeforeAddFriendAgree                BeforeConfig `yaml:"beforeAddFriendAgree"`
@@ -762,6 +917,7 @@ type AllConfig struct {
 	Log          Log
 	Minio        Minio
 	Mongo        Mongo
+	MsgSearch    MsgSearch
 	Notification Notification
 	API          API
 	CronTask     CronTask
@@ -794,6 +950,8 @@ func (a *AllConfig) Name2Config(name string) any {
 		return a.Minio
 	case a.Mongo.GetConfigFileName():
 		return a.Mongo
+	case a.MsgSearch.GetConfigFileName():
+		return a.MsgSearch
 	case a.Notification.GetConfigFileName():
 		return a.Notification
 	case a.API.GetConfigFileName():
@@ -839,6 +997,7 @@ func (a *AllConfig) GetConfigNames() []string {
 		a.Log.GetConfigFileName(),
 		a.Minio.GetConfigFileName(),
 		a.Mongo.GetConfigFileName(),
+		a.MsgSearch.GetConfigFileName(),
 		a.Notification.GetConfigFileName(),
 		a.API.GetConfigFileName(),
 		a.CronTask.GetConfigFileName(),
@@ -866,6 +1025,7 @@ const (
 	LogConfigFileName                = "log.yml"
 	MinioConfigFileName              = "minio.yml"
 	MongodbConfigFileName            = "mongodb.yml"
+	MsgSearchConfigFileName          = "msg-search.yml"
 	NotificationFileName             = "notification.yml"
 	OpenIMAPICfgFileName             = "openim-api.yml"
 	OpenIMCronTaskCfgFileName        = "openim-crontask.yml"
@@ -908,6 +1068,10 @@ func (m *Mongo) GetConfigFileName() string {
 	return MongodbConfigFileName
 }
 
+func (m *MsgSearch) GetConfigFileName() string {
+	return MsgSearchConfigFileName
+}
+
 func (n *Notification) GetConfigFileName() string {
 	return NotificationFileName
 }
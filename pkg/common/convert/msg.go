@@ -16,6 +16,7 @@ package convert
 
 import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/sdkws"
 )
@@ -96,3 +97,25 @@ func MsgDB2Pb(msgModel *model.MsgDataModel) *sdkws.MsgData {
 	msg.Ex = msgModel.Ex
 	return &msg
 }
+
+// MsgReactionsDB2Pb aggregates the reactions of a message per emoji, in the order each emoji was first used.
+func MsgReactionsDB2Pb(reactions []*model.ReactionModel, userID string) []*msgext.MsgReaction {
+	res := make([]*msgext.MsgReaction, 0)
+	emojis := make(map[string]*msgext.MsgReaction)
+	for _, reaction := range reactions {
+		if reaction == nil {
+			continue
+		}
+		r, ok := emojis[reaction.Emoji]
+		if !ok {
+			r = &msgext.MsgReaction{Emoji: reaction.Emoji}
+			emojis[reaction.Emoji] = r
+			res = append(res, r)
+		}
+		r.Count++
+		if userID != "" && reaction.UserID == userID {
+			r.Reacted = true
+		}
+	}
+	return res
+}
@@ -0,0 +1,60 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+)
+
+func TestMsgReactionsDB2Pb(t *testing.T) {
+	reactions := []*model.ReactionModel{
+		{UserID: "u1", Emoji: "👍"},
+		{UserID: "u2", Emoji: "❤️"},
+		{UserID: "u2", Emoji: "👍"},
+		nil,
+	}
+	tests := []struct {
+		name   string
+		userID string
+		want   []*msgext.MsgReaction
+	}{
+		{
+			name:   "reacted",
+			userID: "u1",
+			want: []*msgext.MsgReaction{
+				{Emoji: "👍", Count: 2, Reacted: true},
+				{Emoji: "❤️", Count: 1},
+			},
+		},
+		{
+			name: "anonymous",
+			want: []*msgext.MsgReaction{
+				{Emoji: "👍", Count: 2},
+				{Emoji: "❤️", Count: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MsgReactionsDB2Pb(reactions, tt.userID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MsgReactionsDB2Pb() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -23,8 +23,31 @@ var (
 		Name: "online_user_num",
 		Help: "The number of online user num",
 	})
+	WsWriteQueueDepthHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ws_write_queue_depth",
+		Help:    "The number of frames queued for a connection when a frame is queued",
+		Buckets: []float64{0, 1, 2, 4, 8, 16, 32, 64, 128, 256, 512},
+	})
+	WsWriteDroppedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ws_write_dropped_total",
+		Help: "The number of frames dropped because the client was falling behind",
+	})
+	WsSlowConsumerEvictedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ws_slow_consumer_evicted_total",
+		Help: "The number of connections closed because the write queue was full",
+	})
+	WsReqRateLimitedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ws_req_rate_limited_total",
+		Help: "The number of requests rejected by the rate limit",
+	}, []string{"req_identifier"})
 )
 
 func RegistryMsgGateway() {
-	registry.MustRegister(OnlineUserGauge)
+	registry.MustRegister(
+		OnlineUserGauge,
+		WsWriteQueueDepthHistogram,
+		WsWriteDroppedCounter,
+		WsSlowConsumerEvictedCounter,
+		WsReqRateLimitedCounter,
+	)
 }
@@ -27,11 +27,31 @@ var (
 		Name: "msg_long_time_push_total",
 		Help: "The number of messages with a push time exceeding 10 seconds",
 	})
+	// OfflinePushProviderCounter counts the offline pushes routed to each provider by result, success or failed.
+	OfflinePushProviderCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "offline_push_provider_total",
+		Help: "The number of offline pushes sent through each provider",
+	}, []string{"provider", "result"})
+	OfflinePushProviderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "offline_push_provider_duration_seconds",
+		Help:    "The time each provider takes to push",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"provider"})
+	// OfflinePushRetryCounter counts the retries of the failed offline pushes by result: scheduled when a push
+	// is persisted to be retried, success, failed when it is scheduled again, dead when it runs out of attempts,
+	// dropped when a pusher cannot tell the users it failed for.
+	OfflinePushRetryCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "offline_push_retry_total",
+		Help: "The number of offline push retries by result",
+	}, []string{"result"})
 )
 
 func RegistryPush() {
 	registry.MustRegister(
 		MsgOfflinePushFailedCounter,
 		MsgLoneTimePushCounter,
+		OfflinePushProviderCounter,
+		OfflinePushProviderDuration,
+		OfflinePushRetryCounter,
 	)
 }
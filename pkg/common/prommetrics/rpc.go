@@ -51,7 +51,13 @@ func GetGrpcServerMetrics() *gp.ServerMetrics {
 func GetGrpcCusMetrics(registerName string, discovery *config.Discovery) []prometheus.Collector {
 	switch registerName {
 	case discovery.RpcService.MessageGateway:
-		return []prometheus.Collector{OnlineUserGauge}
+		return []prometheus.Collector{
+			OnlineUserGauge,
+			WsWriteQueueDepthHistogram,
+			WsWriteDroppedCounter,
+			WsSlowConsumerEvictedCounter,
+			WsReqRateLimitedCounter,
+		}
 	case discovery.RpcService.Msg:
 		return []prometheus.Collector{
 			SingleChatMsgProcessSuccessCounter,
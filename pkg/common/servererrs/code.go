@@ -77,6 +77,8 @@ const (
 	MutedInGroup          = 1402 // Member muted in the group
 	MutedGroup            = 1403 // Group is muted
 	MsgAlreadyRevoke      = 1404 // Message already revoked
+	MsgModifyExpired      = 1405 // Message can no longer be modified
+	MsgLegalHold          = 1406 // Message is under legal hold
 
 	// Token error codes.
 	TokenExpiredError     = 1501
@@ -92,6 +94,8 @@ const (
 	ConnArgsErr          = 1602
 	PushMsgErr           = 1603
 	IOSBackgroundPushErr = 1604
+	ConnServerDraining   = 1605
+	ConnReqRateLimited   = 1606
 
 	// S3 error codes.
 	FileUploadedExpiredError = 1701 // Upload expired
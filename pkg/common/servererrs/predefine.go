@@ -60,12 +60,16 @@ var (
 	ErrMutedInGroup     = errs.NewCodeError(MutedInGroup, "MutedInGroup")
 	ErrMutedGroup       = errs.NewCodeError(MutedGroup, "MutedGroup")
 	ErrMsgAlreadyRevoke = errs.NewCodeError(MsgAlreadyRevoke, "MsgAlreadyRevoke")
+	ErrMsgModifyExpired = errs.NewCodeError(MsgModifyExpired, "MsgModifyExpired")
+	ErrMsgLegalHold     = errs.NewCodeError(MsgLegalHold, "MsgLegalHold")
 
 	ErrConnOverMaxNumLimit = errs.NewCodeError(ConnOverMaxNumLimit, "ConnOverMaxNumLimit")
 
 	ErrConnArgsErr          = errs.NewCodeError(ConnArgsErr, "args err, need token, sendID, platformID")
 	ErrPushMsgErr           = errs.NewCodeError(PushMsgErr, "push msg err")
 	ErrIOSBackgroundPushErr = errs.NewCodeError(IOSBackgroundPushErr, "ios background push err")
+	ErrConnServerDraining   = errs.NewCodeError(ConnServerDraining, "ConnServerDraining")
+	ErrConnReqRateLimited   = errs.NewCodeError(ConnReqRateLimited, "ConnReqRateLimited")
 
 	ErrFileUploadedExpired = errs.NewCodeError(FileUploadedExpiredError, "FileUploadedExpiredError")
 )
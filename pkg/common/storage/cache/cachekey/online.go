@@ -9,6 +9,10 @@ const (
 	OnlineKey     = "ONLINE:"
 	OnlineChannel = "online_change"
 	OnlineExpire  = time.Hour / 2
+
+	OnlineSubKey      = "ONLINE_SUB:"
+	OnlineNodeChannel = "online_change:"
+	OnlineSubExpire   = time.Minute * 3
 )
 
 func GetOnlineKey(userID string) string {
@@ -18,3 +22,11 @@ func GetOnlineKey(userID string) string {
 func GetOnlineKeyUserID(key string) string {
 	return strings.TrimPrefix(key, OnlineKey)
 }
+
+func GetOnlineSubKey(userID string) string {
+	return OnlineSubKey + userID
+}
+
+func GetOnlineNodeChannel(nodeID string) string {
+	return OnlineNodeChannel + nodeID
+}
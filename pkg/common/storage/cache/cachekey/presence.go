@@ -0,0 +1,12 @@
+package cachekey
+
+const (
+	PresenceKey = "PRESENCE:"
+	// PresenceChannel is published the json of each presence change, a cleared presence has only the userID and updateTime.
+	// Nothing is published when a presence expires, the gateways push the clear to the clients at its expireAt.
+	PresenceChannel = "presence_change"
+)
+
+func GetPresenceKey(userID string) string {
+	return PresenceKey + userID
+}
@@ -0,0 +1,16 @@
+package cachekey
+
+import "strconv"
+
+const (
+	RateLimitKey = "RATE_LIMIT:"
+)
+
+// GetReqRateLimitKey returns the bucket key, platformID is zero for the bucket shared by the platforms without a rule of their own.
+func GetReqRateLimitKey(userID string, reqIdentifier int32, platformID int) string {
+	key := RateLimitKey + userID + ":" + strconv.Itoa(int(reqIdentifier))
+	if platformID != 0 {
+		key += ":" + strconv.Itoa(platformID)
+	}
+	return key
+}
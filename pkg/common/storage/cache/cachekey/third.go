@@ -16,19 +16,33 @@ package cachekey
 
 import (
 	"strconv"
+	"time"
 )
 
 const (
 	getuiToken              = "GETUI_TOKEN"
 	getuiTaskID             = "GETUI_TASK_ID"
 	fmcToken                = "FCM_TOKEN:"
+	fcmTokenVendor          = "FCM_TOKEN_VENDOR:"
 	userBadgeUnreadCountSum = "USER_BADGE_UNREAD_COUNT_SUM:"
+	webPushSubscription     = "WEB_PUSH_SUBSCRIPTION:"
+
+	// WebPushSubscriptionExpire drops the subscriptions of a user whose browsers stopped registering them.
+	WebPushSubscriptionExpire = time.Hour * 24 * 90
 )
 
 func GetFcmAccountTokenKey(account string, platformID int) string {
 	return fmcToken + account + ":" + strconv.Itoa(platformID)
 }
 
+func GetFcmTokenVendorKey(account string, platformID int) string {
+	return fcmTokenVendor + account + ":" + strconv.Itoa(platformID)
+}
+
+func GetWebPushSubscriptionKey(userID string) string {
+	return webPushSubscription + userID
+}
+
 func GetUserBadgeUnreadCountSumKey(userID string) string {
 	return userBadgeUnreadCountSum + userID
 }
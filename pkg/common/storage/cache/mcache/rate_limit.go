@@ -0,0 +1,72 @@
+package mcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+)
+
+// rateLimitSweepInterval is how often the buckets that have refilled are dropped.
+const rateLimitSweepInterval = time.Minute
+
+func NewRateLimit() cache.RateLimitCache {
+	return &rateLimit{
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	burst  float64
+	rate   float64
+	time   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if now.After(b.time) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.time).Seconds()*b.rate)
+		b.time = now
+	}
+}
+
+type rateLimit struct {
+	lock      sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func (r *rateLimit) Allow(ctx context.Context, key string, rate float64, burst int) (bool, error) {
+	now := time.Now()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if now.Sub(r.lastSweep) > rateLimitSweepInterval {
+		r.sweep(now)
+	}
+	b, ok := r.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), time: now}
+		r.buckets[key] = b
+	}
+	b.burst = float64(burst)
+	b.rate = rate
+	b.refill(now)
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+	return true, nil
+}
+
+// sweep drops the full buckets, a new bucket starts full as well.
+func (r *rateLimit) sweep(now time.Time) {
+	r.lastSweep = now
+	for key, b := range r.buckets {
+		b.refill(now)
+		if b.tokens >= b.burst {
+			delete(r.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package mcache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimitAllow(t *testing.T) {
+	r := NewRateLimit().(*rateLimit)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if ok, _ := r.Allow(ctx, "user1", 10, 3); !ok {
+			t.Fatalf("request %d within the burst limited", i)
+		}
+	}
+	if ok, _ := r.Allow(ctx, "user1", 10, 3); ok {
+		t.Fatal("request over the burst allowed")
+	}
+	if ok, _ := r.Allow(ctx, "user2", 10, 3); !ok {
+		t.Fatal("the buckets are not separate")
+	}
+	// a token is refilled every 100ms
+	r.buckets["user1"].time = time.Now().Add(-time.Millisecond * 150)
+	if ok, _ := r.Allow(ctx, "user1", 10, 3); !ok {
+		t.Fatal("refilled token not taken")
+	}
+	if ok, _ := r.Allow(ctx, "user1", 10, 3); ok {
+		t.Fatal("request over the refill allowed")
+	}
+	r.sweep(time.Now().Add(time.Second))
+	if _, ok := r.buckets["user1"]; ok {
+		t.Fatal("full bucket not swept")
+	}
+}
@@ -2,12 +2,15 @@ package mcache
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strconv"
 	"time"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
 	"github.com/openimsdk/tools/errs"
 	"github.com/redis/go-redis/v9"
 )
@@ -58,7 +61,26 @@ func (c *thirdCache) GetFcmToken(ctx context.Context, account string, platformID
 }
 
 func (c *thirdCache) DelFcmToken(ctx context.Context, account string, platformID int) error {
-	return c.cache.Del(ctx, []string{c.getFcmAccountTokenKey(account, platformID)})
+	return c.cache.Del(ctx, []string{c.getFcmAccountTokenKey(account, platformID), c.getFcmTokenVendorKey(account, platformID)})
+}
+
+func (c *thirdCache) getFcmTokenVendorKey(account string, platformID int) string {
+	return cachekey.GetFcmTokenVendorKey(account, platformID)
+}
+
+func (c *thirdCache) SetFcmTokenVendor(ctx context.Context, account string, platformID int, vendor string, expireTime int64) error {
+	if vendor == "" {
+		return c.cache.Del(ctx, []string{c.getFcmTokenVendorKey(account, platformID)})
+	}
+	return c.cache.Set(ctx, c.getFcmTokenVendorKey(account, platformID), vendor, time.Duration(expireTime)*time.Second)
+}
+
+func (c *thirdCache) GetFcmTokenVendor(ctx context.Context, account string, platformID int) (string, error) {
+	vendor, err := c.get(ctx, c.getFcmTokenVendorKey(account, platformID))
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return vendor, err
 }
 
 func (c *thirdCache) IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
@@ -96,3 +118,67 @@ func (c *thirdCache) SetGetuiTaskID(ctx context.Context, taskID string, expireTi
 func (c *thirdCache) GetGetuiTaskID(ctx context.Context) (string, error) {
 	return c.get(ctx, c.getGetuiTaskIDKey())
 }
+
+func (c *thirdCache) getWebPushSubscriptionKey(userID string) string {
+	return cachekey.GetWebPushSubscriptionKey(userID)
+}
+
+func (c *thirdCache) setWebPushSubscriptions(ctx context.Context, userID string, subscriptions []*model.WebPushSubscription) error {
+	key := c.getWebPushSubscriptionKey(userID)
+	if len(subscriptions) == 0 {
+		return c.cache.Del(ctx, []string{key})
+	}
+	data, err := json.Marshal(subscriptions)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return c.cache.Set(ctx, key, string(data), cachekey.WebPushSubscriptionExpire)
+}
+
+// SetWebPushSubscription keeps the subscriptions of the user as one value, the latest created first.
+func (c *thirdCache) SetWebPushSubscription(ctx context.Context, userID string, subscription *model.WebPushSubscription) error {
+	subscriptions, err := c.GetWebPushSubscriptions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	result := make([]*model.WebPushSubscription, 0, len(subscriptions)+1)
+	result = append(result, subscription)
+	for _, s := range subscriptions {
+		if s.Endpoint != subscription.Endpoint && len(result) < cache.MaxWebPushSubscriptions {
+			result = append(result, s)
+		}
+	}
+	return c.setWebPushSubscriptions(ctx, userID, result)
+}
+
+func (c *thirdCache) GetWebPushSubscriptions(ctx context.Context, userID string) ([]*model.WebPushSubscription, error) {
+	value, err := c.get(ctx, c.getWebPushSubscriptionKey(userID))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var subscriptions []*model.WebPushSubscription
+	if err := json.Unmarshal([]byte(value), &subscriptions); err != nil {
+		return nil, errs.WrapMsg(err, "unmarshal web push subscriptions failed", "userID", userID)
+	}
+	return subscriptions, nil
+}
+
+func (c *thirdCache) DelWebPushSubscription(ctx context.Context, userID string, endpoint string) error {
+	subscriptions, err := c.GetWebPushSubscriptions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	result := make([]*model.WebPushSubscription, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		if s.Endpoint != endpoint {
+			result = append(result, s)
+		}
+	}
+	if len(result) == len(subscriptions) {
+		return nil
+	}
+	return c.setWebPushSubscriptions(ctx, userID, result)
+}
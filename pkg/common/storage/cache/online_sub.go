@@ -0,0 +1,10 @@
+package cache
+
+import "context"
+
+// OnlineSubCache indexes the gateway nodes watching the online status of each user,
+// so that a change is published only to the nodes that need it.
+type OnlineSubCache interface {
+	// Watch adds the node to the watchers of the users for cachekey.OnlineSubExpire, the node renews it meanwhile.
+	Watch(ctx context.Context, nodeID string, userIDs []string) error
+}
@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+)
+
+type PresenceCache interface {
+	// GetPresences returns the presences of the users that have one.
+	GetPresences(ctx context.Context, userIDs []string) ([]*model.Presence, error)
+	// SetPresence keeps the presence until its ExpireAt and publishes the change.
+	SetPresence(ctx context.Context, presence *model.Presence) error
+	// DelPresence clears the presence of the user and publishes the change.
+	DelPresence(ctx context.Context, userID string, updateTime int64) error
+}
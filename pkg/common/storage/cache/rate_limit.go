@@ -0,0 +1,9 @@
+package cache
+
+import "context"
+
+// RateLimitCache keeps token buckets, each refilled by rate tokens per second and holding at most burst tokens.
+type RateLimitCache interface {
+	// Allow takes a token from the bucket of key, false if the bucket is empty.
+	Allow(ctx context.Context, key string, rate float64, burst int) (bool, error)
+}
@@ -25,6 +25,7 @@ func NewUserOnline(rdb redis.UniversalClient) cache.OnlineCache {
 		rdb:         rdb,
 		expire:      cachekey.OnlineExpire,
 		channelName: cachekey.OnlineChannel,
+		fanout:      newOnlineFanout(rdb),
 	}
 }
 
@@ -32,6 +33,7 @@ type userOnline struct {
 	rdb         redis.UniversalClient
 	expire      time.Duration
 	channelName string
+	fanout      *onlineFanout
 }
 
 func (s *userOnline) getUserOnlineKey(userID string) string {
@@ -136,6 +138,10 @@ func (s *userOnline) SetUserOnline(ctx context.Context, userID string, online, o
 		if err := s.rdb.Publish(ctx, s.channelName, msg).Err(); err != nil {
 			return errs.Wrap(err)
 		}
+		// the gateways only hear about the users they watch
+		if err := s.fanout.add(ctx, userID, msg); err != nil {
+			return err
+		}
 	} else {
 		log.ZDebug(ctx, "redis SetUserOnline not push", "userID", userID, "online", online, "offline", offline)
 	}
@@ -0,0 +1,131 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// onlineFanoutBatch is the most changes looked up and published in one round.
+	onlineFanoutBatch = 500
+	// onlineFanoutInterval is how long a change waits for others to be batched with.
+	onlineFanoutInterval = time.Millisecond * 50
+	onlineFanoutTimeout  = time.Second * 5
+)
+
+func NewOnlineSubCache(rdb redis.UniversalClient) cache.OnlineSubCache {
+	return &onlineSub{rdb: rdb}
+}
+
+type onlineSub struct {
+	rdb redis.UniversalClient
+}
+
+func (s *onlineSub) Watch(ctx context.Context, nodeID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	score := float64(now.Add(cachekey.OnlineSubExpire).Unix())
+	pipe := s.rdb.Pipeline()
+	for _, userID := range userIDs {
+		key := cachekey.GetOnlineSubKey(userID)
+		// the nodes that stopped renewing are gone or no longer interested
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Unix(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: nodeID})
+		pipe.Expire(ctx, key, cachekey.OnlineSubExpire)
+	}
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+type onlineChange struct {
+	userID  string
+	payload string
+}
+
+// onlineFanout publishes the online status changes to the channels of the nodes watching the users,
+// the changes made close together are sent to a node as one message of payloads separated by newlines.
+type onlineFanout struct {
+	rdb     redis.UniversalClient
+	changes chan onlineChange
+}
+
+func newOnlineFanout(rdb redis.UniversalClient) *onlineFanout {
+	f := &onlineFanout{
+		rdb:     rdb,
+		changes: make(chan onlineChange, onlineFanoutBatch*4),
+	}
+	go f.run()
+	return f
+}
+
+func (f *onlineFanout) add(ctx context.Context, userID string, payload string) error {
+	select {
+	case f.changes <- onlineChange{userID: userID, payload: payload}:
+		return nil
+	case <-ctx.Done():
+		return errs.Wrap(context.Cause(ctx))
+	}
+}
+
+func (f *onlineFanout) run() {
+	ticker := time.NewTicker(onlineFanoutInterval)
+	defer ticker.Stop()
+	batch := make([]onlineChange, 0, onlineFanoutBatch)
+	for {
+		select {
+		case change := <-f.changes:
+			batch = append(batch, change)
+			if len(batch) < onlineFanoutBatch {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		f.publish(batch)
+		batch = batch[:0]
+	}
+}
+
+func (f *onlineFanout) publish(batch []onlineChange) {
+	ctx, cancel := context.WithTimeout(context.Background(), onlineFanoutTimeout)
+	defer cancel()
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	pipe := f.rdb.Pipeline()
+	cmds := make([]*redis.StringSliceCmd, len(batch))
+	for i, change := range batch {
+		cmds[i] = pipe.ZRangeByScore(ctx, cachekey.GetOnlineSubKey(change.userID), &redis.ZRangeBy{Min: now, Max: "+inf"})
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		log.ZError(ctx, "online fanout get watch nodes failed", err, "changes", len(batch))
+		return
+	}
+	nodes := make(map[string][]string)
+	for i, cmd := range cmds {
+		for _, nodeID := range cmd.Val() {
+			nodes[nodeID] = append(nodes[nodeID], batch[i].payload)
+		}
+	}
+	if len(nodes) == 0 {
+		return
+	}
+	pipe = f.rdb.Pipeline()
+	for nodeID, payloads := range nodes {
+		pipe.Publish(ctx, cachekey.GetOnlineNodeChannel(nodeID), strings.Join(payloads, "\n"))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.ZError(ctx, "online fanout publish failed", err, "nodes", len(nodes))
+	}
+}
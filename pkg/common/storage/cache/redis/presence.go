@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"github.com/redis/go-redis/v9"
+)
+
+func NewPresenceCache(rdb redis.UniversalClient) cache.PresenceCache {
+	return &presenceCache{rdb: rdb}
+}
+
+type presenceCache struct {
+	rdb redis.UniversalClient
+}
+
+func (c *presenceCache) GetPresences(ctx context.Context, userIDs []string) ([]*model.Presence, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, cachekey.GetPresenceKey(userID))
+	}
+	values, err := c.getValues(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	presences := make([]*model.Presence, 0, len(values))
+	for i, value := range values {
+		if value == "" {
+			continue
+		}
+		var presence model.Presence
+		if err := json.Unmarshal([]byte(value), &presence); err != nil {
+			log.ZWarn(ctx, "presence unmarshal failed", err, "key", keys[i])
+			continue
+		}
+		if presence.ExpireAt != 0 && presence.ExpireAt <= now {
+			continue
+		}
+		presences = append(presences, &presence)
+	}
+	return presences, nil
+}
+
+// getValues pipelines the reads, the keys are in different slots on a cluster.
+func (c *presenceCache) getValues(ctx context.Context, keys []string) ([]string, error) {
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.StringCmd, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, pipe.Get(ctx, key))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, errs.Wrap(err)
+	}
+	values := make([]string, len(keys))
+	for i, cmd := range cmds {
+		values[i], _ = cmd.Result()
+	}
+	return values, nil
+}
+
+func (c *presenceCache) SetPresence(ctx context.Context, presence *model.Presence) error {
+	data, err := json.Marshal(presence)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	var expire time.Duration
+	if presence.ExpireAt != 0 {
+		expire = time.Until(time.UnixMilli(presence.ExpireAt))
+		if expire <= 0 {
+			return c.DelPresence(ctx, presence.UserID, presence.UpdateTime)
+		}
+	}
+	if err := c.rdb.Set(ctx, cachekey.GetPresenceKey(presence.UserID), data, expire).Err(); err != nil {
+		return errs.Wrap(err)
+	}
+	return c.publish(ctx, data)
+}
+
+func (c *presenceCache) DelPresence(ctx context.Context, userID string, updateTime int64) error {
+	if err := c.rdb.Del(ctx, cachekey.GetPresenceKey(userID)).Err(); err != nil {
+		return errs.Wrap(err)
+	}
+	data, err := json.Marshal(&model.Presence{UserID: userID, UpdateTime: updateTime})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return c.publish(ctx, data)
+}
+
+func (c *presenceCache) publish(ctx context.Context, data []byte) error {
+	if err := c.rdb.Publish(ctx, cachekey.PresenceChannel, data).Err(); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
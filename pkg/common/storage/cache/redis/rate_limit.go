@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+// takeTokenScript refills the bucket for the time passed since it was last used, by the clock of redis
+// so that every gateway sees the same time, and takes a token. The bucket expires once it would be full.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "time")
+local tokens = tonumber(bucket[1])
+local last = tonumber(bucket[2])
+if tokens == nil or last == nil then
+	tokens = burst
+	last = now
+end
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) * rate / 1000)
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tokens, "time", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return allowed
+`)
+
+func NewRateLimit(rdb redis.UniversalClient) cache.RateLimitCache {
+	return &rateLimit{rdb: rdb}
+}
+
+type rateLimit struct {
+	rdb redis.UniversalClient
+}
+
+func (r *rateLimit) Allow(ctx context.Context, key string, rate float64, burst int) (bool, error) {
+	res, err := callLua(ctx, r.rdb, takeTokenScript, []string{key}, []any{rate, burst})
+	if err != nil {
+		return false, err
+	}
+	allowed, ok := res.(int64)
+	if !ok {
+		return false, errs.ErrInternalServer.WrapMsg("rate limit redis lua invalid return value")
+	}
+	return allowed == 1, nil
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRateLimitAllow(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	ctx := context.Background()
+	r := NewRateLimit(rdb)
+
+	mock.ExpectEvalSha(takeTokenScript.Hash(), []string{"key"}, []any{float64(10), 3}).SetVal(int64(1))
+	if ok, err := r.Allow(ctx, "key", 10, 3); err != nil || !ok {
+		t.Fatalf("got %v %v, want allowed", ok, err)
+	}
+	mock.ExpectEvalSha(takeTokenScript.Hash(), []string{"key"}, []any{float64(10), 3}).SetVal(int64(0))
+	if ok, err := r.Allow(ctx, "key", 10, 3); err != nil || ok {
+		t.Fatalf("got %v %v, want limited", ok, err)
+	}
+	mock.ExpectEvalSha(takeTokenScript.Hash(), []string{"key"}, []any{float64(10), 3}).SetVal("1")
+	if _, err := r.Allow(ctx, "key", 10, 3); err == nil {
+		t.Fatal("invalid return value accepted")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestRateLimitScript runs the script on the redis at localhost:6379, and is skipped without one.
+func TestRateLimitScript(t *testing.T) {
+	ctx := context.Background()
+	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer rdb.Close()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Skip("redis not reachable:", err)
+	}
+	key := "RATE_LIMIT_TEST:" + time.Now().String()
+	defer rdb.Del(ctx, key)
+	r := NewRateLimit(rdb)
+	for i := 0; i < 3; i++ {
+		if ok, err := r.Allow(ctx, key, 10, 3); err != nil || !ok {
+			t.Fatalf("request %d within the burst got %v %v", i, ok, err)
+		}
+	}
+	if ok, err := r.Allow(ctx, key, 10, 3); err != nil || ok {
+		t.Fatalf("request over the burst got %v %v", ok, err)
+	}
+	ttl, err := rdb.PTTL(ctx, key).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 0 || ttl > time.Millisecond*1300 {
+		t.Fatalf("bucket expires in %s, want once it would be full", ttl)
+	}
+	// a token is refilled every 100ms
+	time.Sleep(time.Millisecond * 150)
+	if ok, err := r.Allow(ctx, key, 10, 3); err != nil || !ok {
+		t.Fatalf("refilled token got %v %v", ok, err)
+	}
+	if ok, err := r.Allow(ctx, key, 10, 3); err != nil || ok {
+		t.Fatalf("request over the refill got %v %v", ok, err)
+	}
+}
@@ -2,10 +2,14 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sort"
 	"time"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/cachekey"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
 	"github.com/openimsdk/tools/errs"
 	"github.com/redis/go-redis/v9"
 )
@@ -47,7 +51,26 @@ func (c *thirdCache) GetFcmToken(ctx context.Context, account string, platformID
 }
 
 func (c *thirdCache) DelFcmToken(ctx context.Context, account string, platformID int) error {
-	return errs.Wrap(c.rdb.Del(ctx, c.getFcmAccountTokenKey(account, platformID)).Err())
+	return errs.Wrap(c.rdb.Del(ctx, c.getFcmAccountTokenKey(account, platformID), c.getFcmTokenVendorKey(account, platformID)).Err())
+}
+
+func (c *thirdCache) getFcmTokenVendorKey(account string, platformID int) string {
+	return cachekey.GetFcmTokenVendorKey(account, platformID)
+}
+
+func (c *thirdCache) SetFcmTokenVendor(ctx context.Context, account string, platformID int, vendor string, expireTime int64) error {
+	if vendor == "" {
+		return errs.Wrap(c.rdb.Del(ctx, c.getFcmTokenVendorKey(account, platformID)).Err())
+	}
+	return errs.Wrap(c.rdb.Set(ctx, c.getFcmTokenVendorKey(account, platformID), vendor, time.Duration(expireTime)*time.Second).Err())
+}
+
+func (c *thirdCache) GetFcmTokenVendor(ctx context.Context, account string, platformID int) (string, error) {
+	val, err := c.rdb.Get(ctx, c.getFcmTokenVendorKey(account, platformID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return val, errs.Wrap(err)
 }
 
 func (c *thirdCache) IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
@@ -88,3 +111,58 @@ func (c *thirdCache) GetGetuiTaskID(ctx context.Context) (string, error) {
 	}
 	return val, nil
 }
+
+func (c *thirdCache) getWebPushSubscriptionKey(userID string) string {
+	return cachekey.GetWebPushSubscriptionKey(userID)
+}
+
+func (c *thirdCache) SetWebPushSubscription(ctx context.Context, userID string, subscription *model.WebPushSubscription) error {
+	data, err := json.Marshal(subscription)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	key := c.getWebPushSubscriptionKey(userID)
+	pipe := c.rdb.TxPipeline()
+	pipe.HSet(ctx, key, subscription.Endpoint, data)
+	pipe.Expire(ctx, key, cachekey.WebPushSubscriptionExpire)
+	count := pipe.HLen(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errs.Wrap(err)
+	}
+	if count.Val() <= cache.MaxWebPushSubscriptions {
+		return nil
+	}
+	subscriptions, err := c.GetWebPushSubscriptions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreateTime > subscriptions[j].CreateTime
+	})
+	for _, s := range subscriptions[min(len(subscriptions), cache.MaxWebPushSubscriptions):] {
+		if err := c.DelWebPushSubscription(ctx, userID, s.Endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *thirdCache) GetWebPushSubscriptions(ctx context.Context, userID string) ([]*model.WebPushSubscription, error) {
+	values, err := c.rdb.HGetAll(ctx, c.getWebPushSubscriptionKey(userID)).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	subscriptions := make([]*model.WebPushSubscription, 0, len(values))
+	for _, value := range values {
+		var subscription model.WebPushSubscription
+		if err := json.Unmarshal([]byte(value), &subscription); err != nil {
+			return nil, errs.WrapMsg(err, "unmarshal web push subscription failed", "userID", userID)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	return subscriptions, nil
+}
+
+func (c *thirdCache) DelWebPushSubscription(ctx context.Context, userID string, endpoint string) error {
+	return errs.Wrap(c.rdb.HDel(ctx, c.getWebPushSubscriptionKey(userID), endpoint).Err())
+}
@@ -2,12 +2,21 @@ package cache
 
 import (
 	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
 )
 
+// MaxWebPushSubscriptions is the most browsers a user is pushed to, registering more drops the oldest.
+const MaxWebPushSubscriptions = 10
+
 type ThirdCache interface {
 	SetFcmToken(ctx context.Context, account string, platformID int, fcmToken string, expireTime int64) (err error)
 	GetFcmToken(ctx context.Context, account string, platformID int) (string, error)
 	DelFcmToken(ctx context.Context, account string, platformID int) error
+	// SetFcmTokenVendor tags the token of the platform with the vendor push service that issued it, an empty vendor removes the tag.
+	SetFcmTokenVendor(ctx context.Context, account string, platformID int, vendor string, expireTime int64) error
+	// GetFcmTokenVendor returns the vendor the token of the platform is tagged with, empty if it is not tagged.
+	GetFcmTokenVendor(ctx context.Context, account string, platformID int) (string, error)
 	IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error)
 	SetUserBadgeUnreadCountSum(ctx context.Context, userID string, value int) error
 	GetUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error)
@@ -15,4 +24,8 @@ type ThirdCache interface {
 	GetGetuiToken(ctx context.Context) (string, error)
 	SetGetuiTaskID(ctx context.Context, taskID string, expireTime int64) error
 	GetGetuiTaskID(ctx context.Context) (string, error)
+	// SetWebPushSubscription adds the subscription, or replaces the one with the same endpoint.
+	SetWebPushSubscription(ctx context.Context, userID string, subscription *model.WebPushSubscription) error
+	GetWebPushSubscriptions(ctx context.Context, userID string) ([]*model.WebPushSubscription, error)
+	DelWebPushSubscription(ctx context.Context, userID string, endpoint string) error
 }
@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type ApplicationDatabase interface {
+	AddApplication(ctx context.Context, application *model.Application) error
+	UpdateApplication(ctx context.Context, id string, data map[string]any) error
+	DeleteApplications(ctx context.Context, ids []string) error
+	TakeApplication(ctx context.Context, id string) (*model.Application, error)
+	PageApplications(ctx context.Context, platform string, pagination pagination.Pagination) (int64, []*model.Application, error)
+	GetPlatformApplications(ctx context.Context, platform string) ([]*model.Application, error)
+}
+
+func NewApplicationDatabase(application database.Application) ApplicationDatabase {
+	return &applicationDatabase{application: application}
+}
+
+type applicationDatabase struct {
+	application database.Application
+}
+
+func (a *applicationDatabase) AddApplication(ctx context.Context, application *model.Application) error {
+	return a.application.Create(ctx, application)
+}
+
+func (a *applicationDatabase) UpdateApplication(ctx context.Context, id string, data map[string]any) error {
+	return a.application.Update(ctx, id, data)
+}
+
+func (a *applicationDatabase) DeleteApplications(ctx context.Context, ids []string) error {
+	return a.application.Delete(ctx, ids)
+}
+
+func (a *applicationDatabase) TakeApplication(ctx context.Context, id string) (*model.Application, error) {
+	return a.application.Take(ctx, id)
+}
+
+func (a *applicationDatabase) PageApplications(ctx context.Context, platform string, pagination pagination.Pagination) (int64, []*model.Application, error) {
+	return a.application.FindPage(ctx, platform, pagination)
+}
+
+func (a *applicationDatabase) GetPlatformApplications(ctx context.Context, platform string) ([]*model.Application, error) {
+	return a.application.FindByPlatform(ctx, platform)
+}
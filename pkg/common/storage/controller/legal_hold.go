@@ -0,0 +1,148 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/db/tx"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+// LegalHoldDatabase is the registry of legal holds consulted by every path that deletes msgs or files.
+type LegalHoldDatabase interface {
+	// PlaceHold creates the hold and records it in the audit trail, a hold is never active without its log.
+	PlaceHold(ctx context.Context, hold *model.LegalHold) error
+	// ReleaseHold releases an active hold and records it in the audit trail.
+	ReleaseHold(ctx context.Context, holdID string, userID string, reason string) error
+	PageHolds(ctx context.Context, includeReleased bool, pagination pagination.Pagination) (int64, []*model.LegalHold, error)
+	PageHoldLogs(ctx context.Context, holdID string, pagination pagination.Pagination) (int64, []*model.LegalHoldLog, error)
+	// GetHeldTargets returns the user IDs and conversation IDs under an active hold.
+	GetHeldTargets(ctx context.Context) (userIDs []string, conversationIDs []string, err error)
+	// GetHeldScanSeq returns the seq the msgs of a held conversation were read up to for the objects they reference.
+	GetHeldScanSeq(ctx context.Context, conversationID string) (int64, error)
+	// AddHeldObjects records the objects referenced by the msgs of a held conversation up to seq.
+	AddHeldObjects(ctx context.Context, conversationID string, names []string, seq int64) error
+	// KeepHeldObjects forgets the objects of the conversations no longer held.
+	KeepHeldObjects(ctx context.Context, conversationIDs []string) error
+}
+
+func NewLegalHoldDatabase(legalHold database.LegalHold, tx tx.Tx) LegalHoldDatabase {
+	return &legalHoldDatabase{legalHold: legalHold, tx: tx}
+}
+
+type legalHoldDatabase struct {
+	legalHold database.LegalHold
+	tx        tx.Tx
+}
+
+// PlaceHold writes the log before the hold, so that without transaction support a failure leaves at most a log of a hold that was never placed.
+func (l *legalHoldDatabase) PlaceHold(ctx context.Context, hold *model.LegalHold) error {
+	return l.tx.Transaction(ctx, func(ctx context.Context) error {
+		err := l.legalHold.CreateLog(ctx, &model.LegalHoldLog{
+			HoldID:         hold.HoldID,
+			Action:         model.LegalHoldActionPlace,
+			Type:           hold.Type,
+			Target:         hold.Target,
+			Reason:         hold.Reason,
+			OperatorUserID: hold.PlaceUserID,
+			Time:           hold.PlaceTime,
+		})
+		if err != nil {
+			return err
+		}
+		return l.legalHold.Create(ctx, hold)
+	})
+}
+
+func (l *legalHoldDatabase) ReleaseHold(ctx context.Context, holdID string, userID string, reason string) error {
+	hold, err := l.legalHold.Take(ctx, holdID)
+	if err != nil {
+		return err
+	}
+	if hold.Released {
+		return errs.ErrArgs.WrapMsg("legal hold already released", "holdID", holdID)
+	}
+	now := time.Now()
+	return l.tx.Transaction(ctx, func(ctx context.Context) error {
+		err := l.legalHold.CreateLog(ctx, &model.LegalHoldLog{
+			HoldID:         hold.HoldID,
+			Action:         model.LegalHoldActionRelease,
+			Type:           hold.Type,
+			Target:         hold.Target,
+			Reason:         reason,
+			OperatorUserID: userID,
+			Time:           now,
+		})
+		if err != nil {
+			return err
+		}
+		ok, err := l.legalHold.Release(ctx, holdID, userID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errs.ErrArgs.WrapMsg("legal hold already released", "holdID", holdID)
+		}
+		return nil
+	})
+}
+
+func (l *legalHoldDatabase) PageHolds(ctx context.Context, includeReleased bool, pagination pagination.Pagination) (int64, []*model.LegalHold, error) {
+	return l.legalHold.FindPage(ctx, includeReleased, pagination)
+}
+
+func (l *legalHoldDatabase) PageHoldLogs(ctx context.Context, holdID string, pagination pagination.Pagination) (int64, []*model.LegalHoldLog, error) {
+	return l.legalHold.FindLogPage(ctx, holdID, pagination)
+}
+
+func (l *legalHoldDatabase) GetHeldTargets(ctx context.Context) ([]string, []string, error) {
+	holds, err := l.legalHold.FindActive(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var userIDs, conversationIDs []string
+	for _, hold := range holds {
+		switch hold.Type {
+		case msgext.LegalHoldTypeUser:
+			userIDs = append(userIDs, hold.Target)
+		case msgext.LegalHoldTypeConversation:
+			conversationIDs = append(conversationIDs, hold.Target)
+		}
+	}
+	return datautil.Distinct(userIDs), datautil.Distinct(conversationIDs), nil
+}
+
+func (l *legalHoldDatabase) GetHeldScanSeq(ctx context.Context, conversationID string) (int64, error) {
+	return l.legalHold.TakeScanSeq(ctx, conversationID)
+}
+
+// AddHeldObjects records the objects before the seq, so that a failure in between reads the msgs again.
+func (l *legalHoldDatabase) AddHeldObjects(ctx context.Context, conversationID string, names []string, seq int64) error {
+	if err := l.legalHold.AddObjects(ctx, conversationID, names); err != nil {
+		return err
+	}
+	return l.legalHold.SetScanSeq(ctx, conversationID, seq)
+}
+
+func (l *legalHoldDatabase) KeepHeldObjects(ctx context.Context, conversationIDs []string) error {
+	return l.legalHold.DeleteObjectsExcept(ctx, conversationIDs)
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type fakeTx struct{}
+
+func (fakeTx) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeLegalHold struct {
+	database.LegalHold
+	holds  map[string]*model.LegalHold
+	logs   []*model.LegalHoldLog
+	logErr error
+}
+
+func (f *fakeLegalHold) Create(_ context.Context, hold *model.LegalHold) error {
+	f.holds[hold.HoldID] = hold
+	return nil
+}
+
+func (f *fakeLegalHold) Take(_ context.Context, holdID string) (*model.LegalHold, error) {
+	hold, ok := f.holds[holdID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return hold, nil
+}
+
+func (f *fakeLegalHold) Release(_ context.Context, holdID string, userID string, releaseTime time.Time) (bool, error) {
+	hold, ok := f.holds[holdID]
+	if !ok || hold.Released {
+		return false, nil
+	}
+	hold.Released, hold.ReleaseUserID, hold.ReleaseTime = true, userID, releaseTime
+	return true, nil
+}
+
+func (f *fakeLegalHold) FindPage(context.Context, bool, pagination.Pagination) (int64, []*model.LegalHold, error) {
+	return 0, nil, nil
+}
+
+func (f *fakeLegalHold) FindActive(context.Context) ([]*model.LegalHold, error) {
+	var holds []*model.LegalHold
+	for _, hold := range f.holds {
+		if !hold.Released {
+			holds = append(holds, hold)
+		}
+	}
+	return holds, nil
+}
+
+func (f *fakeLegalHold) CreateLog(_ context.Context, log *model.LegalHoldLog) error {
+	if f.logErr != nil {
+		return f.logErr
+	}
+	f.logs = append(f.logs, log)
+	return nil
+}
+
+func (f *fakeLegalHold) FindLogPage(context.Context, string, pagination.Pagination) (int64, []*model.LegalHoldLog, error) {
+	return 0, nil, nil
+}
+
+func TestLegalHoldLogged(t *testing.T) {
+	ctx := context.Background()
+	db := &fakeLegalHold{holds: make(map[string]*model.LegalHold), logErr: errors.New("log failed")}
+	l := NewLegalHoldDatabase(db, fakeTx{})
+	hold := &model.LegalHold{HoldID: "h1", Type: "user", Target: "u1", PlaceUserID: "admin", PlaceTime: time.Now()}
+	if err := l.PlaceHold(ctx, hold); err == nil {
+		t.Fatal("place must fail when the log fails")
+	}
+	if len(db.holds) != 0 {
+		t.Fatal("hold placed without its log")
+	}
+
+	db.logErr = nil
+	if err := l.PlaceHold(ctx, hold); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.holds) != 1 || len(db.logs) != 1 || db.logs[0].Action != model.LegalHoldActionPlace {
+		t.Fatalf("holds %d logs %+v", len(db.holds), db.logs)
+	}
+
+	db.logErr = errors.New("log failed")
+	if err := l.ReleaseHold(ctx, "h1", "admin", "closed"); err == nil {
+		t.Fatal("release must fail when the log fails")
+	}
+	if db.holds["h1"].Released {
+		t.Fatal("hold released without its log")
+	}
+
+	db.logErr = nil
+	if err := l.ReleaseHold(ctx, "h1", "admin", "closed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.ReleaseHold(ctx, "h1", "admin", "closed"); err == nil {
+		t.Fatal("released twice")
+	}
+	if len(db.logs) != 2 || db.logs[1].Action != model.LegalHoldActionRelease {
+		t.Fatalf("logs %+v", db.logs)
+	}
+}
@@ -35,6 +35,7 @@ import (
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/protocol/constant"
 	pbmsg "github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/sdkws"
@@ -52,6 +53,15 @@ const (
 type CommonMsgDatabase interface {
 	// RevokeMsg revokes a message in a conversation.
 	RevokeMsg(ctx context.Context, conversationID string, seq int64, revoke *model.RevokeModel) error
+	// ModifyMsg replaces the content of a stored message, keeping at most maxHistory previous versions (0 means unlimited).
+	ModifyMsg(ctx context.Context, conversationID string, seq int64, content string, modify *model.ModifyModel, maxHistory int) error
+	// AddMsgReaction adds an emoji reaction to a stored message, adding the same reaction twice is a no-op.
+	// It fails once the message has maxPerMsg reactions or the user left maxPerUser of them (0 means unlimited).
+	AddMsgReaction(ctx context.Context, conversationID string, seq int64, reaction *model.ReactionModel, maxPerMsg int, maxPerUser int) error
+	// DeleteMsgReaction removes the emoji reaction userID left on a stored message.
+	DeleteMsgReaction(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error
+	// GetMsgInfoBySeq retrieves the stored message with its revoke and modify records.
+	GetMsgInfoBySeq(ctx context.Context, conversationID string, seq int64) (*model.MsgInfoModel, error)
 	// MarkSingleChatMsgsAsRead marks messages as read for a single chat by sequence numbers.
 	MarkSingleChatMsgsAsRead(ctx context.Context, userID string, conversationID string, seqs []int64) error
 	// GetMsgBySeqsRange retrieves messages from MongoDB by a range of sequence numbers.
@@ -92,13 +102,19 @@ type CommonMsgDatabase interface {
 	RangeGroupSendCount(ctx context.Context, start time.Time, end time.Time, ase bool, pageNumber int32, showNumber int32) (msgCount int64, userCount int64, groups []*model.GroupCount, dateCount map[string]int64, err error)
 
 	GetRandBeforeMsg(ctx context.Context, ts int64, limit int) ([]*model.MsgDocModel, error)
+	GetRandBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope, limit int) ([]*model.MsgDocModel, error)
+	CountBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope) (int64, error)
 
 	SetUserConversationsMaxSeq(ctx context.Context, conversationID string, userID string, seq int64) error
 	SetUserConversationsMinSeq(ctx context.Context, conversationID string, userID string, seq int64) error
+	// GetUserConversationsMinSeqs returns the min seq of the user in each conversation, 0 when it is not set.
+	GetUserConversationsMinSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error)
 
 	DeleteDoc(ctx context.Context, docID string) error
 
 	GetLastMessageSeqByTime(ctx context.Context, conversationID string, time int64) (int64, error)
+	// FindSeqByClientMsgID returns the seq of the msg sendID sent as clientMsgID no earlier than sendTime, 0 if it is not stored.
+	FindSeqByClientMsgID(ctx context.Context, conversationID string, sendID string, clientMsgID string, sendTime int64) (int64, error)
 
 	GetLastMessage(ctx context.Context, conversationIDS []string, userID string) (map[string]*sdkws.MsgData, error)
 }
@@ -239,6 +255,81 @@ func (db *commonMsgDatabase) RevokeMsg(ctx context.Context, conversationID strin
 	return db.msgCache.DelMessageBySeqs(ctx, conversationID, []int64{seq})
 }
 
+func (db *commonMsgDatabase) ModifyMsg(ctx context.Context, conversationID string, seq int64, content string, modify *model.ModifyModel, maxHistory int) error {
+	msgInfo, err := db.GetMsgInfoBySeq(ctx, conversationID, seq)
+	if err != nil {
+		return err
+	}
+	modify.Content = msgInfo.Msg.Content
+	res, err := db.msgDocDatabase.ModifyMsg(ctx, db.msgTable.GetDocID(conversationID, seq), db.msgTable.GetMsgIndex(seq), content, modify, maxHistory)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errs.ErrArgs.WrapMsg("msg has been modified concurrently", "conversationID", conversationID, "seq", seq)
+	}
+	return db.msgCache.DelMessageBySeqs(ctx, conversationID, []int64{seq})
+}
+
+func (db *commonMsgDatabase) AddMsgReaction(ctx context.Context, conversationID string, seq int64, reaction *model.ReactionModel, maxPerMsg int, maxPerUser int) error {
+	docID := db.msgTable.GetDocID(conversationID, seq)
+	res, err := db.msgDocDatabase.AddReaction(ctx, docID, db.msgTable.GetMsgIndex(seq), reaction, maxPerMsg, maxPerUser)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		// either the reaction is already there or a limit is reached, the message tells which
+		msgs, err := db.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, "", docID, []int64{seq})
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+		return checkReactionLimit(msgs[0].Reactions, reaction, maxPerMsg, maxPerUser)
+	}
+	return db.msgCache.DelMessageBySeqs(ctx, conversationID, []int64{seq})
+}
+
+// checkReactionLimit returns the limit reaction cannot be added beyond, nil if it is already among the reactions.
+func checkReactionLimit(reactions []*model.ReactionModel, reaction *model.ReactionModel, maxPerMsg int, maxPerUser int) error {
+	var userCount int
+	for _, r := range reactions {
+		if r.UserID != reaction.UserID {
+			continue
+		}
+		if r.Emoji == reaction.Emoji {
+			return nil
+		}
+		userCount++
+	}
+	if maxPerUser > 0 && userCount >= maxPerUser {
+		return errs.ErrArgs.WrapMsg("too many reactions of the user on the msg", "userID", reaction.UserID, "maxPerUser", maxPerUser)
+	}
+	if maxPerMsg > 0 && len(reactions) >= maxPerMsg {
+		return errs.ErrArgs.WrapMsg("too many reactions on the msg", "maxPerMsg", maxPerMsg)
+	}
+	return nil
+}
+
+func (db *commonMsgDatabase) DeleteMsgReaction(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error {
+	if _, err := db.msgDocDatabase.DeleteReaction(ctx, db.msgTable.GetDocID(conversationID, seq), db.msgTable.GetMsgIndex(seq), userID, emoji); err != nil {
+		return err
+	}
+	return db.msgCache.DelMessageBySeqs(ctx, conversationID, []int64{seq})
+}
+
+func (db *commonMsgDatabase) GetMsgInfoBySeq(ctx context.Context, conversationID string, seq int64) (*model.MsgInfoModel, error) {
+	msgs, err := db.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, "", db.msgTable.GetDocID(conversationID, seq), []int64{seq})
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 || msgs[0].Msg == nil || msgs[0].Msg.SendTime == 0 {
+		return nil, errs.ErrRecordNotFound.WrapMsg("msg not found", "conversationID", conversationID, "seq", seq)
+	}
+	return msgs[0], nil
+}
+
 func (db *commonMsgDatabase) MarkSingleChatMsgsAsRead(ctx context.Context, userID string, conversationID string, totalSeqs []int64) error {
 	for docID, seqs := range db.msgTable.GetDocIDSeqsMap(conversationID, totalSeqs) {
 		var indexes []int64
@@ -589,6 +680,10 @@ func (db *commonMsgDatabase) SetHasReadSeq(ctx context.Context, userID string, c
 	return db.seqUser.SetUserReadSeq(ctx, conversationID, userID, hasReadSeq)
 }
 
+func (db *commonMsgDatabase) GetUserConversationsMinSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
+	return db.seqUser.GetUserMinSeqs(ctx, userID, conversationIDs)
+}
+
 func (db *commonMsgDatabase) GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
 	return db.seqUser.GetUserReadSeqs(ctx, userID, conversationIDs)
 }
@@ -693,6 +788,14 @@ func (db *commonMsgDatabase) GetRandBeforeMsg(ctx context.Context, ts int64, lim
 	return db.msgDocDatabase.GetRandBeforeMsg(ctx, ts, limit)
 }
 
+func (db *commonMsgDatabase) GetRandBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope, limit int) ([]*model.MsgDocModel, error) {
+	return db.msgDocDatabase.GetRandBeforeMsgByScope(ctx, ts, scope, limit)
+}
+
+func (db *commonMsgDatabase) CountBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope) (int64, error) {
+	return db.msgDocDatabase.CountBeforeMsgByScope(ctx, ts, scope)
+}
+
 func (db *commonMsgDatabase) SetMinSeq(ctx context.Context, conversationID string, seq int64) error {
 	dbSeq, err := db.seqConversation.GetMinSeq(ctx, conversationID)
 	if err != nil {
@@ -745,6 +848,10 @@ func (db *commonMsgDatabase) GetLastMessageSeqByTime(ctx context.Context, conver
 	return db.msgDocDatabase.GetLastMessageSeqByTime(ctx, conversationID, time)
 }
 
+func (db *commonMsgDatabase) FindSeqByClientMsgID(ctx context.Context, conversationID string, sendID string, clientMsgID string, sendTime int64) (int64, error) {
+	return db.msgDocDatabase.FindSeqByClientMsgID(ctx, conversationID, sendID, clientMsgID, sendTime)
+}
+
 func (db *commonMsgDatabase) handlerDeleteAndRevoked(ctx context.Context, userID string, msgs []*model.MsgInfoModel) {
 	for i := range msgs {
 		msg := msgs[i]
@@ -790,6 +897,47 @@ func (db *commonMsgDatabase) handlerDeleteAndRevoked(ctx context.Context, userID
 	}
 }
 
+// handlerAttachedInfo exposes the aggregated reactions and the thread summary of each message to userID through its attachedInfo.
+func (db *commonMsgDatabase) handlerAttachedInfo(ctx context.Context, userID string, msgs []*model.MsgInfoModel) {
+	for _, msg := range msgs {
+		if msg == nil || msg.Msg == nil || msg.Revoke != nil || (len(msg.Reactions) == 0 && msg.Thread == nil) {
+			continue
+		}
+		extra := make(map[string]any)
+		if len(msg.Reactions) > 0 {
+			extra[msgext.AttachedInfoReactionsKey] = convert.MsgReactionsDB2Pb(msg.Reactions, userID)
+		}
+		if msg.Thread != nil {
+			extra[msgext.AttachedInfoThreadSummaryKey] = &msgext.ThreadSummary{
+				ReplyCount:    msg.Thread.ReplyCount,
+				LastReplyTime: msg.Thread.LastReplyTime,
+				LastReplySeq:  msg.Thread.LastReplySeq,
+			}
+		}
+		attachedInfo := make(map[string]json.RawMessage)
+		if msg.Msg.AttachedInfo != "" {
+			if err := json.Unmarshal([]byte(msg.Msg.AttachedInfo), &attachedInfo); err != nil {
+				log.ZWarn(ctx, "handlerAttachedInfo json.Unmarshal attachedInfo", err, "seq", msg.Msg.Seq)
+				continue
+			}
+		}
+		for key, value := range extra {
+			data, err := json.Marshal(value)
+			if err != nil {
+				log.ZWarn(ctx, "handlerAttachedInfo json.Marshal", err, "seq", msg.Msg.Seq, "key", key)
+				continue
+			}
+			attachedInfo[key] = data
+		}
+		data, err := json.Marshal(attachedInfo)
+		if err != nil {
+			log.ZWarn(ctx, "handlerAttachedInfo json.Marshal attachedInfo", err, "seq", msg.Msg.Seq)
+			continue
+		}
+		msg.Msg.AttachedInfo = string(data)
+	}
+}
+
 func (db *commonMsgDatabase) handlerQuote(ctx context.Context, userID, conversationID string, msgs []*model.MsgInfoModel) {
 	temp := make(map[int64][]*model.MsgInfoModel)
 	for i := range msgs {
@@ -804,6 +952,7 @@ func (db *commonMsgDatabase) GetMessageBySeqs(ctx context.Context, conversationI
 	}
 	db.handlerDeleteAndRevoked(ctx, userID, msgs)
 	db.handlerQuote(ctx, userID, conversationID, msgs)
+	db.handlerAttachedInfo(ctx, userID, msgs)
 	seqMsgs := make(map[int64]*model.MsgInfoModel)
 	for i, msg := range msgs {
 		if msg.Msg == nil {
@@ -838,6 +987,7 @@ func (db *commonMsgDatabase) GetLastMessage(ctx context.Context, conversationIDs
 		tmp := []*model.MsgInfoModel{msg}
 		db.handlerDeleteAndRevoked(ctx, userID, tmp)
 		db.handlerQuote(ctx, userID, conversationID, tmp)
+		db.handlerAttachedInfo(ctx, userID, tmp)
 		res[conversationID] = convert.MsgDB2Pb(msg.Msg)
 	}
 	return res, nil
@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MsgExportDatabase reads conversations straight from mongo, one msg doc at a time, to export them.
+type MsgExportDatabase interface {
+	// GetSeqRange returns the min and max seq of a conversation.
+	GetSeqRange(ctx context.Context, conversationID string) (minSeq int64, maxSeq int64, err error)
+	// GetMsgDoc returns the doc at index of a conversation, nil if it does not exist.
+	GetMsgDoc(ctx context.Context, conversationID string, index int64) (*model.MsgDocModel, error)
+	// GetReadStates returns the seqs of the conversation held by each of its users.
+	GetReadStates(ctx context.Context, conversationID string) ([]*model.SeqUser, error)
+}
+
+func NewMsgExportDatabase(msg database.Msg, seqConversation database.SeqConversation, seqUser database.SeqUser) MsgExportDatabase {
+	return &msgExportDatabase{msg: msg, seqConversation: seqConversation, seqUser: seqUser}
+}
+
+type msgExportDatabase struct {
+	msg             database.Msg
+	seqConversation database.SeqConversation
+	seqUser         database.SeqUser
+}
+
+func (m *msgExportDatabase) GetSeqRange(ctx context.Context, conversationID string) (int64, int64, error) {
+	minSeq, err := m.seqConversation.GetMinSeq(ctx, conversationID)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxSeq, err := m.seqConversation.GetMaxSeq(ctx, conversationID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return minSeq, maxSeq, nil
+}
+
+func (m *msgExportDatabase) GetMsgDoc(ctx context.Context, conversationID string, index int64) (*model.MsgDocModel, error) {
+	doc, err := m.msg.FindOneByDocID(ctx, (&model.MsgDocModel{}).BuildDocIDByIndex(conversationID, index))
+	if err != nil {
+		if errs.Unwrap(err) == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (m *msgExportDatabase) GetReadStates(ctx context.Context, conversationID string) ([]*model.SeqUser, error) {
+	return m.seqUser.FindByConversation(ctx, conversationID)
+}
@@ -0,0 +1,177 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+)
+
+// importLockDuration is how long a batch holds the import lock of its conversation at most.
+const importLockDuration = time.Minute * 5
+
+// MsgImportDatabase writes history straight into the msg docs, no msg goes through the msg transfer,
+// so nothing is pushed and no webhook is called. The imported msgs are indexed for search before the checkpoint moves past them.
+type MsgImportDatabase interface {
+	// GetCheckpoint returns the checkpoint of the import of a conversation, an empty one if it has not started.
+	GetCheckpoint(ctx context.Context, importID string, conversationID string) (*model.MsgImportCheckpoint, error)
+	// ImportMsgs writes the source msgs starting at offset, in order, to seqs allocated from the conversation seq.
+	// Msgs before the checkpoint are skipped, so a batch can be sent again after any failure.
+	// The batches of a conversation are imported one at a time, whichever process sends them.
+	ImportMsgs(ctx context.Context, importID string, conversationID string, offset int64, msgs []*model.MsgInfoModel) (*model.MsgImportCheckpoint, error)
+	PageCheckpoints(ctx context.Context, importID string, pagination pagination.Pagination) (int64, []*model.MsgImportCheckpoint, error)
+}
+
+// NewMsgImportDatabase takes the lock of the conversations from locker, searchIndex is nil when search is disabled.
+func NewMsgImportDatabase(msgDocModel database.Msg, seqConversation cache.SeqConversationCache, checkpoint database.MsgImportCheckpoint,
+	locker database.Cache, searchIndex msgsearch.MsgSearchIndex) MsgImportDatabase {
+	return &msgImportDatabase{
+		msg:             &commonMsgDatabase{msgDocDatabase: msgDocModel, seqConversation: seqConversation},
+		seqConversation: seqConversation,
+		checkpoint:      checkpoint,
+		locker:          locker,
+		searchIndex:     searchIndex,
+	}
+}
+
+type msgImportDatabase struct {
+	msg             *commonMsgDatabase
+	seqConversation cache.SeqConversationCache
+	checkpoint      database.MsgImportCheckpoint
+	locker          database.Cache
+	searchIndex     msgsearch.MsgSearchIndex
+}
+
+func (m *msgImportDatabase) GetCheckpoint(ctx context.Context, importID string, conversationID string) (*model.MsgImportCheckpoint, error) {
+	checkpoint, err := m.checkpoint.Take(ctx, importID, conversationID)
+	if err == nil {
+		return checkpoint, nil
+	}
+	if !mgo.IsNotFound(err) {
+		return nil, err
+	}
+	now := time.Now()
+	return &model.MsgImportCheckpoint{ImportID: importID, ConversationID: conversationID, CreateTime: now, UpdateTime: now}, nil
+}
+
+func (m *msgImportDatabase) ImportMsgs(ctx context.Context, importID string, conversationID string, offset int64, msgs []*model.MsgInfoModel) (*model.MsgImportCheckpoint, error) {
+	lockKey := "MSG_IMPORT:" + conversationID
+	lockValue, err := m.locker.Lock(ctx, lockKey, importLockDuration)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := m.locker.Unlock(ctx, lockKey, lockValue); err != nil {
+			log.ZWarn(ctx, "unlock msg import failed", err, "conversationID", conversationID)
+		}
+	}()
+	checkpoint, err := m.GetCheckpoint(ctx, importID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if offset > checkpoint.Offset {
+		return nil, errs.ErrArgs.WrapMsg("offset is ahead of the checkpoint", "offset", offset, "checkpoint", checkpoint.Offset)
+	}
+	if skip := checkpoint.Offset - offset; skip > 0 {
+		if skip >= int64(len(msgs)) {
+			return checkpoint, nil
+		}
+		msgs = msgs[skip:]
+	}
+	if checkpoint.PendingCount > 0 && len(msgs) > 0 {
+		// the batch was interrupted after its seqs were reserved, write it again to the same seqs.
+		// When the batch is sent smaller the seqs left over stay reserved for the next msgs.
+		n := min(checkpoint.PendingCount, int64(len(msgs)))
+		if err := m.commit(ctx, checkpoint, checkpoint.PendingFirstSeq, msgs[:n]); err != nil {
+			return nil, err
+		}
+		msgs = msgs[n:]
+	}
+	if len(msgs) == 0 {
+		return checkpoint, nil
+	}
+	lastSeq, err := m.seqConversation.Malloc(ctx, conversationID, int64(len(msgs)))
+	if err != nil {
+		return nil, err
+	}
+	checkpoint.PendingFirstSeq = lastSeq + 1
+	checkpoint.PendingCount = int64(len(msgs))
+	checkpoint.UpdateTime = time.Now()
+	if err := m.checkpoint.Set(ctx, checkpoint); err != nil {
+		return nil, err
+	}
+	if err := m.commit(ctx, checkpoint, lastSeq+1, msgs); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// commit writes msgs to the reserved seqs starting at firstSeq, indexes them and moves the checkpoint past them.
+func (m *msgImportDatabase) commit(ctx context.Context, checkpoint *model.MsgImportCheckpoint, firstSeq int64, msgs []*model.MsgInfoModel) error {
+	fields := make([]any, 0, len(msgs))
+	for i, msg := range msgs {
+		msg.Msg.Seq = firstSeq + int64(i)
+		fields = append(fields, msg.Msg)
+	}
+	if err := m.msg.batchInsertBlock(ctx, checkpoint.ConversationID, fields, updateKeyMsg, firstSeq); err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if msg.Revoke == nil {
+			continue
+		}
+		if err := m.msg.batchInsertBlock(ctx, checkpoint.ConversationID, []any{msg.Revoke}, updateKeyRevoke, msg.Msg.Seq); err != nil {
+			return err
+		}
+	}
+	if m.searchIndex != nil {
+		searchable := make([]*sdkws.MsgData, 0, len(msgs))
+		for _, msg := range msgs {
+			if msg.Revoke == nil {
+				searchable = append(searchable, convert.MsgDB2Pb(msg.Msg))
+			}
+		}
+		// indexing is idempotent, the batch is indexed again if the checkpoint is not moved
+		if err := m.searchIndex.Index(ctx, msgsearch.NewDocs(checkpoint.ConversationID, searchable)); err != nil {
+			return err
+		}
+	}
+	n := int64(len(msgs))
+	checkpoint.Offset += n
+	checkpoint.LastSeq = firstSeq + n - 1
+	checkpoint.PendingFirstSeq += n
+	checkpoint.PendingCount -= n
+	if checkpoint.PendingCount <= 0 {
+		checkpoint.PendingFirstSeq = 0
+		checkpoint.PendingCount = 0
+	}
+	checkpoint.UpdateTime = time.Now()
+	return m.checkpoint.Set(ctx, checkpoint)
+}
+
+func (m *msgImportDatabase) PageCheckpoints(ctx context.Context, importID string, pagination pagination.Pagination) (int64, []*model.MsgImportCheckpoint, error) {
+	return m.checkpoint.FindPage(ctx, importID, pagination)
+}
@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/db/pagination"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeImportMsgDocs struct {
+	database.Msg
+	docs map[string]*model.MsgDocModel
+}
+
+func (f *fakeImportMsgDocs) UpdateMsg(_ context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error) {
+	doc, ok := f.docs[docID]
+	if !ok {
+		return &mongo.UpdateResult{}, nil
+	}
+	switch key {
+	case "msg":
+		doc.Msg[index].Msg = value.(*model.MsgDataModel)
+	case "revoke":
+		doc.Msg[index].Revoke = value.(*model.RevokeModel)
+	}
+	return &mongo.UpdateResult{MatchedCount: 1}, nil
+}
+
+func (f *fakeImportMsgDocs) Create(_ context.Context, doc *model.MsgDocModel) error {
+	if _, ok := f.docs[doc.DocID]; ok {
+		return mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}
+	}
+	f.docs[doc.DocID] = doc
+	return nil
+}
+
+// msgs returns the client msg IDs of the conversation by seq, from seq 1 to the last written.
+func (f *fakeImportMsgDocs) msgs(conversationID string) []string {
+	var (
+		table model.MsgDocModel
+		ids   []string
+	)
+	for seq := int64(1); ; seq++ {
+		doc, ok := f.docs[table.GetDocID(conversationID, seq)]
+		if !ok {
+			return ids
+		}
+		msg := doc.Msg[table.GetMsgIndex(seq)].Msg
+		if msg == nil {
+			return ids
+		}
+		ids = append(ids, msg.ClientMsgID)
+	}
+}
+
+type fakeSeqConversation struct {
+	cache.SeqConversationCache
+	maxSeq int64
+}
+
+func (f *fakeSeqConversation) Malloc(_ context.Context, _ string, size int64) (int64, error) {
+	seq := f.maxSeq
+	f.maxSeq += size
+	return seq, nil
+}
+
+type fakeImportCheckpoint struct {
+	checkpoints map[string]model.MsgImportCheckpoint
+}
+
+func (f *fakeImportCheckpoint) Take(_ context.Context, importID string, conversationID string) (*model.MsgImportCheckpoint, error) {
+	checkpoint, ok := f.checkpoints[importID+":"+conversationID]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &checkpoint, nil
+}
+
+func (f *fakeImportCheckpoint) Set(_ context.Context, checkpoint *model.MsgImportCheckpoint) error {
+	f.checkpoints[checkpoint.ImportID+":"+checkpoint.ConversationID] = *checkpoint
+	return nil
+}
+
+func (f *fakeImportCheckpoint) FindPage(context.Context, string, pagination.Pagination) (int64, []*model.MsgImportCheckpoint, error) {
+	return 0, nil, nil
+}
+
+type fakeImportLocker struct {
+	database.Cache
+	locked map[string]bool
+}
+
+func (f *fakeImportLocker) Lock(_ context.Context, key string, _ time.Duration) (string, error) {
+	if f.locked[key] {
+		return "", errors.New("locked")
+	}
+	f.locked[key] = true
+	return "value", nil
+}
+
+func (f *fakeImportLocker) Unlock(_ context.Context, key string, _ string) error {
+	delete(f.locked, key)
+	return nil
+}
+
+type fakeSearchIndex struct {
+	msgsearch.MsgSearchIndex
+	seqs []int64
+	err  error
+}
+
+func (f *fakeSearchIndex) Index(_ context.Context, docs []*msgsearch.Doc) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, doc := range docs {
+		f.seqs = append(f.seqs, doc.Seq)
+	}
+	return nil
+}
+
+type importTest struct {
+	docs    *fakeImportMsgDocs
+	seq     *fakeSeqConversation
+	locker  *fakeImportLocker
+	index   *fakeSearchIndex
+	db      MsgImportDatabase
+	sources []*model.MsgInfoModel
+}
+
+func newImportTest(n int) *importTest {
+	it := &importTest{
+		docs:   &fakeImportMsgDocs{docs: make(map[string]*model.MsgDocModel)},
+		seq:    &fakeSeqConversation{},
+		locker: &fakeImportLocker{locked: make(map[string]bool)},
+		index:  &fakeSearchIndex{},
+	}
+	it.db = NewMsgImportDatabase(it.docs, it.seq, &fakeImportCheckpoint{checkpoints: make(map[string]model.MsgImportCheckpoint)}, it.locker, it.index)
+	for i := 1; i <= n; i++ {
+		it.sources = append(it.sources, &model.MsgInfoModel{Msg: &model.MsgDataModel{
+			ClientMsgID: fmt.Sprintf("c%d", i),
+			SendID:      "u1",
+			RecvID:      "u2",
+			SessionType: constant.SingleChatType,
+			ContentType: constant.Text,
+			Content:     fmt.Sprintf(`{"content":"msg %d"}`, i),
+			SendTime:    int64(i),
+		}})
+	}
+	return it
+}
+
+// batch returns a copy of the source msgs from offset, as read again from the input.
+func (it *importTest) batch(offset int, n int) []*model.MsgInfoModel {
+	msgs := make([]*model.MsgInfoModel, 0, n)
+	for _, src := range it.sources[offset:min(offset+n, len(it.sources))] {
+		msg := *src.Msg
+		msgs = append(msgs, &model.MsgInfoModel{Msg: &msg})
+	}
+	return msgs
+}
+
+func (it *importTest) check(t *testing.T, checkpoint *model.MsgImportCheckpoint, n int) {
+	t.Helper()
+	got := it.docs.msgs("si_u1_u2")
+	if len(got) != n {
+		t.Fatalf("imported %v, want the first %d msgs", got, n)
+	}
+	for i, id := range got {
+		if id != it.sources[i].Msg.ClientMsgID {
+			t.Fatalf("seq %d has %s, want %s", i+1, id, it.sources[i].Msg.ClientMsgID)
+		}
+	}
+	if checkpoint.Offset != int64(n) || checkpoint.LastSeq != int64(n) {
+		t.Fatalf("checkpoint at offset %d seq %d, want %d", checkpoint.Offset, checkpoint.LastSeq, n)
+	}
+	if len(it.index.seqs) != n {
+		t.Fatalf("indexed seqs %v, want %d", it.index.seqs, n)
+	}
+	if len(it.locker.locked) != 0 {
+		t.Fatal("conversation left locked")
+	}
+}
+
+func TestImportMsgs(t *testing.T) {
+	it := newImportTest(5)
+	ctx := context.Background()
+	var (
+		checkpoint *model.MsgImportCheckpoint
+		err        error
+	)
+	for offset := 0; offset < 5; offset += 2 {
+		checkpoint, err = it.db.ImportMsgs(ctx, "import", "si_u1_u2", int64(offset), it.batch(offset, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	it.check(t, checkpoint, 5)
+	// a batch sent again is skipped
+	checkpoint, err = it.db.ImportMsgs(ctx, "import", "si_u1_u2", 2, it.batch(2, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.seq.maxSeq != 5 {
+		t.Fatalf("seqs allocated again, max seq %d", it.seq.maxSeq)
+	}
+	it.check(t, checkpoint, 5)
+	if _, err := it.db.ImportMsgs(ctx, "import", "si_u1_u2", 6, it.batch(4, 1)); err == nil {
+		t.Fatal("offset ahead of the checkpoint accepted")
+	}
+}
+
+func TestImportMsgsResume(t *testing.T) {
+	it := newImportTest(6)
+	ctx := context.Background()
+	if _, err := it.db.ImportMsgs(ctx, "import", "si_u1_u2", 0, it.batch(0, 2)); err != nil {
+		t.Fatal(err)
+	}
+	// the batch is written but not indexed, the checkpoint keeps its seqs reserved
+	it.index.err = errors.New("index down")
+	if _, err := it.db.ImportMsgs(ctx, "import", "si_u1_u2", 2, it.batch(2, 3)); err == nil {
+		t.Fatal("batch not indexed reported imported")
+	}
+	it.index.err = nil
+	// resumed with a smaller batch, the seqs left over stay reserved for the next msgs
+	checkpoint, err := it.db.ImportMsgs(ctx, "import", "si_u1_u2", 2, it.batch(2, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.PendingFirstSeq != 4 || checkpoint.PendingCount != 2 {
+		t.Fatalf("pending seqs %d+%d, want 4+2", checkpoint.PendingFirstSeq, checkpoint.PendingCount)
+	}
+	checkpoint, err = it.db.ImportMsgs(ctx, "import", "si_u1_u2", 3, it.batch(3, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.PendingCount != 0 {
+		t.Fatalf("seqs still pending %+v", checkpoint)
+	}
+	if it.seq.maxSeq != 6 {
+		t.Fatalf("max seq %d, want no seq left empty", it.seq.maxSeq)
+	}
+	it.check(t, checkpoint, 6)
+}
+
+func TestImportMsgsLocked(t *testing.T) {
+	it := newImportTest(1)
+	it.locker.locked["MSG_IMPORT:si_u1_u2"] = true
+	if _, err := it.db.ImportMsgs(context.Background(), "import", "si_u1_u2", 0, it.batch(0, 1)); err == nil {
+		t.Fatal("imported while another import holds the conversation")
+	}
+	if len(it.docs.docs) != 0 || it.seq.maxSeq != 0 {
+		t.Fatal("locked import wrote msgs")
+	}
+}
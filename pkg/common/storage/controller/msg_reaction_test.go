@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// fakeReactionMsgDocs keeps the reactions of one message and applies the limits as the update filter does.
+type fakeReactionMsgDocs struct {
+	database.Msg
+	reactions []*model.ReactionModel
+}
+
+func (f *fakeReactionMsgDocs) AddReaction(_ context.Context, _ string, _ int64, reaction *model.ReactionModel, maxPerMsg int, maxPerUser int) (*mongo.UpdateResult, error) {
+	var userCount int
+	for _, r := range f.reactions {
+		if r.UserID == reaction.UserID {
+			if r.Emoji == reaction.Emoji {
+				return &mongo.UpdateResult{}, nil
+			}
+			userCount++
+		}
+	}
+	if (maxPerMsg > 0 && len(f.reactions) >= maxPerMsg) || (maxPerUser > 0 && userCount >= maxPerUser) {
+		return &mongo.UpdateResult{}, nil
+	}
+	f.reactions = append(f.reactions, reaction)
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (f *fakeReactionMsgDocs) GetMsgBySeqIndexIn1Doc(_ context.Context, _ string, _ string, seqs []int64) ([]*model.MsgInfoModel, error) {
+	return []*model.MsgInfoModel{{Msg: &model.MsgDataModel{Seq: seqs[0]}, Reactions: f.reactions}}, nil
+}
+
+func TestAddMsgReactionLimit(t *testing.T) {
+	docs := &fakeReactionMsgDocs{}
+	db := &commonMsgDatabase{msgDocDatabase: docs, msgCache: fakeThreadMsgCache{}}
+	ctx := context.Background()
+	add := func(userID, emoji string) error {
+		return db.AddMsgReaction(ctx, "si_a_b", 1, &model.ReactionModel{UserID: userID, Emoji: emoji}, 3, 2)
+	}
+	for _, r := range [][2]string{{"a", "1"}, {"a", "2"}, {"b", "1"}} {
+		if err := add(r[0], r[1]); err != nil {
+			t.Fatalf("add %v: %v", r, err)
+		}
+	}
+	// adding a reaction again is a no-op, even at the limits
+	if err := add("a", "1"); err != nil {
+		t.Fatalf("add again: %v", err)
+	}
+	if err := add("a", "3"); !errs.ErrArgs.Is(err) {
+		t.Fatalf("add beyond maxPerUser: %v", err)
+	}
+	if err := add("c", "1"); !errs.ErrArgs.Is(err) {
+		t.Fatalf("add beyond maxPerMsg: %v", err)
+	}
+	if len(docs.reactions) != 3 {
+		t.Fatalf("%d reactions kept, want 3", len(docs.reactions))
+	}
+}
+
+func TestCheckReactionLimit(t *testing.T) {
+	reactions := []*model.ReactionModel{{UserID: "a", Emoji: "1"}, {UserID: "a", Emoji: "2"}, {UserID: "b", Emoji: "1"}}
+	cases := []struct {
+		name       string
+		reaction   *model.ReactionModel
+		maxPerMsg  int
+		maxPerUser int
+		limited    bool
+	}{
+		{name: "present", reaction: &model.ReactionModel{UserID: "a", Emoji: "1"}, maxPerMsg: 1, maxPerUser: 1},
+		{name: "per user", reaction: &model.ReactionModel{UserID: "a", Emoji: "3"}, maxPerUser: 2, limited: true},
+		{name: "per msg", reaction: &model.ReactionModel{UserID: "c", Emoji: "1"}, maxPerMsg: 3, limited: true},
+		{name: "unlimited", reaction: &model.ReactionModel{UserID: "c", Emoji: "1"}},
+		{name: "below", reaction: &model.ReactionModel{UserID: "b", Emoji: "2"}, maxPerMsg: 4, maxPerUser: 2},
+	}
+	for _, c := range cases {
+		err := checkReactionLimit(reactions, c.reaction, c.maxPerMsg, c.maxPerUser)
+		if limited := err != nil; limited != c.limited {
+			t.Errorf("%s: err %v, want limited %v", c.name, err, c.limited)
+		}
+	}
+}
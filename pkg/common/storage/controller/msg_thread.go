@@ -0,0 +1,161 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/log"
+	"github.com/openimsdk/tools/utils/datautil"
+)
+
+type MsgThreadDatabase interface {
+	// AddReplies records the thread replies among msgs and refreshes the summary kept on their root messages.
+	// Replies already recorded are skipped, by seq or by client msg id, so a batch may be delivered more than once.
+	// A reply is finished by setting its thread seq last, one recorded but not finished is finished again.
+	AddReplies(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error
+	GetThreads(ctx context.Context, conversationID string, parentServerMsgIDs []string) ([]*model.MsgThread, error)
+	GetReplies(ctx context.Context, conversationID string, parentServerMsgID string, pagination pagination.Pagination) (int64, []*model.MsgThreadReply, error)
+	SetHasReadSeq(ctx context.Context, userID string, conversationID string, parentServerMsgID string, seq int64) error
+	GetHasReadSeqs(ctx context.Context, userID string, conversationID string, parentServerMsgIDs []string) (map[string]int64, error)
+}
+
+func NewMsgThreadDatabase(thread database.MsgThread, msgDocModel database.Msg, msg cache.MsgCache) MsgThreadDatabase {
+	return &msgThreadDatabase{
+		thread:         thread,
+		msgDocDatabase: msgDocModel,
+		msgCache:       msg,
+	}
+}
+
+type msgThreadDatabase struct {
+	thread         database.MsgThread
+	msgDocDatabase database.Msg
+	msgTable       model.MsgDocModel
+	msgCache       cache.MsgCache
+}
+
+func (db *msgThreadDatabase) AddReplies(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	replies := make(map[int64]*msgext.ThreadParent)
+	for _, msg := range msgs {
+		if parent := msgext.GetThreadParent(msg.AttachedInfo); parent != nil && parent.ParentSeq < msg.Seq {
+			replies[msg.Seq] = parent
+		}
+	}
+	if len(replies) == 0 {
+		return nil
+	}
+	recorded, err := db.thread.FindRepliesBySeqs(ctx, conversationID, datautil.Keys(replies))
+	if err != nil {
+		return err
+	}
+	unfinished := make(map[int64]struct{})
+	for _, reply := range recorded {
+		if reply.ThreadSeq > 0 {
+			delete(replies, reply.Seq)
+		} else {
+			unfinished[reply.Seq] = struct{}{}
+		}
+	}
+	var rootSeqs []int64
+	for _, msg := range msgs {
+		parent, ok := replies[msg.Seq]
+		if !ok {
+			continue
+		}
+		if _, ok := unfinished[msg.Seq]; !ok {
+			if ok, err := db.checkParent(ctx, conversationID, parent); err != nil {
+				return err
+			} else if !ok {
+				log.ZWarn(ctx, "thread parent not found", nil, "conversationID", conversationID, "seq", msg.Seq, "parent", parent)
+				continue
+			}
+			reply := &model.MsgThreadReply{
+				ConversationID:    conversationID,
+				ParentServerMsgID: parent.ParentServerMsgID,
+				ClientMsgID:       msg.ClientMsgID,
+				Seq:               msg.Seq,
+				SendTime:          msg.SendTime,
+			}
+			// a reply resent with a new seq, or recorded by a batch delivered again concurrently, is finished by whoever recorded it
+			if created, err := db.thread.CreateReply(ctx, reply); err != nil {
+				return err
+			} else if !created {
+				continue
+			}
+		}
+		// the thread seq is counted from the recorded replies, so finishing a reply again gives the same one
+		threadSeq, err := db.thread.CountReplies(ctx, conversationID, parent.ParentServerMsgID, msg.Seq)
+		if err != nil {
+			return err
+		}
+		thread, err := db.thread.UpdateThread(ctx, conversationID, parent.ParentServerMsgID, parent.ParentSeq, threadSeq, msg.Seq, msg.SendTime)
+		if err != nil {
+			return err
+		}
+		summary := &model.ThreadModel{
+			ReplyCount:    thread.ReplyCount,
+			LastReplyTime: thread.LastReplyTime,
+			LastReplySeq:  thread.LastReplySeq,
+		}
+		docID := db.msgTable.GetDocID(conversationID, parent.ParentSeq)
+		if _, err := db.msgDocDatabase.UpdateMsg(ctx, docID, db.msgTable.GetMsgIndex(parent.ParentSeq), "thread", summary); err != nil {
+			return err
+		}
+		if err := db.thread.SetReplyThreadSeq(ctx, conversationID, msg.Seq, threadSeq); err != nil {
+			return err
+		}
+		rootSeqs = append(rootSeqs, parent.ParentSeq)
+	}
+	if len(rootSeqs) == 0 {
+		return nil
+	}
+	return db.msgCache.DelMessageBySeqs(ctx, conversationID, datautil.Distinct(rootSeqs))
+}
+
+// checkParent reports whether parent is an existing message of the conversation that is not a reply itself.
+func (db *msgThreadDatabase) checkParent(ctx context.Context, conversationID string, parent *msgext.ThreadParent) (bool, error) {
+	docID := db.msgTable.GetDocID(conversationID, parent.ParentSeq)
+	msgs, err := db.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, "", docID, []int64{parent.ParentSeq})
+	if err != nil {
+		return false, err
+	}
+	if len(msgs) == 0 || msgs[0].Msg == nil || msgs[0].Msg.ServerMsgID != parent.ParentServerMsgID {
+		return false, nil
+	}
+	return msgext.GetThreadParent(msgs[0].Msg.AttachedInfo) == nil, nil
+}
+
+func (db *msgThreadDatabase) GetThreads(ctx context.Context, conversationID string, parentServerMsgIDs []string) ([]*model.MsgThread, error) {
+	return db.thread.FindThreads(ctx, conversationID, parentServerMsgIDs)
+}
+
+func (db *msgThreadDatabase) GetReplies(ctx context.Context, conversationID string, parentServerMsgID string, pagination pagination.Pagination) (int64, []*model.MsgThreadReply, error) {
+	return db.thread.FindReplies(ctx, conversationID, parentServerMsgID, pagination)
+}
+
+func (db *msgThreadDatabase) SetHasReadSeq(ctx context.Context, userID string, conversationID string, parentServerMsgID string, seq int64) error {
+	return db.thread.SetHasReadSeq(ctx, userID, conversationID, parentServerMsgID, seq)
+}
+
+func (db *msgThreadDatabase) GetHasReadSeqs(ctx context.Context, userID string, conversationID string, parentServerMsgIDs []string) (map[string]int64, error) {
+	return db.thread.GetHasReadSeqs(ctx, userID, conversationID, parentServerMsgIDs)
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/protocol/sdkws"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeMsgThread struct {
+	database.MsgThread
+	threads map[string]*model.MsgThread
+	replies []*model.MsgThreadReply
+	// hideRecorded finds no recorded replies, as a batch delivered again concurrently would
+	hideRecorded bool
+	// failSetThreadSeq fails finishing the reply of this seq once
+	failSetThreadSeq int64
+}
+
+func (f *fakeMsgThread) UpdateThread(_ context.Context, conversationID string, parentServerMsgID string, parentSeq int64, replyCount int64, replySeq int64, sendTime int64) (*model.MsgThread, error) {
+	thread, ok := f.threads[parentServerMsgID]
+	if !ok {
+		thread = &model.MsgThread{ConversationID: conversationID, ParentServerMsgID: parentServerMsgID, ParentSeq: parentSeq}
+		f.threads[parentServerMsgID] = thread
+	}
+	thread.ReplyCount = max(thread.ReplyCount, replyCount)
+	thread.LastReplySeq = max(thread.LastReplySeq, replySeq)
+	thread.LastReplyTime = max(thread.LastReplyTime, sendTime)
+	res := *thread
+	return &res, nil
+}
+
+func (f *fakeMsgThread) CreateReply(_ context.Context, reply *model.MsgThreadReply) (bool, error) {
+	for _, r := range f.replies {
+		if r.ConversationID == reply.ConversationID && (r.Seq == reply.Seq ||
+			(r.ParentServerMsgID == reply.ParentServerMsgID && r.ClientMsgID == reply.ClientMsgID)) {
+			return false, nil
+		}
+	}
+	f.replies = append(f.replies, reply)
+	return true, nil
+}
+
+func (f *fakeMsgThread) SetReplyThreadSeq(_ context.Context, conversationID string, seq int64, threadSeq int64) error {
+	if seq == f.failSetThreadSeq {
+		f.failSetThreadSeq = 0
+		return errors.New("set thread seq failed")
+	}
+	for _, r := range f.replies {
+		if r.ConversationID == conversationID && r.Seq == seq {
+			r.ThreadSeq = threadSeq
+		}
+	}
+	return nil
+}
+
+func (f *fakeMsgThread) FindRepliesBySeqs(_ context.Context, conversationID string, seqs []int64) ([]*model.MsgThreadReply, error) {
+	if f.hideRecorded {
+		return nil, nil
+	}
+	var res []*model.MsgThreadReply
+	for _, r := range f.replies {
+		if r.ConversationID == conversationID && slices.Contains(seqs, r.Seq) {
+			res = append(res, r)
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeMsgThread) CountReplies(_ context.Context, conversationID string, parentServerMsgID string, seq int64) (int64, error) {
+	var n int64
+	for _, r := range f.replies {
+		if r.ConversationID == conversationID && r.ParentServerMsgID == parentServerMsgID && r.Seq <= seq {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeThreadMsgDocs struct {
+	database.Msg
+	roots   map[int64]*model.MsgDataModel
+	updates int
+}
+
+func (f *fakeThreadMsgDocs) GetMsgBySeqIndexIn1Doc(_ context.Context, _ string, _ string, seqs []int64) ([]*model.MsgInfoModel, error) {
+	var msgs []*model.MsgInfoModel
+	for _, seq := range seqs {
+		if msg, ok := f.roots[seq]; ok {
+			msgs = append(msgs, &model.MsgInfoModel{Msg: msg})
+		}
+	}
+	return msgs, nil
+}
+
+func (f *fakeThreadMsgDocs) UpdateMsg(context.Context, string, int64, string, any) (*mongo.UpdateResult, error) {
+	f.updates++
+	return &mongo.UpdateResult{}, nil
+}
+
+type fakeThreadMsgCache struct {
+	cache.MsgCache
+}
+
+func (fakeThreadMsgCache) DelMessageBySeqs(context.Context, string, []int64) error {
+	return nil
+}
+
+func threadReply(clientMsgID string, seq int64) *sdkws.MsgData {
+	return &sdkws.MsgData{
+		ClientMsgID:  clientMsgID,
+		Seq:          seq,
+		SendTime:     seq * 1000,
+		AttachedInfo: `{"thread":{"parentServerMsgID":"root","parentSeq":1}}`,
+	}
+}
+
+func TestAddRepliesIdempotent(t *testing.T) {
+	thread := &fakeMsgThread{threads: make(map[string]*model.MsgThread), hideRecorded: true}
+	docs := &fakeThreadMsgDocs{roots: map[int64]*model.MsgDataModel{1: {ServerMsgID: "root", Seq: 1}}}
+	db := NewMsgThreadDatabase(thread, docs, fakeThreadMsgCache{})
+	ctx := context.Background()
+	batch := []*sdkws.MsgData{threadReply("c2", 2), {ClientMsgID: "c3", Seq: 3}, threadReply("c4", 4)}
+	for i := 0; i < 2; i++ {
+		if err := db.AddReplies(ctx, "si_a_b", batch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the reply resent by the client with a new seq
+	if err := db.AddReplies(ctx, "si_a_b", []*sdkws.MsgData{threadReply("c2", 5)}); err != nil {
+		t.Fatal(err)
+	}
+	if n := thread.threads["root"].ReplyCount; n != 2 {
+		t.Fatalf("reply count %d, want 2", n)
+	}
+	if len(thread.replies) != 2 {
+		t.Fatalf("recorded %d replies, want 2", len(thread.replies))
+	}
+	for i, r := range thread.replies {
+		if r.ThreadSeq != int64(i+1) {
+			t.Errorf("reply %d has thread seq %d, want %d", r.Seq, r.ThreadSeq, i+1)
+		}
+	}
+	if docs.updates != 2 {
+		t.Fatalf("root summary updated %d times, want 2", docs.updates)
+	}
+}
+
+func TestAddRepliesResume(t *testing.T) {
+	thread := &fakeMsgThread{threads: make(map[string]*model.MsgThread), failSetThreadSeq: 2}
+	docs := &fakeThreadMsgDocs{roots: map[int64]*model.MsgDataModel{1: {ServerMsgID: "root", Seq: 1}}}
+	db := NewMsgThreadDatabase(thread, docs, fakeThreadMsgCache{})
+	ctx := context.Background()
+	batch := []*sdkws.MsgData{threadReply("c2", 2), threadReply("c3", 3)}
+	if err := db.AddReplies(ctx, "si_a_b", batch); err == nil {
+		t.Fatal("want the failed thread seq")
+	}
+	// the batch is delivered again and finishes the reply left without a thread seq
+	if err := db.AddReplies(ctx, "si_a_b", batch); err != nil {
+		t.Fatal(err)
+	}
+	if n := thread.threads["root"].ReplyCount; n != 2 {
+		t.Fatalf("reply count %d, want 2", n)
+	}
+	for i, r := range thread.replies {
+		if r.ThreadSeq != int64(i+1) {
+			t.Errorf("reply %d has thread seq %d, want %d", r.Seq, r.ThreadSeq, i+1)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type OfflinePushRetryDatabase interface {
+	AddRetry(ctx context.Context, retry *model.OfflinePushRetry) error
+	ClaimRetry(ctx context.Context, now time.Time, claimExpire time.Time) (*model.OfflinePushRetry, error)
+	UpdateRetry(ctx context.Context, id string, data map[string]any) error
+	DeleteRetries(ctx context.Context, ids []string) error
+	PageDeadLetters(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*model.OfflinePushRetry, error)
+	RequeueDeadLetters(ctx context.Context, ids []string, now time.Time) (int64, error)
+}
+
+func NewOfflinePushRetryDatabase(retry database.OfflinePushRetry) OfflinePushRetryDatabase {
+	return &offlinePushRetryDatabase{retry: retry}
+}
+
+type offlinePushRetryDatabase struct {
+	retry database.OfflinePushRetry
+}
+
+func (o *offlinePushRetryDatabase) AddRetry(ctx context.Context, retry *model.OfflinePushRetry) error {
+	return o.retry.Create(ctx, retry)
+}
+
+func (o *offlinePushRetryDatabase) ClaimRetry(ctx context.Context, now time.Time, claimExpire time.Time) (*model.OfflinePushRetry, error) {
+	return o.retry.Claim(ctx, now, claimExpire)
+}
+
+func (o *offlinePushRetryDatabase) UpdateRetry(ctx context.Context, id string, data map[string]any) error {
+	return o.retry.Update(ctx, id, data)
+}
+
+func (o *offlinePushRetryDatabase) DeleteRetries(ctx context.Context, ids []string) error {
+	return o.retry.Delete(ctx, ids)
+}
+
+func (o *offlinePushRetryDatabase) PageDeadLetters(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*model.OfflinePushRetry, error) {
+	return o.retry.FindDead(ctx, userID, pagination)
+}
+
+func (o *offlinePushRetryDatabase) RequeueDeadLetters(ctx context.Context, ids []string, now time.Time) (int64, error) {
+	return o.retry.Requeue(ctx, ids, now)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type RetentionRuleDatabase interface {
+	SetRetentionRule(ctx context.Context, rule *model.RetentionRule) error
+	DeleteRetentionRule(ctx context.Context, scope string, target string) error
+	PageRetentionRules(ctx context.Context, pagination pagination.Pagination) (int64, []*model.RetentionRule, error)
+	GetAllRetentionRules(ctx context.Context) ([]*model.RetentionRule, error)
+}
+
+func NewRetentionRuleDatabase(retentionRule database.RetentionRule) RetentionRuleDatabase {
+	return &retentionRuleDatabase{retentionRule: retentionRule}
+}
+
+type retentionRuleDatabase struct {
+	retentionRule database.RetentionRule
+}
+
+func (r *retentionRuleDatabase) SetRetentionRule(ctx context.Context, rule *model.RetentionRule) error {
+	return r.retentionRule.Set(ctx, rule)
+}
+
+func (r *retentionRuleDatabase) DeleteRetentionRule(ctx context.Context, scope string, target string) error {
+	return r.retentionRule.Delete(ctx, scope, target)
+}
+
+func (r *retentionRuleDatabase) PageRetentionRules(ctx context.Context, pagination pagination.Pagination) (int64, []*model.RetentionRule, error) {
+	return r.retentionRule.FindPage(ctx, pagination)
+}
+
+func (r *retentionRuleDatabase) GetAllRetentionRules(ctx context.Context) ([]*model.RetentionRule, error) {
+	return r.retentionRule.FindAll(ctx)
+}
@@ -39,7 +39,7 @@ type S3Database interface {
 	SetObject(ctx context.Context, info *model.Object) error
 	StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error)
 	FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*s3.FormData, error)
-	FindExpirationObject(ctx context.Context, engine string, expiration time.Time, needDelType []string, count int64) ([]*model.Object, error)
+	FindExpirationObject(ctx context.Context, engine string, expiration time.Time, needDelType []string, excludeUserIDs []string, count int64) ([]*model.Object, error)
 	DeleteSpecifiedData(ctx context.Context, engine string, name []string) error
 	DelS3Key(ctx context.Context, engine string, keys ...string) error
 	GetKeyCount(ctx context.Context, engine string, key string) (int64, error)
@@ -119,8 +119,8 @@ func (s *s3Database) FormData(ctx context.Context, name string, size int64, cont
 	return s.s3.FormData(ctx, name, size, contentType, duration)
 }
 
-func (s *s3Database) FindExpirationObject(ctx context.Context, engine string, expiration time.Time, needDelType []string, count int64) ([]*model.Object, error) {
-	return s.db.FindExpirationObject(ctx, engine, expiration, needDelType, count)
+func (s *s3Database) FindExpirationObject(ctx context.Context, engine string, expiration time.Time, needDelType []string, excludeUserIDs []string, count int64) ([]*model.Object, error) {
+	return s.db.FindExpirationObject(ctx, engine, expiration, needDelType, excludeUserIDs, count)
 }
 
 func (s *s3Database) GetKeyCount(ctx context.Context, engine string, key string) (int64, error) {
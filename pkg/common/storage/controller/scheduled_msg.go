@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type ScheduledMsgDatabase interface {
+	CreateScheduledMsg(ctx context.Context, msg *model.ScheduledMsg) error
+	FindScheduledMsgs(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*model.ScheduledMsg, error)
+	CountScheduledMsgs(ctx context.Context, sendID string) (int64, error)
+	// CancelScheduledMsg reports whether the message was still pending and has been deleted.
+	CancelScheduledMsg(ctx context.Context, sendID string, scheduleID string) (bool, error)
+	DeleteScheduledMsg(ctx context.Context, scheduleID string) error
+	// ClaimDueScheduledMsg returns a due message no other dispatcher holds for longer than claimTimeout, or nil.
+	ClaimDueScheduledMsg(ctx context.Context, claimTimeout time.Duration) (*model.ScheduledMsg, error)
+}
+
+func NewScheduledMsgDatabase(scheduledMsg database.ScheduledMsg) ScheduledMsgDatabase {
+	return &scheduledMsgDatabase{scheduledMsg: scheduledMsg}
+}
+
+type scheduledMsgDatabase struct {
+	scheduledMsg database.ScheduledMsg
+}
+
+func (s *scheduledMsgDatabase) CreateScheduledMsg(ctx context.Context, msg *model.ScheduledMsg) error {
+	return s.scheduledMsg.Create(ctx, []*model.ScheduledMsg{msg})
+}
+
+func (s *scheduledMsgDatabase) FindScheduledMsgs(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*model.ScheduledMsg, error) {
+	return s.scheduledMsg.FindBySendID(ctx, sendID, pagination)
+}
+
+func (s *scheduledMsgDatabase) CountScheduledMsgs(ctx context.Context, sendID string) (int64, error) {
+	return s.scheduledMsg.CountBySendID(ctx, sendID)
+}
+
+func (s *scheduledMsgDatabase) CancelScheduledMsg(ctx context.Context, sendID string, scheduleID string) (bool, error) {
+	return s.scheduledMsg.DeletePending(ctx, sendID, scheduleID)
+}
+
+func (s *scheduledMsgDatabase) DeleteScheduledMsg(ctx context.Context, scheduleID string) error {
+	return s.scheduledMsg.Delete(ctx, scheduleID)
+}
+
+func (s *scheduledMsgDatabase) ClaimDueScheduledMsg(ctx context.Context, claimTimeout time.Duration) (*model.ScheduledMsg, error) {
+	now := time.Now()
+	return s.scheduledMsg.Claim(ctx, now, now.Add(-claimTimeout))
+}
@@ -27,7 +27,11 @@ import (
 
 type ThirdDatabase interface {
 	FcmUpdateToken(ctx context.Context, account string, platformID int, fcmToken string, expireTime int64) error
+	// VendorUpdateToken sets the token of the platform issued by the vendor push service.
+	VendorUpdateToken(ctx context.Context, account string, platformID int, vendor string, token string, expireTime int64) error
 	SetAppBadge(ctx context.Context, userID string, value int) error
+	SetWebPushSubscription(ctx context.Context, userID string, subscription *model.WebPushSubscription) error
+	DelWebPushSubscription(ctx context.Context, userID string, endpoint string) error
 	// about log for debug
 	UploadLogs(ctx context.Context, logs []*model.Log) error
 	DeleteLogs(ctx context.Context, logID []string, userID string) error
@@ -65,9 +69,28 @@ func NewThirdDatabase(cache cache.ThirdCache, logdb database.Log) ThirdDatabase
 }
 
 func (t *thirdDatabase) FcmUpdateToken(ctx context.Context, account string, platformID int, fcmToken string, expireTime int64) error {
-	return t.cache.SetFcmToken(ctx, account, platformID, fcmToken, expireTime)
+	if err := t.cache.SetFcmToken(ctx, account, platformID, fcmToken, expireTime); err != nil {
+		return err
+	}
+	// the device may have been pushed through a vendor before
+	return t.cache.SetFcmTokenVendor(ctx, account, platformID, "", expireTime)
+}
+
+func (t *thirdDatabase) VendorUpdateToken(ctx context.Context, account string, platformID int, vendor string, token string, expireTime int64) error {
+	if err := t.cache.SetFcmToken(ctx, account, platformID, token, expireTime); err != nil {
+		return err
+	}
+	return t.cache.SetFcmTokenVendor(ctx, account, platformID, vendor, expireTime)
 }
 
 func (t *thirdDatabase) SetAppBadge(ctx context.Context, userID string, value int) error {
 	return t.cache.SetUserBadgeUnreadCountSum(ctx, userID, value)
 }
+
+func (t *thirdDatabase) SetWebPushSubscription(ctx context.Context, userID string, subscription *model.WebPushSubscription) error {
+	return t.cache.SetWebPushSubscription(ctx, userID, subscription)
+}
+
+func (t *thirdDatabase) DelWebPushSubscription(ctx context.Context, userID string, endpoint string) error {
+	return t.cache.DelWebPushSubscription(ctx, userID, endpoint)
+}
@@ -0,0 +1,32 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type Application interface {
+	Create(ctx context.Context, application *model.Application) error
+	Update(ctx context.Context, id string, data map[string]any) error
+	Delete(ctx context.Context, ids []string) error
+	Take(ctx context.Context, id string) (*model.Application, error)
+	// FindPage returns the releases of the platform, of all platforms if it is empty, the latest created first.
+	FindPage(ctx context.Context, platform string, pagination pagination.Pagination) (int64, []*model.Application, error)
+	FindByPlatform(ctx context.Context, platform string) ([]*model.Application, error)
+}
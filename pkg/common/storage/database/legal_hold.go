@@ -0,0 +1,41 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type LegalHold interface {
+	Create(ctx context.Context, hold *model.LegalHold) error
+	Take(ctx context.Context, holdID string) (*model.LegalHold, error)
+	// Release marks an active hold released, reporting whether it was active.
+	Release(ctx context.Context, holdID string, userID string, releaseTime time.Time) (bool, error)
+	FindPage(ctx context.Context, includeReleased bool, pagination pagination.Pagination) (int64, []*model.LegalHold, error)
+	FindActive(ctx context.Context) ([]*model.LegalHold, error)
+	CreateLog(ctx context.Context, log *model.LegalHoldLog) error
+	FindLogPage(ctx context.Context, holdID string, pagination pagination.Pagination) (int64, []*model.LegalHoldLog, error)
+	// AddObjects records the objects referenced by the msgs of a held conversation, recording one twice is a no-op.
+	AddObjects(ctx context.Context, conversationID string, names []string) error
+	// TakeScanSeq returns the seq the msgs of the conversation were read up to, 0 if they were not read.
+	TakeScanSeq(ctx context.Context, conversationID string) (int64, error)
+	SetScanSeq(ctx context.Context, conversationID string, seq int64) error
+	// DeleteObjectsExcept deletes the objects and scan seqs of the conversations other than conversationIDs.
+	DeleteObjectsExcept(ctx context.Context, conversationIDs []string) error
+}
@@ -0,0 +1,111 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewApplicationMongo(db *mongo.Database) (database.Application, error) {
+	coll := db.Collection(database.ApplicationName)
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "platform", Value: 1},
+			{Key: "version", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the releases added before the rollout were offered to every user
+	_, err = coll.UpdateMany(context.Background(), bson.M{"rollout": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"rollout": 100}})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &ApplicationMgo{coll: coll}, nil
+}
+
+type ApplicationMgo struct {
+	coll *mongo.Collection
+}
+
+func (a *ApplicationMgo) idFilter(id string) (bson.M, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errs.ErrArgs.WrapMsg("invalid application id", "id", id)
+	}
+	return bson.M{"_id": objID}, nil
+}
+
+func (a *ApplicationMgo) Create(ctx context.Context, application *model.Application) error {
+	return mongoutil.InsertMany(ctx, a.coll, []*model.Application{application})
+}
+
+func (a *ApplicationMgo) Update(ctx context.Context, id string, data map[string]any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	filter, err := a.idFilter(id)
+	if err != nil {
+		return err
+	}
+	return mongoutil.UpdateOne(ctx, a.coll, filter, bson.M{"$set": data}, true)
+}
+
+func (a *ApplicationMgo) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return errs.ErrArgs.WrapMsg("invalid application id", "id", id)
+		}
+		objIDs = append(objIDs, objID)
+	}
+	return mongoutil.DeleteMany(ctx, a.coll, bson.M{"_id": bson.M{"$in": objIDs}})
+}
+
+func (a *ApplicationMgo) Take(ctx context.Context, id string) (*model.Application, error) {
+	filter, err := a.idFilter(id)
+	if err != nil {
+		return nil, err
+	}
+	return mongoutil.FindOne[*model.Application](ctx, a.coll, filter)
+}
+
+func (a *ApplicationMgo) FindPage(ctx context.Context, platform string, pagination pagination.Pagination) (int64, []*model.Application, error) {
+	filter := bson.M{}
+	if platform != "" {
+		filter["platform"] = platform
+	}
+	return mongoutil.FindPage[*model.Application](ctx, a.coll, filter, pagination, options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}}))
+}
+
+func (a *ApplicationMgo) FindByPlatform(ctx context.Context, platform string) ([]*model.Application, error) {
+	return mongoutil.Find[*model.Application](ctx, a.coll, bson.M{"platform": platform})
+}
@@ -0,0 +1,184 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewLegalHoldMongo(db *mongo.Database) (database.LegalHold, error) {
+	coll := db.Collection(database.LegalHoldName)
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "hold_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "released", Value: 1},
+				{Key: "place_time", Value: -1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	logColl := db.Collection(database.LegalHoldLogName)
+	_, err = logColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "hold_id", Value: 1},
+			{Key: "time", Value: 1},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	objectColl := db.Collection(database.LegalHeldObjectName)
+	_, err = objectColl.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "conversation_id", Value: 1},
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	scanColl := db.Collection(database.LegalHoldScanName)
+	_, err = scanColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LegalHoldMgo{coll: coll, logColl: logColl, objectColl: objectColl, scanColl: scanColl}, nil
+}
+
+type LegalHoldMgo struct {
+	coll       *mongo.Collection
+	logColl    *mongo.Collection
+	objectColl *mongo.Collection
+	scanColl   *mongo.Collection
+}
+
+func (l *LegalHoldMgo) Create(ctx context.Context, hold *model.LegalHold) error {
+	return mongoutil.InsertMany(ctx, l.coll, []*model.LegalHold{hold})
+}
+
+func (l *LegalHoldMgo) Take(ctx context.Context, holdID string) (*model.LegalHold, error) {
+	return mongoutil.FindOne[*model.LegalHold](ctx, l.coll, bson.M{"hold_id": holdID})
+}
+
+func (l *LegalHoldMgo) Release(ctx context.Context, holdID string, userID string, releaseTime time.Time) (bool, error) {
+	filter := bson.M{
+		"hold_id":  holdID,
+		"released": false,
+	}
+	update := bson.M{"$set": bson.M{
+		"released":        true,
+		"release_user_id": userID,
+		"release_time":    releaseTime,
+	}}
+	res, err := l.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (l *LegalHoldMgo) FindPage(ctx context.Context, includeReleased bool, pagination pagination.Pagination) (int64, []*model.LegalHold, error) {
+	filter := bson.M{}
+	if !includeReleased {
+		filter["released"] = false
+	}
+	return mongoutil.FindPage[*model.LegalHold](ctx, l.coll, filter, pagination, options.Find().SetSort(bson.M{"place_time": -1}))
+}
+
+func (l *LegalHoldMgo) FindActive(ctx context.Context) ([]*model.LegalHold, error) {
+	return mongoutil.Find[*model.LegalHold](ctx, l.coll, bson.M{"released": false})
+}
+
+func (l *LegalHoldMgo) CreateLog(ctx context.Context, log *model.LegalHoldLog) error {
+	return mongoutil.InsertMany(ctx, l.logColl, []*model.LegalHoldLog{log})
+}
+
+func (l *LegalHoldMgo) FindLogPage(ctx context.Context, holdID string, pagination pagination.Pagination) (int64, []*model.LegalHoldLog, error) {
+	filter := bson.M{}
+	if holdID != "" {
+		filter["hold_id"] = holdID
+	}
+	return mongoutil.FindPage[*model.LegalHoldLog](ctx, l.logColl, filter, pagination, options.Find().SetSort(bson.M{"time": -1}))
+}
+
+func (l *LegalHoldMgo) AddObjects(ctx context.Context, conversationID string, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	writes := make([]mongo.WriteModel, 0, len(names))
+	for _, name := range names {
+		object := bson.M{"conversation_id": conversationID, "name": name}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(object).SetUpdate(bson.M{"$setOnInsert": object}).SetUpsert(true))
+	}
+	if _, err := l.objectColl.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+func (l *LegalHoldMgo) TakeScanSeq(ctx context.Context, conversationID string) (int64, error) {
+	scan, err := mongoutil.FindOne[*model.LegalHoldScan](ctx, l.scanColl, bson.M{"conversation_id": conversationID})
+	if err != nil {
+		if IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return scan.Seq, nil
+}
+
+func (l *LegalHoldMgo) SetScanSeq(ctx context.Context, conversationID string, seq int64) error {
+	filter := bson.M{"conversation_id": conversationID}
+	return mongoutil.UpdateOne(ctx, l.scanColl, filter, bson.M{"$max": bson.M{"seq": seq}}, false, options.Update().SetUpsert(true))
+}
+
+func (l *LegalHoldMgo) DeleteObjectsExcept(ctx context.Context, conversationIDs []string) error {
+	filter := bson.M{"conversation_id": bson.M{"$nin": conversationIDs}}
+	if err := mongoutil.DeleteMany(ctx, l.objectColl, filter); err != nil {
+		return err
+	}
+	return mongoutil.DeleteMany(ctx, l.scanColl, filter)
+}
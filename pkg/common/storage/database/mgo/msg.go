@@ -3,6 +3,7 @@ package mgo
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -72,6 +73,77 @@ func (m *MsgMgo) PushUnique(ctx context.Context, docID string, index int64, key
 	return mongoutil.UpdateOneResult(ctx, m.coll, filter, update)
 }
 
+// ModifyMsg replaces the content of the message at index and appends the replaced version to its modify list.
+// The update only applies while the stored content still equals modify.Content, so concurrent edits can't lose a version.
+func (m *MsgMgo) ModifyMsg(ctx context.Context, docID string, index int64, content string, modify *model.ModifyModel, maxHistory int) (*mongo.UpdateResult, error) {
+	filter := bson.M{
+		"doc_id": docID,
+		fmt.Sprintf("msgs.%d.msg.content", index): modify.Content,
+	}
+	push := bson.M{"$each": []*model.ModifyModel{modify}}
+	if maxHistory > 0 {
+		push["$slice"] = -maxHistory
+	}
+	update := bson.M{
+		"$set": bson.M{
+			fmt.Sprintf("msgs.%d.msg.content", index): content,
+		},
+		"$push": bson.M{
+			fmt.Sprintf("msgs.%d.modify", index): push,
+		},
+	}
+	return mongoutil.UpdateOneResult(ctx, m.coll, filter, update)
+}
+
+// AddReaction appends the reaction unless the same user already left the same emoji on the message, or the message
+// already has maxPerMsg reactions, or the user already left maxPerUser of them (0 means unlimited).
+func (m *MsgMgo) AddReaction(ctx context.Context, docID string, index int64, reaction *model.ReactionModel, maxPerMsg int, maxPerUser int) (*mongo.UpdateResult, error) {
+	field := fmt.Sprintf("msgs.%d.reactions", index)
+	filter := bson.M{
+		"doc_id": docID,
+		field: bson.M{
+			"$not": bson.M{
+				"$elemMatch": bson.M{"user_id": reaction.UserID, "emoji": reaction.Emoji},
+			},
+		},
+	}
+	if maxPerMsg > 0 {
+		// the array has fewer than maxPerMsg elements when its last allowed position is empty
+		filter[fmt.Sprintf("%s.%d", field, maxPerMsg-1)] = bson.M{"$exists": false}
+	}
+	if maxPerUser > 0 {
+		// aggregation paths do not index arrays, the reactions of the message are taken with $arrayElemAt
+		reactions := bson.M{"$ifNull": bson.A{
+			bson.M{"$let": bson.M{
+				"vars": bson.M{"msg": bson.M{"$arrayElemAt": bson.A{"$msgs", index}}},
+				"in":   "$$msg.reactions",
+			}},
+			bson.A{},
+		}}
+		filter["$expr"] = bson.M{"$lt": bson.A{
+			bson.M{"$size": bson.M{"$filter": bson.M{
+				"input": reactions,
+				"cond":  bson.M{"$eq": bson.A{"$$this.user_id", reaction.UserID}},
+			}}},
+			maxPerUser,
+		}}
+	}
+	update := bson.M{
+		"$push": bson.M{field: reaction},
+	}
+	return mongoutil.UpdateOneResult(ctx, m.coll, filter, update)
+}
+
+func (m *MsgMgo) DeleteReaction(ctx context.Context, docID string, index int64, userID string, emoji string) (*mongo.UpdateResult, error) {
+	filter := bson.M{"doc_id": docID}
+	update := bson.M{
+		"$pull": bson.M{
+			fmt.Sprintf("msgs.%d.reactions", index): bson.M{"user_id": userID, "emoji": emoji},
+		},
+	}
+	return mongoutil.UpdateOneResult(ctx, m.coll, filter, update)
+}
+
 func (m *MsgMgo) FindOneByDocID(ctx context.Context, docID string) (*model.MsgDocModel, error) {
 	return mongoutil.FindOne[*model.MsgDocModel](ctx, m.coll, bson.M{"doc_id": docID})
 }
@@ -947,6 +1019,118 @@ func (m *MsgMgo) GetRandBeforeMsg(ctx context.Context, ts int64, limit int) ([]*
 	})
 }
 
+// beforeMsgByScopeMatch selects the docs of the conversations in scope by doc ID ranges, which the doc ID index serves.
+// The excluded conversations are left to the caller, as a list of them in the query grows with the rules and holds.
+func (m *MsgMgo) beforeMsgByScopeMatch(ts int64, scope *database.MsgDocScope) bson.M {
+	match := bson.M{
+		"msgs": bson.M{
+			"$not": bson.M{
+				"$elemMatch": bson.M{
+					"msg.send_time": bson.M{
+						"$gt": ts,
+					},
+				},
+			},
+		},
+	}
+	var ranges []bson.M
+	if len(scope.ConversationIDs) > 0 {
+		for _, conversationID := range scope.ConversationIDs {
+			ranges = append(ranges, docIDPrefixRange(conversationID+":"))
+		}
+	} else {
+		for _, prefix := range scope.Prefixes {
+			ranges = append(ranges, docIDPrefixRange(prefix))
+		}
+	}
+	if len(ranges) > 0 {
+		match["$or"] = ranges
+	}
+	return match
+}
+
+// docIDPrefixRange matches the doc IDs starting with prefix, which sort before prefix with its last byte incremented.
+func docIDPrefixRange(prefix string) bson.M {
+	end := []byte(prefix)
+	end[len(end)-1]++
+	return bson.M{"doc_id": bson.M{"$gte": prefix, "$lt": string(end)}}
+}
+
+// rangeBeforeMsgByScope calls fn with the docs in scope until it returns false.
+func (m *MsgMgo) rangeBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope, projection bson.M, fn func(doc *model.MsgDocModel) bool) error {
+	match := scope.Matcher()
+	cursor, err := m.coll.Find(ctx, m.beforeMsgByScopeMatch(ts, scope), options.Find().SetProjection(projection))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var doc model.MsgDocModel
+		if err := cursor.Decode(&doc); err != nil {
+			return errs.Wrap(err)
+		}
+		index := strings.LastIndex(doc.DocID, ":")
+		if index < 0 || !match(doc.DocID[:index]) {
+			continue
+		}
+		if !fn(&doc) {
+			break
+		}
+	}
+	return errs.Wrap(cursor.Err())
+}
+
+func (m *MsgMgo) GetRandBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope, limit int) ([]*model.MsgDocModel, error) {
+	projection := bson.M{
+		"_id":                0,
+		"doc_id":             1,
+		"msgs.msg.send_time": 1,
+		"msgs.msg.seq":       1,
+	}
+	if scope.HasExclusions() {
+		var docs []*model.MsgDocModel
+		if limit <= 0 {
+			return docs, nil
+		}
+		err := m.rangeBeforeMsgByScope(ctx, ts, scope, projection, func(doc *model.MsgDocModel) bool {
+			docs = append(docs, doc)
+			return len(docs) < limit
+		})
+		if err != nil {
+			return nil, err
+		}
+		return docs, nil
+	}
+	return mongoutil.Aggregate[*model.MsgDocModel](ctx, m.coll, []bson.M{
+		{
+			"$match": m.beforeMsgByScopeMatch(ts, scope),
+		},
+		{
+			"$project": projection,
+		},
+		{
+			"$sample": bson.M{
+				"size": limit,
+			},
+		},
+	})
+}
+
+func (m *MsgMgo) CountBeforeMsgByScope(ctx context.Context, ts int64, scope *database.MsgDocScope) (int64, error) {
+	if !scope.HasExclusions() {
+		return mongoutil.Count(ctx, m.coll, m.beforeMsgByScopeMatch(ts, scope))
+	}
+	var count int64
+	err := m.rangeBeforeMsgByScope(ctx, ts, scope, bson.M{"_id": 0, "doc_id": 1}, func(*model.MsgDocModel) bool {
+		count++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (m *MsgMgo) DeleteDoc(ctx context.Context, docID string) error {
 	return mongoutil.DeleteOne(ctx, m.coll, bson.M{"doc_id": docID})
 }
@@ -1003,6 +1187,51 @@ func (m *MsgMgo) GetLastMessageSeqByTime(ctx context.Context, conversationID str
 	return seq, nil
 }
 
+func (m *MsgMgo) FindSeqByClientMsgID(ctx context.Context, conversationID string, sendID string, clientMsgID string, sendTime int64) (int64, error) {
+	msgFilter := bson.M{
+		"msg.send_id":       sendID,
+		"msg.client_msg_id": clientMsgID,
+		"msg.send_time":     bson.M{"$gte": sendTime},
+	}
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"doc_id": bson.M{
+					"$regex": fmt.Sprintf("^%s:", conversationID),
+				},
+				"msgs": bson.M{"$elemMatch": msgFilter},
+			},
+		},
+		{
+			"$limit": 1,
+		},
+		{
+			"$project": bson.M{
+				"_id": 0,
+				"msgs": bson.M{
+					"$filter": bson.M{
+						"input": "$msgs",
+						"as":    "info",
+						"cond": bson.M{"$and": bson.A{
+							bson.M{"$eq": bson.A{"$$info.msg.send_id", sendID}},
+							bson.M{"$eq": bson.A{"$$info.msg.client_msg_id", clientMsgID}},
+							bson.M{"$gte": bson.A{"$$info.msg.send_time", sendTime}},
+						}},
+					},
+				},
+			},
+		},
+	}
+	res, err := mongoutil.Aggregate[*model.MsgDocModel](ctx, m.coll, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 || len(res[0].Msg) == 0 || res[0].Msg[0].Msg == nil {
+		return 0, nil
+	}
+	return res[0].Msg[0].Msg.Seq, nil
+}
+
 func (m *MsgMgo) GetLastMessage(ctx context.Context, conversationID string) (*model.MsgInfoModel, error) {
 	pipeline := []bson.M{
 		{
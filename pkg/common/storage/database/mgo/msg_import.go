@@ -0,0 +1,78 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgImportCheckpointMongo(db *mongo.Database) (database.MsgImportCheckpoint, error) {
+	coll := db.Collection(database.MsgImportCheckpointName)
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "import_id", Value: 1},
+			{Key: "conversation_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MsgImportCheckpointMgo{coll: coll}, nil
+}
+
+type MsgImportCheckpointMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *MsgImportCheckpointMgo) Take(ctx context.Context, importID string, conversationID string) (*model.MsgImportCheckpoint, error) {
+	return mongoutil.FindOne[*model.MsgImportCheckpoint](ctx, m.coll, bson.M{"import_id": importID, "conversation_id": conversationID})
+}
+
+func (m *MsgImportCheckpointMgo) Set(ctx context.Context, checkpoint *model.MsgImportCheckpoint) error {
+	filter := bson.M{
+		"import_id":       checkpoint.ImportID,
+		"conversation_id": checkpoint.ConversationID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"offset":            checkpoint.Offset,
+			"last_seq":          checkpoint.LastSeq,
+			"pending_first_seq": checkpoint.PendingFirstSeq,
+			"pending_count":     checkpoint.PendingCount,
+			"update_time":       checkpoint.UpdateTime,
+		},
+		"$setOnInsert": bson.M{
+			"create_time": checkpoint.CreateTime,
+		},
+	}
+	return mongoutil.UpdateOne(ctx, m.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (m *MsgImportCheckpointMgo) FindPage(ctx context.Context, importID string, pagination pagination.Pagination) (int64, []*model.MsgImportCheckpoint, error) {
+	filter := bson.M{}
+	if importID != "" {
+		filter["import_id"] = importID
+	}
+	return mongoutil.FindPage[*model.MsgImportCheckpoint](ctx, m.coll, filter, pagination, options.Find().SetSort(bson.D{{Key: "update_time", Value: -1}}))
+}
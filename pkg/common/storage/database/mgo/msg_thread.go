@@ -0,0 +1,172 @@
+package mgo
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgThreadMongo(db *mongo.Database) (database.MsgThread, error) {
+	thread := db.Collection(database.MsgThreadName)
+	_, err := thread.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "parent_server_msg_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reply := db.Collection(database.MsgThreadReplyName)
+	_, err = reply.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "conversation_id", Value: 1},
+				{Key: "seq", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "conversation_id", Value: 1},
+				{Key: "parent_server_msg_id", Value: 1},
+				{Key: "client_msg_id", Value: 1},
+			},
+			// the replies recorded before client_msg_id was kept are left out
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"client_msg_id": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{
+				{Key: "conversation_id", Value: 1},
+				{Key: "parent_server_msg_id", Value: 1},
+				{Key: "thread_seq", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	hasRead := db.Collection(database.MsgThreadHasReadName)
+	_, err = hasRead.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "conversation_id", Value: 1},
+			{Key: "parent_server_msg_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &msgThreadMongo{thread: thread, reply: reply, hasRead: hasRead}, nil
+}
+
+type msgThreadMongo struct {
+	thread  *mongo.Collection
+	reply   *mongo.Collection
+	hasRead *mongo.Collection
+}
+
+func (m *msgThreadMongo) UpdateThread(ctx context.Context, conversationID string, parentServerMsgID string, parentSeq int64, replyCount int64, replySeq int64, sendTime int64) (*model.MsgThread, error) {
+	filter := bson.M{
+		"conversation_id":      conversationID,
+		"parent_server_msg_id": parentServerMsgID,
+	}
+	update := bson.M{
+		"$max": bson.M{
+			"reply_count":     replyCount,
+			"last_reply_time": sendTime,
+			"last_reply_seq":  replySeq,
+		},
+		"$setOnInsert": bson.M{"parent_seq": parentSeq},
+	}
+	opt := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After).SetProjection(bson.M{"_id": 0})
+	return mongoutil.FindOneAndUpdate[*model.MsgThread](ctx, m.thread, filter, update, opt)
+}
+
+func (m *msgThreadMongo) FindThreads(ctx context.Context, conversationID string, parentServerMsgIDs []string) ([]*model.MsgThread, error) {
+	filter := bson.M{
+		"conversation_id":      conversationID,
+		"parent_server_msg_id": bson.M{"$in": parentServerMsgIDs},
+	}
+	return mongoutil.Find[*model.MsgThread](ctx, m.thread, filter, options.Find().SetProjection(bson.M{"_id": 0}))
+}
+
+func (m *msgThreadMongo) CreateReply(ctx context.Context, reply *model.MsgThreadReply) (bool, error) {
+	if err := mongoutil.InsertOne(ctx, m.reply, reply); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *msgThreadMongo) SetReplyThreadSeq(ctx context.Context, conversationID string, seq int64, threadSeq int64) error {
+	filter := bson.M{
+		"conversation_id": conversationID,
+		"seq":             seq,
+	}
+	return mongoutil.UpdateOne(ctx, m.reply, filter, bson.M{"$set": bson.M{"thread_seq": threadSeq}}, false)
+}
+
+func (m *msgThreadMongo) FindRepliesBySeqs(ctx context.Context, conversationID string, seqs []int64) ([]*model.MsgThreadReply, error) {
+	filter := bson.M{
+		"conversation_id": conversationID,
+		"seq":             bson.M{"$in": seqs},
+	}
+	return mongoutil.Find[*model.MsgThreadReply](ctx, m.reply, filter, options.Find().SetProjection(bson.M{"_id": 0}))
+}
+
+func (m *msgThreadMongo) CountReplies(ctx context.Context, conversationID string, parentServerMsgID string, seq int64) (int64, error) {
+	filter := bson.M{
+		"conversation_id":      conversationID,
+		"parent_server_msg_id": parentServerMsgID,
+		"seq":                  bson.M{"$lte": seq},
+	}
+	return mongoutil.Count(ctx, m.reply, filter)
+}
+
+func (m *msgThreadMongo) FindReplies(ctx context.Context, conversationID string, parentServerMsgID string, pagination pagination.Pagination) (int64, []*model.MsgThreadReply, error) {
+	// a reply is listed once it is finished, until then it has no thread seq
+	filter := bson.M{
+		"conversation_id":      conversationID,
+		"parent_server_msg_id": parentServerMsgID,
+		"thread_seq":           bson.M{"$gt": 0},
+	}
+	return mongoutil.FindPage[*model.MsgThreadReply](ctx, m.reply, filter, pagination, options.Find().SetSort(bson.M{"thread_seq": 1}))
+}
+
+func (m *msgThreadMongo) SetHasReadSeq(ctx context.Context, userID string, conversationID string, parentServerMsgID string, seq int64) error {
+	filter := bson.M{
+		"user_id":              userID,
+		"conversation_id":      conversationID,
+		"parent_server_msg_id": parentServerMsgID,
+	}
+	update := bson.M{"$max": bson.M{"has_read_seq": seq}}
+	return mongoutil.UpdateOne(ctx, m.hasRead, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (m *msgThreadMongo) GetHasReadSeqs(ctx context.Context, userID string, conversationID string, parentServerMsgIDs []string) (map[string]int64, error) {
+	filter := bson.M{
+		"user_id":              userID,
+		"conversation_id":      conversationID,
+		"parent_server_msg_id": bson.M{"$in": parentServerMsgIDs},
+	}
+	res, err := mongoutil.Find[*model.MsgThreadHasRead](ctx, m.hasRead, filter, options.Find().SetProjection(bson.M{"_id": 0}))
+	if err != nil {
+		return nil, err
+	}
+	seqs := make(map[string]int64, len(res))
+	for _, r := range res {
+		seqs[r.ParentServerMsgID] = r.HasReadSeq
+	}
+	return seqs, nil
+}
@@ -97,16 +97,32 @@ func (o *S3Mongo) Delete(ctx context.Context, engine string, name []string) erro
 	return mongoutil.DeleteOne(ctx, o.coll, bson.M{"engine": engine, "name": bson.M{"$in": name}})
 }
 
-func (o *S3Mongo) FindExpirationObject(ctx context.Context, engine string, expiration time.Time, needDelType []string, count int64) ([]*model.Object, error) {
-	opt := options.Find()
-	if count > 0 {
-		opt.SetLimit(count)
-	}
-	return mongoutil.Find[*model.Object](ctx, o.coll, bson.M{
+// FindExpirationObject skips the objects recorded in database.LegalHeldObjectName, each candidate is looked up by name
+// so that the held objects do not have to be listed in the query.
+func (o *S3Mongo) FindExpirationObject(ctx context.Context, engine string, expiration time.Time, needDelType []string, excludeUserIDs []string, count int64) ([]*model.Object, error) {
+	filter := bson.M{
 		"engine":      engine,
 		"create_time": bson.M{"$lt": expiration},
 		"group":       bson.M{"$in": needDelType},
-	}, opt)
+	}
+	if len(excludeUserIDs) > 0 {
+		filter["user_id"] = bson.M{"$nin": excludeUserIDs}
+	}
+	pipeline := []bson.M{
+		{"$match": filter},
+		{"$lookup": bson.M{
+			"from":         database.LegalHeldObjectName,
+			"localField":   "name",
+			"foreignField": "name",
+			"as":           "legal_held",
+		}},
+		{"$match": bson.M{"legal_held": bson.M{"$size": 0}}},
+		{"$project": bson.M{"legal_held": 0}},
+	}
+	if count > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": count})
+	}
+	return mongoutil.Aggregate[*model.Object](ctx, o.coll, pipeline)
 }
 
 func (o *S3Mongo) GetKeyCount(ctx context.Context, engine string, key string) (int64, error) {
@@ -0,0 +1,153 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewOfflinePushRetryMongo(db *mongo.Database) (database.OfflinePushRetry, error) {
+	coll := db.Collection(database.OfflinePushRetryName)
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "next_time", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_ids", Value: 1},
+				{Key: "status", Value: 1},
+			},
+		},
+		{
+			Keys:    bson.D{{Key: "expire_time", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OfflinePushRetryMgo{coll: coll}, nil
+}
+
+type OfflinePushRetryMgo struct {
+	coll *mongo.Collection
+}
+
+func (o *OfflinePushRetryMgo) objectIDs(ids []string) ([]primitive.ObjectID, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, errs.ErrArgs.WrapMsg("invalid offline push retry id", "id", id)
+		}
+		objIDs = append(objIDs, objID)
+	}
+	return objIDs, nil
+}
+
+func (o *OfflinePushRetryMgo) Create(ctx context.Context, retry *model.OfflinePushRetry) error {
+	return mongoutil.InsertMany(ctx, o.coll, []*model.OfflinePushRetry{retry})
+}
+
+func (o *OfflinePushRetryMgo) Claim(ctx context.Context, now time.Time, claimExpire time.Time) (*model.OfflinePushRetry, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": model.OfflinePushRetryStatusPending, "next_time": bson.M{"$lte": now}},
+			bson.M{"status": model.OfflinePushRetryStatusRetrying, "claim_time": bson.M{"$lt": claimExpire}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":     model.OfflinePushRetryStatusRetrying,
+		"claim_time": now,
+	}}
+	opt := options.FindOneAndUpdate().SetSort(bson.M{"next_time": 1}).SetReturnDocument(options.After)
+	retry, err := mongoutil.FindOneAndUpdate[*model.OfflinePushRetry](ctx, o.coll, filter, update, opt)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return retry, nil
+}
+
+func (o *OfflinePushRetryMgo) Update(ctx context.Context, id string, data map[string]any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	objIDs, err := o.objectIDs([]string{id})
+	if err != nil {
+		return err
+	}
+	return mongoutil.UpdateOne(ctx, o.coll, bson.M{"_id": objIDs[0]}, bson.M{"$set": data}, true)
+}
+
+func (o *OfflinePushRetryMgo) Delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	objIDs, err := o.objectIDs(ids)
+	if err != nil {
+		return err
+	}
+	return mongoutil.DeleteMany(ctx, o.coll, bson.M{"_id": bson.M{"$in": objIDs}})
+}
+
+func (o *OfflinePushRetryMgo) FindDead(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*model.OfflinePushRetry, error) {
+	filter := bson.M{"status": model.OfflinePushRetryStatusDead}
+	if userID != "" {
+		filter["user_ids"] = userID
+	}
+	return mongoutil.FindPage[*model.OfflinePushRetry](ctx, o.coll, filter, pagination, options.Find().SetSort(bson.D{{Key: "update_time", Value: -1}}))
+}
+
+func (o *OfflinePushRetryMgo) Requeue(ctx context.Context, ids []string, now time.Time) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	objIDs, err := o.objectIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+	filter := bson.M{"_id": bson.M{"$in": objIDs}, "status": model.OfflinePushRetryStatusDead}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      model.OfflinePushRetryStatusPending,
+			"attempts":    0,
+			"next_time":   now,
+			"update_time": now,
+		},
+		"$unset": bson.M{"expire_time": ""},
+	}
+	res, err := mongoutil.UpdateMany(ctx, o.coll, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
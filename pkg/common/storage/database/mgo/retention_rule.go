@@ -0,0 +1,75 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewRetentionRuleMongo(db *mongo.Database) (database.RetentionRule, error) {
+	coll := db.Collection(database.RetentionRuleName)
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "scope", Value: 1},
+			{Key: "target", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RetentionRuleMgo{coll: coll}, nil
+}
+
+type RetentionRuleMgo struct {
+	coll *mongo.Collection
+}
+
+func (r *RetentionRuleMgo) Set(ctx context.Context, rule *model.RetentionRule) error {
+	filter := bson.M{
+		"scope":  rule.Scope,
+		"target": rule.Target,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"retain_days": rule.RetainDays,
+			"update_time": rule.UpdateTime,
+		},
+		"$setOnInsert": bson.M{
+			"create_time": rule.CreateTime,
+		},
+	}
+	return mongoutil.UpdateOne(ctx, r.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (r *RetentionRuleMgo) Delete(ctx context.Context, scope string, target string) error {
+	return mongoutil.DeleteOne(ctx, r.coll, bson.M{"scope": scope, "target": target})
+}
+
+func (r *RetentionRuleMgo) FindPage(ctx context.Context, pagination pagination.Pagination) (int64, []*model.RetentionRule, error) {
+	return mongoutil.FindPage[*model.RetentionRule](ctx, r.coll, bson.M{}, pagination, options.Find().SetSort(bson.D{{Key: "scope", Value: 1}, {Key: "target", Value: 1}}))
+}
+
+func (r *RetentionRuleMgo) FindAll(ctx context.Context) ([]*model.RetentionRule, error) {
+	return mongoutil.Find[*model.RetentionRule](ctx, r.coll, bson.M{})
+}
@@ -0,0 +1,115 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/pagination"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewScheduledMsgMongo(db *mongo.Database) (database.ScheduledMsg, error) {
+	coll := db.Collection(database.ScheduledMsgName)
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "schedule_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "send_id", Value: 1},
+				{Key: "send_time", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "send_time", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ScheduledMsgMgo{coll: coll}, nil
+}
+
+type ScheduledMsgMgo struct {
+	coll *mongo.Collection
+}
+
+func (s *ScheduledMsgMgo) Create(ctx context.Context, msgs []*model.ScheduledMsg) error {
+	return mongoutil.InsertMany(ctx, s.coll, msgs)
+}
+
+func (s *ScheduledMsgMgo) FindBySendID(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*model.ScheduledMsg, error) {
+	return mongoutil.FindPage[*model.ScheduledMsg](ctx, s.coll, bson.M{"send_id": sendID}, pagination, options.Find().SetSort(bson.M{"send_time": 1}))
+}
+
+func (s *ScheduledMsgMgo) CountBySendID(ctx context.Context, sendID string) (int64, error) {
+	return mongoutil.Count(ctx, s.coll, bson.M{"send_id": sendID})
+}
+
+func (s *ScheduledMsgMgo) DeletePending(ctx context.Context, sendID string, scheduleID string) (bool, error) {
+	filter := bson.M{
+		"schedule_id": scheduleID,
+		"send_id":     sendID,
+		"status":      model.ScheduledMsgStatusPending,
+	}
+	res, err := s.coll.DeleteOne(ctx, filter)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return res.DeletedCount > 0, nil
+}
+
+func (s *ScheduledMsgMgo) Delete(ctx context.Context, scheduleID string) error {
+	return mongoutil.DeleteOne(ctx, s.coll, bson.M{"schedule_id": scheduleID})
+}
+
+func (s *ScheduledMsgMgo) Claim(ctx context.Context, now time.Time, claimExpire time.Time) (*model.ScheduledMsg, error) {
+	filter := bson.M{
+		"send_time": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"status": model.ScheduledMsgStatusPending},
+			bson.M{"status": model.ScheduledMsgStatusSending, "claim_time": bson.M{"$lt": claimExpire}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     model.ScheduledMsgStatusSending,
+			"claim_time": now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opt := options.FindOneAndUpdate().SetSort(bson.M{"send_time": 1}).SetReturnDocument(options.After)
+	msg, err := mongoutil.FindOneAndUpdate[*model.ScheduledMsg](ctx, s.coll, filter, update, opt)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return msg, nil
+}
@@ -13,10 +13,17 @@ import (
 
 func NewSeqUserMongo(db *mongo.Database) (database.SeqUser, error) {
 	coll := db.Collection(database.SeqUserName)
-	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "user_id", Value: 1},
-			{Key: "conversation_id", Value: 1},
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "conversation_id", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "conversation_id", Value: 1},
+			},
 		},
 	})
 	if err != nil {
@@ -114,6 +121,24 @@ func (s *seqUserMongo) GetUserReadSeqs(ctx context.Context, userID string, conve
 	return res, nil
 }
 
+func (s *seqUserMongo) GetUserMinSeqs(ctx context.Context, userID string, conversationID []string) (map[string]int64, error) {
+	if len(conversationID) == 0 {
+		return map[string]int64{}, nil
+	}
+	filter := bson.M{"user_id": userID, "conversation_id": bson.M{"$in": conversationID}}
+	opt := options.Find().SetProjection(bson.M{"_id": 0, "conversation_id": 1, "min_seq": 1})
+	seqs, err := mongoutil.Find[*model.SeqUser](ctx, s.coll, filter, opt)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]int64)
+	for _, seq := range seqs {
+		res[seq.ConversationID] = seq.MinSeq
+	}
+	s.notFoundSet0(res, conversationID)
+	return res, nil
+}
+
 func (s *seqUserMongo) SetUserReadSeq(ctx context.Context, conversationID string, userID string, seq int64) error {
 	dbSeq, err := s.GetUserReadSeq(ctx, conversationID, userID)
 	if err != nil {
@@ -124,3 +149,7 @@ func (s *seqUserMongo) SetUserReadSeq(ctx context.Context, conversationID string
 	}
 	return s.setSeq(ctx, conversationID, userID, seq, "read_seq")
 }
+
+func (s *seqUserMongo) FindByConversation(ctx context.Context, conversationID string) ([]*model.SeqUser, error) {
+	return mongoutil.Find[*model.SeqUser](ctx, s.coll, bson.M{"conversation_id": conversationID}, options.Find().SetProjection(bson.M{"_id": 0}))
+}
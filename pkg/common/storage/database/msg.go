@@ -16,6 +16,7 @@ package database
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
@@ -27,6 +28,9 @@ type Msg interface {
 	Create(ctx context.Context, model *model.MsgDocModel) error
 	UpdateMsg(ctx context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error)
 	PushUnique(ctx context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error)
+	ModifyMsg(ctx context.Context, docID string, index int64, content string, modify *model.ModifyModel, maxHistory int) (*mongo.UpdateResult, error)
+	AddReaction(ctx context.Context, docID string, index int64, reaction *model.ReactionModel, maxPerMsg int, maxPerUser int) (*mongo.UpdateResult, error)
+	DeleteReaction(ctx context.Context, docID string, index int64, userID string, emoji string) (*mongo.UpdateResult, error)
 	FindOneByDocID(ctx context.Context, docID string) (*model.MsgDocModel, error)
 	GetMsgBySeqIndexIn1Doc(ctx context.Context, userID, docID string, seqs []int64) ([]*model.MsgInfoModel, error)
 	GetNewestMsg(ctx context.Context, conversationID string) (*model.MsgInfoModel, error)
@@ -38,7 +42,62 @@ type Msg interface {
 	RangeGroupSendCount(ctx context.Context, start time.Time, end time.Time, ase bool, pageNumber int32, showNumber int32) (msgCount int64, userCount int64, groups []*model.GroupCount, dateCount map[string]int64, err error)
 	DeleteDoc(ctx context.Context, docID string) error
 	GetRandBeforeMsg(ctx context.Context, ts int64, limit int) ([]*model.MsgDocModel, error)
+	// GetRandBeforeMsgByScope is GetRandBeforeMsg limited to the docs of the conversations in scope.
+	// The docs of a scope excluding conversations are taken in doc ID order instead of at random.
+	GetRandBeforeMsgByScope(ctx context.Context, ts int64, scope *MsgDocScope, limit int) ([]*model.MsgDocModel, error)
+	CountBeforeMsgByScope(ctx context.Context, ts int64, scope *MsgDocScope) (int64, error)
 	GetLastMessageSeqByTime(ctx context.Context, conversationID string, time int64) (int64, error)
+	// FindSeqByClientMsgID returns the seq of the msg sendID sent as clientMsgID no earlier than sendTime, 0 if it is not stored.
+	FindSeqByClientMsgID(ctx context.Context, conversationID string, sendID string, clientMsgID string, sendTime int64) (int64, error)
 	GetLastMessage(ctx context.Context, conversationID string) (*model.MsgInfoModel, error)
 	FindSeqs(ctx context.Context, conversationID string, seqs []int64) ([]*model.MsgInfoModel, error)
 }
+
+// MsgDocScope selects msg docs by the conversation ID they belong to.
+type MsgDocScope struct {
+	// ConversationIDs selects these conversations, when empty Prefixes select them.
+	ConversationIDs []string
+	// Prefixes select the conversations whose ID starts with any of them, empty selects all.
+	Prefixes []string
+	// ExcludeConversationIDs and ExcludePrefixes skip conversations selected above.
+	ExcludeConversationIDs []string
+	ExcludePrefixes        []string
+}
+
+// HasExclusions reports whether the scope skips some of the conversations it selects.
+func (s *MsgDocScope) HasExclusions() bool {
+	return len(s.ExcludeConversationIDs) > 0 || len(s.ExcludePrefixes) > 0
+}
+
+// Matcher returns whether the conversation is in the scope.
+func (s *MsgDocScope) Matcher() func(conversationID string) bool {
+	selected := make(map[string]struct{}, len(s.ConversationIDs))
+	for _, conversationID := range s.ConversationIDs {
+		selected[conversationID] = struct{}{}
+	}
+	excluded := make(map[string]struct{}, len(s.ExcludeConversationIDs))
+	for _, conversationID := range s.ExcludeConversationIDs {
+		excluded[conversationID] = struct{}{}
+	}
+	hasPrefix := func(conversationID string, prefixes []string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(conversationID, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(conversationID string) bool {
+		if len(selected) > 0 {
+			if _, ok := selected[conversationID]; !ok {
+				return false
+			}
+		} else if len(s.Prefixes) > 0 && !hasPrefix(conversationID, s.Prefixes) {
+			return false
+		}
+		if _, ok := excluded[conversationID]; ok {
+			return false
+		}
+		return !hasPrefix(conversationID, s.ExcludePrefixes)
+	}
+}
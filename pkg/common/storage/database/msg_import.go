@@ -0,0 +1,30 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type MsgImportCheckpoint interface {
+	// Take returns the checkpoint, a not found error if the import has not started.
+	Take(ctx context.Context, importID string, conversationID string) (*model.MsgImportCheckpoint, error)
+	// Set creates or replaces the checkpoint of the import of a conversation.
+	Set(ctx context.Context, checkpoint *model.MsgImportCheckpoint) error
+	FindPage(ctx context.Context, importID string, pagination pagination.Pagination) (int64, []*model.MsgImportCheckpoint, error)
+}
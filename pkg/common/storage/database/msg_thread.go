@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type MsgThread interface {
+	// UpdateThread raises the reply count and the last reply of the thread to at least the given ones
+	// and returns the thread after the update, so updating it again with the same reply changes nothing.
+	UpdateThread(ctx context.Context, conversationID string, parentServerMsgID string, parentSeq int64, replyCount int64, replySeq int64, sendTime int64) (*model.MsgThread, error)
+	FindThreads(ctx context.Context, conversationID string, parentServerMsgIDs []string) ([]*model.MsgThread, error)
+	// CreateReply records the reply unless the thread already has it, by seq or by client msg id,
+	// and reports whether it was recorded.
+	CreateReply(ctx context.Context, reply *model.MsgThreadReply) (bool, error)
+	SetReplyThreadSeq(ctx context.Context, conversationID string, seq int64, threadSeq int64) error
+	// FindRepliesBySeqs returns the replies among seqs that are already recorded, finished or not.
+	FindRepliesBySeqs(ctx context.Context, conversationID string, seqs []int64) ([]*model.MsgThreadReply, error)
+	// CountReplies counts the replies of the thread up to seq.
+	CountReplies(ctx context.Context, conversationID string, parentServerMsgID string, seq int64) (int64, error)
+	FindReplies(ctx context.Context, conversationID string, parentServerMsgID string, pagination pagination.Pagination) (int64, []*model.MsgThreadReply, error)
+	SetHasReadSeq(ctx context.Context, userID string, conversationID string, parentServerMsgID string, seq int64) error
+	GetHasReadSeqs(ctx context.Context, userID string, conversationID string, parentServerMsgIDs []string) (map[string]int64, error)
+}
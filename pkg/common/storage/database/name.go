@@ -19,4 +19,16 @@ const (
 	SeqUserName             = "seq_user"
 	StreamMsgName           = "stream_msg"
 	CacheName               = "cache"
+	MsgThreadName           = "msg_thread"
+	MsgThreadReplyName      = "msg_thread_reply"
+	MsgThreadHasReadName    = "msg_thread_has_read"
+	ScheduledMsgName        = "scheduled_msg"
+	RetentionRuleName       = "retention_rule"
+	LegalHoldName           = "legal_hold"
+	LegalHoldLogName        = "legal_hold_log"
+	LegalHeldObjectName     = "legal_held_object"
+	LegalHoldScanName       = "legal_hold_scan"
+	ApplicationName         = "application"
+	MsgImportCheckpointName = "msg_import_checkpoint"
+	OfflinePushRetryName    = "offline_push_retry"
 )
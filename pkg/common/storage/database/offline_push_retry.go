@@ -0,0 +1,36 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type OfflinePushRetry interface {
+	Create(ctx context.Context, retry *model.OfflinePushRetry) error
+	// Claim marks one due pending retry as retrying and returns it, or nil when none is due.
+	// Retries claimed before claimExpire are considered abandoned and claimed again.
+	Claim(ctx context.Context, now time.Time, claimExpire time.Time) (*model.OfflinePushRetry, error)
+	Update(ctx context.Context, id string, data map[string]any) error
+	Delete(ctx context.Context, ids []string) error
+	// FindDead lists the dead letters, of the pushes to the user if it is not empty, the latest first.
+	FindDead(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*model.OfflinePushRetry, error)
+	// Requeue makes the dead letters pending again with their attempts reset, returning how many it requeued.
+	Requeue(ctx context.Context, ids []string, now time.Time) (int64, error)
+}
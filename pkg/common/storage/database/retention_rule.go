@@ -0,0 +1,30 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type RetentionRule interface {
+	// Set creates the rule or replaces the retain days of the rule with the same scope and target.
+	Set(ctx context.Context, rule *model.RetentionRule) error
+	Delete(ctx context.Context, scope string, target string) error
+	FindPage(ctx context.Context, pagination pagination.Pagination) (int64, []*model.RetentionRule, error)
+	FindAll(ctx context.Context) ([]*model.RetentionRule, error)
+}
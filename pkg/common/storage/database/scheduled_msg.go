@@ -0,0 +1,35 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/tools/db/pagination"
+)
+
+type ScheduledMsg interface {
+	Create(ctx context.Context, msgs []*model.ScheduledMsg) error
+	FindBySendID(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*model.ScheduledMsg, error)
+	CountBySendID(ctx context.Context, sendID string) (int64, error)
+	// DeletePending deletes the message only while it has not been claimed for sending, reporting whether it did.
+	DeletePending(ctx context.Context, sendID string, scheduleID string) (bool, error)
+	Delete(ctx context.Context, scheduleID string) error
+	// Claim marks one due message as sending and returns it, or nil when none is due.
+	// Messages claimed before claimExpire are considered abandoned and claimed again.
+	Claim(ctx context.Context, now time.Time, claimExpire time.Time) (*model.ScheduledMsg, error)
+}
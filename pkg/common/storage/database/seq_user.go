@@ -1,6 +1,10 @@
 package database
 
-import "context"
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+)
 
 type SeqUser interface {
 	GetUserMaxSeq(ctx context.Context, conversationID string, userID string) (int64, error)
@@ -10,4 +14,6 @@ type SeqUser interface {
 	GetUserReadSeq(ctx context.Context, conversationID string, userID string) (int64, error)
 	SetUserReadSeq(ctx context.Context, conversationID string, userID string, seq int64) error
 	GetUserReadSeqs(ctx context.Context, userID string, conversationID []string) (map[string]int64, error)
+	GetUserMinSeqs(ctx context.Context, userID string, conversationID []string) (map[string]int64, error)
+	FindByConversation(ctx context.Context, conversationID string) ([]*model.SeqUser, error)
 }
@@ -5,6 +5,8 @@ import (
 	"time"
 )
 
+// Application is a release of the app for a platform. Force releases must be installed by the clients on an older version,
+// and Rollout is the percentage of the users the release is offered to, picked by user ID, so raising it keeps the users already offered.
 type Application struct {
 	ID         primitive.ObjectID `bson:"_id"`
 	Platform   string             `bson:"platform"`
@@ -14,5 +16,7 @@ type Application struct {
 	Text       string             `bson:"text"`
 	Force      bool               `bson:"force"`
 	Latest     bool               `bson:"latest"`
+	Rollout    int32              `bson:"rollout"`
 	CreateTime time.Time          `bson:"create_time"`
+	UpdateTime time.Time          `bson:"update_time"`
 }
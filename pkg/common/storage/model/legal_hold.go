@@ -0,0 +1,60 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// LegalHold freezes the data of a user or a conversation, it is active until ReleaseTime is set.
+type LegalHold struct {
+	HoldID        string    `bson:"hold_id"`
+	Type          string    `bson:"type"`
+	Target        string    `bson:"target"`
+	Reason        string    `bson:"reason"`
+	PlaceUserID   string    `bson:"place_user_id"`
+	PlaceTime     time.Time `bson:"place_time"`
+	Released      bool      `bson:"released"`
+	ReleaseUserID string    `bson:"release_user_id"`
+	ReleaseTime   time.Time `bson:"release_time"`
+}
+
+// LegalHoldLog is an audit record of placing or releasing a hold.
+type LegalHoldLog struct {
+	HoldID         string    `bson:"hold_id"`
+	Action         string    `bson:"action"`
+	Type           string    `bson:"type"`
+	Target         string    `bson:"target"`
+	Reason         string    `bson:"reason"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	Time           time.Time `bson:"time"`
+}
+
+// LegalHeldObject is an object referenced by a msg of a held conversation.
+type LegalHeldObject struct {
+	ConversationID string `bson:"conversation_id"`
+	Name           string `bson:"name"`
+}
+
+// LegalHoldScan is the seq up to which the msgs of a held conversation were read for the objects they reference.
+type LegalHoldScan struct {
+	ConversationID string `bson:"conversation_id"`
+	Seq            int64  `bson:"seq"`
+}
+
+const (
+	LegalHoldActionPlace   = "place"
+	LegalHoldActionRelease = "release"
+)
@@ -43,6 +43,27 @@ type RevokeModel struct {
 	Time     int64  `bson:"time"`
 }
 
+// ModifyModel is a previous version of an edited message: the content UserID replaced at Time.
+type ModifyModel struct {
+	Content string `bson:"content"`
+	UserID  string `bson:"user_id"`
+	Time    int64  `bson:"time"`
+}
+
+// ReactionModel is an emoji reaction UserID left on a message at Time.
+type ReactionModel struct {
+	UserID string `bson:"user_id"`
+	Emoji  string `bson:"emoji"`
+	Time   int64  `bson:"time"`
+}
+
+// ThreadModel is the summary of the replies to a thread root message.
+type ThreadModel struct {
+	ReplyCount    int64 `bson:"reply_count"`
+	LastReplyTime int64 `bson:"last_reply_time"`
+	LastReplySeq  int64 `bson:"last_reply_seq"`
+}
+
 type OfflinePushModel struct {
 	Title         string `bson:"title"`
 	Desc          string `bson:"desc"`
@@ -77,10 +98,13 @@ type MsgDataModel struct {
 }
 
 type MsgInfoModel struct {
-	Msg     *MsgDataModel `bson:"msg"`
-	Revoke  *RevokeModel  `bson:"revoke"`
-	DelList []string      `bson:"del_list"`
-	IsRead  bool          `bson:"is_read"`
+	Msg       *MsgDataModel    `bson:"msg"`
+	Revoke    *RevokeModel     `bson:"revoke"`
+	Modify    []*ModifyModel   `bson:"modify,omitempty"`
+	Reactions []*ReactionModel `bson:"reactions,omitempty"`
+	Thread    *ThreadModel     `bson:"thread,omitempty"`
+	DelList   []string         `bson:"del_list"`
+	IsRead    bool             `bson:"is_read"`
 }
 
 type UserCount struct {
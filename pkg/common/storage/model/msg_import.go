@@ -0,0 +1,34 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+)
+
+// MsgImportCheckpoint is how far an import has got into the source msgs of a conversation.
+// A batch is reserved before it is written, so a batch interrupted by a crash is written again to the same seqs.
+type MsgImportCheckpoint struct {
+	ImportID       string `bson:"import_id"`
+	ConversationID string `bson:"conversation_id"`
+	// Offset is the number of source msgs imported.
+	Offset  int64 `bson:"offset"`
+	LastSeq int64 `bson:"last_seq"`
+	// PendingFirstSeq and PendingCount are the seqs reserved for the batch starting at Offset.
+	PendingFirstSeq int64     `bson:"pending_first_seq"`
+	PendingCount    int64     `bson:"pending_count"`
+	CreateTime      time.Time `bson:"create_time"`
+	UpdateTime      time.Time `bson:"update_time"`
+}
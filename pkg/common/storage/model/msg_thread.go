@@ -0,0 +1,28 @@
+package model
+
+// MsgThread is the reply counter of the thread rooted at ParentServerMsgID; ReplyCount doubles as the thread max seq.
+type MsgThread struct {
+	ConversationID    string `bson:"conversation_id"`
+	ParentServerMsgID string `bson:"parent_server_msg_id"`
+	ParentSeq         int64  `bson:"parent_seq"`
+	ReplyCount        int64  `bson:"reply_count"`
+	LastReplyTime     int64  `bson:"last_reply_time"`
+	LastReplySeq      int64  `bson:"last_reply_seq"`
+}
+
+// MsgThreadReply maps the conversation seq of a reply to its seq within the thread.
+type MsgThreadReply struct {
+	ConversationID    string `bson:"conversation_id"`
+	ParentServerMsgID string `bson:"parent_server_msg_id"`
+	ClientMsgID       string `bson:"client_msg_id"`
+	ThreadSeq         int64  `bson:"thread_seq"`
+	Seq               int64  `bson:"seq"`
+	SendTime          int64  `bson:"send_time"`
+}
+
+type MsgThreadHasRead struct {
+	UserID            string `bson:"user_id"`
+	ConversationID    string `bson:"conversation_id"`
+	ParentServerMsgID string `bson:"parent_server_msg_id"`
+	HasReadSeq        int64  `bson:"has_read_seq"`
+}
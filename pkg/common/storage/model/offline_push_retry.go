@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OfflinePushRetryStatusPending  = 0
+	OfflinePushRetryStatusRetrying = 1
+	OfflinePushRetryStatusDead     = 2
+)
+
+// OfflinePushRetry is an offline push that failed, retried at NextTime with a growing backoff until it succeeds.
+// A push that runs out of attempts is kept as a dead letter until ExpireTime.
+type OfflinePushRetry struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	UserIDs       []string           `bson:"user_ids"`
+	ClientMsgID   string             `bson:"client_msg_id"`
+	Title         string             `bson:"title"`
+	Content       string             `bson:"content"`
+	Ex            string             `bson:"ex"`
+	IOSPushSound  string             `bson:"ios_push_sound"`
+	IOSBadgeCount bool               `bson:"ios_badge_count"`
+	// Providers are the routed providers the push failed on, it is retried on them only. Empty retries the whole push.
+	Providers  []string  `bson:"providers"`
+	Attempts   int32     `bson:"attempts"`
+	Status     int32     `bson:"status"`
+	LastError  string    `bson:"last_error"`
+	NextTime   time.Time `bson:"next_time"`
+	ClaimTime  time.Time `bson:"claim_time"`
+	CreateTime time.Time `bson:"create_time"`
+	UpdateTime time.Time `bson:"update_time"`
+	// ExpireTime is only set on the dead letters, which are dropped after it.
+	ExpireTime *time.Time `bson:"expire_time,omitempty"`
+}
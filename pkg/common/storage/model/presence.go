@@ -0,0 +1,26 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// Presence is what a user tells about themself besides being online, it is only kept in redis.
+type Presence struct {
+	UserID string `json:"userID"`
+	State  int32  `json:"state"`
+	Text   string `json:"text"`
+	Emoji  string `json:"emoji"`
+	// ExpireAt is when the presence is cleared in milliseconds, zero if it is kept until changed.
+	ExpireAt   int64 `json:"expireAt"`
+	UpdateTime int64 `json:"updateTime"`
+}
@@ -0,0 +1,26 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+// WebPushSubscription is the PushSubscription a browser created for the user, the pushes are encrypted
+// to P256dh with the Auth secret and posted to Endpoint.
+type WebPushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	P256dh   string `json:"p256dh"`
+	Auth     string `json:"auth"`
+	// ExpirationTime is the unix milli the browser drops the subscription at, 0 if it does not expire.
+	ExpirationTime int64 `json:"expirationTime"`
+	CreateTime     int64 `json:"createTime"`
+}
@@ -0,0 +1,138 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgexport
+
+import (
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/tools/utils/idutil"
+)
+
+// DocMsgs converts the stored msgs of a doc, skipping the unused slots.
+func DocMsgs(doc *model.MsgDocModel) []*msgext.ExportedMsg {
+	if doc == nil {
+		return nil
+	}
+	msgs := make([]*msgext.ExportedMsg, 0, len(doc.Msg))
+	for _, info := range doc.Msg {
+		if info == nil || info.Msg == nil {
+			continue
+		}
+		msgs = append(msgs, Msg(info))
+	}
+	return msgs
+}
+
+func Msg(info *model.MsgInfoModel) *msgext.ExportedMsg {
+	m := info.Msg
+	msg := &msgext.ExportedMsg{
+		SendID:           m.SendID,
+		RecvID:           m.RecvID,
+		GroupID:          m.GroupID,
+		ClientMsgID:      m.ClientMsgID,
+		ServerMsgID:      m.ServerMsgID,
+		SenderPlatformID: m.SenderPlatformID,
+		SenderNickname:   m.SenderNickname,
+		SenderFaceURL:    m.SenderFaceURL,
+		SessionType:      m.SessionType,
+		MsgFrom:          m.MsgFrom,
+		ContentType:      m.ContentType,
+		Content:          m.Content,
+		Seq:              m.Seq,
+		SendTime:         m.SendTime,
+		CreateTime:       m.CreateTime,
+		Status:           m.Status,
+		IsRead:           info.IsRead || m.IsRead,
+		Options:          m.Options,
+		AtUserIDList:     m.AtUserIDList,
+		AttachedInfo:     m.AttachedInfo,
+		Ex:               m.Ex,
+		DeletedBy:        info.DelList,
+	}
+	if info.Revoke != nil {
+		msg.Revoke = &msgext.ExportedRevoke{
+			Role:     info.Revoke.Role,
+			UserID:   info.Revoke.UserID,
+			Nickname: info.Revoke.Nickname,
+			Time:     info.Revoke.Time,
+		}
+	}
+	for _, modify := range info.Modify {
+		msg.Modify = append(msg.Modify, &msgext.ExportedModify{Content: modify.Content, UserID: modify.UserID, Time: modify.Time})
+	}
+	for _, reaction := range info.Reactions {
+		msg.Reactions = append(msg.Reactions, &msgext.ExportedReaction{UserID: reaction.UserID, Emoji: reaction.Emoji, Time: reaction.Time})
+	}
+	return msg
+}
+
+// MsgInfo converts an exported msg back into the stored msg and its revoke, the seq is assigned on import.
+func MsgInfo(msg *msgext.ExportedMsg) *model.MsgInfoModel {
+	info := &model.MsgInfoModel{
+		Msg: &model.MsgDataModel{
+			SendID:           msg.SendID,
+			RecvID:           msg.RecvID,
+			GroupID:          msg.GroupID,
+			ClientMsgID:      msg.ClientMsgID,
+			ServerMsgID:      msg.ServerMsgID,
+			SenderPlatformID: msg.SenderPlatformID,
+			SenderNickname:   msg.SenderNickname,
+			SenderFaceURL:    msg.SenderFaceURL,
+			SessionType:      msg.SessionType,
+			MsgFrom:          msg.MsgFrom,
+			ContentType:      msg.ContentType,
+			Content:          msg.Content,
+			SendTime:         msg.SendTime,
+			CreateTime:       msg.CreateTime,
+			Status:           msg.Status,
+			IsRead:           msg.IsRead,
+			Options:          msg.Options,
+			AtUserIDList:     msg.AtUserIDList,
+			AttachedInfo:     msg.AttachedInfo,
+			Ex:               msg.Ex,
+		},
+		IsRead: msg.IsRead,
+	}
+	if info.Msg.ClientMsgID == "" {
+		info.Msg.ClientMsgID = idutil.GetMsgIDByMD5(msg.SendID)
+	}
+	if info.Msg.ServerMsgID == "" {
+		info.Msg.ServerMsgID = idutil.GetMsgIDByMD5(msg.SendID)
+	}
+	if info.Msg.CreateTime == 0 {
+		info.Msg.CreateTime = msg.SendTime
+	}
+	if info.Msg.Status == 0 || info.Msg.Status == constant.MsgStatusSending {
+		info.Msg.Status = constant.MsgStatusSendSuccess
+	}
+	if msg.Revoke != nil {
+		info.Revoke = &model.RevokeModel{
+			Role:     msg.Revoke.Role,
+			UserID:   msg.Revoke.UserID,
+			Nickname: msg.Revoke.Nickname,
+			Time:     msg.Revoke.Time,
+		}
+	}
+	return info
+}
+
+func ReadStates(seqs []*model.SeqUser) []*msgext.ExportedReadState {
+	states := make([]*msgext.ExportedReadState, 0, len(seqs))
+	for _, seq := range seqs {
+		states = append(states, &msgext.ExportedReadState{UserID: seq.UserID, HasReadSeq: seq.ReadSeq})
+	}
+	return states
+}
@@ -0,0 +1,298 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgexport writes conversations into a portable zip archive:
+//
+//	manifest.json                                    format version, counts and attachment index, written last
+//	conversations/<conversationID>/messages.jsonl    one msgext.ExportedMsg per line in seq order
+//	conversations/<conversationID>/read_states.jsonl one msgext.ExportedReadState per line
+//	attachments/<object name>                        the objects referenced by the msgs
+package msgexport
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/errs"
+)
+
+// Version is the archive format version, bumped on incompatible changes.
+const Version = 1
+
+const ManifestName = "manifest.json"
+
+// Source reads the conversations being exported.
+type Source interface {
+	GetConversation(ctx context.Context, conversationID string) (*msgext.GetExportConversationResp, error)
+	// GetMsgDoc returns the msgs stored in the doc at index, empty if it does not exist.
+	GetMsgDoc(ctx context.Context, conversationID string, index int64) ([]*msgext.ExportedMsg, error)
+}
+
+// DownloadTimeout bounds the download of a single attachment when Options.Client is nil.
+const DownloadTimeout = time.Minute * 5
+
+type Options struct {
+	ConversationIDs []string
+	// Attachments downloads the objects referenced by the msgs into the archive.
+	Attachments bool
+	// AccessURL resolves an object name to the url it is downloaded from, required by Attachments.
+	// Only the object names are taken from the msgs, the urls in their content are never requested.
+	AccessURL func(ctx context.Context, name string) (string, error)
+	// Client downloads the attachments, a client with DownloadTimeout if nil.
+	Client *http.Client
+}
+
+type Manifest struct {
+	Version       int                     `json:"version"`
+	ExportTime    int64                   `json:"exportTime"`
+	Conversations []*ConversationManifest `json:"conversations"`
+	Attachments   []*AttachmentManifest   `json:"attachments"`
+}
+
+type ConversationManifest struct {
+	ConversationID string `json:"conversationID"`
+	MinSeq         int64  `json:"minSeq"`
+	MaxSeq         int64  `json:"maxSeq"`
+	MsgCount       int64  `json:"msgCount"`
+	ReadStateCount int64  `json:"readStateCount"`
+	Path           string `json:"path"`
+}
+
+type AttachmentManifest struct {
+	Name string `json:"name"`
+	// URL is the url found in the msgs, kept for reference and never requested.
+	URL string `json:"url"`
+	// Path is the file in the archive, empty if the attachment was not downloaded.
+	Path  string `json:"path,omitempty"`
+	Size  int64  `json:"size,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Export streams the conversations into w as a zip archive, holding no more than one msg doc in memory.
+// Attachments that fail to download are reported in the manifest instead of failing the export.
+func Export(ctx context.Context, w io.Writer, source Source, opts Options) (*Manifest, error) {
+	e := &exporter{
+		source:      source,
+		opts:        opts,
+		zw:          zip.NewWriter(w),
+		attachments: make(map[string]*AttachmentManifest),
+		manifest: &Manifest{
+			Version:       Version,
+			ExportTime:    time.Now().UnixMilli(),
+			Conversations: []*ConversationManifest{},
+			Attachments:   []*AttachmentManifest{},
+		},
+	}
+	if opts.Attachments && opts.AccessURL == nil {
+		return nil, errs.ErrArgs.WrapMsg("attachments require an object access url")
+	}
+	if e.opts.Client == nil {
+		e.opts.Client = &http.Client{Timeout: DownloadTimeout}
+	}
+	for _, conversationID := range opts.ConversationIDs {
+		if err := e.exportConversation(ctx, conversationID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Attachments {
+		for _, attachment := range e.manifest.Attachments {
+			if err := e.exportAttachment(ctx, attachment); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := e.writeJSON(ManifestName, e.manifest); err != nil {
+		return nil, err
+	}
+	if err := e.zw.Close(); err != nil {
+		return nil, errs.WrapMsg(err, "close zip failed")
+	}
+	return e.manifest, nil
+}
+
+type exporter struct {
+	source      Source
+	opts        Options
+	zw          *zip.Writer
+	manifest    *Manifest
+	attachments map[string]*AttachmentManifest
+}
+
+func (e *exporter) exportConversation(ctx context.Context, conversationID string) error {
+	conversation, err := e.source.GetConversation(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	dir := "conversations/" + url.PathEscape(conversationID) + "/"
+	cm := &ConversationManifest{
+		ConversationID: conversationID,
+		MinSeq:         conversation.MinSeq,
+		MaxSeq:         conversation.MaxSeq,
+		ReadStateCount: int64(len(conversation.ReadStates)),
+		Path:           dir,
+	}
+	e.manifest.Conversations = append(e.manifest.Conversations, cm)
+	fw, err := e.zw.Create(dir + "read_states.jsonl")
+	if err != nil {
+		return errs.WrapMsg(err, "create zip file failed")
+	}
+	enc := json.NewEncoder(fw)
+	for _, state := range conversation.ReadStates {
+		if err := enc.Encode(state); err != nil {
+			return errs.WrapMsg(err, "write read state failed", "conversationID", conversationID)
+		}
+	}
+	fw, err = e.zw.Create(dir + "messages.jsonl")
+	if err != nil {
+		return errs.WrapMsg(err, "create zip file failed")
+	}
+	if conversation.MaxSeq <= 0 {
+		return nil
+	}
+	var (
+		doc   model.MsgDocModel
+		start int64
+	)
+	if conversation.MinSeq > 0 {
+		start = doc.GetDocIndex(conversation.MinSeq)
+	}
+	enc = json.NewEncoder(fw)
+	for index := start; index <= doc.GetDocIndex(conversation.MaxSeq); index++ {
+		msgs, err := e.source.GetMsgDoc(ctx, conversationID, index)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			if err := enc.Encode(msg); err != nil {
+				return errs.WrapMsg(err, "write msg failed", "conversationID", conversationID, "seq", msg.Seq)
+			}
+			cm.MsgCount++
+			e.addAttachments(msg.Content)
+		}
+	}
+	return nil
+}
+
+func (e *exporter) addAttachments(content string) {
+	for _, ref := range AttachmentRefs(content) {
+		if _, ok := e.attachments[ref.Name]; ok {
+			continue
+		}
+		attachment := &AttachmentManifest{Name: ref.Name, URL: ref.URL}
+		e.attachments[ref.Name] = attachment
+		e.manifest.Attachments = append(e.manifest.Attachments, attachment)
+	}
+}
+
+func (e *exporter) exportAttachment(ctx context.Context, attachment *AttachmentManifest) error {
+	body, err := e.download(ctx, attachment.Name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		attachment.Error = err.Error()
+		return nil
+	}
+	defer body.Close()
+	attachment.Path = "attachments/" + attachmentPath(attachment.Name)
+	fw, err := e.zw.Create(attachment.Path)
+	if err != nil {
+		return errs.WrapMsg(err, "create zip file failed")
+	}
+	attachment.Size, err = io.Copy(fw, body)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// the file is already in the archive, flag it as truncated
+		attachment.Error = err.Error()
+	}
+	return nil
+}
+
+func (e *exporter) download(ctx context.Context, name string) (io.ReadCloser, error) {
+	rawURL, err := e.opts.AccessURL(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("download status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (e *exporter) writeJSON(name string, v any) error {
+	fw, err := e.zw.Create(name)
+	if err != nil {
+		return errs.WrapMsg(err, "create zip file failed")
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return errs.WrapMsg(err, "write json failed", "name", name)
+	}
+	return nil
+}
+
+// objectURL matches the urls of uploaded objects, which the api serves under /object/<name>.
+var objectURL = regexp.MustCompile(`https?://[^\s"'<>\\]+/object/[^\s"'<>\\?#]+`)
+
+type AttachmentRef struct {
+	Name string
+	URL  string
+}
+
+// AttachmentRefs returns the objects referenced by msg content, such as the url of a picture or file.
+func AttachmentRefs(content string) []AttachmentRef {
+	if !strings.Contains(content, "/object/") {
+		return nil
+	}
+	var refs []AttachmentRef
+	for _, u := range objectURL.FindAllString(content, -1) {
+		name := u[strings.Index(u, "/object/")+len("/object/"):]
+		if unescaped, err := url.PathUnescape(name); err == nil {
+			name = unescaped
+		}
+		if name == "" {
+			continue
+		}
+		refs = append(refs, AttachmentRef{Name: name, URL: u})
+	}
+	return refs
+}
+
+// attachmentPath keeps an object name from escaping the attachments directory.
+func attachmentPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
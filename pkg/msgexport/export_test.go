@@ -0,0 +1,150 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgexport
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+)
+
+type memSource struct {
+	conversations map[string]*msgext.GetExportConversationResp
+	docs          map[string]map[int64][]*msgext.ExportedMsg
+}
+
+func (m *memSource) GetConversation(_ context.Context, conversationID string) (*msgext.GetExportConversationResp, error) {
+	return m.conversations[conversationID], nil
+}
+
+func (m *memSource) GetMsgDoc(_ context.Context, conversationID string, index int64) ([]*msgext.ExportedMsg, error) {
+	return m.docs[conversationID][index], nil
+}
+
+func TestAttachmentRefs(t *testing.T) {
+	content := `{"sourcePicture":{"url":"http://127.0.0.1:10002/object/u1/a%20b.png?x=1"},"bigPicture":{"url":"https://cdn/x.png"}}`
+	refs := AttachmentRefs(content)
+	if len(refs) != 1 {
+		t.Fatalf("refs %+v", refs)
+	}
+	if refs[0].Name != "u1/a b.png" || refs[0].URL != "http://127.0.0.1:10002/object/u1/a%20b.png" {
+		t.Fatalf("ref %+v", refs[0])
+	}
+	if refs := AttachmentRefs(`{"content":"hello"}`); len(refs) != 0 {
+		t.Fatalf("refs %+v", refs)
+	}
+	if p := attachmentPath("../../etc/passwd"); p != "etc/passwd" {
+		t.Fatalf("path %s", p)
+	}
+}
+
+func TestExport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/object/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "data:"+r.URL.Path)
+	}))
+	defer srv.Close()
+	// the host in the content is ignored, objects are fetched through AccessURL by name
+	picture := `{"url":"http://169.254.169.254/object/pic.png"}`
+	source := &memSource{
+		conversations: map[string]*msgext.GetExportConversationResp{
+			"si_a_b": {
+				ConversationID: "si_a_b",
+				MinSeq:         99,
+				MaxSeq:         102,
+				ReadStates:     []*msgext.ExportedReadState{{UserID: "a", HasReadSeq: 102}, {UserID: "b", HasReadSeq: 100}},
+			},
+		},
+		docs: map[string]map[int64][]*msgext.ExportedMsg{
+			"si_a_b": {
+				0: {{Seq: 99, Content: "hi"}, {Seq: 100, Content: picture, Revoke: &msgext.ExportedRevoke{UserID: "a"}}},
+				1: {{Seq: 101, Content: picture}, {Seq: 102, Content: `{"url":"http://127.0.0.1:1/object/missing.png"}`}},
+				// past the doc of max seq, must not be read
+				5: {{Seq: 999}},
+			},
+		},
+	}
+	opts := Options{ConversationIDs: []string{"si_a_b"}, Attachments: true}
+	if _, err := Export(context.Background(), io.Discard, source, opts); err == nil {
+		t.Fatal("attachments without AccessURL must fail")
+	}
+	opts.AccessURL = func(_ context.Context, name string) (string, error) {
+		return srv.URL + "/object/" + name, nil
+	}
+	var buf bytes.Buffer
+	manifest, err := Export(context.Background(), &buf, source, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifest.Conversations) != 1 || manifest.Conversations[0].MsgCount != 4 || manifest.Conversations[0].ReadStateCount != 2 {
+		t.Fatalf("conversations %+v", manifest.Conversations[0])
+	}
+	if len(manifest.Attachments) != 2 || manifest.Attachments[0].Error != "" || manifest.Attachments[1].Error == "" {
+		t.Fatalf("attachments %+v %+v", manifest.Attachments[0], manifest.Attachments[1])
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(data)
+	}
+	lines := strings.Split(strings.TrimSpace(files["conversations/si_a_b/messages.jsonl"]), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("messages %q", lines)
+	}
+	var msg msgext.ExportedMsg
+	if err := json.Unmarshal([]byte(lines[1]), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Seq != 100 || msg.Revoke == nil || msg.Revoke.UserID != "a" {
+		t.Fatalf("msg %+v", msg)
+	}
+	if n := strings.Count(files["conversations/si_a_b/read_states.jsonl"], "\n"); n != 2 {
+		t.Fatalf("read states %d", n)
+	}
+	if files["attachments/pic.png"] != "data:/object/pic.png" {
+		t.Fatalf("attachment %q", files["attachments/pic.png"])
+	}
+	var read Manifest
+	if err := json.Unmarshal([]byte(files[ManifestName]), &read); err != nil {
+		t.Fatal(err)
+	}
+	if read.Version != Version {
+		t.Fatalf("version %d", read.Version)
+	}
+}
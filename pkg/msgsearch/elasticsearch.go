@@ -0,0 +1,317 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/tools/errs"
+)
+
+const elasticsearchTimeout = 10 * time.Second
+
+// elasticsearchMapping analyzes text with the built-in cjk analyzer, which bigrams CJK text and lowercases words.
+const elasticsearchMapping = `{
+	"mappings": {
+		"properties": {
+			"conversation_id": {"type": "keyword"},
+			"seq": {"type": "long"},
+			"send_id": {"type": "keyword"},
+			"content_type": {"type": "integer"},
+			"send_time": {"type": "long"},
+			"text": {"type": "text", "analyzer": "cjk"},
+			"del_user_ids": {"type": "keyword"}
+		}
+	}
+}`
+
+// elasticsearchDelUserIDsMapping adds the field to indexes created before it existed.
+const elasticsearchDelUserIDsMapping = `{"properties": {"del_user_ids": {"type": "keyword"}}}`
+
+// elasticsearchAddDelUserID appends the user to del_user_ids once.
+const elasticsearchAddDelUserID = `if (ctx._source.del_user_ids == null) { ctx._source.del_user_ids = [params.userID] } ` +
+	`else if (!ctx._source.del_user_ids.contains(params.userID)) { ctx._source.del_user_ids.add(params.userID) }`
+
+type elasticsearchDoc struct {
+	ConversationID string `json:"conversation_id"`
+	Seq            int64  `json:"seq"`
+	SendID         string `json:"send_id,omitempty"`
+	ContentType    int32  `json:"content_type,omitempty"`
+	SendTime       int64  `json:"send_time,omitempty"`
+	Text           string `json:"text,omitempty"`
+}
+
+// NewElasticsearchIndex talks to Elasticsearch over its REST API and creates the index when it does not exist.
+func NewElasticsearchIndex(ctx context.Context, conf *config.MsgSearch) (MsgSearchIndex, error) {
+	if len(conf.Elasticsearch.Address) == 0 {
+		return nil, errs.ErrArgs.WrapMsg("elasticsearch address is empty")
+	}
+	if conf.Elasticsearch.Index == "" {
+		return nil, errs.ErrArgs.WrapMsg("elasticsearch index is empty")
+	}
+	e := &elasticsearchIndex{
+		address:  conf.Elasticsearch.Address,
+		index:    url.PathEscape(conf.Elasticsearch.Index),
+		username: conf.Elasticsearch.Username,
+		password: conf.Elasticsearch.Password,
+		client:   &http.Client{Timeout: elasticsearchTimeout},
+	}
+	if err := e.createIndex(ctx); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+type elasticsearchIndex struct {
+	address  []string
+	index    string
+	username string
+	password string
+	client   *http.Client
+}
+
+// do sends the request to the first reachable address and decodes a successful response into resp.
+func (e *elasticsearchIndex) do(ctx context.Context, method string, path string, contentType string, body []byte, resp any) (int, error) {
+	var lastErr error
+	for _, address := range e.address {
+		req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(address, "/")+path, bytes.NewReader(body))
+		if err != nil {
+			return 0, errs.WrapMsg(err, "new elasticsearch request failed")
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if e.username != "" {
+			req.SetBasicAuth(e.username, e.password)
+		}
+		res, err := e.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		data, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			return 0, errs.WrapMsg(err, "read elasticsearch response failed")
+		}
+		if res.StatusCode/100 != 2 {
+			return res.StatusCode, errs.New("elasticsearch request failed", "method", method, "path", path, "status", res.StatusCode, "body", string(data)).Wrap()
+		}
+		if resp != nil {
+			if err := json.Unmarshal(data, resp); err != nil {
+				return res.StatusCode, errs.WrapMsg(err, "decode elasticsearch response failed")
+			}
+		}
+		return res.StatusCode, nil
+	}
+	return 0, errs.WrapMsg(lastErr, "elasticsearch is unreachable", "address", e.address)
+}
+
+func (e *elasticsearchIndex) createIndex(ctx context.Context) error {
+	status, err := e.do(ctx, http.MethodHead, "/"+e.index, "", nil, nil)
+	if err == nil {
+		_, err = e.do(ctx, http.MethodPut, "/"+e.index+"/_mapping", "application/json", []byte(elasticsearchDelUserIDsMapping), nil)
+		return err
+	}
+	if status != http.StatusNotFound {
+		return err
+	}
+	_, err = e.do(ctx, http.MethodPut, "/"+e.index, "application/json", []byte(elasticsearchMapping), nil)
+	return err
+}
+
+func (e *elasticsearchIndex) docID(conversationID string, seq int64) string {
+	return conversationID + ":" + strconv.FormatInt(seq, 10)
+}
+
+func (e *elasticsearchIndex) Index(ctx context.Context, docs []*Doc) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, doc := range docs {
+		// a partial update keeps the del_user_ids of a reindexed msg
+		action := map[string]any{"update": map[string]string{"_id": e.docID(doc.ConversationID, doc.Seq)}}
+		if err := encoder.Encode(action); err != nil {
+			return errs.Wrap(err)
+		}
+		update := map[string]any{
+			"doc": &elasticsearchDoc{
+				ConversationID: doc.ConversationID,
+				Seq:            doc.Seq,
+				SendID:         doc.SendID,
+				ContentType:    doc.ContentType,
+				SendTime:       doc.SendTime,
+				Text:           doc.Text,
+			},
+			"doc_as_upsert": true,
+		}
+		if err := encoder.Encode(update); err != nil {
+			return errs.Wrap(err)
+		}
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Error json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if _, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_bulk", "application/x-ndjson", body.Bytes(), &resp); err != nil {
+		return err
+	}
+	if resp.Errors {
+		for _, item := range resp.Items {
+			for _, result := range item {
+				if len(result.Error) > 0 {
+					return errs.New("elasticsearch bulk index failed", "error", string(result.Error)).Wrap()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (e *elasticsearchIndex) Search(ctx context.Context, query *Query) (int64, []*Hit, error) {
+	if strings.TrimSpace(query.Keyword) == "" || len(query.ConversationIDs) == 0 {
+		return 0, nil, nil
+	}
+	var conversations []any
+	unbounded, bounded := query.boundedConversationIDs()
+	if len(unbounded) > 0 {
+		conversations = append(conversations, map[string]any{"terms": map[string]any{"conversation_id": unbounded}})
+	}
+	for conversationID, seq := range bounded {
+		conversations = append(conversations, map[string]any{"bool": map[string]any{"filter": []any{
+			map[string]any{"term": map[string]any{"conversation_id": conversationID}},
+			map[string]any{"range": map[string]any{"seq": map[string]any{"gte": seq}}},
+		}}})
+	}
+	filter := []any{
+		map[string]any{"bool": map[string]any{"should": conversations, "minimum_should_match": 1}},
+	}
+	if query.SendID != "" {
+		filter = append(filter, map[string]any{"term": map[string]any{"send_id": query.SendID}})
+	}
+	if len(query.ContentTypes) > 0 {
+		filter = append(filter, map[string]any{"terms": map[string]any{"content_type": query.ContentTypes}})
+	}
+	sendTime := make(map[string]any)
+	if query.StartTime > 0 {
+		sendTime["gte"] = query.StartTime
+	}
+	if query.EndTime > 0 {
+		sendTime["lte"] = query.EndTime
+	}
+	if len(sendTime) > 0 {
+		filter = append(filter, map[string]any{"range": map[string]any{"send_time": sendTime}})
+	}
+	mustNot := []any{}
+	if query.UserID != "" {
+		mustNot = append(mustNot, map[string]any{"term": map[string]any{"del_user_ids": query.UserID}})
+	}
+	body, err := json.Marshal(map[string]any{
+		"from":             query.Offset,
+		"size":             query.Limit,
+		"track_total_hits": true,
+		"_source":          []string{"conversation_id", "seq"},
+		"sort":             []any{map[string]string{"send_time": "desc"}, map[string]string{"seq": "desc"}},
+		"query": map[string]any{
+			"bool": map[string]any{
+				"must": []any{
+					map[string]any{"match": map[string]any{"text": map[string]any{"query": query.Keyword, "operator": "and"}}},
+				},
+				"filter":   filter,
+				"must_not": mustNot,
+			},
+		},
+	})
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source elasticsearchDoc `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if _, err := e.do(ctx, http.MethodPost, fmt.Sprintf("/%s/_search", e.index), "application/json", body, &resp); err != nil {
+		return 0, nil, err
+	}
+	hits := make([]*Hit, 0, len(resp.Hits.Hits))
+	for _, hit := range resp.Hits.Hits {
+		hits = append(hits, &Hit{ConversationID: hit.Source.ConversationID, Seq: hit.Source.Seq})
+	}
+	return resp.Hits.Total.Value, hits, nil
+}
+
+// byQuery runs _delete_by_query or _update_by_query over the msgs of the conversation matched by seqQuery.
+func (e *elasticsearchIndex) byQuery(ctx context.Context, api string, conversationID string, seqQuery any, script any) error {
+	req := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": []any{
+					map[string]any{"term": map[string]any{"conversation_id": conversationID}},
+					seqQuery,
+				},
+			},
+		},
+	}
+	if script != nil {
+		req["script"] = script
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	_, err = e.do(ctx, http.MethodPost, fmt.Sprintf("/%s/%s?conflicts=proceed", e.index, api), "application/json", body, nil)
+	return err
+}
+
+func (e *elasticsearchIndex) Delete(ctx context.Context, conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	return e.byQuery(ctx, "_delete_by_query", conversationID, map[string]any{"terms": map[string]any{"seq": seqs}}, nil)
+}
+
+func (e *elasticsearchIndex) DeleteBefore(ctx context.Context, conversationID string, seq int64) error {
+	return e.byQuery(ctx, "_delete_by_query", conversationID, map[string]any{"range": map[string]any{"seq": map[string]any{"lt": seq}}}, nil)
+}
+
+func (e *elasticsearchIndex) DeleteForUser(ctx context.Context, userID string, conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	script := map[string]any{
+		"source": elasticsearchAddDelUserID,
+		"params": map[string]any{"userID": userID},
+	}
+	return e.byQuery(ctx, "_update_by_query", conversationID, map[string]any{"terms": map[string]any{"seq": seqs}}, script)
+}
@@ -0,0 +1,88 @@
+package msgsearch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+func TestElasticsearchVisibility(t *testing.T) {
+	bodies := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies[r.Method+" "+r.URL.Path] = string(data)
+		if strings.HasSuffix(r.URL.Path, "/_search") {
+			io.WriteString(w, `{"hits":{"total":{"value":1},"hits":[{"_source":{"conversation_id":"sg_1","seq":7}}]}}`)
+			return
+		}
+		io.WriteString(w, `{}`)
+	}))
+	defer srv.Close()
+	var conf config.MsgSearch
+	conf.Elasticsearch.Address = []string{srv.URL}
+	conf.Elasticsearch.Index = "msgs"
+	index, err := NewElasticsearchIndex(context.Background(), &conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bodies["PUT /msgs/_mapping"]; !ok {
+		t.Fatal("del_user_ids mapping not added to the existing index")
+	}
+	total, hits, err := index.Search(context.Background(), &Query{
+		ConversationIDs: []string{"sg_1", "si_a_b"},
+		Keyword:         "hello",
+		UserID:          "a",
+		MinSeqs:         map[string]int64{"sg_1": 5},
+		Limit:           10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 || len(hits) != 1 || hits[0].Seq != 7 {
+		t.Fatalf("total %d hits %+v", total, hits)
+	}
+	var search struct {
+		Query struct {
+			Bool struct {
+				Filter  []json.RawMessage `json:"filter"`
+				MustNot []json.RawMessage `json:"must_not"`
+			} `json:"bool"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(bodies["POST /msgs/_search"]), &search); err != nil {
+		t.Fatal(err)
+	}
+	if len(search.Query.Bool.MustNot) != 1 || string(search.Query.Bool.MustNot[0]) != `{"term":{"del_user_ids":"a"}}` {
+		t.Fatalf("must_not %s", search.Query.Bool.MustNot)
+	}
+	conversations := string(search.Query.Bool.Filter[0])
+	if !strings.Contains(conversations, `{"terms":{"conversation_id":["si_a_b"]}}`) ||
+		!strings.Contains(conversations, `{"term":{"conversation_id":"sg_1"}},{"range":{"seq":{"gte":5}}}`) {
+		t.Fatalf("conversation filter %s", conversations)
+	}
+
+	if err := index.Delete(context.Background(), "sg_1", []int64{7}); err != nil {
+		t.Fatal(err)
+	}
+	if body := bodies["POST /msgs/_delete_by_query"]; !strings.Contains(body, `{"terms":{"seq":[7]}}`) {
+		t.Fatalf("delete %s", body)
+	}
+	if err := index.DeleteBefore(context.Background(), "sg_1", 5); err != nil {
+		t.Fatal(err)
+	}
+	if body := bodies["POST /msgs/_delete_by_query"]; !strings.Contains(body, `{"range":{"seq":{"lt":5}}}`) {
+		t.Fatalf("delete before %s", body)
+	}
+	if err := index.DeleteForUser(context.Background(), "a", "sg_1", []int64{7}); err != nil {
+		t.Fatal(err)
+	}
+	if body := bodies["POST /msgs/_update_by_query"]; !strings.Contains(body, `"params":{"userID":"a"}`) {
+		t.Fatalf("delete for user %s", body)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgsearch
+
+import (
+	"context"
+
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const mongoIndexName = "msg_search"
+
+type mongoDoc struct {
+	ConversationID string   `bson:"conversation_id"`
+	Seq            int64    `bson:"seq"`
+	SendID         string   `bson:"send_id"`
+	ContentType    int32    `bson:"content_type"`
+	SendTime       int64    `bson:"send_time"`
+	Terms          []string `bson:"terms"`
+	// DelUserIDs are the users who deleted the msg for themselves.
+	DelUserIDs []string `bson:"del_user_ids,omitempty"`
+}
+
+// NewMongoIndex keeps an inverted index in MongoDB: every doc lists its terms and a multikey index maps terms to docs.
+func NewMongoIndex(ctx context.Context, db *mongo.Database) (MsgSearchIndex, error) {
+	coll := db.Collection(mongoIndexName)
+	_, err := coll.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "conversation_id", Value: 1},
+				{Key: "seq", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "terms", Value: 1},
+				{Key: "send_time", Value: -1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &mongoIndex{coll: coll}, nil
+}
+
+type mongoIndex struct {
+	coll *mongo.Collection
+}
+
+func (m *mongoIndex) Index(ctx context.Context, docs []*Doc) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		filter := bson.M{"conversation_id": doc.ConversationID, "seq": doc.Seq}
+		// $set keeps the del_user_ids of a reindexed msg
+		update := bson.M{"$set": bson.M{
+			"send_id":      doc.SendID,
+			"content_type": doc.ContentType,
+			"send_time":    doc.SendTime,
+			"terms":        Tokenize(doc.Text),
+		}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpsert(true).SetUpdate(update))
+	}
+	_, err := m.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return errs.Wrap(err)
+}
+
+func (m *mongoIndex) Search(ctx context.Context, query *Query) (int64, []*Hit, error) {
+	terms := QueryTerms(query.Keyword)
+	if len(terms) == 0 || len(query.ConversationIDs) == 0 {
+		return 0, nil, nil
+	}
+	filter := bson.M{"terms": bson.M{"$all": terms}}
+	unbounded, bounded := query.boundedConversationIDs()
+	if len(bounded) == 0 {
+		filter["conversation_id"] = bson.M{"$in": unbounded}
+	} else {
+		conversations := make(bson.A, 0, len(bounded)+1)
+		if len(unbounded) > 0 {
+			conversations = append(conversations, bson.M{"conversation_id": bson.M{"$in": unbounded}})
+		}
+		for conversationID, seq := range bounded {
+			conversations = append(conversations, bson.M{"conversation_id": conversationID, "seq": bson.M{"$gte": seq}})
+		}
+		filter["$or"] = conversations
+	}
+	if query.UserID != "" {
+		filter["del_user_ids"] = bson.M{"$ne": query.UserID}
+	}
+	if query.SendID != "" {
+		filter["send_id"] = query.SendID
+	}
+	if len(query.ContentTypes) > 0 {
+		filter["content_type"] = bson.M{"$in": query.ContentTypes}
+	}
+	sendTime := bson.M{}
+	if query.StartTime > 0 {
+		sendTime["$gte"] = query.StartTime
+	}
+	if query.EndTime > 0 {
+		sendTime["$lte"] = query.EndTime
+	}
+	if len(sendTime) > 0 {
+		filter["send_time"] = sendTime
+	}
+	total, err := mongoutil.Count(ctx, m.coll, filter)
+	if err != nil {
+		return 0, nil, err
+	}
+	opt := options.Find().
+		SetSort(bson.D{{Key: "send_time", Value: -1}, {Key: "seq", Value: -1}}).
+		SetSkip(int64(query.Offset)).
+		SetLimit(int64(query.Limit)).
+		SetProjection(bson.M{"_id": 0, "conversation_id": 1, "seq": 1})
+	docs, err := mongoutil.Find[*mongoDoc](ctx, m.coll, filter, opt)
+	if err != nil {
+		return 0, nil, err
+	}
+	hits := make([]*Hit, 0, len(docs))
+	for _, doc := range docs {
+		hits = append(hits, &Hit{ConversationID: doc.ConversationID, Seq: doc.Seq})
+	}
+	return total, hits, nil
+}
+
+func (m *mongoIndex) Delete(ctx context.Context, conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	return mongoutil.DeleteMany(ctx, m.coll, bson.M{"conversation_id": conversationID, "seq": bson.M{"$in": seqs}})
+}
+
+func (m *mongoIndex) DeleteBefore(ctx context.Context, conversationID string, seq int64) error {
+	return mongoutil.DeleteMany(ctx, m.coll, bson.M{"conversation_id": conversationID, "seq": bson.M{"$lt": seq}})
+}
+
+func (m *mongoIndex) DeleteForUser(ctx context.Context, userID string, conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	filter := bson.M{"conversation_id": conversationID, "seq": bson.M{"$in": seqs}}
+	return mongoutil.Ignore(mongoutil.UpdateMany(ctx, m.coll, filter, bson.M{"$addToSet": bson.M{"del_user_ids": userID}}))
+}
@@ -0,0 +1,132 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgsearch indexes message text for keyword search.
+// Msgs deleted for everyone, revoked or destructed are removed from the index,
+// msgs a user deleted for themselves are marked on the doc, and per-user min seqs are passed with the query,
+// so pagination and totals only count the msgs the user can see.
+package msgsearch
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/protocol/sdkws"
+	"github.com/openimsdk/tools/errs"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	BackendMongo         = "mongo"
+	BackendElasticsearch = "elasticsearch"
+)
+
+type Doc struct {
+	ConversationID string
+	Seq            int64
+	SendID         string
+	ContentType    int32
+	SendTime       int64
+	Text           string
+}
+
+type Query struct {
+	ConversationIDs []string
+	Keyword         string
+	SendID          string
+	ContentTypes    []int32
+	// StartTime and EndTime bound the send time in unix milli, 0 means unbounded.
+	StartTime int64
+	EndTime   int64
+	// UserID hides the msgs the user deleted for themselves.
+	UserID string
+	// MinSeqs hides the msgs of a conversation below its seq, such as those the user cleared.
+	MinSeqs map[string]int64
+	Offset  int
+	Limit   int
+}
+
+// boundedConversationIDs splits the conversations into those searched from their first msg and those with a min seq.
+func (q *Query) boundedConversationIDs() ([]string, map[string]int64) {
+	var (
+		unbounded []string
+		bounded   = make(map[string]int64)
+	)
+	for _, conversationID := range q.ConversationIDs {
+		if seq := q.MinSeqs[conversationID]; seq > 0 {
+			bounded[conversationID] = seq
+		} else {
+			unbounded = append(unbounded, conversationID)
+		}
+	}
+	return unbounded, bounded
+}
+
+type Hit struct {
+	ConversationID string
+	Seq            int64
+}
+
+// MsgSearchIndex is the index backend, hits are sorted by send time, newest first.
+type MsgSearchIndex interface {
+	// Index adds docs, replacing the text of those already indexed at the same conversationID and seq.
+	Index(ctx context.Context, docs []*Doc) error
+	Search(ctx context.Context, query *Query) (int64, []*Hit, error)
+	// Delete removes the msgs deleted for everyone or revoked.
+	Delete(ctx context.Context, conversationID string, seqs []int64) error
+	// DeleteBefore removes the msgs of the conversation below seq, when its min seq is raised.
+	DeleteBefore(ctx context.Context, conversationID string, seq int64) error
+	// DeleteForUser hides the msgs from the searches of the user who deleted them for themselves.
+	DeleteForUser(ctx context.Context, userID string, conversationID string, seqs []int64) error
+}
+
+// NewMsgSearchIndex returns the configured backend, keeping the docs it fails to index pending in db.
+func NewMsgSearchIndex(ctx context.Context, conf *config.MsgSearch, db *mongo.Database) (*PendingIndex, error) {
+	var (
+		index MsgSearchIndex
+		err   error
+	)
+	switch conf.Backend {
+	case BackendMongo, "":
+		index, err = NewMongoIndex(ctx, db)
+	case BackendElasticsearch:
+		index, err = NewElasticsearchIndex(ctx, conf)
+	default:
+		return nil, errs.ErrArgs.WrapMsg("unknown msg search backend", "backend", conf.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewPendingIndex(ctx, index, db)
+}
+
+// NewDocs returns the docs of the searchable msgs of a conversation.
+func NewDocs(conversationID string, msgs []*sdkws.MsgData) []*Doc {
+	docs := make([]*Doc, 0, len(msgs))
+	for _, msg := range msgs {
+		text := MsgText(msg)
+		if text == "" {
+			continue
+		}
+		docs = append(docs, &Doc{
+			ConversationID: conversationID,
+			Seq:            msg.Seq,
+			SendID:         msg.SendID,
+			ContentType:    msg.ContentType,
+			SendTime:       msg.SendTime,
+			Text:           text,
+		})
+	}
+	return docs
+}
@@ -0,0 +1,176 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgsearch
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/errs"
+	"github.com/openimsdk/tools/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	pendingCollName = "msg_search_pending"
+	// pendingInterval is how often the docs that failed to index are indexed again.
+	pendingInterval = 30 * time.Second
+	pendingBatch    = 500
+)
+
+type pendingDoc struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	ConversationID string             `bson:"conversation_id"`
+	Seq            int64              `bson:"seq"`
+	SendID         string             `bson:"send_id"`
+	ContentType    int32              `bson:"content_type"`
+	SendTime       int64              `bson:"send_time"`
+	Text           string             `bson:"text"`
+	// DelUserIDs are the users who deleted the msg for themselves while it was pending.
+	DelUserIDs []string `bson:"del_user_ids,omitempty"`
+}
+
+// PendingIndex keeps the docs the backend failed to index in MongoDB until RunPending indexes them,
+// deletes applied meanwhile are applied to the kept docs as well.
+type PendingIndex struct {
+	MsgSearchIndex
+	coll *mongo.Collection
+}
+
+func NewPendingIndex(ctx context.Context, index MsgSearchIndex, db *mongo.Database) (*PendingIndex, error) {
+	coll := db.Collection(pendingCollName)
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "seq", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &PendingIndex{MsgSearchIndex: index, coll: coll}, nil
+}
+
+// Index keeps the docs to index them again when the backend fails, it only fails if they can not be kept.
+func (p *PendingIndex) Index(ctx context.Context, docs []*Doc) error {
+	err := p.MsgSearchIndex.Index(ctx, docs)
+	if err == nil {
+		return nil
+	}
+	log.ZWarn(ctx, "index msgs failed, keep them pending", err, "count", len(docs))
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		filter := bson.M{"conversation_id": doc.ConversationID, "seq": doc.Seq}
+		update := bson.M{"$set": bson.M{
+			"send_id":      doc.SendID,
+			"content_type": doc.ContentType,
+			"send_time":    doc.SendTime,
+			"text":         doc.Text,
+		}}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpsert(true).SetUpdate(update))
+	}
+	if _, err := p.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+func (p *PendingIndex) Delete(ctx context.Context, conversationID string, seqs []int64) error {
+	if err := mongoutil.DeleteMany(ctx, p.coll, bson.M{"conversation_id": conversationID, "seq": bson.M{"$in": seqs}}); err != nil {
+		return err
+	}
+	return p.MsgSearchIndex.Delete(ctx, conversationID, seqs)
+}
+
+func (p *PendingIndex) DeleteBefore(ctx context.Context, conversationID string, seq int64) error {
+	if err := mongoutil.DeleteMany(ctx, p.coll, bson.M{"conversation_id": conversationID, "seq": bson.M{"$lt": seq}}); err != nil {
+		return err
+	}
+	return p.MsgSearchIndex.DeleteBefore(ctx, conversationID, seq)
+}
+
+func (p *PendingIndex) DeleteForUser(ctx context.Context, userID string, conversationID string, seqs []int64) error {
+	filter := bson.M{"conversation_id": conversationID, "seq": bson.M{"$in": seqs}}
+	if _, err := mongoutil.UpdateMany(ctx, p.coll, filter, bson.M{"$addToSet": bson.M{"del_user_ids": userID}}); err != nil {
+		return err
+	}
+	return p.MsgSearchIndex.DeleteForUser(ctx, userID, conversationID, seqs)
+}
+
+// RunPending indexes the pending docs again every pendingInterval until ctx is done.
+func (p *PendingIndex) RunPending(ctx context.Context) {
+	ticker := time.NewTicker(pendingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := p.indexPending(ctx)
+			if err != nil {
+				log.ZWarn(ctx, "index pending msgs failed", err)
+				break
+			}
+			if n < pendingBatch {
+				break
+			}
+		}
+	}
+}
+
+// indexPending indexes a batch of the pending docs and returns how many it took.
+func (p *PendingIndex) indexPending(ctx context.Context) (int, error) {
+	pending, err := mongoutil.Find[*pendingDoc](ctx, p.coll, bson.M{}, options.Find().SetLimit(pendingBatch))
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	docs := make([]*Doc, 0, len(pending))
+	ids := make([]primitive.ObjectID, 0, len(pending))
+	for _, doc := range pending {
+		docs = append(docs, &Doc{
+			ConversationID: doc.ConversationID,
+			Seq:            doc.Seq,
+			SendID:         doc.SendID,
+			ContentType:    doc.ContentType,
+			SendTime:       doc.SendTime,
+			Text:           doc.Text,
+		})
+		ids = append(ids, doc.ID)
+	}
+	if err := p.MsgSearchIndex.Index(ctx, docs); err != nil {
+		return 0, err
+	}
+	for _, doc := range pending {
+		for _, userID := range doc.DelUserIDs {
+			if err := p.MsgSearchIndex.DeleteForUser(ctx, userID, doc.ConversationID, []int64{doc.Seq}); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := mongoutil.DeleteMany(ctx, p.coll, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgsearch
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// MsgText returns the searchable text of msg, empty for content types that carry none.
+func MsgText(msg *sdkws.MsgData) string {
+	var fields []string
+	switch msg.ContentType {
+	case constant.Text:
+		fields = []string{"content"}
+	case constant.AtText, constant.Quote, constant.AdvancedText:
+		fields = []string{"text"}
+	case constant.MarkdownText:
+		fields = []string{"content"}
+	case constant.File:
+		fields = []string{"fileName"}
+	case constant.Card:
+		fields = []string{"nickname"}
+	case constant.Location:
+		fields = []string{"description"}
+	case constant.Merger:
+		fields = []string{"title"}
+	default:
+		return ""
+	}
+	var content map[string]any
+	if err := json.Unmarshal(msg.Content, &content); err != nil {
+		return ""
+	}
+	texts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if text, ok := content[field].(string); ok && text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
@@ -0,0 +1,95 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxTermLength truncates words so a single long token can not bloat the index.
+const maxTermLength = 32
+
+// Tokenize splits text into the lowercase terms it is indexed under.
+// Runs of letters and digits form words, CJK text has no word boundaries and is indexed
+// as single characters and overlapping bigrams so that queries of any length match.
+func Tokenize(text string) []string {
+	return tokenize(text, false)
+}
+
+// QueryTerms splits keyword into the terms that must all be present in a matching doc.
+func QueryTerms(keyword string) []string {
+	return tokenize(keyword, true)
+}
+
+func tokenize(text string, query bool) []string {
+	var (
+		terms []string
+		seen  = make(map[string]struct{})
+		word  []rune
+		cjk   []rune
+	)
+	add := func(term string) {
+		if _, ok := seen[term]; ok {
+			return
+		}
+		seen[term] = struct{}{}
+		terms = append(terms, term)
+	}
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		if len(word) > maxTermLength {
+			word = word[:maxTermLength]
+		}
+		add(strings.ToLower(string(word)))
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 0 {
+			return
+		}
+		if len(cjk) == 1 || !query {
+			for _, r := range cjk {
+				add(string(r))
+			}
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			add(string(cjk[i : i+2]))
+		}
+		cjk = cjk[:0]
+	}
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return terms
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,40 @@
+package msgsearch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Hello, World hello", []string{"hello", "world"}},
+		{"开会时间", []string{"开", "会", "时", "间", "开会", "会时", "时间"}},
+		{"OpenIM消息", []string{"openim", "消", "息", "消息"}},
+		{"", nil},
+	}
+	for _, test := range tests {
+		if got := Tokenize(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Tokenize(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    []string
+	}{
+		{"会", []string{"会"}},
+		{"时间", []string{"时间"}},
+		{"开会时间", []string{"开会", "会时", "时间"}},
+		{"Meeting 时间", []string{"meeting", "时间"}},
+	}
+	for _, test := range tests {
+		if got := QueryTerms(test.keyword); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("QueryTerms(%q) = %v, want %v", test.keyword, got, test.want)
+		}
+	}
+}
@@ -19,9 +19,8 @@ import (
 	"encoding/json"
 	"time"
 
-	"google.golang.org/protobuf/proto"
-
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
 	"github.com/openimsdk/protocol/constant"
 	"github.com/openimsdk/protocol/msg"
 	"github.com/openimsdk/protocol/sdkws"
@@ -77,6 +76,8 @@ func newContentTypeConf(conf *config.Notification) map[int32]config.Notification
 		constant.MsgRevokeNotification:  {IsSendMsg: false, ReliabilityLevel: constant.ReliableNotificationNoMsg},
 		constant.HasReadReceipt:         {IsSendMsg: false, ReliabilityLevel: constant.ReliableNotificationNoMsg},
 		constant.DeleteMsgsNotification: {IsSendMsg: false, ReliabilityLevel: constant.ReliableNotificationNoMsg},
+		msgext.MsgModifyNotification:    {IsSendMsg: false, ReliabilityLevel: constant.ReliableNotificationNoMsg},
+		msgext.MsgReactionNotification:  {IsSendMsg: false, ReliabilityLevel: constant.UnreliableNotification},
 	}
 }
 
@@ -196,7 +197,7 @@ func WithSendMessage(sendMessage *bool) NotificationOptions {
 	}
 }
 
-func (s *NotificationSender) send(ctx context.Context, sendID, recvID string, contentType, sessionType int32, m proto.Message, opts ...NotificationOptions) {
+func (s *NotificationSender) send(ctx context.Context, sendID, recvID string, contentType, sessionType int32, m any, opts ...NotificationOptions) {
 	ctx = context.WithoutCancel(ctx)
 	ctx, cancel := context.WithTimeout(ctx, time.Second*time.Duration(5))
 	defer cancel()
@@ -253,13 +254,13 @@ func (s *NotificationSender) send(ctx context.Context, sendID, recvID string, co
 	}
 }
 
-func (s *NotificationSender) NotificationWithSessionType(ctx context.Context, sendID, recvID string, contentType, sessionType int32, m proto.Message, opts ...NotificationOptions) {
+func (s *NotificationSender) NotificationWithSessionType(ctx context.Context, sendID, recvID string, contentType, sessionType int32, m any, opts ...NotificationOptions) {
 	if err := s.queue.Push(func() { s.send(ctx, sendID, recvID, contentType, sessionType, m, opts...) }); err != nil {
 		log.ZWarn(ctx, "Push to queue failed", err, "sendID", sendID, "recvID", recvID, "msg", jsonutil.StructToJsonString(m))
 	}
 }
 
-func (s *NotificationSender) Notification(ctx context.Context, sendID, recvID string, contentType int32, m proto.Message, opts ...NotificationOptions) {
+func (s *NotificationSender) Notification(ctx context.Context, sendID, recvID string, contentType int32, m any, opts ...NotificationOptions) {
 	s.NotificationWithSessionType(ctx, sendID, recvID, contentType, s.sessionTypeConf[contentType], m, opts...)
 }
 
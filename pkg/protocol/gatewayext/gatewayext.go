@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gatewayext holds the msg gateway methods served next to github.com/openimsdk/protocol/msggateway.
+package gatewayext
+
+import "errors"
+
+// GatewayNode is a msg gateway instance, NodeID is its host name and websocket port.
+type GatewayNode struct {
+	NodeID        string `json:"nodeID"`
+	OnlineUserNum int64  `json:"onlineUserNum"`
+	OnlineConnNum int64  `json:"onlineConnNum"`
+	Draining      bool   `json:"draining"`
+}
+
+type GetGatewayNodeReq struct{}
+
+type GetGatewayNodeResp struct {
+	Node *GatewayNode `json:"node"`
+}
+
+type DrainGatewayReq struct {
+	NodeID string `json:"nodeID"`
+}
+
+func (x *DrainGatewayReq) Check() error {
+	if x.NodeID == "" {
+		return errors.New("nodeID is empty")
+	}
+	return nil
+}
+
+type DrainGatewayResp struct {
+	// Node is the drained gateway, nil if the node is another one.
+	Node *GatewayNode `json:"node"`
+}
+
+// EphemeralEvent is delivered to the online members of a conversation and never stored,
+// such as typing, a live location or a cursor. Its content is up to the clients.
+type EphemeralEvent struct {
+	Type             string `json:"type"`
+	SendID           string `json:"sendID"`
+	SenderPlatformID int32  `json:"senderPlatformID"`
+	RecvID           string `json:"recvID"`
+	GroupID          string `json:"groupID"`
+	SessionType      int32  `json:"sessionType"`
+	Content          string `json:"content"`
+	SendTime         int64  `json:"sendTime"`
+}
+
+type PushEphemeralEventReq struct {
+	Event         *EphemeralEvent `json:"event"`
+	PushToUserIDs []string        `json:"pushToUserIDs"`
+}
+
+type PushEphemeralEventResp struct{}
@@ -0,0 +1,102 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gatewayext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	MsgGatewayExt_GetGatewayNode_FullMethodName     = "/openim.gatewayext.MsgGatewayExt/GetGatewayNode"
+	MsgGatewayExt_DrainGateway_FullMethodName       = "/openim.gatewayext.MsgGatewayExt/DrainGateway"
+	MsgGatewayExt_PushEphemeralEvent_FullMethodName = "/openim.gatewayext.MsgGatewayExt/PushEphemeralEvent"
+)
+
+type MsgGatewayExtClient interface {
+	GetGatewayNode(ctx context.Context, in *GetGatewayNodeReq, opts ...grpc.CallOption) (*GetGatewayNodeResp, error)
+	DrainGateway(ctx context.Context, in *DrainGatewayReq, opts ...grpc.CallOption) (*DrainGatewayResp, error)
+	PushEphemeralEvent(ctx context.Context, in *PushEphemeralEventReq, opts ...grpc.CallOption) (*PushEphemeralEventResp, error)
+}
+
+type msgGatewayExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMsgGatewayExtClient(cc grpc.ClientConnInterface) MsgGatewayExtClient {
+	return &msgGatewayExtClient{cc}
+}
+
+func (c *msgGatewayExtClient) GetGatewayNode(ctx context.Context, in *GetGatewayNodeReq, opts ...grpc.CallOption) (*GetGatewayNodeResp, error) {
+	return jsonrpc.Invoke[GetGatewayNodeReq, GetGatewayNodeResp](ctx, c.cc, MsgGatewayExt_GetGatewayNode_FullMethodName, in, opts...)
+}
+
+func (c *msgGatewayExtClient) DrainGateway(ctx context.Context, in *DrainGatewayReq, opts ...grpc.CallOption) (*DrainGatewayResp, error) {
+	return jsonrpc.Invoke[DrainGatewayReq, DrainGatewayResp](ctx, c.cc, MsgGatewayExt_DrainGateway_FullMethodName, in, opts...)
+}
+
+func (c *msgGatewayExtClient) PushEphemeralEvent(ctx context.Context, in *PushEphemeralEventReq, opts ...grpc.CallOption) (*PushEphemeralEventResp, error) {
+	return jsonrpc.Invoke[PushEphemeralEventReq, PushEphemeralEventResp](ctx, c.cc, MsgGatewayExt_PushEphemeralEvent_FullMethodName, in, opts...)
+}
+
+type MsgGatewayExtServer interface {
+	GetGatewayNode(context.Context, *GetGatewayNodeReq) (*GetGatewayNodeResp, error)
+	DrainGateway(context.Context, *DrainGatewayReq) (*DrainGatewayResp, error)
+	PushEphemeralEvent(context.Context, *PushEphemeralEventReq) (*PushEphemeralEventResp, error)
+}
+
+// UnimplementedMsgGatewayExtServer can be embedded to have forward compatible implementations.
+type UnimplementedMsgGatewayExtServer struct{}
+
+func (UnimplementedMsgGatewayExtServer) GetGatewayNode(context.Context, *GetGatewayNodeReq) (*GetGatewayNodeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGatewayNode not implemented")
+}
+
+func (UnimplementedMsgGatewayExtServer) DrainGateway(context.Context, *DrainGatewayReq) (*DrainGatewayResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainGateway not implemented")
+}
+
+func (UnimplementedMsgGatewayExtServer) PushEphemeralEvent(context.Context, *PushEphemeralEventReq) (*PushEphemeralEventResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PushEphemeralEvent not implemented")
+}
+
+func RegisterMsgGatewayExtServer(s grpc.ServiceRegistrar, srv MsgGatewayExtServer) {
+	s.RegisterService(&MsgGatewayExt_ServiceDesc, srv)
+}
+
+var MsgGatewayExt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "openim.gatewayext.MsgGatewayExt",
+	HandlerType: (*MsgGatewayExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetGatewayNode",
+			Handler:    jsonrpc.UnaryHandler(MsgGatewayExt_GetGatewayNode_FullMethodName, MsgGatewayExtServer.GetGatewayNode),
+		},
+		{
+			MethodName: "DrainGateway",
+			Handler:    jsonrpc.UnaryHandler(MsgGatewayExt_DrainGateway_FullMethodName, MsgGatewayExtServer.DrainGateway),
+		},
+		{
+			MethodName: "PushEphemeralEvent",
+			Handler:    jsonrpc.UnaryHandler(MsgGatewayExt_PushEphemeralEvent_FullMethodName, MsgGatewayExtServer.PushEphemeralEvent),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gatewayext",
+}
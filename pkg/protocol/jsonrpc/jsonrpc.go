@@ -0,0 +1,78 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonrpc carries the server side protocol extensions that are not (yet) part of
+// github.com/openimsdk/protocol. The extension services are plain Go structs sent over the
+// existing gRPC connections with a JSON codec instead of protobuf.
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// Name is the gRPC content-subtype of the JSON codec.
+const Name = "json"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+// Invoke calls a unary extension method on cc using the JSON codec.
+func Invoke[Req, Resp any](ctx context.Context, cc grpc.ClientConnInterface, method string, req *Req, opts ...grpc.CallOption) (*Resp, error) {
+	resp := new(Resp)
+	opts = append(opts, grpc.CallContentSubtype(Name))
+	if err := cc.Invoke(ctx, method, req, resp, opts...); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UnaryHandler builds the grpc.MethodHandler of an extension method implemented by server S.
+func UnaryHandler[S, Req, Resp any](method string, fn func(S, context.Context, *Req) (*Resp, error)) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(Req)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return fn(srv.(S), ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: method,
+		}
+		handler := func(ctx context.Context, req any) (any, error) {
+			return fn(srv.(S), ctx, req.(*Req))
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
@@ -0,0 +1,56 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type msgExtServer struct {
+	msgext.UnimplementedMsgExtServer
+}
+
+func (msgExtServer) ModifyMsg(_ context.Context, req *msgext.ModifyMsgReq) (*msgext.ModifyMsgResp, error) {
+	return &msgext.ModifyMsgResp{ModifyTime: req.Seq}, nil
+}
+
+func TestInvoke(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	var fullMethod string
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		fullMethod = info.FullMethod
+		return handler(ctx, req)
+	}))
+	msgext.RegisterMsgExtServer(server, msgExtServer{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := msgext.NewMsgExtClient(conn)
+	resp, err := client.ModifyMsg(context.Background(), &msgext.ModifyMsgReq{ConversationID: "si_1_2", Seq: 7, UserID: "1", Content: "{}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ModifyTime != 7 {
+		t.Fatalf("unexpected resp %+v", resp)
+	}
+	if fullMethod != msgext.MsgExt_ModifyMsg_FullMethodName {
+		t.Fatalf("unexpected method %s", fullMethod)
+	}
+	if _, err := client.GetMsgModifyHistory(context.Background(), &msgext.GetMsgModifyHistoryReq{}); err == nil {
+		t.Fatal("expected unimplemented error")
+	}
+}
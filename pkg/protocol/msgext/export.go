@@ -0,0 +1,176 @@
+package msgext
+
+import (
+	"errors"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+type ExportedRevoke struct {
+	Role     int32  `json:"role"`
+	UserID   string `json:"userID"`
+	Nickname string `json:"nickname"`
+	Time     int64  `json:"time"`
+}
+
+type ExportedModify struct {
+	Content string `json:"content"`
+	UserID  string `json:"userID"`
+	Time    int64  `json:"time"`
+}
+
+type ExportedReaction struct {
+	UserID string `json:"userID"`
+	Emoji  string `json:"emoji"`
+	Time   int64  `json:"time"`
+}
+
+// ExportedMsg is a stored msg together with everything that happened to it.
+type ExportedMsg struct {
+	SendID           string              `json:"sendID"`
+	RecvID           string              `json:"recvID"`
+	GroupID          string              `json:"groupID"`
+	ClientMsgID      string              `json:"clientMsgID"`
+	ServerMsgID      string              `json:"serverMsgID"`
+	SenderPlatformID int32               `json:"senderPlatformID"`
+	SenderNickname   string              `json:"senderNickname"`
+	SenderFaceURL    string              `json:"senderFaceURL"`
+	SessionType      int32               `json:"sessionType"`
+	MsgFrom          int32               `json:"msgFrom"`
+	ContentType      int32               `json:"contentType"`
+	Content          string              `json:"content"`
+	Seq              int64               `json:"seq"`
+	SendTime         int64               `json:"sendTime"`
+	CreateTime       int64               `json:"createTime"`
+	Status           int32               `json:"status"`
+	IsRead           bool                `json:"isRead"`
+	Options          map[string]bool     `json:"options,omitempty"`
+	AtUserIDList     []string            `json:"atUserIDList,omitempty"`
+	AttachedInfo     string              `json:"attachedInfo,omitempty"`
+	Ex               string              `json:"ex,omitempty"`
+	Revoke           *ExportedRevoke     `json:"revoke,omitempty"`
+	Modify           []*ExportedModify   `json:"modify,omitempty"`
+	Reactions        []*ExportedReaction `json:"reactions,omitempty"`
+	// DeletedBy are the users who deleted the msg for themselves.
+	DeletedBy []string `json:"deletedBy,omitempty"`
+}
+
+type ExportedReadState struct {
+	UserID     string `json:"userID"`
+	HasReadSeq int64  `json:"hasReadSeq"`
+}
+
+type GetExportConversationReq struct {
+	ConversationID string `json:"conversationID"`
+}
+
+func (x *GetExportConversationReq) Check() error {
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	return nil
+}
+
+type GetExportConversationResp struct {
+	ConversationID string               `json:"conversationID"`
+	MinSeq         int64                `json:"minSeq"`
+	MaxSeq         int64                `json:"maxSeq"`
+	ReadStates     []*ExportedReadState `json:"readStates"`
+}
+
+type GetExportMsgDocReq struct {
+	ConversationID string `json:"conversationID"`
+	// Index is the doc index, a doc holds the msgs of seqs index*100+1 to index*100+100.
+	Index int64 `json:"index"`
+}
+
+func (x *GetExportMsgDocReq) Check() error {
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if x.Index < 0 {
+		return errors.New("index is invalid")
+	}
+	return nil
+}
+
+type GetExportMsgDocResp struct {
+	Msgs []*ExportedMsg `json:"msgs"`
+}
+
+type ImportMsgsReq struct {
+	// ImportID names the import, the checkpoint of each conversation is kept under it.
+	ImportID       string `json:"importID"`
+	ConversationID string `json:"conversationID"`
+	// Offset is the position of the first of Msgs among all the source msgs of the conversation.
+	Offset int64          `json:"offset"`
+	Msgs   []*ExportedMsg `json:"msgs"`
+}
+
+func (x *ImportMsgsReq) Check() error {
+	if x.ImportID == "" {
+		return errors.New("importID is empty")
+	}
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if x.Offset < 0 {
+		return errors.New("offset is invalid")
+	}
+	if len(x.Msgs) == 0 {
+		return errors.New("msgs is empty")
+	}
+	if len(x.Msgs) > 1000 {
+		return errors.New("too many msgs")
+	}
+	for _, msg := range x.Msgs {
+		if msg == nil {
+			return errors.New("msg is nil")
+		}
+		if msg.SendID == "" {
+			return errors.New("sendID is empty")
+		}
+		if msg.SendTime <= 0 {
+			return errors.New("sendTime is invalid")
+		}
+	}
+	return nil
+}
+
+type ImportCheckpoint struct {
+	ImportID       string `json:"importID"`
+	ConversationID string `json:"conversationID"`
+	// Offset is the number of source msgs imported, the next batch starts there.
+	Offset     int64 `json:"offset"`
+	LastSeq    int64 `json:"lastSeq"`
+	UpdateTime int64 `json:"updateTime"`
+}
+
+type ImportMsgsResp struct {
+	Checkpoint *ImportCheckpoint `json:"checkpoint"`
+}
+
+type GetImportCheckpointsReq struct {
+	ImportID string `json:"importID"`
+	// ConversationID returns the checkpoint of one conversation, empty pages through all of the import.
+	ConversationID string                   `json:"conversationID"`
+	Pagination     *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetImportCheckpointsReq) Check() error {
+	if x.ConversationID != "" {
+		if x.ImportID == "" {
+			return errors.New("importID is empty")
+		}
+		return nil
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetImportCheckpointsResp struct {
+	Total       int64               `json:"total"`
+	Checkpoints []*ImportCheckpoint `json:"checkpoints"`
+}
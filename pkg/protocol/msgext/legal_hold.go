@@ -0,0 +1,119 @@
+package msgext
+
+import (
+	"errors"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+const (
+	// LegalHoldTypeUser holds the conversations of a user and the files they uploaded.
+	LegalHoldTypeUser = "user"
+	// LegalHoldTypeConversation holds the msgs of a conversation.
+	LegalHoldTypeConversation = "conversation"
+)
+
+type LegalHold struct {
+	HoldID        string `json:"holdID"`
+	Type          string `json:"type"`
+	Target        string `json:"target"`
+	Reason        string `json:"reason"`
+	PlaceUserID   string `json:"placeUserID"`
+	PlaceTime     int64  `json:"placeTime"`
+	Released      bool   `json:"released"`
+	ReleaseUserID string `json:"releaseUserID"`
+	ReleaseTime   int64  `json:"releaseTime"`
+}
+
+type LegalHoldLog struct {
+	HoldID         string `json:"holdID"`
+	Action         string `json:"action"`
+	Type           string `json:"type"`
+	Target         string `json:"target"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	Time           int64  `json:"time"`
+}
+
+type PlaceLegalHoldReq struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+func (x *PlaceLegalHoldReq) Check() error {
+	switch x.Type {
+	case LegalHoldTypeUser, LegalHoldTypeConversation:
+	default:
+		return errors.New("type is invalid")
+	}
+	if x.Target == "" {
+		return errors.New("target is empty")
+	}
+	if x.Reason == "" {
+		return errors.New("reason is empty")
+	}
+	return nil
+}
+
+type PlaceLegalHoldResp struct {
+	HoldID string `json:"holdID"`
+}
+
+type ReleaseLegalHoldReq struct {
+	HoldID string `json:"holdID"`
+	Reason string `json:"reason"`
+}
+
+func (x *ReleaseLegalHoldReq) Check() error {
+	if x.HoldID == "" {
+		return errors.New("holdID is empty")
+	}
+	if x.Reason == "" {
+		return errors.New("reason is empty")
+	}
+	return nil
+}
+
+type ReleaseLegalHoldResp struct{}
+
+type GetLegalHoldsReq struct {
+	IncludeReleased bool                     `json:"includeReleased"`
+	Pagination      *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetLegalHoldsReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetLegalHoldsResp struct {
+	Total int64        `json:"total"`
+	Holds []*LegalHold `json:"holds"`
+}
+
+type GetLegalHoldLogsReq struct {
+	// HoldID filters the audit trail of one hold, empty returns all.
+	HoldID     string                   `json:"holdID"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetLegalHoldLogsReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetLegalHoldLogsResp struct {
+	Total int64           `json:"total"`
+	Logs  []*LegalHoldLog `json:"logs"`
+}
+
+// SyncLegalHeldObjectsReq records the objects referenced by the msgs of held conversations that were not recorded yet,
+// the third service keeps the recorded objects when deleting outdated objects.
+type SyncLegalHeldObjectsReq struct{}
+
+type SyncLegalHeldObjectsResp struct{}
@@ -0,0 +1,167 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgext holds the msg service methods served next to github.com/openimsdk/protocol/msg.
+package msgext
+
+import "errors"
+
+const (
+	// MsgModifyNotification follows constant.DeleteMsgsNotification in the msg notification range.
+	MsgModifyNotification   = 2103
+	MsgReactionNotification = 2104
+)
+
+// AttachedInfoReactionsKey is the attachedInfo field carrying the aggregated []*MsgReaction of a pulled message.
+const AttachedInfoReactionsKey = "reactions"
+
+const maxEmojiLength = 64
+
+type ModifyMsgReq struct {
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	UserID         string `json:"userID"`
+	Content        string `json:"content"`
+}
+
+func (x *ModifyMsgReq) Check() error {
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if x.Seq <= 0 {
+		return errors.New("seq is invalid")
+	}
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.Content == "" {
+		return errors.New("content is empty")
+	}
+	return nil
+}
+
+type ModifyMsgResp struct {
+	ModifyTime int64 `json:"modifyTime"`
+}
+
+type GetMsgModifyHistoryReq struct {
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	UserID         string `json:"userID"`
+}
+
+func (x *GetMsgModifyHistoryReq) Check() error {
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if x.Seq <= 0 {
+		return errors.New("seq is invalid")
+	}
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	return nil
+}
+
+// MsgVersion is a previous content of an edited message, replaced by ModifierUserID at ModifyTime.
+type MsgVersion struct {
+	Content        string `json:"content"`
+	ModifierUserID string `json:"modifierUserID"`
+	ModifyTime     int64  `json:"modifyTime"`
+}
+
+// GetMsgModifyHistoryResp lists Versions from the oldest to the latest, Content is the current one.
+type GetMsgModifyHistoryResp struct {
+	Content  string        `json:"content"`
+	Versions []*MsgVersion `json:"versions"`
+}
+
+// ModifyMsgTips is the detail of a MsgModifyNotification.
+type ModifyMsgTips struct {
+	ModifierUserID string `json:"modifierUserID"`
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	ClientMsgID    string `json:"clientMsgID"`
+	SessionType    int32  `json:"sessionType"`
+	Content        string `json:"content"`
+	ModifyTime     int64  `json:"modifyTime"`
+}
+
+type AddMsgReactionReq struct {
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	UserID         string `json:"userID"`
+	Emoji          string `json:"emoji"`
+}
+
+func (x *AddMsgReactionReq) Check() error {
+	return checkReaction(x.ConversationID, x.Seq, x.UserID, x.Emoji)
+}
+
+type AddMsgReactionResp struct {
+	Reactions []*MsgReaction `json:"reactions"`
+}
+
+type DeleteMsgReactionReq struct {
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	UserID         string `json:"userID"`
+	Emoji          string `json:"emoji"`
+}
+
+func (x *DeleteMsgReactionReq) Check() error {
+	return checkReaction(x.ConversationID, x.Seq, x.UserID, x.Emoji)
+}
+
+type DeleteMsgReactionResp struct {
+	Reactions []*MsgReaction `json:"reactions"`
+}
+
+func checkReaction(conversationID string, seq int64, userID string, emoji string) error {
+	if conversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if seq <= 0 {
+		return errors.New("seq is invalid")
+	}
+	if userID == "" {
+		return errors.New("userID is empty")
+	}
+	if emoji == "" {
+		return errors.New("emoji is empty")
+	}
+	if len(emoji) > maxEmojiLength {
+		return errors.New("emoji is too long")
+	}
+	return nil
+}
+
+// MsgReaction is the aggregated count of one emoji on a message, Reacted tells whether the requesting user is among them.
+type MsgReaction struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted,omitempty"`
+}
+
+// MsgReactionTips is the detail of a MsgReactionNotification.
+type MsgReactionTips struct {
+	UserID         string         `json:"userID"`
+	ConversationID string         `json:"conversationID"`
+	Seq            int64          `json:"seq"`
+	ClientMsgID    string         `json:"clientMsgID"`
+	SessionType    int32          `json:"sessionType"`
+	Emoji          string         `json:"emoji"`
+	IsDelete       bool           `json:"isDelete"`
+	Reactions      []*MsgReaction `json:"reactions"`
+}
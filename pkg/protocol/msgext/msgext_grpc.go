@@ -0,0 +1,436 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	MsgExt_ModifyMsg_FullMethodName                  = "/openim.msgext.MsgExt/ModifyMsg"
+	MsgExt_GetMsgModifyHistory_FullMethodName        = "/openim.msgext.MsgExt/GetMsgModifyHistory"
+	MsgExt_AddMsgReaction_FullMethodName             = "/openim.msgext.MsgExt/AddMsgReaction"
+	MsgExt_DeleteMsgReaction_FullMethodName          = "/openim.msgext.MsgExt/DeleteMsgReaction"
+	MsgExt_GetThreadReplies_FullMethodName           = "/openim.msgext.MsgExt/GetThreadReplies"
+	MsgExt_SetThreadHasReadSeq_FullMethodName        = "/openim.msgext.MsgExt/SetThreadHasReadSeq"
+	MsgExt_GetThreadsHasReadAndMaxSeq_FullMethodName = "/openim.msgext.MsgExt/GetThreadsHasReadAndMaxSeq"
+	MsgExt_ScheduleMsg_FullMethodName                = "/openim.msgext.MsgExt/ScheduleMsg"
+	MsgExt_CancelScheduledMsg_FullMethodName         = "/openim.msgext.MsgExt/CancelScheduledMsg"
+	MsgExt_ListScheduledMsgs_FullMethodName          = "/openim.msgext.MsgExt/ListScheduledMsgs"
+	MsgExt_DispatchScheduledMsgs_FullMethodName      = "/openim.msgext.MsgExt/DispatchScheduledMsgs"
+	MsgExt_SearchMsg_FullMethodName                  = "/openim.msgext.MsgExt/SearchMsg"
+	MsgExt_SetRetentionRule_FullMethodName           = "/openim.msgext.MsgExt/SetRetentionRule"
+	MsgExt_DeleteRetentionRule_FullMethodName        = "/openim.msgext.MsgExt/DeleteRetentionRule"
+	MsgExt_GetRetentionRules_FullMethodName          = "/openim.msgext.MsgExt/GetRetentionRules"
+	MsgExt_GetRetentionReport_FullMethodName         = "/openim.msgext.MsgExt/GetRetentionReport"
+	MsgExt_ApplyRetentionRules_FullMethodName        = "/openim.msgext.MsgExt/ApplyRetentionRules"
+	MsgExt_PlaceLegalHold_FullMethodName             = "/openim.msgext.MsgExt/PlaceLegalHold"
+	MsgExt_ReleaseLegalHold_FullMethodName           = "/openim.msgext.MsgExt/ReleaseLegalHold"
+	MsgExt_GetLegalHolds_FullMethodName              = "/openim.msgext.MsgExt/GetLegalHolds"
+	MsgExt_GetLegalHoldLogs_FullMethodName           = "/openim.msgext.MsgExt/GetLegalHoldLogs"
+	MsgExt_SyncLegalHeldObjects_FullMethodName       = "/openim.msgext.MsgExt/SyncLegalHeldObjects"
+	MsgExt_GetExportConversation_FullMethodName      = "/openim.msgext.MsgExt/GetExportConversation"
+	MsgExt_GetExportMsgDoc_FullMethodName            = "/openim.msgext.MsgExt/GetExportMsgDoc"
+	MsgExt_ImportMsgs_FullMethodName                 = "/openim.msgext.MsgExt/ImportMsgs"
+	MsgExt_GetImportCheckpoints_FullMethodName       = "/openim.msgext.MsgExt/GetImportCheckpoints"
+)
+
+type MsgExtClient interface {
+	ModifyMsg(ctx context.Context, in *ModifyMsgReq, opts ...grpc.CallOption) (*ModifyMsgResp, error)
+	GetMsgModifyHistory(ctx context.Context, in *GetMsgModifyHistoryReq, opts ...grpc.CallOption) (*GetMsgModifyHistoryResp, error)
+	AddMsgReaction(ctx context.Context, in *AddMsgReactionReq, opts ...grpc.CallOption) (*AddMsgReactionResp, error)
+	DeleteMsgReaction(ctx context.Context, in *DeleteMsgReactionReq, opts ...grpc.CallOption) (*DeleteMsgReactionResp, error)
+	GetThreadReplies(ctx context.Context, in *GetThreadRepliesReq, opts ...grpc.CallOption) (*GetThreadRepliesResp, error)
+	SetThreadHasReadSeq(ctx context.Context, in *SetThreadHasReadSeqReq, opts ...grpc.CallOption) (*SetThreadHasReadSeqResp, error)
+	GetThreadsHasReadAndMaxSeq(ctx context.Context, in *GetThreadsHasReadAndMaxSeqReq, opts ...grpc.CallOption) (*GetThreadsHasReadAndMaxSeqResp, error)
+	ScheduleMsg(ctx context.Context, in *ScheduleMsgReq, opts ...grpc.CallOption) (*ScheduleMsgResp, error)
+	CancelScheduledMsg(ctx context.Context, in *CancelScheduledMsgReq, opts ...grpc.CallOption) (*CancelScheduledMsgResp, error)
+	ListScheduledMsgs(ctx context.Context, in *ListScheduledMsgsReq, opts ...grpc.CallOption) (*ListScheduledMsgsResp, error)
+	DispatchScheduledMsgs(ctx context.Context, in *DispatchScheduledMsgsReq, opts ...grpc.CallOption) (*DispatchScheduledMsgsResp, error)
+	SearchMsg(ctx context.Context, in *SearchMsgReq, opts ...grpc.CallOption) (*SearchMsgResp, error)
+	SetRetentionRule(ctx context.Context, in *SetRetentionRuleReq, opts ...grpc.CallOption) (*SetRetentionRuleResp, error)
+	DeleteRetentionRule(ctx context.Context, in *DeleteRetentionRuleReq, opts ...grpc.CallOption) (*DeleteRetentionRuleResp, error)
+	GetRetentionRules(ctx context.Context, in *GetRetentionRulesReq, opts ...grpc.CallOption) (*GetRetentionRulesResp, error)
+	GetRetentionReport(ctx context.Context, in *GetRetentionReportReq, opts ...grpc.CallOption) (*GetRetentionReportResp, error)
+	ApplyRetentionRules(ctx context.Context, in *ApplyRetentionRulesReq, opts ...grpc.CallOption) (*ApplyRetentionRulesResp, error)
+	PlaceLegalHold(ctx context.Context, in *PlaceLegalHoldReq, opts ...grpc.CallOption) (*PlaceLegalHoldResp, error)
+	ReleaseLegalHold(ctx context.Context, in *ReleaseLegalHoldReq, opts ...grpc.CallOption) (*ReleaseLegalHoldResp, error)
+	GetLegalHolds(ctx context.Context, in *GetLegalHoldsReq, opts ...grpc.CallOption) (*GetLegalHoldsResp, error)
+	GetLegalHoldLogs(ctx context.Context, in *GetLegalHoldLogsReq, opts ...grpc.CallOption) (*GetLegalHoldLogsResp, error)
+	SyncLegalHeldObjects(ctx context.Context, in *SyncLegalHeldObjectsReq, opts ...grpc.CallOption) (*SyncLegalHeldObjectsResp, error)
+	GetExportConversation(ctx context.Context, in *GetExportConversationReq, opts ...grpc.CallOption) (*GetExportConversationResp, error)
+	GetExportMsgDoc(ctx context.Context, in *GetExportMsgDocReq, opts ...grpc.CallOption) (*GetExportMsgDocResp, error)
+	ImportMsgs(ctx context.Context, in *ImportMsgsReq, opts ...grpc.CallOption) (*ImportMsgsResp, error)
+	GetImportCheckpoints(ctx context.Context, in *GetImportCheckpointsReq, opts ...grpc.CallOption) (*GetImportCheckpointsResp, error)
+}
+
+type msgExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMsgExtClient(cc grpc.ClientConnInterface) MsgExtClient {
+	return &msgExtClient{cc}
+}
+
+func (c *msgExtClient) ModifyMsg(ctx context.Context, in *ModifyMsgReq, opts ...grpc.CallOption) (*ModifyMsgResp, error) {
+	return jsonrpc.Invoke[ModifyMsgReq, ModifyMsgResp](ctx, c.cc, MsgExt_ModifyMsg_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetMsgModifyHistory(ctx context.Context, in *GetMsgModifyHistoryReq, opts ...grpc.CallOption) (*GetMsgModifyHistoryResp, error) {
+	return jsonrpc.Invoke[GetMsgModifyHistoryReq, GetMsgModifyHistoryResp](ctx, c.cc, MsgExt_GetMsgModifyHistory_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) AddMsgReaction(ctx context.Context, in *AddMsgReactionReq, opts ...grpc.CallOption) (*AddMsgReactionResp, error) {
+	return jsonrpc.Invoke[AddMsgReactionReq, AddMsgReactionResp](ctx, c.cc, MsgExt_AddMsgReaction_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) DeleteMsgReaction(ctx context.Context, in *DeleteMsgReactionReq, opts ...grpc.CallOption) (*DeleteMsgReactionResp, error) {
+	return jsonrpc.Invoke[DeleteMsgReactionReq, DeleteMsgReactionResp](ctx, c.cc, MsgExt_DeleteMsgReaction_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetThreadReplies(ctx context.Context, in *GetThreadRepliesReq, opts ...grpc.CallOption) (*GetThreadRepliesResp, error) {
+	return jsonrpc.Invoke[GetThreadRepliesReq, GetThreadRepliesResp](ctx, c.cc, MsgExt_GetThreadReplies_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) SetThreadHasReadSeq(ctx context.Context, in *SetThreadHasReadSeqReq, opts ...grpc.CallOption) (*SetThreadHasReadSeqResp, error) {
+	return jsonrpc.Invoke[SetThreadHasReadSeqReq, SetThreadHasReadSeqResp](ctx, c.cc, MsgExt_SetThreadHasReadSeq_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetThreadsHasReadAndMaxSeq(ctx context.Context, in *GetThreadsHasReadAndMaxSeqReq, opts ...grpc.CallOption) (*GetThreadsHasReadAndMaxSeqResp, error) {
+	return jsonrpc.Invoke[GetThreadsHasReadAndMaxSeqReq, GetThreadsHasReadAndMaxSeqResp](ctx, c.cc, MsgExt_GetThreadsHasReadAndMaxSeq_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) ScheduleMsg(ctx context.Context, in *ScheduleMsgReq, opts ...grpc.CallOption) (*ScheduleMsgResp, error) {
+	return jsonrpc.Invoke[ScheduleMsgReq, ScheduleMsgResp](ctx, c.cc, MsgExt_ScheduleMsg_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) CancelScheduledMsg(ctx context.Context, in *CancelScheduledMsgReq, opts ...grpc.CallOption) (*CancelScheduledMsgResp, error) {
+	return jsonrpc.Invoke[CancelScheduledMsgReq, CancelScheduledMsgResp](ctx, c.cc, MsgExt_CancelScheduledMsg_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) ListScheduledMsgs(ctx context.Context, in *ListScheduledMsgsReq, opts ...grpc.CallOption) (*ListScheduledMsgsResp, error) {
+	return jsonrpc.Invoke[ListScheduledMsgsReq, ListScheduledMsgsResp](ctx, c.cc, MsgExt_ListScheduledMsgs_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) DispatchScheduledMsgs(ctx context.Context, in *DispatchScheduledMsgsReq, opts ...grpc.CallOption) (*DispatchScheduledMsgsResp, error) {
+	return jsonrpc.Invoke[DispatchScheduledMsgsReq, DispatchScheduledMsgsResp](ctx, c.cc, MsgExt_DispatchScheduledMsgs_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) SearchMsg(ctx context.Context, in *SearchMsgReq, opts ...grpc.CallOption) (*SearchMsgResp, error) {
+	return jsonrpc.Invoke[SearchMsgReq, SearchMsgResp](ctx, c.cc, MsgExt_SearchMsg_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) SetRetentionRule(ctx context.Context, in *SetRetentionRuleReq, opts ...grpc.CallOption) (*SetRetentionRuleResp, error) {
+	return jsonrpc.Invoke[SetRetentionRuleReq, SetRetentionRuleResp](ctx, c.cc, MsgExt_SetRetentionRule_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) DeleteRetentionRule(ctx context.Context, in *DeleteRetentionRuleReq, opts ...grpc.CallOption) (*DeleteRetentionRuleResp, error) {
+	return jsonrpc.Invoke[DeleteRetentionRuleReq, DeleteRetentionRuleResp](ctx, c.cc, MsgExt_DeleteRetentionRule_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetRetentionRules(ctx context.Context, in *GetRetentionRulesReq, opts ...grpc.CallOption) (*GetRetentionRulesResp, error) {
+	return jsonrpc.Invoke[GetRetentionRulesReq, GetRetentionRulesResp](ctx, c.cc, MsgExt_GetRetentionRules_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetRetentionReport(ctx context.Context, in *GetRetentionReportReq, opts ...grpc.CallOption) (*GetRetentionReportResp, error) {
+	return jsonrpc.Invoke[GetRetentionReportReq, GetRetentionReportResp](ctx, c.cc, MsgExt_GetRetentionReport_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) ApplyRetentionRules(ctx context.Context, in *ApplyRetentionRulesReq, opts ...grpc.CallOption) (*ApplyRetentionRulesResp, error) {
+	return jsonrpc.Invoke[ApplyRetentionRulesReq, ApplyRetentionRulesResp](ctx, c.cc, MsgExt_ApplyRetentionRules_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) PlaceLegalHold(ctx context.Context, in *PlaceLegalHoldReq, opts ...grpc.CallOption) (*PlaceLegalHoldResp, error) {
+	return jsonrpc.Invoke[PlaceLegalHoldReq, PlaceLegalHoldResp](ctx, c.cc, MsgExt_PlaceLegalHold_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) ReleaseLegalHold(ctx context.Context, in *ReleaseLegalHoldReq, opts ...grpc.CallOption) (*ReleaseLegalHoldResp, error) {
+	return jsonrpc.Invoke[ReleaseLegalHoldReq, ReleaseLegalHoldResp](ctx, c.cc, MsgExt_ReleaseLegalHold_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetLegalHolds(ctx context.Context, in *GetLegalHoldsReq, opts ...grpc.CallOption) (*GetLegalHoldsResp, error) {
+	return jsonrpc.Invoke[GetLegalHoldsReq, GetLegalHoldsResp](ctx, c.cc, MsgExt_GetLegalHolds_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetLegalHoldLogs(ctx context.Context, in *GetLegalHoldLogsReq, opts ...grpc.CallOption) (*GetLegalHoldLogsResp, error) {
+	return jsonrpc.Invoke[GetLegalHoldLogsReq, GetLegalHoldLogsResp](ctx, c.cc, MsgExt_GetLegalHoldLogs_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) SyncLegalHeldObjects(ctx context.Context, in *SyncLegalHeldObjectsReq, opts ...grpc.CallOption) (*SyncLegalHeldObjectsResp, error) {
+	return jsonrpc.Invoke[SyncLegalHeldObjectsReq, SyncLegalHeldObjectsResp](ctx, c.cc, MsgExt_SyncLegalHeldObjects_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetExportConversation(ctx context.Context, in *GetExportConversationReq, opts ...grpc.CallOption) (*GetExportConversationResp, error) {
+	return jsonrpc.Invoke[GetExportConversationReq, GetExportConversationResp](ctx, c.cc, MsgExt_GetExportConversation_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetExportMsgDoc(ctx context.Context, in *GetExportMsgDocReq, opts ...grpc.CallOption) (*GetExportMsgDocResp, error) {
+	return jsonrpc.Invoke[GetExportMsgDocReq, GetExportMsgDocResp](ctx, c.cc, MsgExt_GetExportMsgDoc_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) ImportMsgs(ctx context.Context, in *ImportMsgsReq, opts ...grpc.CallOption) (*ImportMsgsResp, error) {
+	return jsonrpc.Invoke[ImportMsgsReq, ImportMsgsResp](ctx, c.cc, MsgExt_ImportMsgs_FullMethodName, in, opts...)
+}
+
+func (c *msgExtClient) GetImportCheckpoints(ctx context.Context, in *GetImportCheckpointsReq, opts ...grpc.CallOption) (*GetImportCheckpointsResp, error) {
+	return jsonrpc.Invoke[GetImportCheckpointsReq, GetImportCheckpointsResp](ctx, c.cc, MsgExt_GetImportCheckpoints_FullMethodName, in, opts...)
+}
+
+type MsgExtServer interface {
+	ModifyMsg(context.Context, *ModifyMsgReq) (*ModifyMsgResp, error)
+	GetMsgModifyHistory(context.Context, *GetMsgModifyHistoryReq) (*GetMsgModifyHistoryResp, error)
+	AddMsgReaction(context.Context, *AddMsgReactionReq) (*AddMsgReactionResp, error)
+	DeleteMsgReaction(context.Context, *DeleteMsgReactionReq) (*DeleteMsgReactionResp, error)
+	GetThreadReplies(context.Context, *GetThreadRepliesReq) (*GetThreadRepliesResp, error)
+	SetThreadHasReadSeq(context.Context, *SetThreadHasReadSeqReq) (*SetThreadHasReadSeqResp, error)
+	GetThreadsHasReadAndMaxSeq(context.Context, *GetThreadsHasReadAndMaxSeqReq) (*GetThreadsHasReadAndMaxSeqResp, error)
+	ScheduleMsg(context.Context, *ScheduleMsgReq) (*ScheduleMsgResp, error)
+	CancelScheduledMsg(context.Context, *CancelScheduledMsgReq) (*CancelScheduledMsgResp, error)
+	ListScheduledMsgs(context.Context, *ListScheduledMsgsReq) (*ListScheduledMsgsResp, error)
+	DispatchScheduledMsgs(context.Context, *DispatchScheduledMsgsReq) (*DispatchScheduledMsgsResp, error)
+	SearchMsg(context.Context, *SearchMsgReq) (*SearchMsgResp, error)
+	SetRetentionRule(context.Context, *SetRetentionRuleReq) (*SetRetentionRuleResp, error)
+	DeleteRetentionRule(context.Context, *DeleteRetentionRuleReq) (*DeleteRetentionRuleResp, error)
+	GetRetentionRules(context.Context, *GetRetentionRulesReq) (*GetRetentionRulesResp, error)
+	GetRetentionReport(context.Context, *GetRetentionReportReq) (*GetRetentionReportResp, error)
+	ApplyRetentionRules(context.Context, *ApplyRetentionRulesReq) (*ApplyRetentionRulesResp, error)
+	PlaceLegalHold(context.Context, *PlaceLegalHoldReq) (*PlaceLegalHoldResp, error)
+	ReleaseLegalHold(context.Context, *ReleaseLegalHoldReq) (*ReleaseLegalHoldResp, error)
+	GetLegalHolds(context.Context, *GetLegalHoldsReq) (*GetLegalHoldsResp, error)
+	GetLegalHoldLogs(context.Context, *GetLegalHoldLogsReq) (*GetLegalHoldLogsResp, error)
+	SyncLegalHeldObjects(context.Context, *SyncLegalHeldObjectsReq) (*SyncLegalHeldObjectsResp, error)
+	GetExportConversation(context.Context, *GetExportConversationReq) (*GetExportConversationResp, error)
+	GetExportMsgDoc(context.Context, *GetExportMsgDocReq) (*GetExportMsgDocResp, error)
+	ImportMsgs(context.Context, *ImportMsgsReq) (*ImportMsgsResp, error)
+	GetImportCheckpoints(context.Context, *GetImportCheckpointsReq) (*GetImportCheckpointsResp, error)
+}
+
+// UnimplementedMsgExtServer can be embedded to have forward compatible implementations.
+type UnimplementedMsgExtServer struct{}
+
+func (UnimplementedMsgExtServer) ModifyMsg(context.Context, *ModifyMsgReq) (*ModifyMsgResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyMsg not implemented")
+}
+
+func (UnimplementedMsgExtServer) GetMsgModifyHistory(context.Context, *GetMsgModifyHistoryReq) (*GetMsgModifyHistoryResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMsgModifyHistory not implemented")
+}
+
+func (UnimplementedMsgExtServer) AddMsgReaction(context.Context, *AddMsgReactionReq) (*AddMsgReactionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMsgReaction not implemented")
+}
+
+func (UnimplementedMsgExtServer) DeleteMsgReaction(context.Context, *DeleteMsgReactionReq) (*DeleteMsgReactionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMsgReaction not implemented")
+}
+
+func (UnimplementedMsgExtServer) GetThreadReplies(context.Context, *GetThreadRepliesReq) (*GetThreadRepliesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThreadReplies not implemented")
+}
+
+func (UnimplementedMsgExtServer) SetThreadHasReadSeq(context.Context, *SetThreadHasReadSeqReq) (*SetThreadHasReadSeqResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetThreadHasReadSeq not implemented")
+}
+
+func (UnimplementedMsgExtServer) GetThreadsHasReadAndMaxSeq(context.Context, *GetThreadsHasReadAndMaxSeqReq) (*GetThreadsHasReadAndMaxSeqResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThreadsHasReadAndMaxSeq not implemented")
+}
+
+func (UnimplementedMsgExtServer) ScheduleMsg(context.Context, *ScheduleMsgReq) (*ScheduleMsgResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleMsg not implemented")
+}
+
+func (UnimplementedMsgExtServer) CancelScheduledMsg(context.Context, *CancelScheduledMsgReq) (*CancelScheduledMsgResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduledMsg not implemented")
+}
+
+func (UnimplementedMsgExtServer) ListScheduledMsgs(context.Context, *ListScheduledMsgsReq) (*ListScheduledMsgsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledMsgs not implemented")
+}
+
+func (UnimplementedMsgExtServer) DispatchScheduledMsgs(context.Context, *DispatchScheduledMsgsReq) (*DispatchScheduledMsgsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DispatchScheduledMsgs not implemented")
+}
+func (UnimplementedMsgExtServer) SearchMsg(context.Context, *SearchMsgReq) (*SearchMsgResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchMsg not implemented")
+}
+func (UnimplementedMsgExtServer) SetRetentionRule(context.Context, *SetRetentionRuleReq) (*SetRetentionRuleResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRetentionRule not implemented")
+}
+func (UnimplementedMsgExtServer) DeleteRetentionRule(context.Context, *DeleteRetentionRuleReq) (*DeleteRetentionRuleResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRetentionRule not implemented")
+}
+func (UnimplementedMsgExtServer) GetRetentionRules(context.Context, *GetRetentionRulesReq) (*GetRetentionRulesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionRules not implemented")
+}
+func (UnimplementedMsgExtServer) GetRetentionReport(context.Context, *GetRetentionReportReq) (*GetRetentionReportResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRetentionReport not implemented")
+}
+func (UnimplementedMsgExtServer) ApplyRetentionRules(context.Context, *ApplyRetentionRulesReq) (*ApplyRetentionRulesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyRetentionRules not implemented")
+}
+func (UnimplementedMsgExtServer) PlaceLegalHold(context.Context, *PlaceLegalHoldReq) (*PlaceLegalHoldResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlaceLegalHold not implemented")
+}
+func (UnimplementedMsgExtServer) ReleaseLegalHold(context.Context, *ReleaseLegalHoldReq) (*ReleaseLegalHoldResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseLegalHold not implemented")
+}
+func (UnimplementedMsgExtServer) GetLegalHolds(context.Context, *GetLegalHoldsReq) (*GetLegalHoldsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLegalHolds not implemented")
+}
+func (UnimplementedMsgExtServer) GetLegalHoldLogs(context.Context, *GetLegalHoldLogsReq) (*GetLegalHoldLogsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLegalHoldLogs not implemented")
+}
+func (UnimplementedMsgExtServer) SyncLegalHeldObjects(context.Context, *SyncLegalHeldObjectsReq) (*SyncLegalHeldObjectsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncLegalHeldObjects not implemented")
+}
+
+func (UnimplementedMsgExtServer) GetExportConversation(context.Context, *GetExportConversationReq) (*GetExportConversationResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExportConversation not implemented")
+}
+
+func (UnimplementedMsgExtServer) GetExportMsgDoc(context.Context, *GetExportMsgDocReq) (*GetExportMsgDocResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExportMsgDoc not implemented")
+}
+
+func (UnimplementedMsgExtServer) ImportMsgs(context.Context, *ImportMsgsReq) (*ImportMsgsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMsgs not implemented")
+}
+
+func (UnimplementedMsgExtServer) GetImportCheckpoints(context.Context, *GetImportCheckpointsReq) (*GetImportCheckpointsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetImportCheckpoints not implemented")
+}
+
+func RegisterMsgExtServer(s grpc.ServiceRegistrar, srv MsgExtServer) {
+	s.RegisterService(&MsgExt_ServiceDesc, srv)
+}
+
+var MsgExt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "openim.msgext.MsgExt",
+	HandlerType: (*MsgExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ModifyMsg",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_ModifyMsg_FullMethodName, MsgExtServer.ModifyMsg),
+		},
+		{
+			MethodName: "GetMsgModifyHistory",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetMsgModifyHistory_FullMethodName, MsgExtServer.GetMsgModifyHistory),
+		},
+		{
+			MethodName: "AddMsgReaction",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_AddMsgReaction_FullMethodName, MsgExtServer.AddMsgReaction),
+		},
+		{
+			MethodName: "DeleteMsgReaction",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_DeleteMsgReaction_FullMethodName, MsgExtServer.DeleteMsgReaction),
+		},
+		{
+			MethodName: "GetThreadReplies",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetThreadReplies_FullMethodName, MsgExtServer.GetThreadReplies),
+		},
+		{
+			MethodName: "SetThreadHasReadSeq",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_SetThreadHasReadSeq_FullMethodName, MsgExtServer.SetThreadHasReadSeq),
+		},
+		{
+			MethodName: "GetThreadsHasReadAndMaxSeq",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetThreadsHasReadAndMaxSeq_FullMethodName, MsgExtServer.GetThreadsHasReadAndMaxSeq),
+		},
+		{
+			MethodName: "ScheduleMsg",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_ScheduleMsg_FullMethodName, MsgExtServer.ScheduleMsg),
+		},
+		{
+			MethodName: "CancelScheduledMsg",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_CancelScheduledMsg_FullMethodName, MsgExtServer.CancelScheduledMsg),
+		},
+		{
+			MethodName: "ListScheduledMsgs",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_ListScheduledMsgs_FullMethodName, MsgExtServer.ListScheduledMsgs),
+		},
+		{
+			MethodName: "DispatchScheduledMsgs",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_DispatchScheduledMsgs_FullMethodName, MsgExtServer.DispatchScheduledMsgs),
+		},
+		{
+			MethodName: "SearchMsg",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_SearchMsg_FullMethodName, MsgExtServer.SearchMsg),
+		},
+		{
+			MethodName: "SetRetentionRule",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_SetRetentionRule_FullMethodName, MsgExtServer.SetRetentionRule),
+		},
+		{
+			MethodName: "DeleteRetentionRule",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_DeleteRetentionRule_FullMethodName, MsgExtServer.DeleteRetentionRule),
+		},
+		{
+			MethodName: "GetRetentionRules",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetRetentionRules_FullMethodName, MsgExtServer.GetRetentionRules),
+		},
+		{
+			MethodName: "GetRetentionReport",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetRetentionReport_FullMethodName, MsgExtServer.GetRetentionReport),
+		},
+		{
+			MethodName: "ApplyRetentionRules",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_ApplyRetentionRules_FullMethodName, MsgExtServer.ApplyRetentionRules),
+		},
+		{
+			MethodName: "PlaceLegalHold",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_PlaceLegalHold_FullMethodName, MsgExtServer.PlaceLegalHold),
+		},
+		{
+			MethodName: "ReleaseLegalHold",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_ReleaseLegalHold_FullMethodName, MsgExtServer.ReleaseLegalHold),
+		},
+		{
+			MethodName: "GetLegalHolds",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetLegalHolds_FullMethodName, MsgExtServer.GetLegalHolds),
+		},
+		{
+			MethodName: "GetLegalHoldLogs",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetLegalHoldLogs_FullMethodName, MsgExtServer.GetLegalHoldLogs),
+		},
+		{
+			MethodName: "SyncLegalHeldObjects",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_SyncLegalHeldObjects_FullMethodName, MsgExtServer.SyncLegalHeldObjects),
+		},
+		{
+			MethodName: "GetExportConversation",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetExportConversation_FullMethodName, MsgExtServer.GetExportConversation),
+		},
+		{
+			MethodName: "GetExportMsgDoc",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetExportMsgDoc_FullMethodName, MsgExtServer.GetExportMsgDoc),
+		},
+		{
+			MethodName: "ImportMsgs",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_ImportMsgs_FullMethodName, MsgExtServer.ImportMsgs),
+		},
+		{
+			MethodName: "GetImportCheckpoints",
+			Handler:    jsonrpc.UnaryHandler(MsgExt_GetImportCheckpoints_FullMethodName, MsgExtServer.GetImportCheckpoints),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "msgext",
+}
@@ -0,0 +1,144 @@
+package msgext
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// Retention rule scopes, a conversation rule overrides a group rule, which overrides a conversation type rule,
+// which overrides the crontask retainChatRecords.
+const (
+	// RetentionScopeConversation targets a conversation ID.
+	RetentionScopeConversation = "conversation"
+	// RetentionScopeGroup targets a group ID, covering its chat conversation.
+	RetentionScopeGroup = "group"
+	// RetentionScopeConversationType targets a session type: 1 single chat, 3 group chat or 4 notification chat.
+	RetentionScopeConversationType = "conversationType"
+	// RetentionScopeDefault is the crontask retainChatRecords, only reported.
+	RetentionScopeDefault = "default"
+)
+
+type RetentionRule struct {
+	Scope  string `json:"scope"`
+	Target string `json:"target"`
+	// RetainDays is how long msgs are kept, 0 keeps them forever.
+	RetainDays int64 `json:"retainDays"`
+	CreateTime int64 `json:"createTime"`
+	UpdateTime int64 `json:"updateTime"`
+}
+
+func checkRetentionTarget(scope string, target string) error {
+	if target == "" {
+		return errors.New("target is empty")
+	}
+	switch scope {
+	case RetentionScopeConversation, RetentionScopeGroup:
+	case RetentionScopeConversationType:
+		sessionType, err := strconv.Atoi(target)
+		if err != nil {
+			return errors.New("target is not a session type")
+		}
+		switch sessionType {
+		case constant.SingleChatType, constant.ReadGroupChatType, constant.NotificationChatType:
+		default:
+			return errors.New("target session type is not supported")
+		}
+	default:
+		return errors.New("scope is invalid")
+	}
+	return nil
+}
+
+type SetRetentionRuleReq struct {
+	Scope      string `json:"scope"`
+	Target     string `json:"target"`
+	RetainDays int64  `json:"retainDays"`
+}
+
+func (x *SetRetentionRuleReq) Check() error {
+	if err := checkRetentionTarget(x.Scope, x.Target); err != nil {
+		return err
+	}
+	if x.RetainDays < 0 {
+		return errors.New("retainDays is invalid")
+	}
+	return nil
+}
+
+type SetRetentionRuleResp struct{}
+
+type DeleteRetentionRuleReq struct {
+	Scope  string `json:"scope"`
+	Target string `json:"target"`
+}
+
+func (x *DeleteRetentionRuleReq) Check() error {
+	return checkRetentionTarget(x.Scope, x.Target)
+}
+
+type DeleteRetentionRuleResp struct{}
+
+type GetRetentionRulesReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetRetentionRulesReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type GetRetentionRulesResp struct {
+	Total int64            `json:"total"`
+	Rules []*RetentionRule `json:"rules"`
+}
+
+// GetRetentionReportReq is a dry run of ApplyRetentionRules.
+type GetRetentionReportReq struct {
+	// DefaultRetainDays is the retainChatRecords applied to conversations no rule covers, 0 keeps them forever.
+	DefaultRetainDays int64 `json:"defaultRetainDays"`
+}
+
+func (x *GetRetentionReportReq) Check() error {
+	if x.DefaultRetainDays < 0 {
+		return errors.New("defaultRetainDays is invalid")
+	}
+	return nil
+}
+
+type RetentionReport struct {
+	Scope      string `json:"scope"`
+	Target     string `json:"target"`
+	RetainDays int64  `json:"retainDays"`
+	// Deadline is the unix milli send time at or before which whole msg docs are deleted, 0 when kept forever.
+	Deadline int64 `json:"deadline"`
+	DocCount int64 `json:"docCount"`
+}
+
+type GetRetentionReportResp struct {
+	Reports []*RetentionReport `json:"reports"`
+}
+
+// ApplyRetentionRulesReq is sent by the crontask to delete up to Limit expired msg docs.
+type ApplyRetentionRulesReq struct {
+	DefaultRetainDays int64 `json:"defaultRetainDays"`
+	Limit             int32 `json:"limit"`
+}
+
+func (x *ApplyRetentionRulesReq) Check() error {
+	if x.DefaultRetainDays < 0 {
+		return errors.New("defaultRetainDays is invalid")
+	}
+	if x.Limit <= 0 {
+		return errors.New("limit is invalid")
+	}
+	return nil
+}
+
+type ApplyRetentionRulesResp struct {
+	Count int32 `json:"count"`
+}
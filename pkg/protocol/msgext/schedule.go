@@ -0,0 +1,94 @@
+package msgext
+
+import (
+	"errors"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+type ScheduleMsgReq struct {
+	MsgData *sdkws.MsgData `json:"msgData"`
+	// SendTime is the unix milli time the message is sent at.
+	SendTime int64 `json:"sendTime"`
+}
+
+func (x *ScheduleMsgReq) Check() error {
+	if x.MsgData == nil {
+		return errors.New("msgData is empty")
+	}
+	if x.MsgData.SendID == "" {
+		return errors.New("sendID is empty")
+	}
+	if x.MsgData.ClientMsgID == "" {
+		return errors.New("clientMsgID is empty")
+	}
+	if x.SendTime <= 0 {
+		return errors.New("sendTime is invalid")
+	}
+	return nil
+}
+
+type ScheduleMsgResp struct {
+	ScheduleID string `json:"scheduleID"`
+	SendTime   int64  `json:"sendTime"`
+}
+
+type CancelScheduledMsgReq struct {
+	UserID     string `json:"userID"`
+	ScheduleID string `json:"scheduleID"`
+}
+
+func (x *CancelScheduledMsgReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.ScheduleID == "" {
+		return errors.New("scheduleID is empty")
+	}
+	return nil
+}
+
+type CancelScheduledMsgResp struct{}
+
+type ListScheduledMsgsReq struct {
+	UserID     string                   `json:"userID"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *ListScheduledMsgsReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type ScheduledMsg struct {
+	ScheduleID string         `json:"scheduleID"`
+	SendTime   int64          `json:"sendTime"`
+	CreateTime int64          `json:"createTime"`
+	MsgData    *sdkws.MsgData `json:"msgData"`
+}
+
+type ListScheduledMsgsResp struct {
+	Total int64           `json:"total"`
+	Msgs  []*ScheduledMsg `json:"msgs"`
+}
+
+// DispatchScheduledMsgsReq is sent by the crontask to send up to Limit due messages.
+type DispatchScheduledMsgsReq struct {
+	Limit int32 `json:"limit"`
+}
+
+func (x *DispatchScheduledMsgsReq) Check() error {
+	if x.Limit <= 0 {
+		return errors.New("limit is invalid")
+	}
+	return nil
+}
+
+type DispatchScheduledMsgsResp struct {
+	Count int32 `json:"count"`
+}
@@ -0,0 +1,48 @@
+package msgext
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+type SearchMsgReq struct {
+	UserID  string `json:"userID"`
+	Keyword string `json:"keyword"`
+	// ConversationIDs restricts the search, empty searches all conversations of the user.
+	ConversationIDs []string `json:"conversationIDs"`
+	SendID          string   `json:"sendID"`
+	ContentTypes    []int32  `json:"contentTypes"`
+	// StartTime and EndTime bound the send time in unix milli, 0 means unbounded.
+	StartTime  int64                    `json:"startTime"`
+	EndTime    int64                    `json:"endTime"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *SearchMsgReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if strings.TrimSpace(x.Keyword) == "" {
+		return errors.New("keyword is empty")
+	}
+	if x.StartTime < 0 || x.EndTime < 0 || (x.EndTime > 0 && x.StartTime > x.EndTime) {
+		return errors.New("time range is invalid")
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type SearchedMsg struct {
+	ConversationID string         `json:"conversationID"`
+	MsgData        *sdkws.MsgData `json:"msgData"`
+}
+
+type SearchMsgResp struct {
+	// Total is the number of index hits, a page may hold fewer msgs once deleted and revoked ones are dropped.
+	Total int64          `json:"total"`
+	Msgs  []*SearchedMsg `json:"msgs"`
+}
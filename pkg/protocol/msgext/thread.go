@@ -0,0 +1,132 @@
+package msgext
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+const (
+	// AttachedInfoThreadKey is the attachedInfo field a reply sets to the *ThreadParent it belongs to.
+	AttachedInfoThreadKey = "thread"
+	// AttachedInfoThreadSummaryKey is the attachedInfo field carrying the *ThreadSummary of a pulled thread root.
+	AttachedInfoThreadSummaryKey = "threadSummary"
+)
+
+type ThreadParent struct {
+	ParentServerMsgID string `json:"parentServerMsgID"`
+	ParentSeq         int64  `json:"parentSeq"`
+}
+
+// GetThreadParent returns the thread parent declared in attachedInfo, or nil if the message is not a thread reply.
+func GetThreadParent(attachedInfo string) *ThreadParent {
+	if attachedInfo == "" {
+		return nil
+	}
+	var info struct {
+		Thread *ThreadParent `json:"thread"`
+	}
+	if err := json.Unmarshal([]byte(attachedInfo), &info); err != nil {
+		return nil
+	}
+	if info.Thread == nil || info.Thread.ParentServerMsgID == "" || info.Thread.ParentSeq <= 0 {
+		return nil
+	}
+	return info.Thread
+}
+
+type ThreadSummary struct {
+	ReplyCount    int64 `json:"replyCount"`
+	LastReplyTime int64 `json:"lastReplyTime"`
+	LastReplySeq  int64 `json:"lastReplySeq"`
+}
+
+type GetThreadRepliesReq struct {
+	UserID            string                   `json:"userID"`
+	ConversationID    string                   `json:"conversationID"`
+	ParentServerMsgID string                   `json:"parentServerMsgID"`
+	Pagination        *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *GetThreadRepliesReq) Check() error {
+	if err := checkThread(x.UserID, x.ConversationID, x.ParentServerMsgID); err != nil {
+		return err
+	}
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type ThreadReply struct {
+	ThreadSeq int64          `json:"threadSeq"`
+	Msg       *sdkws.MsgData `json:"msg"`
+}
+
+type GetThreadRepliesResp struct {
+	Total   int64          `json:"total"`
+	Replies []*ThreadReply `json:"replies"`
+}
+
+type SetThreadHasReadSeqReq struct {
+	UserID            string `json:"userID"`
+	ConversationID    string `json:"conversationID"`
+	ParentServerMsgID string `json:"parentServerMsgID"`
+	HasReadSeq        int64  `json:"hasReadSeq"`
+}
+
+func (x *SetThreadHasReadSeqReq) Check() error {
+	if err := checkThread(x.UserID, x.ConversationID, x.ParentServerMsgID); err != nil {
+		return err
+	}
+	if x.HasReadSeq < 0 {
+		return errors.New("hasReadSeq is invalid")
+	}
+	return nil
+}
+
+type SetThreadHasReadSeqResp struct{}
+
+type GetThreadsHasReadAndMaxSeqReq struct {
+	UserID             string   `json:"userID"`
+	ConversationID     string   `json:"conversationID"`
+	ParentServerMsgIDs []string `json:"parentServerMsgIDs"`
+}
+
+func (x *GetThreadsHasReadAndMaxSeqReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.ConversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if len(x.ParentServerMsgIDs) == 0 {
+		return errors.New("parentServerMsgIDs is empty")
+	}
+	return nil
+}
+
+// ThreadSeqs are counted in thread seqs, the position of a reply within its thread.
+type ThreadSeqs struct {
+	HasReadSeq    int64 `json:"hasReadSeq"`
+	MaxSeq        int64 `json:"maxSeq"`
+	LastReplyTime int64 `json:"lastReplyTime"`
+}
+
+type GetThreadsHasReadAndMaxSeqResp struct {
+	Seqs map[string]*ThreadSeqs `json:"seqs"`
+}
+
+func checkThread(userID string, conversationID string, parentServerMsgID string) error {
+	if userID == "" {
+		return errors.New("userID is empty")
+	}
+	if conversationID == "" {
+		return errors.New("conversationID is empty")
+	}
+	if parentServerMsgID == "" {
+		return errors.New("parentServerMsgID is empty")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package msgext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetThreadParent(t *testing.T) {
+	tests := []struct {
+		name         string
+		attachedInfo string
+		want         *ThreadParent
+	}{
+		{name: "empty"},
+		{name: "invalid json", attachedInfo: "{"},
+		{name: "no thread", attachedInfo: `{"groupHasReadInfo":{}}`},
+		{name: "missing seq", attachedInfo: `{"thread":{"parentServerMsgID":"s1"}}`},
+		{
+			name:         "reply",
+			attachedInfo: `{"thread":{"parentServerMsgID":"s1","parentSeq":3}}`,
+			want:         &ThreadParent{ParentServerMsgID: "s1", ParentSeq: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetThreadParent(tt.attachedInfo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetThreadParent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
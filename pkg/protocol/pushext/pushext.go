@@ -0,0 +1,84 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pushext holds the push service methods served next to github.com/openimsdk/protocol/push.
+package pushext
+
+import (
+	"errors"
+
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+// OfflinePushDeadLetter is an offline push that still failed after all its retries.
+type OfflinePushDeadLetter struct {
+	ID          string   `json:"id"`
+	UserIDs     []string `json:"userIDs"`
+	ClientMsgID string   `json:"clientMsgID"`
+	Title       string   `json:"title"`
+	Content     string   `json:"content"`
+	// Providers are the routed providers the push failed on, empty if it failed as a whole.
+	Providers  []string `json:"providers"`
+	Attempts   int32    `json:"attempts"`
+	LastError  string   `json:"lastError"`
+	CreateTime int64    `json:"createTime"`
+	UpdateTime int64    `json:"updateTime"`
+}
+
+// PageOfflinePushDeadLettersReq lists the dead letters of the pushes to the user, of all pushes if it is empty.
+type PageOfflinePushDeadLettersReq struct {
+	UserID     string                   `json:"userID"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *PageOfflinePushDeadLettersReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type PageOfflinePushDeadLettersResp struct {
+	Total       int64                    `json:"total"`
+	DeadLetters []*OfflinePushDeadLetter `json:"deadLetters"`
+}
+
+// RetryOfflinePushDeadLettersReq queues the dead letters to be retried again from the first attempt.
+type RetryOfflinePushDeadLettersReq struct {
+	IDs []string `json:"ids"`
+}
+
+func (x *RetryOfflinePushDeadLettersReq) Check() error {
+	if len(x.IDs) == 0 {
+		return errors.New("ids is empty")
+	}
+	return nil
+}
+
+type RetryOfflinePushDeadLettersResp struct {
+	Count int64 `json:"count"`
+}
+
+type DeleteOfflinePushDeadLettersReq struct {
+	IDs []string `json:"ids"`
+}
+
+func (x *DeleteOfflinePushDeadLettersReq) Check() error {
+	if len(x.IDs) == 0 {
+		return errors.New("ids is empty")
+	}
+	return nil
+}
+
+type DeleteOfflinePushDeadLettersResp struct{}
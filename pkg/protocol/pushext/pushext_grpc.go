@@ -0,0 +1,102 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pushext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	PushExt_PageOfflinePushDeadLetters_FullMethodName   = "/openim.pushext.PushExt/PageOfflinePushDeadLetters"
+	PushExt_RetryOfflinePushDeadLetters_FullMethodName  = "/openim.pushext.PushExt/RetryOfflinePushDeadLetters"
+	PushExt_DeleteOfflinePushDeadLetters_FullMethodName = "/openim.pushext.PushExt/DeleteOfflinePushDeadLetters"
+)
+
+type PushExtClient interface {
+	PageOfflinePushDeadLetters(ctx context.Context, in *PageOfflinePushDeadLettersReq, opts ...grpc.CallOption) (*PageOfflinePushDeadLettersResp, error)
+	RetryOfflinePushDeadLetters(ctx context.Context, in *RetryOfflinePushDeadLettersReq, opts ...grpc.CallOption) (*RetryOfflinePushDeadLettersResp, error)
+	DeleteOfflinePushDeadLetters(ctx context.Context, in *DeleteOfflinePushDeadLettersReq, opts ...grpc.CallOption) (*DeleteOfflinePushDeadLettersResp, error)
+}
+
+type pushExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPushExtClient(cc grpc.ClientConnInterface) PushExtClient {
+	return &pushExtClient{cc}
+}
+
+func (c *pushExtClient) PageOfflinePushDeadLetters(ctx context.Context, in *PageOfflinePushDeadLettersReq, opts ...grpc.CallOption) (*PageOfflinePushDeadLettersResp, error) {
+	return jsonrpc.Invoke[PageOfflinePushDeadLettersReq, PageOfflinePushDeadLettersResp](ctx, c.cc, PushExt_PageOfflinePushDeadLetters_FullMethodName, in, opts...)
+}
+
+func (c *pushExtClient) RetryOfflinePushDeadLetters(ctx context.Context, in *RetryOfflinePushDeadLettersReq, opts ...grpc.CallOption) (*RetryOfflinePushDeadLettersResp, error) {
+	return jsonrpc.Invoke[RetryOfflinePushDeadLettersReq, RetryOfflinePushDeadLettersResp](ctx, c.cc, PushExt_RetryOfflinePushDeadLetters_FullMethodName, in, opts...)
+}
+
+func (c *pushExtClient) DeleteOfflinePushDeadLetters(ctx context.Context, in *DeleteOfflinePushDeadLettersReq, opts ...grpc.CallOption) (*DeleteOfflinePushDeadLettersResp, error) {
+	return jsonrpc.Invoke[DeleteOfflinePushDeadLettersReq, DeleteOfflinePushDeadLettersResp](ctx, c.cc, PushExt_DeleteOfflinePushDeadLetters_FullMethodName, in, opts...)
+}
+
+type PushExtServer interface {
+	PageOfflinePushDeadLetters(context.Context, *PageOfflinePushDeadLettersReq) (*PageOfflinePushDeadLettersResp, error)
+	RetryOfflinePushDeadLetters(context.Context, *RetryOfflinePushDeadLettersReq) (*RetryOfflinePushDeadLettersResp, error)
+	DeleteOfflinePushDeadLetters(context.Context, *DeleteOfflinePushDeadLettersReq) (*DeleteOfflinePushDeadLettersResp, error)
+}
+
+// UnimplementedPushExtServer can be embedded to have forward compatible implementations.
+type UnimplementedPushExtServer struct{}
+
+func (UnimplementedPushExtServer) PageOfflinePushDeadLetters(context.Context, *PageOfflinePushDeadLettersReq) (*PageOfflinePushDeadLettersResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PageOfflinePushDeadLetters not implemented")
+}
+
+func (UnimplementedPushExtServer) RetryOfflinePushDeadLetters(context.Context, *RetryOfflinePushDeadLettersReq) (*RetryOfflinePushDeadLettersResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryOfflinePushDeadLetters not implemented")
+}
+
+func (UnimplementedPushExtServer) DeleteOfflinePushDeadLetters(context.Context, *DeleteOfflinePushDeadLettersReq) (*DeleteOfflinePushDeadLettersResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteOfflinePushDeadLetters not implemented")
+}
+
+func RegisterPushExtServer(s grpc.ServiceRegistrar, srv PushExtServer) {
+	s.RegisterService(&PushExt_ServiceDesc, srv)
+}
+
+var PushExt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "openim.pushext.PushExt",
+	HandlerType: (*PushExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PageOfflinePushDeadLetters",
+			Handler:    jsonrpc.UnaryHandler(PushExt_PageOfflinePushDeadLetters_FullMethodName, PushExtServer.PageOfflinePushDeadLetters),
+		},
+		{
+			MethodName: "RetryOfflinePushDeadLetters",
+			Handler:    jsonrpc.UnaryHandler(PushExt_RetryOfflinePushDeadLetters_FullMethodName, PushExtServer.RetryOfflinePushDeadLetters),
+		},
+		{
+			MethodName: "DeleteOfflinePushDeadLetters",
+			Handler:    jsonrpc.UnaryHandler(PushExt_DeleteOfflinePushDeadLetters_FullMethodName, PushExtServer.DeleteOfflinePushDeadLetters),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pushext",
+}
@@ -0,0 +1,324 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package thirdext holds the third service methods served next to github.com/openimsdk/protocol/third.
+package thirdext
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/openimsdk/protocol/constant"
+	"github.com/openimsdk/protocol/sdkws"
+)
+
+const maxApplicationTextLength = 4096
+
+// ApplicationVersion is a release of the app, Rollout is the percentage of the users it is offered to.
+type ApplicationVersion struct {
+	ID         string `json:"id"`
+	Platform   string `json:"platform"`
+	Version    string `json:"version"`
+	Url        string `json:"url"`
+	Text       string `json:"text"`
+	Force      bool   `json:"force"`
+	Hot        bool   `json:"hot"`
+	Rollout    int32  `json:"rollout"`
+	CreateTime int64  `json:"createTime"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// CompareVersion compares the dot separated numeric versions, a missing part counts as 0, so 1.2 equals 1.2.0.
+func CompareVersion(a string, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func checkVersion(version string) error {
+	if version == "" {
+		return errors.New("version is empty")
+	}
+	for _, part := range strings.Split(version, ".") {
+		if _, err := strconv.ParseUint(part, 10, 31); err != nil {
+			return errors.New("version must be dot separated numbers")
+		}
+	}
+	return nil
+}
+
+func checkRollout(rollout int32) error {
+	if rollout < 0 || rollout > 100 {
+		return errors.New("rollout must be a percentage")
+	}
+	return nil
+}
+
+// AddApplicationVersionReq adds a release rolled out to every user unless Rollout is set.
+type AddApplicationVersionReq struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+	Url      string `json:"url"`
+	Text     string `json:"text"`
+	Force    bool   `json:"force"`
+	Hot      bool   `json:"hot"`
+	Rollout  *int32 `json:"rollout"`
+}
+
+func (x *AddApplicationVersionReq) Check() error {
+	if x.Platform == "" {
+		return errors.New("platform is empty")
+	}
+	if err := checkVersion(x.Version); err != nil {
+		return err
+	}
+	if x.Url == "" {
+		return errors.New("url is empty")
+	}
+	if len(x.Text) > maxApplicationTextLength {
+		return errors.New("text is too long")
+	}
+	if x.Rollout != nil {
+		return checkRollout(*x.Rollout)
+	}
+	return nil
+}
+
+type AddApplicationVersionResp struct {
+	ID string `json:"id"`
+}
+
+// UpdateApplicationVersionReq changes the fields that are set, the platform and version of a release cannot change.
+type UpdateApplicationVersionReq struct {
+	ID      string  `json:"id"`
+	Url     *string `json:"url"`
+	Text    *string `json:"text"`
+	Force   *bool   `json:"force"`
+	Hot     *bool   `json:"hot"`
+	Rollout *int32  `json:"rollout"`
+}
+
+func (x *UpdateApplicationVersionReq) Check() error {
+	if x.ID == "" {
+		return errors.New("id is empty")
+	}
+	if x.Url != nil && *x.Url == "" {
+		return errors.New("url is empty")
+	}
+	if x.Text != nil && len(*x.Text) > maxApplicationTextLength {
+		return errors.New("text is too long")
+	}
+	if x.Rollout != nil {
+		return checkRollout(*x.Rollout)
+	}
+	return nil
+}
+
+type UpdateApplicationVersionResp struct{}
+
+type DeleteApplicationVersionReq struct {
+	IDs []string `json:"ids"`
+}
+
+func (x *DeleteApplicationVersionReq) Check() error {
+	if len(x.IDs) == 0 {
+		return errors.New("ids is empty")
+	}
+	return nil
+}
+
+type DeleteApplicationVersionResp struct{}
+
+// PageApplicationVersionReq lists the releases of the platform, of all platforms if it is empty.
+type PageApplicationVersionReq struct {
+	Platform   string                   `json:"platform"`
+	Pagination *sdkws.RequestPagination `json:"pagination"`
+}
+
+func (x *PageApplicationVersionReq) Check() error {
+	if x.Pagination == nil {
+		return errors.New("pagination is empty")
+	}
+	return nil
+}
+
+type PageApplicationVersionResp struct {
+	Total    int64                 `json:"total"`
+	Versions []*ApplicationVersion `json:"versions"`
+}
+
+// LatestApplicationVersionReq is sent by the clients at startup with the version they run, and with the token once logged in.
+// The releases are rolled out to the user of the token, without one only the releases rolled out to every user are offered.
+type LatestApplicationVersionReq struct {
+	Platform string `json:"platform"`
+	Version  string `json:"version"`
+}
+
+func (x *LatestApplicationVersionReq) Check() error {
+	if x.Platform == "" {
+		return errors.New("platform is empty")
+	}
+	if x.Version != "" {
+		return checkVersion(x.Version)
+	}
+	return nil
+}
+
+// LatestApplicationVersionResp has no Version when the client is up to date.
+// Force is set when a force release newer than the client's version is offered, the client must then update to Version.
+type LatestApplicationVersionResp struct {
+	Version *ApplicationVersion `json:"version"`
+	Force   bool                `json:"force"`
+}
+
+// RegisterWebPushSubscriptionReq carries the PushSubscription the browser created with the VAPID public key,
+// P256dh and Auth are its keys, base64url encoded as PushSubscription.toJSON returns them.
+type RegisterWebPushSubscriptionReq struct {
+	UserID         string `json:"userID"`
+	Endpoint       string `json:"endpoint"`
+	P256dh         string `json:"p256dh"`
+	Auth           string `json:"auth"`
+	ExpirationTime int64  `json:"expirationTime"`
+}
+
+func (x *RegisterWebPushSubscriptionReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if err := CheckWebPushEndpoint(x.Endpoint); err != nil {
+		return err
+	}
+	if key, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(x.P256dh, "=")); err != nil || len(key) != 65 {
+		return errors.New("p256dh must be a base64url encoded P-256 public key")
+	}
+	if auth, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(x.Auth, "=")); err != nil || len(auth) != 16 {
+		return errors.New("auth must be a base64url encoded 16 bytes secret")
+	}
+	if x.ExpirationTime < 0 {
+		return errors.New("expirationTime is invalid")
+	}
+	return nil
+}
+
+type RegisterWebPushSubscriptionResp struct{}
+
+type UnregisterWebPushSubscriptionReq struct {
+	UserID   string `json:"userID"`
+	Endpoint string `json:"endpoint"`
+}
+
+func (x *UnregisterWebPushSubscriptionReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.Endpoint == "" {
+		return errors.New("endpoint is empty")
+	}
+	return nil
+}
+
+type UnregisterWebPushSubscriptionResp struct{}
+
+// webPushHosts are the push services of the browsers, a leading dot matches the subdomains.
+var webPushHosts = []string{
+	"fcm.googleapis.com",         // Chrome, Opera, Samsung Internet
+	".push.services.mozilla.com", // Firefox
+	".notify.windows.com",        // Edge
+	".push.apple.com",            // Safari
+}
+
+// CheckWebPushEndpoint only accepts the endpoints of the browser push services,
+// the server posts to the endpoint so any other host would let a client make it request internal addresses.
+func CheckWebPushEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return errors.New("endpoint must be an https url")
+	}
+	if port := u.Port(); port != "" && port != "443" {
+		return errors.New("endpoint must use the default https port")
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range webPushHosts {
+		if host == h || (strings.HasPrefix(h, ".") && strings.HasSuffix(host, h)) {
+			return nil
+		}
+	}
+	return errors.New("endpoint is not a known web push service")
+}
+
+// GetWebPushPublicKeyResp has the VAPID public key the browsers subscribe with as applicationServerKey,
+// it is empty when web push is not configured.
+type GetWebPushPublicKeyResp struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// The vendor push services of the Android devices that are pushed to directly instead of through FCM.
+const (
+	VendorHuawei = "huawei"
+	VendorHonor  = "honor"
+	VendorXiaomi = "xiaomi"
+	VendorOPPO   = "oppo"
+	VendorVivo   = "vivo"
+)
+
+// UpdateVendorTokenReq registers the token a vendor push service issued to the device of the platform,
+// it replaces the FCM token of the platform until FcmUpdateToken registers one again.
+type UpdateVendorTokenReq struct {
+	UserID     string `json:"userID"`
+	PlatformID int32  `json:"platformID"`
+	Vendor     string `json:"vendor"`
+	Token      string `json:"token"`
+	// ExpireTime is the seconds the token is kept for.
+	ExpireTime int64 `json:"expireTime"`
+}
+
+func (x *UpdateVendorTokenReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	if x.PlatformID != constant.AndroidPlatformID && x.PlatformID != constant.AndroidPadPlatformID {
+		return errors.New("vendor tokens are only for android platforms")
+	}
+	switch x.Vendor {
+	case VendorHuawei, VendorHonor, VendorXiaomi, VendorOPPO, VendorVivo:
+	default:
+		return errors.New("vendor is invalid")
+	}
+	if x.Token == "" {
+		return errors.New("token is empty")
+	}
+	if x.ExpireTime <= 0 {
+		return errors.New("expireTime is invalid")
+	}
+	return nil
+}
+
+type UpdateVendorTokenResp struct{}
@@ -0,0 +1,177 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thirdext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ThirdExt_AddApplicationVersion_FullMethodName         = "/openim.thirdext.ThirdExt/AddApplicationVersion"
+	ThirdExt_UpdateApplicationVersion_FullMethodName      = "/openim.thirdext.ThirdExt/UpdateApplicationVersion"
+	ThirdExt_DeleteApplicationVersion_FullMethodName      = "/openim.thirdext.ThirdExt/DeleteApplicationVersion"
+	ThirdExt_PageApplicationVersion_FullMethodName        = "/openim.thirdext.ThirdExt/PageApplicationVersion"
+	ThirdExt_LatestApplicationVersion_FullMethodName      = "/openim.thirdext.ThirdExt/LatestApplicationVersion"
+	ThirdExt_RegisterWebPushSubscription_FullMethodName   = "/openim.thirdext.ThirdExt/RegisterWebPushSubscription"
+	ThirdExt_UnregisterWebPushSubscription_FullMethodName = "/openim.thirdext.ThirdExt/UnregisterWebPushSubscription"
+	ThirdExt_UpdateVendorToken_FullMethodName             = "/openim.thirdext.ThirdExt/UpdateVendorToken"
+)
+
+type ThirdExtClient interface {
+	AddApplicationVersion(ctx context.Context, in *AddApplicationVersionReq, opts ...grpc.CallOption) (*AddApplicationVersionResp, error)
+	UpdateApplicationVersion(ctx context.Context, in *UpdateApplicationVersionReq, opts ...grpc.CallOption) (*UpdateApplicationVersionResp, error)
+	DeleteApplicationVersion(ctx context.Context, in *DeleteApplicationVersionReq, opts ...grpc.CallOption) (*DeleteApplicationVersionResp, error)
+	PageApplicationVersion(ctx context.Context, in *PageApplicationVersionReq, opts ...grpc.CallOption) (*PageApplicationVersionResp, error)
+	LatestApplicationVersion(ctx context.Context, in *LatestApplicationVersionReq, opts ...grpc.CallOption) (*LatestApplicationVersionResp, error)
+	RegisterWebPushSubscription(ctx context.Context, in *RegisterWebPushSubscriptionReq, opts ...grpc.CallOption) (*RegisterWebPushSubscriptionResp, error)
+	UnregisterWebPushSubscription(ctx context.Context, in *UnregisterWebPushSubscriptionReq, opts ...grpc.CallOption) (*UnregisterWebPushSubscriptionResp, error)
+	UpdateVendorToken(ctx context.Context, in *UpdateVendorTokenReq, opts ...grpc.CallOption) (*UpdateVendorTokenResp, error)
+}
+
+type thirdExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewThirdExtClient(cc grpc.ClientConnInterface) ThirdExtClient {
+	return &thirdExtClient{cc}
+}
+
+func (c *thirdExtClient) AddApplicationVersion(ctx context.Context, in *AddApplicationVersionReq, opts ...grpc.CallOption) (*AddApplicationVersionResp, error) {
+	return jsonrpc.Invoke[AddApplicationVersionReq, AddApplicationVersionResp](ctx, c.cc, ThirdExt_AddApplicationVersion_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) UpdateApplicationVersion(ctx context.Context, in *UpdateApplicationVersionReq, opts ...grpc.CallOption) (*UpdateApplicationVersionResp, error) {
+	return jsonrpc.Invoke[UpdateApplicationVersionReq, UpdateApplicationVersionResp](ctx, c.cc, ThirdExt_UpdateApplicationVersion_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) DeleteApplicationVersion(ctx context.Context, in *DeleteApplicationVersionReq, opts ...grpc.CallOption) (*DeleteApplicationVersionResp, error) {
+	return jsonrpc.Invoke[DeleteApplicationVersionReq, DeleteApplicationVersionResp](ctx, c.cc, ThirdExt_DeleteApplicationVersion_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) PageApplicationVersion(ctx context.Context, in *PageApplicationVersionReq, opts ...grpc.CallOption) (*PageApplicationVersionResp, error) {
+	return jsonrpc.Invoke[PageApplicationVersionReq, PageApplicationVersionResp](ctx, c.cc, ThirdExt_PageApplicationVersion_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) LatestApplicationVersion(ctx context.Context, in *LatestApplicationVersionReq, opts ...grpc.CallOption) (*LatestApplicationVersionResp, error) {
+	return jsonrpc.Invoke[LatestApplicationVersionReq, LatestApplicationVersionResp](ctx, c.cc, ThirdExt_LatestApplicationVersion_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) RegisterWebPushSubscription(ctx context.Context, in *RegisterWebPushSubscriptionReq, opts ...grpc.CallOption) (*RegisterWebPushSubscriptionResp, error) {
+	return jsonrpc.Invoke[RegisterWebPushSubscriptionReq, RegisterWebPushSubscriptionResp](ctx, c.cc, ThirdExt_RegisterWebPushSubscription_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) UnregisterWebPushSubscription(ctx context.Context, in *UnregisterWebPushSubscriptionReq, opts ...grpc.CallOption) (*UnregisterWebPushSubscriptionResp, error) {
+	return jsonrpc.Invoke[UnregisterWebPushSubscriptionReq, UnregisterWebPushSubscriptionResp](ctx, c.cc, ThirdExt_UnregisterWebPushSubscription_FullMethodName, in, opts...)
+}
+
+func (c *thirdExtClient) UpdateVendorToken(ctx context.Context, in *UpdateVendorTokenReq, opts ...grpc.CallOption) (*UpdateVendorTokenResp, error) {
+	return jsonrpc.Invoke[UpdateVendorTokenReq, UpdateVendorTokenResp](ctx, c.cc, ThirdExt_UpdateVendorToken_FullMethodName, in, opts...)
+}
+
+type ThirdExtServer interface {
+	AddApplicationVersion(context.Context, *AddApplicationVersionReq) (*AddApplicationVersionResp, error)
+	UpdateApplicationVersion(context.Context, *UpdateApplicationVersionReq) (*UpdateApplicationVersionResp, error)
+	DeleteApplicationVersion(context.Context, *DeleteApplicationVersionReq) (*DeleteApplicationVersionResp, error)
+	PageApplicationVersion(context.Context, *PageApplicationVersionReq) (*PageApplicationVersionResp, error)
+	LatestApplicationVersion(context.Context, *LatestApplicationVersionReq) (*LatestApplicationVersionResp, error)
+	RegisterWebPushSubscription(context.Context, *RegisterWebPushSubscriptionReq) (*RegisterWebPushSubscriptionResp, error)
+	UnregisterWebPushSubscription(context.Context, *UnregisterWebPushSubscriptionReq) (*UnregisterWebPushSubscriptionResp, error)
+	UpdateVendorToken(context.Context, *UpdateVendorTokenReq) (*UpdateVendorTokenResp, error)
+}
+
+// UnimplementedThirdExtServer can be embedded to have forward compatible implementations.
+type UnimplementedThirdExtServer struct{}
+
+func (UnimplementedThirdExtServer) AddApplicationVersion(context.Context, *AddApplicationVersionReq) (*AddApplicationVersionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddApplicationVersion not implemented")
+}
+
+func (UnimplementedThirdExtServer) UpdateApplicationVersion(context.Context, *UpdateApplicationVersionReq) (*UpdateApplicationVersionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateApplicationVersion not implemented")
+}
+
+func (UnimplementedThirdExtServer) DeleteApplicationVersion(context.Context, *DeleteApplicationVersionReq) (*DeleteApplicationVersionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApplicationVersion not implemented")
+}
+
+func (UnimplementedThirdExtServer) PageApplicationVersion(context.Context, *PageApplicationVersionReq) (*PageApplicationVersionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PageApplicationVersion not implemented")
+}
+
+func (UnimplementedThirdExtServer) LatestApplicationVersion(context.Context, *LatestApplicationVersionReq) (*LatestApplicationVersionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LatestApplicationVersion not implemented")
+}
+
+func (UnimplementedThirdExtServer) RegisterWebPushSubscription(context.Context, *RegisterWebPushSubscriptionReq) (*RegisterWebPushSubscriptionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebPushSubscription not implemented")
+}
+
+func (UnimplementedThirdExtServer) UnregisterWebPushSubscription(context.Context, *UnregisterWebPushSubscriptionReq) (*UnregisterWebPushSubscriptionResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnregisterWebPushSubscription not implemented")
+}
+
+func (UnimplementedThirdExtServer) UpdateVendorToken(context.Context, *UpdateVendorTokenReq) (*UpdateVendorTokenResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVendorToken not implemented")
+}
+
+func RegisterThirdExtServer(s grpc.ServiceRegistrar, srv ThirdExtServer) {
+	s.RegisterService(&ThirdExt_ServiceDesc, srv)
+}
+
+var ThirdExt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "openim.thirdext.ThirdExt",
+	HandlerType: (*ThirdExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddApplicationVersion",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_AddApplicationVersion_FullMethodName, ThirdExtServer.AddApplicationVersion),
+		},
+		{
+			MethodName: "UpdateApplicationVersion",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_UpdateApplicationVersion_FullMethodName, ThirdExtServer.UpdateApplicationVersion),
+		},
+		{
+			MethodName: "DeleteApplicationVersion",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_DeleteApplicationVersion_FullMethodName, ThirdExtServer.DeleteApplicationVersion),
+		},
+		{
+			MethodName: "PageApplicationVersion",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_PageApplicationVersion_FullMethodName, ThirdExtServer.PageApplicationVersion),
+		},
+		{
+			MethodName: "LatestApplicationVersion",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_LatestApplicationVersion_FullMethodName, ThirdExtServer.LatestApplicationVersion),
+		},
+		{
+			MethodName: "RegisterWebPushSubscription",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_RegisterWebPushSubscription_FullMethodName, ThirdExtServer.RegisterWebPushSubscription),
+		},
+		{
+			MethodName: "UnregisterWebPushSubscription",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_UnregisterWebPushSubscription_FullMethodName, ThirdExtServer.UnregisterWebPushSubscription),
+		},
+		{
+			MethodName: "UpdateVendorToken",
+			Handler:    jsonrpc.UnaryHandler(ThirdExt_UpdateVendorToken_FullMethodName, ThirdExtServer.UpdateVendorToken),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "thirdext",
+}
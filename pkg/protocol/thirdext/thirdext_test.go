@@ -0,0 +1,46 @@
+package thirdext
+
+import "testing"
+
+func TestCompareVersion(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.10.0", "1.9.9", 1},
+		{"2.0", "10.0", -1},
+		{"1.2.3.1", "1.2.3", 1},
+	}
+	for _, c := range cases {
+		if got := CompareVersion(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersion(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestCheckWebPushEndpoint(t *testing.T) {
+	cases := []struct {
+		endpoint string
+		ok       bool
+	}{
+		{"https://fcm.googleapis.com/fcm/send/abc", true},
+		{"https://updates.push.services.mozilla.com/wpush/v2/abc", true},
+		{"https://wns2-by3p.notify.windows.com/w/?token=abc", true},
+		{"https://web.push.apple.com/abc", true},
+		{"http://fcm.googleapis.com/fcm/send/abc", false},
+		{"https://fcm.googleapis.com:8443/fcm/send/abc", false},
+		{"https://user@fcm.googleapis.com/fcm/send/abc", false},
+		{"https://push.apple.com.evil.com/abc", false},
+		{"https://evilpush.apple.com/abc", false},
+		{"https://127.0.0.1/abc", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://localhost/abc", false},
+	}
+	for _, c := range cases {
+		if err := CheckWebPushEndpoint(c.endpoint); (err == nil) != c.ok {
+			t.Errorf("CheckWebPushEndpoint(%q) = %v, want ok %v", c.endpoint, err, c.ok)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package userext holds the user service methods served next to github.com/openimsdk/protocol/user.
+package userext
+
+import "errors"
+
+// The presence states, a user is available unless told otherwise.
+const (
+	PresenceAvailable    = 0
+	PresenceAway         = 1
+	PresenceDoNotDisturb = 2
+)
+
+const (
+	maxPresenceTextLength  = 128
+	maxPresenceEmojiLength = 64
+	maxPresenceUserIDs     = 1000
+)
+
+// UserPresence is pushed to the subscribers of the user's online status on every change,
+// the clients clear it themselves once ExpireAt in milliseconds passes.
+type UserPresence struct {
+	UserID     string `json:"userID"`
+	State      int32  `json:"state"`
+	Text       string `json:"text"`
+	Emoji      string `json:"emoji"`
+	ExpireAt   int64  `json:"expireAt"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// SetUserPresenceReq clears the presence if it is available with no text nor emoji.
+type SetUserPresenceReq struct {
+	UserID   string `json:"userID"`
+	State    int32  `json:"state"`
+	Text     string `json:"text"`
+	Emoji    string `json:"emoji"`
+	ExpireAt int64  `json:"expireAt"`
+}
+
+func (x *SetUserPresenceReq) Check() error {
+	if x.UserID == "" {
+		return errors.New("userID is empty")
+	}
+	switch x.State {
+	case PresenceAvailable, PresenceAway, PresenceDoNotDisturb:
+	default:
+		return errors.New("state is invalid")
+	}
+	if len(x.Text) > maxPresenceTextLength {
+		return errors.New("text is too long")
+	}
+	if len(x.Emoji) > maxPresenceEmojiLength {
+		return errors.New("emoji is too long")
+	}
+	if x.ExpireAt < 0 {
+		return errors.New("expireAt is invalid")
+	}
+	return nil
+}
+
+type SetUserPresenceResp struct{}
+
+type GetUsersPresenceReq struct {
+	UserIDs []string `json:"userIDs"`
+}
+
+func (x *GetUsersPresenceReq) Check() error {
+	if len(x.UserIDs) == 0 {
+		return errors.New("userIDs is empty")
+	}
+	if len(x.UserIDs) > maxPresenceUserIDs {
+		return errors.New("too many userIDs")
+	}
+	return nil
+}
+
+type GetUsersPresenceResp struct {
+	// Presences of the users that have one, the others are available.
+	Presences []*UserPresence `json:"presences"`
+}
@@ -0,0 +1,87 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/jsonrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	UserExt_SetUserPresence_FullMethodName  = "/openim.userext.UserExt/SetUserPresence"
+	UserExt_GetUsersPresence_FullMethodName = "/openim.userext.UserExt/GetUsersPresence"
+)
+
+type UserExtClient interface {
+	SetUserPresence(ctx context.Context, in *SetUserPresenceReq, opts ...grpc.CallOption) (*SetUserPresenceResp, error)
+	GetUsersPresence(ctx context.Context, in *GetUsersPresenceReq, opts ...grpc.CallOption) (*GetUsersPresenceResp, error)
+}
+
+type userExtClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserExtClient(cc grpc.ClientConnInterface) UserExtClient {
+	return &userExtClient{cc}
+}
+
+func (c *userExtClient) SetUserPresence(ctx context.Context, in *SetUserPresenceReq, opts ...grpc.CallOption) (*SetUserPresenceResp, error) {
+	return jsonrpc.Invoke[SetUserPresenceReq, SetUserPresenceResp](ctx, c.cc, UserExt_SetUserPresence_FullMethodName, in, opts...)
+}
+
+func (c *userExtClient) GetUsersPresence(ctx context.Context, in *GetUsersPresenceReq, opts ...grpc.CallOption) (*GetUsersPresenceResp, error) {
+	return jsonrpc.Invoke[GetUsersPresenceReq, GetUsersPresenceResp](ctx, c.cc, UserExt_GetUsersPresence_FullMethodName, in, opts...)
+}
+
+type UserExtServer interface {
+	SetUserPresence(context.Context, *SetUserPresenceReq) (*SetUserPresenceResp, error)
+	GetUsersPresence(context.Context, *GetUsersPresenceReq) (*GetUsersPresenceResp, error)
+}
+
+// UnimplementedUserExtServer can be embedded to have forward compatible implementations.
+type UnimplementedUserExtServer struct{}
+
+func (UnimplementedUserExtServer) SetUserPresence(context.Context, *SetUserPresenceReq) (*SetUserPresenceResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetUserPresence not implemented")
+}
+
+func (UnimplementedUserExtServer) GetUsersPresence(context.Context, *GetUsersPresenceReq) (*GetUsersPresenceResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsersPresence not implemented")
+}
+
+func RegisterUserExtServer(s grpc.ServiceRegistrar, srv UserExtServer) {
+	s.RegisterService(&UserExt_ServiceDesc, srv)
+}
+
+var UserExt_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "openim.userext.UserExt",
+	HandlerType: (*UserExtServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetUserPresence",
+			Handler:    jsonrpc.UnaryHandler(UserExt_SetUserPresence_FullMethodName, UserExtServer.SetUserPresence),
+		},
+		{
+			MethodName: "GetUsersPresence",
+			Handler:    jsonrpc.UnaryHandler(UserExt_GetUsersPresence_FullMethodName, UserExtServer.GetUsersPresence),
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "userext",
+}
@@ -0,0 +1,154 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/utils/datautil"
+	"github.com/openimsdk/tools/utils/runtimeenv"
+	"github.com/spf13/viper"
+)
+
+const StructTagName = "yaml"
+
+func readConfig[T any](dir string, name string) (*T, error) {
+	if runtimeenv.RuntimeEnvironment() == config.KUBERNETES {
+		dir = os.Getenv(config.MountConfigFilePath)
+	}
+	v := viper.New()
+	v.SetEnvPrefix(config.EnvPrefixMap[name])
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetConfigFile(filepath.Join(dir, name))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var conf T
+	if err := v.Unmarshal(&conf, func(config *mapstructure.DecoderConfig) {
+		config.TagName = StructTagName
+	}); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
+
+func Main(conf string, conversationIDs []string, output string, attachments bool, objectURL string) error {
+	mongodbConfig, err := readConfig[config.Mongo](conf, config.MongodbConfigFileName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	connCtx, connCancel := context.WithTimeout(ctx, time.Second*10)
+	defer connCancel()
+	mgocli, err := mongoutil.NewMongoDB(connCtx, mongodbConfig.Build())
+	if err != nil {
+		return err
+	}
+	msgDocModel, err := mgo.NewMsgMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	seqConversation, err := mgo.NewSeqConversationMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	seqUser, err := mgo.NewSeqUserMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	source := &dbSource{db: controller.NewMsgExportDatabase(msgDocModel, seqConversation, seqUser)}
+
+	tmp := output + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	opts := msgexport.Options{
+		ConversationIDs: datautil.Distinct(conversationIDs),
+		Attachments:     attachments,
+		AccessURL:       objectAccessURL(objectURL),
+	}
+	manifest, err := msgexport.Export(ctx, f, source, opts)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		return err
+	}
+	for _, conversation := range manifest.Conversations {
+		fmt.Printf("[export] conversation %s msgs %d read states %d\n", conversation.ConversationID, conversation.MsgCount, conversation.ReadStateCount)
+	}
+	var failed int
+	for _, attachment := range manifest.Attachments {
+		if attachment.Error != "" {
+			failed++
+			fmt.Printf("[export] attachment %s failed: %s\n", attachment.Name, attachment.Error)
+		}
+	}
+	fmt.Printf("[export] attachments %d failed %d, archive %s\n", len(manifest.Attachments), failed, output)
+	return nil
+}
+
+// objectAccessURL serves every object from the configured url, whatever host the msgs reference.
+func objectAccessURL(objectURL string) func(ctx context.Context, name string) (string, error) {
+	base := strings.TrimSuffix(objectURL, "/") + "/"
+	return func(ctx context.Context, name string) (string, error) {
+		parts := strings.Split(name, "/")
+		for i, part := range parts {
+			parts[i] = url.PathEscape(part)
+		}
+		return base + strings.Join(parts, "/"), nil
+	}
+}
+
+// dbSource reads the conversations straight from mongo, no OpenIM service needs to be running.
+type dbSource struct {
+	db controller.MsgExportDatabase
+}
+
+func (d *dbSource) GetConversation(ctx context.Context, conversationID string) (*msgext.GetExportConversationResp, error) {
+	minSeq, maxSeq, err := d.db.GetSeqRange(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	readStates, err := d.db.GetReadStates(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return &msgext.GetExportConversationResp{
+		ConversationID: conversationID,
+		MinSeq:         minSeq,
+		MaxSeq:         maxSeq,
+		ReadStates:     msgexport.ReadStates(readStates),
+	}, nil
+}
+
+func (d *dbSource) GetMsgDoc(ctx context.Context, conversationID string, index int64) ([]*msgext.ExportedMsg, error) {
+	doc, err := d.db.GetMsgDoc(ctx, conversationID, index)
+	if err != nil {
+		return nil, err
+	}
+	return msgexport.DocMsgs(doc), nil
+}
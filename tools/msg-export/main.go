@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/openimsdk/open-im-server/v3/tools/msg-export/internal"
+)
+
+func main() {
+	var (
+		config          string
+		conversationIDs string
+		output          string
+		attachments     bool
+		objectURL       string
+	)
+	flag.StringVar(&config, "c", "", "config directory")
+	flag.StringVar(&conversationIDs, "conversations", "", "comma separated conversation IDs to export")
+	flag.StringVar(&output, "o", "export.zip", "output archive path")
+	flag.BoolVar(&attachments, "attachments", false, "download the files referenced by the messages into the archive")
+	flag.StringVar(&objectURL, "object-url", "", "url the objects are served from when downloading attachments, e.g. http://127.0.0.1:10002/object/")
+	flag.Parse()
+	if conversationIDs == "" {
+		fmt.Fprintln(os.Stderr, "conversations is empty")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if attachments && objectURL == "" {
+		fmt.Fprintln(os.Stderr, "object-url is required by attachments")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := internal.Main(config, strings.Split(conversationIDs, ","), output, attachments, objectURL); err != nil {
+		fmt.Fprintln(os.Stderr, "msg export", err)
+		os.Exit(1)
+	}
+	fmt.Println("msg export success!")
+}
@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/cache/redis"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/database/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/storage/model"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsearch"
+	"github.com/openimsdk/open-im-server/v3/pkg/protocol/msgext"
+	"github.com/openimsdk/tools/db/mongoutil"
+	"github.com/openimsdk/tools/db/redisutil"
+	"github.com/openimsdk/tools/utils/runtimeenv"
+	"github.com/spf13/viper"
+)
+
+const StructTagName = "yaml"
+
+func readConfig[T any](dir string, name string) (*T, error) {
+	if runtimeenv.RuntimeEnvironment() == config.KUBERNETES {
+		dir = os.Getenv(config.MountConfigFilePath)
+	}
+	v := viper.New()
+	v.SetEnvPrefix(config.EnvPrefixMap[name])
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetConfigFile(filepath.Join(dir, name))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	var conf T
+	if err := v.Unmarshal(&conf, func(config *mapstructure.DecoderConfig) {
+		config.TagName = StructTagName
+	}); err != nil {
+		return nil, err
+	}
+
+	return &conf, nil
+}
+
+func Main(conf string, input string, conversationID string, importID string, batch int) error {
+	if batch <= 0 {
+		return fmt.Errorf("invalid batch %d", batch)
+	}
+	redisConfig, err := readConfig[config.Redis](conf, config.RedisConfigFileName)
+	if err != nil {
+		return err
+	}
+	mongodbConfig, err := readConfig[config.Mongo](conf, config.MongodbConfigFileName)
+	if err != nil {
+		return err
+	}
+	searchConfig, err := readConfig[config.MsgSearch](conf, config.MsgSearchConfigFileName)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	connCtx, connCancel := context.WithTimeout(ctx, time.Second*10)
+	defer connCancel()
+	rdb, err := redisutil.NewRedisClient(connCtx, redisConfig.Build())
+	if err != nil {
+		return err
+	}
+	mgocli, err := mongoutil.NewMongoDB(connCtx, mongodbConfig.Build())
+	if err != nil {
+		return err
+	}
+	msgDocModel, err := mgo.NewMsgMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	seqConversation, err := mgo.NewSeqConversationMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	checkpointModel, err := mgo.NewMsgImportCheckpointMongo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	// the imports of the msg service take the same lock
+	locker, err := mgo.NewCacheMgo(mgocli.GetDB())
+	if err != nil {
+		return err
+	}
+	var searchIndex msgsearch.MsgSearchIndex
+	if searchConfig.Enable {
+		searchIndex, err = msgsearch.NewMsgSearchIndex(ctx, searchConfig, mgocli.GetDB())
+		if err != nil {
+			return err
+		}
+	}
+	db := controller.NewMsgImportDatabase(msgDocModel, redis.NewSeqConversationCacheRedis(rdb, seqConversation), checkpointModel, locker, searchIndex)
+
+	checkpoint, err := db.GetCheckpoint(ctx, importID, conversationID)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Offset > 0 {
+		fmt.Printf("[import] resume %s %s from message %d, last seq %d\n", importID, conversationID, checkpoint.Offset, checkpoint.LastSeq)
+	}
+	f, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var (
+		offset int64
+		line   int64
+		msgs   = make([]*model.MsgInfoModel, 0, batch)
+		start  = time.Now()
+	)
+	flush := func() error {
+		if len(msgs) == 0 {
+			return nil
+		}
+		checkpoint, err = db.ImportMsgs(ctx, importID, conversationID, offset, msgs)
+		if err != nil {
+			return err
+		}
+		offset += int64(len(msgs))
+		msgs = msgs[:0]
+		fmt.Printf("[import] %s imported %d messages, last seq %d, cost %s\n", conversationID, checkpoint.Offset, checkpoint.LastSeq, time.Since(start))
+		return nil
+	}
+	for {
+		data, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			line++
+			// skip what the checkpoint says is already imported
+			if line > checkpoint.Offset {
+				info, err := parseMsg(conversationID, data)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", line, err)
+				}
+				msgs = append(msgs, info)
+				if len(msgs) == batch {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			} else {
+				offset++
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return flush()
+}
+
+func parseMsg(conversationID string, data []byte) (*model.MsgInfoModel, error) {
+	var msg msgext.ExportedMsg
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	if msg.SendID == "" || msg.SendTime <= 0 {
+		return nil, fmt.Errorf("sendID and sendTime are required")
+	}
+	info := msgexport.MsgInfo(&msg)
+	if id := msgprocessor.GetConversationIDByMsg(convert.MsgDB2Pb(info.Msg)); id != conversationID {
+		return nil, fmt.Errorf("message belongs to conversation %s", id)
+	}
+	return info, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/openimsdk/open-im-server/v3/tools/msg-import/internal"
+)
+
+func main() {
+	var (
+		config         string
+		input          string
+		conversationID string
+		importID       string
+		batch          int
+	)
+	flag.StringVar(&config, "c", "", "config directory")
+	flag.StringVar(&input, "i", "", "jsonl file of the messages to import, one per line in the original order")
+	flag.StringVar(&conversationID, "conversation", "", "conversation ID the messages are imported into")
+	flag.StringVar(&importID, "id", "", "import ID the checkpoint is kept under, defaults to the input file name")
+	flag.IntVar(&batch, "batch", 500, "messages written per batch")
+	flag.Parse()
+	if input == "" || conversationID == "" {
+		fmt.Fprintln(os.Stderr, "input and conversation are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if importID == "" {
+		importID = filepath.Base(input)
+	}
+	if err := internal.Main(config, input, conversationID, importID, batch); err != nil {
+		fmt.Fprintln(os.Stderr, "msg import", err)
+		os.Exit(1)
+	}
+	fmt.Println("msg import success!")
+}
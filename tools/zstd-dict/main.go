@@ -0,0 +1,110 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// zstd-dict trains the zstd dictionary used by the msggateway zstd compression protocol.
+// Feed it captured websocket frames (one file per frame in a directory, or one base64 frame per line),
+// then point longConnSvr.compression.zstdDictionary at the output and ship the same file to the SDKs.
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/dict"
+	"github.com/klauspost/compress/zstd"
+)
+
+func main() {
+	var (
+		input   = flag.String("input", "", "directory of sample frames, or a file with one base64 encoded frame per line")
+		output  = flag.String("output", "openim.zstd.dict", "path of the generated dictionary")
+		maxSize = flag.Int("size", 32<<10, "maximum dictionary size in bytes")
+		id      = flag.Uint("id", 0, "zstd dictionary ID, random when zero")
+	)
+	flag.Parse()
+	if *input == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+	samples, err := readSamples(*input)
+	if err != nil {
+		fmt.Println("read samples failed:", err)
+		os.Exit(1)
+	}
+	data, err := dict.BuildZstdDict(samples, dict.Options{
+		MaxDictSize: *maxSize,
+		HashBytes:   6,
+		ZstdDictID:  uint32(*id),
+		ZstdLevel:   zstd.SpeedDefault,
+		// Keep the dictionary loadable by older libzstd builds bundled in mobile SDKs.
+		ZstdDictCompat: true,
+	})
+	if err != nil {
+		fmt.Println("build dictionary failed:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		fmt.Println("write dictionary failed:", err)
+		os.Exit(1)
+	}
+	fmt.Printf("trained %d byte dictionary from %d samples, written to %s\n", len(data), len(samples), *output)
+}
+
+func readSamples(path string) ([][]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		samples := make([][]byte, 0, len(entries))
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, data)
+		}
+		return samples, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var samples [][]byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, data)
+	}
+	return samples, scanner.Err()
+}